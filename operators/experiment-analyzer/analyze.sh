@@ -36,13 +36,28 @@ if [ ! -f "${CLAUDE_CREDS}" ]; then
 fi
 echo "==> Claude Code credentials file found at ${CLAUDE_CREDS}"
 
+# Exit code telling the operator the AI API rate-limited us: it re-queues the
+# analysis with backoff instead of failing it.
+RATE_LIMIT_EXIT=75
+
+# --- Helper: check whether claude output files report a rate limit ---
+is_rate_limited() {
+  grep -qiE "rate_limit_error|overloaded_error|usage limit|429 Too Many Requests" "$@" 2>/dev/null
+}
+
 # Pre-flight auth check — triggers token refresh if access token is expired
 echo "==> Pre-flight auth check (triggers token refresh if needed)..."
-if ! claude -p "respond with ok" --output-format json > /dev/null 2>&1; then
+PREFLIGHT_OUT="$(mktemp)"
+if ! claude -p "respond with ok" --output-format json > "${PREFLIGHT_OUT}" 2>&1; then
+  if is_rate_limited "${PREFLIGHT_OUT}"; then
+    echo "ERROR: Claude API rate limit reached — exiting for the operator to retry later"
+    exit "${RATE_LIMIT_EXIT}"
+  fi
   echo "ERROR: Claude CLI auth failed — credentials may need manual refresh in OpenBao"
   echo "Run: bao kv put secret/experiment-operator/claude-auth credentials=\"\$(cat ~/.claude/.credentials.json)\""
   exit 1
 fi
+rm -f "${PREFLIGHT_OUT}"
 echo "==> Auth check passed"
 
 # Safety: GITHUB_BRANCH must be explicitly set by the operator (to experiment/{name}).
@@ -103,6 +118,59 @@ any_section_requested() {
   return 1
 }
 
+export AWS_ACCESS_KEY_ID="${S3_ACCESS_KEY:-any}"
+export AWS_SECRET_ACCESS_KEY="${S3_SECRET_KEY:-any}"
+S3_BASE="s3://experiment-results/${EXPERIMENT_NAME}"
+
+# --- Helper: upload a file under the experiment's S3 prefix ---
+s3_put() {
+  local local_file="$1"
+  local remote="$2"
+  aws --endpoint-url "http://${S3_ENDPOINT}" s3 cp "${local_file}" "${S3_BASE}/${remote}" --no-sign-request > /dev/null 2>&1 || \
+    aws --endpoint-url "http://${S3_ENDPOINT}" s3 cp "${local_file}" "${S3_BASE}/${remote}" > /dev/null 2>&1
+}
+
+# --- Progress marker ---
+# analysis/progress.json lists every pass with its phase (Pending, Running,
+# Succeeded, Failed, Skipped) and, once done, the sections it produced. The
+# operator copies it into status.analysisPasses and, if the Job fails, keeps
+# the sections of the passes that succeeded (uploaded as soon as they finish).
+PROGRESS_FILE="${WORK_DIR}/progress.json"
+jq -n --arg ts "$(date -u +%Y-%m-%dT%H:%M:%SZ)" '{
+  updatedAt: $ts,
+  passes: [
+    "pass_1_plan", "pass_2_core", "pass_3_finops_secops", "pass_4_capabilities", "pass_5_body_synthesis"
+  ] | map({name: ., phase: "Pending"})
+}' > "${PROGRESS_FILE}"
+
+# Args: pass_name, phase, [output file whose keys are the produced sections]
+set_pass_phase() {
+  local sections='[]'
+  if [ -n "${3:-}" ] && [ "$1" != "pass_1_plan" ]; then
+    sections=$(jq -c 'keys' "$3" 2>/dev/null || echo '[]')
+  fi
+  jq --arg name "$1" --arg phase "$2" --argjson sections "${sections}" \
+    --arg ts "$(date -u +%Y-%m-%dT%H:%M:%SZ)" '
+    .updatedAt = $ts
+    | .passes |= map(if .name == $name then
+        .phase = $phase | if ($sections | length) > 0 then .sections = $sections else . end
+      else . end)
+  ' "${PROGRESS_FILE}" > "${PROGRESS_FILE}.tmp" && mv "${PROGRESS_FILE}.tmp" "${PROGRESS_FILE}"
+  s3_put "${PROGRESS_FILE}" "analysis/progress.json" || echo "WARNING: Failed to upload analysis progress"
+}
+
+# A pass still Running when the script dies did not finish; record that.
+on_exit() {
+  local status=$?
+  if [ "${status}" -ne 0 ] && [ -f "${PROGRESS_FILE}" ]; then
+    for pass_name in $(jq -r '.passes[] | select(.phase == "Running") | .name' "${PROGRESS_FILE}"); do
+      set_pass_phase "${pass_name}" "Failed"
+    done
+  fi
+  cleanup
+}
+trap on_exit EXIT
+
 # --- Helper: extract JSON from claude --output-format json (JSONL) response ---
 extract_json() {
   local raw_file="$1"
@@ -160,12 +228,15 @@ run_pass() {
   local stderr_file="${WORK_DIR}/${pass_name}_stderr.log"
 
   echo "==> Running ${pass_name}..."
+  set_pass_phase "${pass_name}" "Running"
 
   local attempt
   for attempt in 1 2; do
     if claude -p --output-format json < "${prompt_file}" > "${raw_file}" 2>"${stderr_file}"; then
       if extract_json "${raw_file}" "${out_file}"; then
         echo "==> ${pass_name} complete ($(wc -c < "${out_file}") bytes)"
+        s3_put "${out_file}" "analysis/${pass_name}.json" || echo "WARNING: Failed to upload ${pass_name} output"
+        set_pass_phase "${pass_name}" "Succeeded" "${out_file}"
         return 0
       fi
     fi
@@ -173,6 +244,12 @@ run_pass() {
        grep -q "expired\|authentication_error" "${raw_file}" 2>/dev/null; then
       echo "ERROR: Authentication failure in ${pass_name} — token may be expired"
     fi
+    # Retrying now would hit the same limit; the operator backs off and
+    # re-runs the whole analysis later.
+    if is_rate_limited "${stderr_file}" "${raw_file}"; then
+      echo "ERROR: Claude API rate limit reached in ${pass_name} — exiting for the operator to retry later"
+      exit "${RATE_LIMIT_EXIT}"
+    fi
     if [ "${attempt}" -eq 1 ]; then
       echo "WARNING: ${pass_name} attempt 1 failed, retrying..."
       sleep 2
@@ -180,6 +257,7 @@ run_pass() {
   done
 
   echo "WARNING: ${pass_name} failed after 2 attempts — section will be null"
+  set_pass_phase "${pass_name}" "Failed"
   echo '{}' > "${out_file}"
   return 1
 }
@@ -452,6 +530,8 @@ Rules:
 - "metricInsights" must have one entry per metric key in metrics.queries, using exact key names
 - "codeInsights" must have one entry per code snippet key in codeSnippets, using exact key names. If no codeSnippets exist, omit codeInsights entirely
 - Reference specific numbers from the data (CPU cores, memory bytes, durations)
+- The data's "analysis" object is the operator's rule-based baseline (per-metric min/max/mean and trend,
+  winner tables, cost figures). Use its numbers, but explain causes and implications instead of restating them
 - Be technical and concise — this is for infrastructure engineers
 - "architectureDiagram": Mermaid flowchart for an 800px-wide container.
   SYNTAX: 'flowchart TD' only. 'subgraph' for boundaries (max 3, NO nesting).
@@ -528,6 +608,7 @@ ${DIAGRAM_TEXT}"
         # Merge retry diagram back into pass 2 output
         jq -s '.[0] * .[1]' "${PASS2_FILE}" "${RETRY_FILE}" > "${PASS2_FILE}.tmp" \
           && mv "${PASS2_FILE}.tmp" "${PASS2_FILE}"
+        s3_put "${PASS2_FILE}" "analysis/pass_2_core.json" || true
         echo "==> Diagram retry merged into pass 2 output"
       else
         echo "WARNING: Diagram retry failed — using original diagram"
@@ -541,6 +622,7 @@ fi
 else
   echo "==> Skipping pass 2 (core) — no relevant sections requested"
   echo '{}' > "${PASS2_FILE}"
+  set_pass_phase "pass_2_core" "Skipped"
 fi
 
 # ============================================================================
@@ -603,6 +685,7 @@ run_pass "pass_3_finops_secops" "${PASS3_PROMPT_FILE}" "${PASS3_FILE}" || true
 else
   echo "==> Skipping pass 3 (finops/secops) — no relevant sections requested"
   echo '{}' > "${PASS3_FILE}"
+  set_pass_phase "pass_3_finops_secops" "Skipped"
 fi
 
 # ============================================================================
@@ -679,6 +762,7 @@ run_pass "pass_4_capabilities" "${PASS4_PROMPT_FILE}" "${PASS4_FILE}" || true
 else
   echo "==> Skipping pass 4 (capabilities) — no relevant sections requested"
   echo '{}' > "${PASS4_FILE}"
+  set_pass_phase "pass_4_capabilities" "Skipped"
 fi
 
 # ============================================================================
@@ -817,6 +901,7 @@ run_pass "pass_5_body_synthesis" "${PASS5_PROMPT_FILE}" "${PASS5_FILE}" || true
 else
   echo "==> Skipping pass 5 (body synthesis) — body not requested"
   echo '{}' > "${PASS5_FILE}"
+  set_pass_phase "pass_5_body_synthesis" "Skipped"
 fi
 
 # ============================================================================
@@ -838,7 +923,7 @@ jq -s --arg ts "$(date -u +%Y-%m-%dT%H:%M:%SZ)" --arg model "claude-opus-4-6" '
   (.[4] // {}) +
   # Add backward-compat fields + metadata
   {
-    summary: ((.[1] // {}).abstract // "Analysis incomplete"),
+    summary: (.[1] // {}).abstract,
     generatedAt: $ts,
     model: $model
   }
@@ -848,8 +933,10 @@ jq -s --arg ts "$(date -u +%Y-%m-%dT%H:%M:%SZ)" --arg model "claude-opus-4-6" '
 jq 'del(.technologies, .isComparison, .focusAreas, .domainContext, .domain)' \
   "${FINAL_FILE}" > "${FINAL_FILE}.tmp" && mv "${FINAL_FILE}.tmp" "${FINAL_FILE}"
 
-# Remove null capabilitiesMatrix for non-comparison experiments
-jq 'if .capabilitiesMatrix == null then del(.capabilitiesMatrix) else . end' \
+# Remove null capabilitiesMatrix for non-comparison experiments, and a null
+# summary when pass 2 produced no abstract (the baseline summary is kept)
+jq 'if .capabilitiesMatrix == null then del(.capabilitiesMatrix) else . end
+  | if .summary == null then del(.summary) else . end' \
   "${FINAL_FILE}" > "${FINAL_FILE}.tmp" && mv "${FINAL_FILE}.tmp" "${FINAL_FILE}"
 
 # Strip any sections that weren't explicitly requested
@@ -863,10 +950,11 @@ done
 echo "==> Final analysis assembled ($(wc -c < "${FINAL_FILE}") bytes)"
 echo "==> Sections present: $(jq -r 'keys | join(", ")' "${FINAL_FILE}")"
 
-# Merge analysis into summary
+# Merge analysis into summary, over the operator's rule-based baseline:
+# sections the model produced replace the baseline's, the rest are kept.
 echo "==> Merging analysis into summary.json"
 jq --slurpfile analysis "${FINAL_FILE}" \
-  '. + {analysis: $analysis[0]}' \
+  '. + {analysis: ((.analysis // {summary: "Analysis incomplete"}) * $analysis[0])}' \
   "${SUMMARY_FILE}" > "${ENRICHED_FILE}"
 
 # Ensure architectureDiagramFormat is set when diagram contains Mermaid syntax
@@ -885,9 +973,6 @@ fi
 # Verbose logging: Upload all intermediate pass outputs to S3
 # ============================================================================
 echo "==> Uploading verbose analysis artifacts to S3"
-export AWS_ACCESS_KEY_ID="${S3_ACCESS_KEY:-any}"
-export AWS_SECRET_ACCESS_KEY="${S3_SECRET_KEY:-any}"
-S3_BASE="s3://experiment-results/${EXPERIMENT_NAME}"
 
 # Build a trace manifest with timing and sizes
 TRACE_FILE="${WORK_DIR}/analysis_trace.json"
@@ -925,9 +1010,7 @@ for artifact in \
   LOCAL="${artifact%%:*}"
   REMOTE="${artifact##*:}"
   if [ -f "${LOCAL}" ]; then
-    aws --endpoint-url "http://${S3_ENDPOINT}" s3 cp "${LOCAL}" "${S3_BASE}/${REMOTE}" --no-sign-request 2>/dev/null || \
-    aws --endpoint-url "http://${S3_ENDPOINT}" s3 cp "${LOCAL}" "${S3_BASE}/${REMOTE}" 2>/dev/null || \
-    echo "WARNING: Failed to upload ${REMOTE}"
+    s3_put "${LOCAL}" "${REMOTE}" || echo "WARNING: Failed to upload ${REMOTE}"
   fi
 done
 
@@ -936,8 +1019,7 @@ for pass_name in pass_1_plan pass_2_core pass_3_finops_secops pass_4_capabilitie
   for suffix in _raw.json _stderr.log; do
     LOCAL="${WORK_DIR}/${pass_name}${suffix}"
     if [ -f "${LOCAL}" ] && [ -s "${LOCAL}" ]; then
-      aws --endpoint-url "http://${S3_ENDPOINT}" s3 cp "${LOCAL}" "${S3_BASE}/analysis/${pass_name}${suffix}" --no-sign-request 2>/dev/null || \
-      aws --endpoint-url "http://${S3_ENDPOINT}" s3 cp "${LOCAL}" "${S3_BASE}/analysis/${pass_name}${suffix}" 2>/dev/null || true
+      s3_put "${LOCAL}" "analysis/${pass_name}${suffix}" || true
     fi
   done
 done
@@ -957,6 +1039,8 @@ fi
 # Commit to GitHub if token is available and branch was explicitly set
 if [ -n "${SKIP_GITHUB_COMMIT}" ]; then
   echo "==> Skipping GitHub commit — GITHUB_BRANCH was not set"
+elif [ "${GITHUB_COMMIT:-true}" = "false" ]; then
+  echo "==> Skipping GitHub commit — the operator validates and pushes the enriched summary"
 elif [ -n "${GITHUB_TOKEN:-}" ] && [ -n "${GITHUB_REPO:-}" ]; then
   echo "==> Committing enriched results to GitHub"
 
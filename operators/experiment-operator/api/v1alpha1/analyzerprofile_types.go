@@ -0,0 +1,134 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnalyzerProfileSpec describes the analyzer Job run for published experiments:
+// its image, environment, credentials, volumes and resources. The operator
+// always adds the experiment context (EXPERIMENT_NAME, S3_ENDPOINT, and the
+// GITHUB_* publish variables) to the analyzer container's environment.
+type AnalyzerProfileSpec struct {
+	// Description of the profile
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Image of the analyzer container. Defaults to the manager's ANALYZER_IMAGE.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Command overrides the image entrypoint
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args to the analyzer container
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env adds environment variables to the analyzer container, e.g. a model
+	// endpoint or an API key from a secret.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom adds environment variables from ConfigMaps or Secrets
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// RequiredSecrets are checked in the Job namespace before the Job is
+	// created. If one is missing or empty, analysis is skipped with a
+	// CredentialsMissing condition instead of failing the experiment.
+	// +optional
+	RequiredSecrets []SecretKeyRef `json:"requiredSecrets,omitempty"`
+
+	// Volumes of the analyzer pod
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// VolumeMounts of the analyzer container
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// InitContainers run before the analyzer, e.g. to seed credentials
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Resources of the analyzer container
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ServiceAccountName runs the analyzer pod as this service account
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// BackoffLimit is the number of retries before the Job is failed
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// SecretKeyRef names a key in a Secret
+type SecretKeyRef struct {
+	// Name of the Secret
+	// +required
+	Name string `json:"name"`
+
+	// Key within the Secret
+	// +required
+	Key string `json:"key"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.image`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AnalyzerProfile is the Schema for the analyzerprofiles API
+type AnalyzerProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AnalyzerProfileSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AnalyzerProfileList contains a list of AnalyzerProfile
+type AnalyzerProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AnalyzerProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AnalyzerProfile{}, &AnalyzerProfileList{})
+}
@@ -42,6 +42,13 @@ type ComponentSpec struct {
 	// Observability configuration for this component
 	// +optional
 	Observability *ComponentObservability `json:"observability,omitempty"`
+
+	// Layer is the deployment layer experiments deploy this component in
+	// unless the ComponentRef sets one (e.g., "operators" for a CRD
+	// operator). Defaults to "workload".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Layer string `json:"layer,omitempty"`
 }
 
 // ComponentSource defines a source location for a component
@@ -53,6 +53,12 @@ type ExperimentSpec struct {
 	// +optional
 	Metrics []MetricsQuery `json:"metrics,omitempty"`
 
+	// Probes defines benchmark queries replayed against each target's backend
+	// during the Running phase. Latency distributions and error rates are
+	// recorded in status and in summary.json alongside spec.metrics results.
+	// +optional
+	Probes []ProbeSpec `json:"probes,omitempty"`
+
 	// Tags for categorization on the benchmark site (e.g., "observability", "networking").
 	// +optional
 	Tags []string `json:"tags,omitempty"`
@@ -119,6 +125,16 @@ type AnalyzerConfig struct {
 	// +optional
 	// +kubebuilder:validation:items:Enum=abstract;targetAnalysis;performanceAnalysis;metricInsights;finopsAnalysis;secopsAnalysis;body;capabilitiesMatrix;feedback;architectureDiagram
 	Sections []string `json:"sections,omitempty"`
+
+	// Profile names the AnalyzerProfile that runs the analysis. Defaults to
+	// the manager's ANALYZER_PROFILE, then to the built-in Claude profile.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
+	// Priority orders this experiment in the analyzer queue; higher values
+	// start first, ties go to the experiment queued earliest.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
 }
 
 // QualityGateSpec configures auto-iteration for metrics quality.
@@ -267,9 +283,12 @@ type MetricsQuery struct {
 	Name string `json:"name"`
 
 	// Query is a PromQL expression. Variable substitution:
-	//   $EXPERIMENT — experiment name
-	//   $NAMESPACE  — experiment namespace
-	//   $DURATION   — experiment duration as Prometheus duration (e.g., "15m", "2h")
+	//   $EXPERIMENT        — experiment name
+	//   $NAMESPACE         — experiment namespace
+	//   $DURATION          — query window as Prometheus duration (e.g., "15m", "2h")
+	//   $WORKLOAD_DURATION — full workload window (workflow start to finish)
+	//   $START, $END       — query window bounds as Unix seconds (e.g., for "@ $END")
+	// The query window is the workload window unless Window names a phase.
 	// +required
 	Query string `json:"query"`
 
@@ -290,6 +309,120 @@ type MetricsQuery struct {
 	// Group is an optional grouping label for organizing metrics in the UI.
 	// +optional
 	Group string `json:"group,omitempty"`
+
+	// Required marks the metric as essential to the experiment. The quality gate
+	// fails while any required metric has no data, regardless of overall coverage.
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// Window selects a named phase window from spec.workflow.phaseWindows
+	// (e.g., "steady-state"). Range queries cover only that window and instant
+	// queries are evaluated at its end. Defaults to the whole workload window.
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// Trim controls how warmup and cooldown are excluded from range query
+	// statistics (success criteria means). Chart data is never trimmed; the
+	// chosen steady-state interval is recorded alongside it. Defaults to auto.
+	// +optional
+	Trim *TrimSpec `json:"trim,omitempty"`
+}
+
+// TrimSpec configures steady-state trimming for a range query.
+type TrimSpec struct {
+	// Mode: auto (detect steady state per series by coefficient-of-variation
+	// windowing), fixed (drop WarmupSeconds/CooldownSeconds from the window
+	// edges), or none (use every point).
+	// +optional
+	// +kubebuilder:validation:Enum=auto;fixed;none
+	// +kubebuilder:default="auto"
+	Mode string `json:"mode,omitempty"`
+
+	// WarmupSeconds to drop from the start of the window in fixed mode.
+	// +optional
+	WarmupSeconds int `json:"warmupSeconds,omitempty"`
+
+	// CooldownSeconds to drop from the end of the window in fixed mode.
+	// +optional
+	CooldownSeconds int `json:"cooldownSeconds,omitempty"`
+
+	// MaxCV is the coefficient of variation (stddev/mean) below which a sliding
+	// window counts as steady in auto mode. When unset, the threshold is 0.1,
+	// or 1.5x the series' median window CV for noisier series.
+	// +optional
+	MaxCV *float64 `json:"maxCV,omitempty"`
+}
+
+// ProbeSpec defines a benchmark query replayed against a service on a target
+// cluster to measure read-path latency of the system under test. Requests go
+// through the target's API server service proxy, so no ingress is required.
+type ProbeSpec struct {
+	// Name is the key for this probe in output JSON (e.g., "vm_high_cardinality_sum").
+	// +required
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Target is the target name from spec.targets whose backend is probed.
+	// If omitted, the probe runs against every non-hub target.
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// Type selects the request shape:
+	//   promql — GET {path|/api/v1/query}?query=<query>
+	//   logql  — GET {path|/loki/api/v1/query_range}?query=<query>&start=<now-range>&end=<now>
+	//   http   — GET {path} with params only
+	// +optional
+	// +kubebuilder:validation:Enum=promql;logql;http
+	// +kubebuilder:default="promql"
+	Type string `json:"type,omitempty"`
+
+	// Service is the Kubernetes service name of the backend on the target cluster.
+	// +required
+	Service string `json:"service"`
+
+	// Namespace of the service. Defaults to the experiment namespace on the
+	// target cluster (the experiment name).
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Port is the service port.
+	// +required
+	Port int `json:"port"`
+
+	// Path overrides the request path for the probe type (e.g., "/prometheus/api/v1/query"
+	// for Mimir). Required when type is http.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Query is the PromQL/LogQL expression. Same variable substitution as spec.metrics.
+	// +optional
+	Query string `json:"query,omitempty"`
+
+	// Range is the lookback window for logql probes (Prometheus duration, default "5m").
+	// +optional
+	Range string `json:"range,omitempty"`
+
+	// Params are extra query parameters added to every request.
+	// +optional
+	Params map[string]string `json:"params,omitempty"`
+
+	// Iterations is the number of times the query is replayed (default 20).
+	// +optional
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	Iterations int `json:"iterations,omitempty"`
+
+	// TimeoutSeconds is the per-request timeout; timed-out requests count as errors (default 10).
+	// +optional
+	// +kubebuilder:default=10
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// DelaySeconds is how long to wait after the workflow starts before probing,
+	// so the backend has ingested data worth querying. Probes that have not run
+	// by the time the workflow finishes are run before leaving the Running phase.
+	// +optional
+	DelaySeconds int `json:"delaySeconds,omitempty"`
 }
 
 // TutorialSpec defines tutorial configuration for interactive experiments
@@ -352,8 +485,31 @@ type Target struct {
 	// before this target's apps are created. Used for ordered multi-target deployment.
 	// +optional
 	Depends []string `json:"depends,omitempty"`
+
+	// ApplicationMode selects how components map to ArgoCD Applications.
+	// Combined (default) deploys all components as one multi-source
+	// Application; PerComponent deploys each component as its own
+	// Application, so a broken chart only fails its own health check.
+	// +optional
+	// +kubebuilder:validation:Enum=Combined;PerComponent
+	ApplicationMode string `json:"applicationMode,omitempty"`
+
+	// Layers orders the deployment layers of this target's components (see
+	// ComponentRef.Layer). Each layer's Application is created only once the
+	// previous layer is healthy. Defaults to infra, obs, then other layers in
+	// order of first use, then workload; layers used but not listed here
+	// deploy before workload in order of first use.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Layers []string `json:"layers,omitempty"`
 }
 
+// Application modes for Target.ApplicationMode.
+const (
+	ApplicationModeCombined     = "Combined"
+	ApplicationModePerComponent = "PerComponent"
+)
+
 // ClusterSpec defines cluster configuration
 type ClusterSpec struct {
 	// Type: gke, hub (existing hub cluster)
@@ -396,6 +552,13 @@ type ComponentRef struct {
 	// Parameters to pass to component
 	// +optional
 	Params map[string]string `json:"params,omitempty"`
+
+	// Layer is the deployment layer the component belongs to, overriding the
+	// Component's spec.layer (e.g., "operators" for CRD operators that
+	// workloads need first). Defaults to "workload".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Layer string `json:"layer,omitempty"`
 }
 
 // WorkflowSpec defines the experiment workflow
@@ -411,6 +574,26 @@ type WorkflowSpec struct {
 	// Parameters
 	// +optional
 	Params map[string]string `json:"params,omitempty"`
+
+	// PhaseWindows names workflow steps whose start and finish times bound a
+	// metrics window (e.g., warmup, steady-state, cooldown). Metrics select a
+	// window with spec.metrics[].window.
+	// +optional
+	PhaseWindows []PhaseWindowSpec `json:"phaseWindows,omitempty"`
+}
+
+// PhaseWindowSpec maps a named metrics window to the workflow step that marks it.
+type PhaseWindowSpec struct {
+	// Name of the window, referenced by spec.metrics[].window.
+	// +required
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9-]*$`
+	Name string `json:"name"`
+
+	// Step is the Argo workflow node display name or template name whose
+	// execution marks the window. If the step runs more than once, the window
+	// spans the first start to the last finish.
+	// +required
+	Step string `json:"step"`
 }
 
 // CompletionSpec defines when experiment completes
@@ -487,11 +670,36 @@ type ExperimentStatus struct {
 	AnalysisJobName string `json:"analysisJobName,omitempty"`
 
 	// AnalysisPhase tracks the AI analyzer Job lifecycle.
-	// Empty when no analysis was requested. Set to Pending when the Job is created,
-	// Running when the Job starts, Succeeded/Failed on completion.
+	// Empty when no analysis was requested. Set to Queued until the analyzer
+	// queue admits it, Pending when the Job is created, Running when the Job
+	// starts, Succeeded/Failed on completion.
 	// +optional
 	AnalysisPhase AnalysisPhase `json:"analysisPhase,omitempty"`
 
+	// AnalysisQueuedAt is when the analysis entered the analyzer queue
+	// +optional
+	AnalysisQueuedAt *metav1.Time `json:"analysisQueuedAt,omitempty"`
+
+	// AnalysisRetries counts analyzer runs re-queued after hitting the AI
+	// API rate limit
+	// +optional
+	AnalysisRetries int32 `json:"analysisRetries,omitempty"`
+
+	// AnalysisNotBefore holds a re-queued analysis back until its rate-limit
+	// backoff expires
+	// +optional
+	AnalysisNotBefore *metav1.Time `json:"analysisNotBefore,omitempty"`
+
+	// AnalysisPasses is the analyzer's per-pass progress, read from the
+	// analysis/progress.json marker it keeps in the results store.
+	// +optional
+	AnalysisPasses []AnalysisPassStatus `json:"analysisPasses,omitempty"`
+
+	// AnalysisHistory records previous analyzer runs, oldest first, when
+	// analysis is re-run with the reanalyze annotation.
+	// +optional
+	AnalysisHistory []AnalysisRun `json:"analysisHistory,omitempty"`
+
 	// HypothesisResult is the machine-evaluated verdict from success criteria.
 	// Values: "validated" (all criteria pass), "invalidated" (any fail),
 	// "insufficient" (missing/errored metrics), or empty (no criteria / AI decides).
@@ -499,8 +707,9 @@ type ExperimentStatus struct {
 	HypothesisResult string `json:"hypothesisResult,omitempty"`
 
 	// ReviewPhase tracks the human review gate for published experiments.
-	// Set to Pending after analysis resolves, Approved/Rejected by annotation,
-	// or Skipped for non-published experiments.
+	// Set to Pending after analysis resolves, ChangesRequested while a PR review
+	// requests changes, Approved/Rejected by annotation or by merging/closing
+	// the PR, or Skipped for non-published experiments.
 	// +optional
 	ReviewPhase ReviewPhase `json:"reviewPhase,omitempty"`
 
@@ -508,6 +717,11 @@ type ExperimentStatus struct {
 	// +optional
 	IterationStatus *IterationStatus `json:"iterationStatus,omitempty"`
 
+	// ProbeResults holds latency and error statistics for each (probe, target)
+	// pair from spec.probes, recorded once the probe has run.
+	// +optional
+	ProbeResults []ProbeResult `json:"probeResults,omitempty"`
+
 	// Conditions
 	// +listType=map
 	// +listMapKey=type
@@ -543,21 +757,85 @@ type DiscoveredService struct {
 
 // IterationStatus tracks quality gate iteration progress for metrics re-collection.
 type IterationStatus struct {
-	CurrentIteration int              `json:"currentIteration"`
-	MaxIterations    int              `json:"maxIterations"`
-	Phase            IterationPhase   `json:"phase,omitempty"`
-	QualityResults   []QualityResult  `json:"qualityResults,omitempty"`
+	CurrentIteration int             `json:"currentIteration"`
+	MaxIterations    int             `json:"maxIterations"`
+	Phase            IterationPhase  `json:"phase,omitempty"`
+	QualityResults   []QualityResult `json:"qualityResults,omitempty"`
+
+	// WindowStep counts shortenWindow remedies applied so far and selects the
+	// $DURATION used on re-collection (see metrics.IterationDuration).
+	WindowStep int `json:"windowStep,omitempty"`
+
+	// PreferredSource pins re-collection to one metrics backend ("hub" or
+	// "target") after a switchBackend remedy. Empty uses the default order.
+	PreferredSource string `json:"preferredSource,omitempty"`
 }
 
 // QualityResult records the outcome of a single metrics quality evaluation.
 type QualityResult struct {
-	Iteration       int      `json:"iteration"`
-	TotalMetrics    int      `json:"totalMetrics"`
-	MetricsWithData int      `json:"metricsWithData"`
-	Coverage        float64  `json:"coverage"`
-	MissingMetrics  []string `json:"missingMetrics,omitempty"`
-	Passed          bool     `json:"passed"`
-	Remedy          string   `json:"remedy,omitempty"`
+	Iteration       int               `json:"iteration"`
+	TotalMetrics    int               `json:"totalMetrics"`
+	MetricsWithData int               `json:"metricsWithData"`
+	Coverage        float64           `json:"coverage"`
+	MissingMetrics  []string          `json:"missingMetrics,omitempty"`
+	MissingRequired []string          `json:"missingRequired,omitempty"`
+	Diagnoses       []MetricDiagnosis `json:"diagnoses,omitempty"`
+	Action          RemedyAction      `json:"action,omitempty"`
+	Passed          bool              `json:"passed"`
+	Remedy          string            `json:"remedy,omitempty"`
+}
+
+// MetricDiagnosis explains why a single metric returned no data.
+type MetricDiagnosis struct {
+	Metric string           `json:"metric"`
+	Cause  MissingDataCause `json:"cause"`
+	Detail string           `json:"detail,omitempty"`
+}
+
+// MissingDataCause classifies why a metric query returned no data.
+// +kubebuilder:validation:Enum=backendError;targetDown;seriesAbsent;rateWindow
+type MissingDataCause string
+
+const (
+	// MissingDataBackendError: the query itself failed (HTTP error, timeout, bad PromQL).
+	MissingDataBackendError MissingDataCause = "backendError"
+	// MissingDataTargetDown: no scrape target for the series is up (up{} is 0 or absent).
+	MissingDataTargetDown MissingDataCause = "targetDown"
+	// MissingDataSeriesAbsent: scrape targets are up but the series was never ingested.
+	MissingDataSeriesAbsent MissingDataCause = "seriesAbsent"
+	// MissingDataRateWindow: the series exists but the query's range/window yields nothing.
+	MissingDataRateWindow MissingDataCause = "rateWindow"
+)
+
+// RemedyAction is the re-collection strategy chosen from missing-data diagnoses.
+// +kubebuilder:validation:Enum=waitForScrape;shortenWindow;switchBackend
+type RemedyAction string
+
+const (
+	RemedyWaitForScrape RemedyAction = "waitForScrape"
+	RemedyShortenWindow RemedyAction = "shortenWindow"
+	RemedySwitchBackend RemedyAction = "switchBackend"
+)
+
+// ProbeResult records the latency distribution of a probe against one target.
+// Latencies are in milliseconds and cover successful requests only.
+type ProbeResult struct {
+	Name        string       `json:"name"`
+	Target      string       `json:"target"`
+	Type        string       `json:"type,omitempty"`
+	Endpoint    string       `json:"endpoint,omitempty"`
+	Iterations  int          `json:"iterations"`
+	Errors      int          `json:"errors"`
+	ErrorRate   float64      `json:"errorRate"`
+	MinMs       float64      `json:"minMs,omitempty"`
+	MeanMs      float64      `json:"meanMs,omitempty"`
+	P50Ms       float64      `json:"p50Ms,omitempty"`
+	P90Ms       float64      `json:"p90Ms,omitempty"`
+	P95Ms       float64      `json:"p95Ms,omitempty"`
+	P99Ms       float64      `json:"p99Ms,omitempty"`
+	MaxMs       float64      `json:"maxMs,omitempty"`
+	LastError   string       `json:"lastError,omitempty"`
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
 }
 
 // IterationPhase represents the current phase of quality gate iteration.
@@ -585,10 +863,13 @@ const (
 )
 
 // AnalysisPhase tracks the lifecycle of the AI analyzer Job.
-// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Skipped
+// +kubebuilder:validation:Enum=Queued;Pending;Running;Succeeded;Failed;Skipped
 type AnalysisPhase string
 
 const (
+	// AnalysisPhaseQueued means the analyzer Job waits for a free slot under
+	// the manager's concurrency limit, or for a rate-limit backoff to expire.
+	AnalysisPhaseQueued    AnalysisPhase = "Queued"
 	AnalysisPhasePending   AnalysisPhase = "Pending"
 	AnalysisPhaseRunning   AnalysisPhase = "Running"
 	AnalysisPhaseSucceeded AnalysisPhase = "Succeeded"
@@ -596,21 +877,124 @@ const (
 	AnalysisPhaseSkipped   AnalysisPhase = "Skipped"
 )
 
+// AnalysisPassStatus is the progress of one analyzer pass.
+type AnalysisPassStatus struct {
+	// Name of the pass, e.g. pass_2_core
+	Name string `json:"name"`
+
+	// Phase of the pass
+	Phase AnalysisPhase `json:"phase"`
+
+	// Sections the pass produced
+	// +optional
+	Sections []string `json:"sections,omitempty"`
+}
+
+// AnalysisRun is a finished analyzer run kept in status.analysisHistory.
+type AnalysisRun struct {
+	// JobName of the analyzer Job
+	JobName string `json:"jobName"`
+
+	// Sections the run was asked for (empty means all)
+	// +optional
+	Sections []string `json:"sections,omitempty"`
+
+	// Phase the run ended in
+	Phase AnalysisPhase `json:"phase"`
+
+	// ResultsKey is the results store key of the summary analysis this run
+	// produced, kept when a later run replaced it
+	// +optional
+	ResultsKey string `json:"resultsKey,omitempty"`
+
+	// CompletedAt is when the run was superseded by a new one
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
 // ReviewPhase tracks the human review gate for published experiments.
-// +kubebuilder:validation:Enum=Pending;Approved;Rejected;Skipped
+// +kubebuilder:validation:Enum=Pending;ChangesRequested;Approved;Rejected;Skipped
 type ReviewPhase string
 
 const (
-	ReviewPhasePending  ReviewPhase = "Pending"
-	ReviewPhaseApproved ReviewPhase = "Approved"
-	ReviewPhaseRejected ReviewPhase = "Rejected"
-	ReviewPhaseSkipped  ReviewPhase = "Skipped"
+	ReviewPhasePending ReviewPhase = "Pending"
+	// ReviewPhaseChangesRequested means a reviewer requested changes on the
+	// results PR. The gate stays open until the PR is approved, merged or closed.
+	ReviewPhaseChangesRequested ReviewPhase = "ChangesRequested"
+	ReviewPhaseApproved         ReviewPhase = "Approved"
+	ReviewPhaseRejected         ReviewPhase = "Rejected"
+	ReviewPhaseSkipped          ReviewPhase = "Skipped"
 )
 
 // AnnotationReview is the annotation key used to signal human review decisions.
-// Values: "approved" or "rejected".
+// Values: "approved" or "rejected". Merging or closing the results PR on
+// GitHub has the same effect.
 const AnnotationReview = "experiments.illm.io/review"
 
+// AnnotationReanalyze requests a new analyzer run against the stored
+// summary.json of a completed experiment. The value is a comma-separated list
+// of analysis sections, or "true" to reuse spec.analyzerConfig.sections. The
+// operator removes the annotation once the Job is created.
+const AnnotationReanalyze = "experiments.illm.io/reanalyze"
+
+// TargetComponentStatus is the deployment status of one component of a target.
+type TargetComponentStatus struct {
+	// Name of the component
+	Name string `json:"name"`
+
+	// Application is the ArgoCD Application deploying the component; in
+	// Combined mode it is shared by all components of the target.
+	// +optional
+	Application string `json:"application,omitempty"`
+
+	// Health is the Application's health status (Healthy, Progressing,
+	// Degraded, Missing, ...)
+	// +optional
+	Health string `json:"health,omitempty"`
+
+	// Sync is the Application's sync status (Synced, OutOfSync, Unknown)
+	// +optional
+	Sync string `json:"sync,omitempty"`
+
+	// Message explains an unhealthy or failed state, e.g. a manifest
+	// generation error
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UnhealthyResource is a resource an ArgoCD Application reports as not
+// Healthy or not Synced.
+type UnhealthyResource struct {
+	// Application is the ArgoCD Application managing the resource
+	Application string `json:"application"`
+
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Health is the resource's health status (Degraded, Progressing, Missing, ...)
+	// +optional
+	Health string `json:"health,omitempty"`
+
+	// Sync is the resource's sync status (Synced, OutOfSync)
+	// +optional
+	Sync string `json:"sync,omitempty"`
+
+	// Message is ArgoCD's health message for the resource
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// TargetConditionDeploymentHealthy is the TargetStatus condition reporting
+// the state of what the deployer (ArgoCD or direct apply) deployed for the
+// target.
+const TargetConditionDeploymentHealthy = "DeploymentHealthy"
+
 // TargetStatus represents the status of a deployment target
 type TargetStatus struct {
 	// +required
@@ -636,12 +1020,31 @@ type TargetStatus struct {
 	// +optional
 	Components []string `json:"components,omitempty"`
 
+	// ComponentStatuses is the deployment health and sync status of each
+	// component deployed to the target.
+	// +optional
+	ComponentStatuses []TargetComponentStatus `json:"componentStatuses,omitempty"`
+
+	// Conditions holds the target's DeploymentHealthy condition: the health
+	// and sync status of its ArgoCD Applications (or directly applied layers),
+	// the last sync error and the resources reported unhealthy.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// UnhealthyResources lists the resources deployed for the target that
+	// are not Healthy or not Synced.
+	// +optional
+	UnhealthyResources []UnhealthyResource `json:"unhealthyResources,omitempty"`
+
 	// KubeconfigSecret is the name of the secret containing the kubeconfig for this target
 	// +optional
 	KubeconfigSecret string `json:"kubeconfigSecret,omitempty"`
 
 	// DeployedLayers tracks which ArgoCD application layers have been created
-	// for this target. Values: "infra", "obs", "workload".
+	// for this target, in rollout order (e.g., "infra", "obs", "operators",
+	// "workload").
 	// +optional
 	DeployedLayers []string `json:"deployedLayers,omitempty"`
 
@@ -649,6 +1052,15 @@ type TargetStatus struct {
 	// Used for dependency gating in multi-target experiments.
 	// +optional
 	AppsCreated bool `json:"appsCreated,omitempty"`
+
+	// CadvisorSamples is the number of cadvisor samples recorded for this target
+	// during the Running phase (tailscale-transport targets only).
+	// +optional
+	CadvisorSamples int `json:"cadvisorSamples,omitempty"`
+
+	// LastCadvisorSampleAt is when the most recent cadvisor sample was taken.
+	// +optional
+	LastCadvisorSampleAt *metav1.Time `json:"lastCadvisorSampleAt,omitempty"`
 }
 
 // WorkflowStatus represents the status of the experiment workflow
@@ -664,6 +1076,22 @@ type WorkflowStatus struct {
 
 	// +optional
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// Phases records the observed bounds of each spec.workflow.phaseWindows entry.
+	// +optional
+	Phases []PhaseWindowStatus `json:"phases,omitempty"`
+}
+
+// PhaseWindowStatus is the observed time window of a named workflow phase.
+type PhaseWindowStatus struct {
+	// +required
+	Name string `json:"name"`
+
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
 }
 
 // +kubebuilder:object:root=true
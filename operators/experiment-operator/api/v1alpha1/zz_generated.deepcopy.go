@@ -21,10 +21,204 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisPassStatus) DeepCopyInto(out *AnalysisPassStatus) {
+	*out = *in
+	if in.Sections != nil {
+		in, out := &in.Sections, &out.Sections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisPassStatus.
+func (in *AnalysisPassStatus) DeepCopy() *AnalysisPassStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisPassStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisRun) DeepCopyInto(out *AnalysisRun) {
+	*out = *in
+	if in.Sections != nil {
+		in, out := &in.Sections, &out.Sections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisRun.
+func (in *AnalysisRun) DeepCopy() *AnalysisRun {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyzerConfig) DeepCopyInto(out *AnalyzerConfig) {
+	*out = *in
+	if in.Sections != nil {
+		in, out := &in.Sections, &out.Sections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzerConfig.
+func (in *AnalyzerConfig) DeepCopy() *AnalyzerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyzerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyzerProfile) DeepCopyInto(out *AnalyzerProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzerProfile.
+func (in *AnalyzerProfile) DeepCopy() *AnalyzerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyzerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AnalyzerProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyzerProfileList) DeepCopyInto(out *AnalyzerProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AnalyzerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzerProfileList.
+func (in *AnalyzerProfileList) DeepCopy() *AnalyzerProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyzerProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AnalyzerProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyzerProfileSpec) DeepCopyInto(out *AnalyzerProfileSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RequiredSecrets != nil {
+		in, out := &in.RequiredSecrets, &out.RequiredSecrets
+		*out = make([]SecretKeyRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzerProfileSpec.
+func (in *AnalyzerProfileSpec) DeepCopy() *AnalyzerProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyzerProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
@@ -40,6 +234,26 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeSnippet) DeepCopyInto(out *CodeSnippet) {
+	*out = *in
+	if in.UsedBy != nil {
+		in, out := &in.UsedBy, &out.UsedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeSnippet.
+func (in *CodeSnippet) DeepCopy() *CodeSnippet {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeSnippet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompletionSpec) DeepCopyInto(out *CompletionSpec) {
 	*out = *in
@@ -230,7 +444,7 @@ func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -321,21 +535,6 @@ func (in *ExperimentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricsQuery) DeepCopyInto(out *MetricsQuery) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsQuery.
-func (in *MetricsQuery) DeepCopy() *MetricsQuery {
-	if in == nil {
-		return nil
-	}
-	out := new(MetricsQuery)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 	*out = *in
@@ -355,7 +554,16 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 	if in.Metrics != nil {
 		in, out := &in.Metrics, &out.Metrics
 		*out = make([]MetricsQuery, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]ProbeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
@@ -367,6 +575,18 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 		*out = new(HypothesisSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.QualityGate != nil {
+		in, out := &in.QualityGate, &out.QualityGate
+		*out = new(QualityGateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CodeSnippets != nil {
+		in, out := &in.CodeSnippets, &out.CodeSnippets
+		*out = make(map[string]CodeSnippet, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.AnalyzerConfig != nil {
 		in, out := &in.AnalyzerConfig, &out.AnalyzerConfig
 		*out = new(AnalyzerConfig)
@@ -408,9 +628,43 @@ func (in *ExperimentStatus) DeepCopyInto(out *ExperimentStatus) {
 		in, out := &in.CompletedAt, &out.CompletedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.AnalysisQueuedAt != nil {
+		in, out := &in.AnalysisQueuedAt, &out.AnalysisQueuedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.AnalysisNotBefore != nil {
+		in, out := &in.AnalysisNotBefore, &out.AnalysisNotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.AnalysisPasses != nil {
+		in, out := &in.AnalysisPasses, &out.AnalysisPasses
+		*out = make([]AnalysisPassStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnalysisHistory != nil {
+		in, out := &in.AnalysisHistory, &out.AnalysisHistory
+		*out = make([]AnalysisRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IterationStatus != nil {
+		in, out := &in.IterationStatus, &out.IterationStatus
+		*out = new(IterationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeResults != nil {
+		in, out := &in.ProbeResults, &out.ProbeResults
+		*out = make([]ProbeResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -498,36 +752,58 @@ func (in *HypothesisSpec) DeepCopy() *HypothesisSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SuccessCriterion) DeepCopyInto(out *SuccessCriterion) {
+func (in *IterationStatus) DeepCopyInto(out *IterationStatus) {
+	*out = *in
+	if in.QualityResults != nil {
+		in, out := &in.QualityResults, &out.QualityResults
+		*out = make([]QualityResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IterationStatus.
+func (in *IterationStatus) DeepCopy() *IterationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IterationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricDiagnosis) DeepCopyInto(out *MetricDiagnosis) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuccessCriterion.
-func (in *SuccessCriterion) DeepCopy() *SuccessCriterion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricDiagnosis.
+func (in *MetricDiagnosis) DeepCopy() *MetricDiagnosis {
 	if in == nil {
 		return nil
 	}
-	out := new(SuccessCriterion)
+	out := new(MetricDiagnosis)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AnalyzerConfig) DeepCopyInto(out *AnalyzerConfig) {
+func (in *MetricsQuery) DeepCopyInto(out *MetricsQuery) {
 	*out = *in
-	if in.Sections != nil {
-		in, out := &in.Sections, &out.Sections
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Trim != nil {
+		in, out := &in.Trim, &out.Trim
+		*out = new(TrimSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzerConfig.
-func (in *AnalyzerConfig) DeepCopy() *AnalyzerConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsQuery.
+func (in *MetricsQuery) DeepCopy() *MetricsQuery {
 	if in == nil {
 		return nil
 	}
-	out := new(AnalyzerConfig)
+	out := new(MetricsQuery)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -547,6 +823,165 @@ func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseWindowSpec) DeepCopyInto(out *PhaseWindowSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseWindowSpec.
+func (in *PhaseWindowSpec) DeepCopy() *PhaseWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseWindowStatus) DeepCopyInto(out *PhaseWindowStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseWindowStatus.
+func (in *PhaseWindowStatus) DeepCopy() *PhaseWindowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseWindowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeResult) DeepCopyInto(out *ProbeResult) {
+	*out = *in
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeResult.
+func (in *ProbeResult) DeepCopy() *ProbeResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeSpec) DeepCopyInto(out *ProbeSpec) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSpec.
+func (in *ProbeSpec) DeepCopy() *ProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QualityGateSpec) DeepCopyInto(out *QualityGateSpec) {
+	*out = *in
+	if in.MinDataCoverage != nil {
+		in, out := &in.MinDataCoverage, &out.MinDataCoverage
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QualityGateSpec.
+func (in *QualityGateSpec) DeepCopy() *QualityGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QualityGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QualityResult) DeepCopyInto(out *QualityResult) {
+	*out = *in
+	if in.MissingMetrics != nil {
+		in, out := &in.MissingMetrics, &out.MissingMetrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingRequired != nil {
+		in, out := &in.MissingRequired, &out.MissingRequired
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Diagnoses != nil {
+		in, out := &in.Diagnoses, &out.Diagnoses
+		*out = make([]MetricDiagnosis, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QualityResult.
+func (in *QualityResult) DeepCopy() *QualityResult {
+	if in == nil {
+		return nil
+	}
+	out := new(QualityResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SuccessCriterion) DeepCopyInto(out *SuccessCriterion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuccessCriterion.
+func (in *SuccessCriterion) DeepCopy() *SuccessCriterion {
+	if in == nil {
+		return nil
+	}
+	out := new(SuccessCriterion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Target) DeepCopyInto(out *Target) {
 	*out = *in
@@ -563,6 +998,16 @@ func (in *Target) DeepCopyInto(out *Target) {
 		*out = new(ObservabilitySpec)
 		**out = **in
 	}
+	if in.Depends != nil {
+		in, out := &in.Depends, &out.Depends
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Layers != nil {
+		in, out := &in.Layers, &out.Layers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Target.
@@ -575,6 +1020,21 @@ func (in *Target) DeepCopy() *Target {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetComponentStatus) DeepCopyInto(out *TargetComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetComponentStatus.
+func (in *TargetComponentStatus) DeepCopy() *TargetComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetStatus) DeepCopyInto(out *TargetStatus) {
 	*out = *in
@@ -583,11 +1043,32 @@ func (in *TargetStatus) DeepCopyInto(out *TargetStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ComponentStatuses != nil {
+		in, out := &in.ComponentStatuses, &out.ComponentStatuses
+		*out = make([]TargetComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UnhealthyResources != nil {
+		in, out := &in.UnhealthyResources, &out.UnhealthyResources
+		*out = make([]UnhealthyResource, len(*in))
+		copy(*out, *in)
+	}
 	if in.DeployedLayers != nil {
 		in, out := &in.DeployedLayers, &out.DeployedLayers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LastCadvisorSampleAt != nil {
+		in, out := &in.LastCadvisorSampleAt, &out.LastCadvisorSampleAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetStatus.
@@ -600,6 +1081,26 @@ func (in *TargetStatus) DeepCopy() *TargetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrimSpec) DeepCopyInto(out *TrimSpec) {
+	*out = *in
+	if in.MaxCV != nil {
+		in, out := &in.MaxCV, &out.MaxCV
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrimSpec.
+func (in *TrimSpec) DeepCopy() *TrimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TutorialServiceRef) DeepCopyInto(out *TutorialServiceRef) {
 	*out = *in
@@ -662,6 +1163,21 @@ func (in *TutorialStatus) DeepCopy() *TutorialStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnhealthyResource) DeepCopyInto(out *UnhealthyResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnhealthyResource.
+func (in *UnhealthyResource) DeepCopy() *UnhealthyResource {
+	if in == nil {
+		return nil
+	}
+	out := new(UnhealthyResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowSpec) DeepCopyInto(out *WorkflowSpec) {
 	*out = *in
@@ -673,6 +1189,11 @@ func (in *WorkflowSpec) DeepCopyInto(out *WorkflowSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.PhaseWindows != nil {
+		in, out := &in.PhaseWindows, &out.PhaseWindows
+		*out = make([]PhaseWindowSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowSpec.
@@ -696,6 +1217,13 @@ func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 		in, out := &in.FinishedAt, &out.FinishedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]PhaseWindowStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStatus.
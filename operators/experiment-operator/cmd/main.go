@@ -17,10 +17,14 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -28,19 +32,25 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
 	"github.com/illmadecoder/experiment-operator/internal/argocd"
+	"github.com/illmadecoder/experiment-operator/internal/catalog"
 	"github.com/illmadecoder/experiment-operator/internal/controller"
 	"github.com/illmadecoder/experiment-operator/internal/crossplane"
-	ghclient "github.com/illmadecoder/experiment-operator/internal/github"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
+	"github.com/illmadecoder/experiment-operator/internal/publish"
+	"github.com/illmadecoder/experiment-operator/internal/retention"
 	"github.com/illmadecoder/experiment-operator/internal/storage"
 	"github.com/illmadecoder/experiment-operator/internal/workflow"
 	// +kubebuilder:scaffold:imports
@@ -65,12 +75,21 @@ func main() {
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
+	var resultsAPIAddr string
+	var resultsAPIOrigins string
+	var githubWebhookAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&resultsAPIAddr, "results-api-bind-address", ":8082", "The address the read-only results "+
+		"index API binds to. Use 0 to disable it. Requires a results store.")
+	flag.StringVar(&resultsAPIOrigins, "results-api-allowed-origins", "", "Comma-separated browser origins "+
+		"allowed to read the results API cross-origin, or * for any. Empty disables CORS.")
+	flag.StringVar(&githubWebhookAddr, "github-webhook-bind-address", ":8083", "The address the GitHub webhook "+
+		"receiver binds to. Use 0 to disable it. Requires GITHUB_TOKEN and GITHUB_WEBHOOK_SECRET.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -184,32 +203,41 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize S3 client for experiment results storage (optional)
-	var s3Client *storage.Client
-	s3Endpoint := getEnvOrDefault("S3_ENDPOINT", "seaweedfs-s3.seaweedfs.svc.cluster.local:8333")
-	s3Bucket := getEnvOrDefault("S3_BUCKET", "experiment-results")
-	s3Client, err = storage.NewClient(s3Endpoint, "any", "any", s3Bucket)
+	// Initialize the experiment results store (optional). RESULTS_STORE selects
+	// s3 (SeaweedFS by default), gcs, or local (a directory or PVC mount).
+	storeCfg := resultsStoreConfig()
+	resultsStore, err := storage.New(context.Background(), storeCfg)
 	if err != nil {
-		setupLog.Error(err, "Failed to create S3 client — results collection disabled")
+		setupLog.Error(err, "Failed to create results store — results collection disabled", "backend", storeCfg.Backend)
+	} else {
+		setupLog.Info("Results store configured", "backend", storeCfg.Backend, "url", resultsStore.URL(""))
+	}
+	// Cross-experiment index of completed runs, kept next to the results.
+	var resultsCatalog *catalog.Catalog
+	if resultsStore != nil {
+		resultsCatalog = catalog.New(resultsStore)
+	}
+	// The analyzer Job reads results over S3, so it only gets an endpoint
+	// when that is the configured backend.
+	s3Endpoint := ""
+	if storeCfg.Backend == storage.BackendS3 {
+		s3Endpoint = storeCfg.S3.Endpoint
 	}
 
 	metricsURL := getEnvOrDefault("METRICS_URL", "http://victoria-metrics-server.observability.svc:8428")
 
-	// Initialize GitHub client for auto-publishing results to benchmark site (optional)
-	var gitClient *ghclient.Client
-	if ghToken := os.Getenv("GITHUB_TOKEN"); ghToken != "" {
-		ghRepo := getEnvOrDefault("GITHUB_REPO", "illMadeCoder/k8s-ai-cloud-testbed")
-		ghBranch := getEnvOrDefault("GITHUB_BRANCH", "main")
-		ghPath := getEnvOrDefault("GITHUB_RESULTS_PATH", "site/data")
-		parts := strings.SplitN(ghRepo, "/", 2)
-		if len(parts) == 2 {
-			gitClient = ghclient.NewClient(ghToken, parts[0], parts[1], ghBranch, ghPath)
-			setupLog.Info("GitHub client initialized for site auto-publish", "repo", ghRepo)
+	// Initialize the results publisher for auto-publishing results to the benchmark site (optional)
+	var gitClient *publish.Client
+	pubCfg := publishConfig()
+	if pubCfg.Token != "" || pubCfg.Git.Remote != "" {
+		gitClient, err = publish.New(pubCfg)
+		if err != nil {
+			setupLog.Error(err, "Failed to create results publisher — site auto-publish disabled", "provider", pubCfg.Provider)
 		} else {
-			setupLog.Info("Invalid GITHUB_REPO format (expected owner/repo)", "value", ghRepo)
+			setupLog.Info("Results publisher initialized for site auto-publish", "provider", pubCfg.Provider, "repo", gitClient.RepoPath())
 		}
 	} else {
-		setupLog.Info("GITHUB_TOKEN not set — site auto-publish disabled")
+		setupLog.Info("GIT_TOKEN/GITHUB_TOKEN (or GIT_REMOTE) not set — site auto-publish disabled")
 	}
 
 	analyzerImage := getEnvOrDefault("ANALYZER_IMAGE", "ghcr.io/illmadecoder/experiment-analyzer:latest")
@@ -223,24 +251,139 @@ func main() {
 		setupLog.Info("TAILSCALE_CLIENT_ID/SECRET not set — target cluster Tailscale egress will not authenticate")
 	}
 
-	if err := (&controller.ExperimentReconciler{
+	// Deployer backend: ArgoCD Applications (default), or direct server-side
+	// apply for lightweight setups without ArgoCD
+	var deployer deploy.Deployer
+	deployBackend := getEnvOrDefault("DEPLOYER", deploy.BackendArgoCD)
+	switch deployBackend {
+	case deploy.BackendArgoCD:
+		deployer = argocd.NewClient(mgr.GetClient(), argocd.WithTailscaleOAuth(tsClientID, tsClientSecret))
+	case deploy.BackendDirect:
+		direct, err := deploy.NewDirect(mgr.GetClient(), deploy.DirectConfig{
+			Namespace:             getEnvOrDefault("DEPLOY_NAMESPACE", "experiment-operator-system"),
+			HubConfig:             mgr.GetConfig(),
+			TailscaleClientID:     tsClientID,
+			TailscaleClientSecret: tsClientSecret,
+			WorkDir:               os.Getenv("DEPLOY_WORK_DIR"),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create direct deployer")
+			os.Exit(1)
+		}
+		deployer = direct
+	default:
+		setupLog.Error(fmt.Errorf("unknown deployer %q", deployBackend), "unable to set up deployer",
+			"supported", []string{deploy.BackendArgoCD, deploy.BackendDirect})
+		os.Exit(1)
+	}
+	setupLog.Info("Deployer configured", "backend", deployBackend)
+
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create Kubernetes clientset")
+		os.Exit(1)
+	}
+
+	reconciler := &controller.ExperimentReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
 		ClusterManager: crossplane.NewClusterManager(mgr.GetClient()),
-		ArgoCD:         argocd.NewClient(mgr.GetClient(), argocd.WithTailscaleOAuth(tsClientID, tsClientSecret)),
+		Deployer:       deployer,
 		Workflow:       workflow.NewManager(mgr.GetClient()),
-		S3Client:       s3Client,
+		Store:          resultsStore,
+		Catalog:        resultsCatalog,
 		GitClient:      gitClient,
+		KubeClient:     kubeClient,
 		MetricsURL:     metricsURL,
 		AnalyzerImage:  analyzerImage,
 		S3Endpoint:     s3Endpoint,
 		GitHubRepo:     getEnvOrDefault("GITHUB_REPO", "illMadeCoder/k8s-ai-cloud-testbed"),
-	}).SetupWithManager(mgr); err != nil {
+
+		AnalyzerProfile: os.Getenv("ANALYZER_PROFILE"),
+
+		MaxConcurrentAnalyzers: getEnvInt("ANALYZER_MAX_CONCURRENT", 1),
+		AnalyzerRetryBackoff:   getEnvDuration("ANALYZER_RETRY_BACKOFF", 5*time.Minute),
+		AnalyzerMaxRetries:     getEnvInt("ANALYZER_MAX_RETRIES", 3),
+	}
+
+	// GitHub webhook receiver (optional): results PR merges, closes and reviews
+	// wake the review gate immediately instead of at its next poll.
+	webhookSecret := os.Getenv("GITHUB_WEBHOOK_SECRET")
+	if gitClient != nil && pubCfg.Provider == publish.ProviderGitHub && webhookSecret != "" && githubWebhookAddr != "0" {
+		reconciler.ReviewEvents = make(chan event.GenericEvent, 64)
+		if err := mgr.Add(&publish.WebhookServer{
+			Addr:   githubWebhookAddr,
+			Secret: []byte(webhookSecret),
+			Notify: reconciler.NotifyPR,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up GitHub webhook receiver")
+			os.Exit(1)
+		}
+		setupLog.Info("GitHub webhook receiver enabled", "addr", githubWebhookAddr, "path", publish.WebhookPath)
+	}
+
+	if err := reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Experiment")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	// Results retention (optional): prunes the results store and terminal
+	// Experiment CRs on a schedule. RETENTION_DRY_RUN=true only reports.
+	if os.Getenv("RETENTION_ENABLED") == "true" {
+		if resultsStore == nil {
+			setupLog.Info("RETENTION_ENABLED set but no results store — retention disabled")
+		} else {
+			policy := retention.Policy{
+				KeepLast:              getEnvInt("RETENTION_KEEP_LAST", 0),
+				UnpublishedMaxAge:     getEnvDuration("RETENTION_UNPUBLISHED_MAX_AGE", 0),
+				ArchivePublishedAfter: getEnvDuration("RETENTION_ARCHIVE_PUBLISHED_AFTER", 0),
+				PruneCRsAfter:         getEnvDuration("RETENTION_PRUNE_CRS_AFTER", 0),
+				DryRun:                os.Getenv("RETENTION_DRY_RUN") == "true",
+				Interval:              getEnvDuration("RETENTION_INTERVAL", 6*time.Hour),
+			}
+			if err := mgr.Add(&retention.Runner{
+				Client:  mgr.GetClient(),
+				Store:   resultsStore,
+				Policy:  policy,
+				Catalog: resultsCatalog,
+			}); err != nil {
+				setupLog.Error(err, "unable to set up results retention")
+				os.Exit(1)
+			}
+			setupLog.Info("Results retention enabled", "policy", fmt.Sprintf("%+v", policy))
+		}
+	}
+
+	// Results index API (read-only) plus a one-off backfill on the leader for
+	// results stored before the index existed.
+	if resultsCatalog != nil {
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			n, err := resultsCatalog.Backfill(ctx)
+			if err != nil {
+				setupLog.Error(err, "Results index backfill failed — non-fatal")
+			} else if n > 0 {
+				setupLog.Info("Backfilled results index", "added", n)
+			}
+			return nil
+		})); err != nil {
+			setupLog.Error(err, "unable to set up results index backfill")
+			os.Exit(1)
+		}
+		if resultsAPIAddr != "0" {
+			var origins []string
+			for o := range strings.SplitSeq(resultsAPIOrigins, ",") {
+				if o = strings.TrimSpace(o); o != "" {
+					origins = append(origins, o)
+				}
+			}
+			if err := mgr.Add(&catalog.Server{Catalog: resultsCatalog, Addr: resultsAPIAddr, AllowedOrigins: origins}); err != nil {
+				setupLog.Error(err, "unable to set up results API")
+				os.Exit(1)
+			}
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -257,6 +400,94 @@ func main() {
 	}
 }
 
+// resultsStoreConfig builds the results store configuration from the
+// environment. S3 credentials come from S3_ACCESS_KEY_ID/S3_SECRET_ACCESS_KEY,
+// the standard AWS_* variables, or a container credentials endpoint; with none
+// set, SeaweedFS's unauthenticated mode is assumed.
+func resultsStoreConfig() storage.Config {
+	cfg := storage.Config{
+		Backend:     getEnvOrDefault("RESULTS_STORE", storage.BackendS3),
+		GCSEndpoint: os.Getenv("GCS_ENDPOINT"),
+		LocalDir:    getEnvOrDefault("RESULTS_DIR", "/var/lib/experiment-results"),
+		S3: storage.S3Config{
+			Endpoint:             getEnvOrDefault("S3_ENDPOINT", "seaweedfs-s3.seaweedfs.svc.cluster.local:8333"),
+			Region:               getEnvOrDefault("S3_REGION", getEnvOrDefault("AWS_REGION", "us-east-1")),
+			UseTLS:               os.Getenv("S3_USE_TLS") == "true",
+			PathStyle:            os.Getenv("S3_PATH_STYLE") != "false",
+			CAFile:               os.Getenv("S3_CA_FILE"),
+			AccessKeyID:          getEnvOrDefault("S3_ACCESS_KEY_ID", os.Getenv("AWS_ACCESS_KEY_ID")),
+			SecretAccessKey:      getEnvOrDefault("S3_SECRET_ACCESS_KEY", os.Getenv("AWS_SECRET_ACCESS_KEY")),
+			SessionToken:         os.Getenv("AWS_SESSION_TOKEN"),
+			CredentialsURI:       os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"),
+			CredentialsTokenFile: os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"),
+		},
+	}
+	if cfg.Backend == storage.BackendGCS {
+		cfg.Bucket = getEnvOrDefault("GCS_BUCKET", "experiment-results")
+	} else {
+		cfg.Bucket = getEnvOrDefault("S3_BUCKET", "experiment-results")
+	}
+	return cfg
+}
+
+// publishConfig reads the results publisher configuration from the
+// environment. The GIT_* variables fall back to the GITHUB_* ones so existing
+// GitHub deployments keep working unchanged.
+func publishConfig() publish.Config {
+	return publish.Config{
+		Provider: getEnvOrDefault("GIT_PROVIDER", publish.ProviderGitHub),
+		Repo:     getEnvOrDefault("GIT_REPO", getEnvOrDefault("GITHUB_REPO", "illMadeCoder/k8s-ai-cloud-testbed")),
+		BaseURL:  os.Getenv("GIT_BASE_URL"),
+		Token:    getEnvOrDefault("GIT_TOKEN", os.Getenv("GITHUB_TOKEN")),
+		Branch:   getEnvOrDefault("GIT_BRANCH", getEnvOrDefault("GITHUB_BRANCH", "main")),
+		Path:     getEnvOrDefault("GIT_RESULTS_PATH", getEnvOrDefault("GITHUB_RESULTS_PATH", "site/data")),
+		Git: publish.GitConfig{
+			Remote:         os.Getenv("GIT_REMOTE"),
+			SSHKeyFile:     os.Getenv("GIT_SSH_KEY_FILE"),
+			KnownHostsFile: os.Getenv("GIT_KNOWN_HOSTS_FILE"),
+			WorkDir:        os.Getenv("GIT_WORK_DIR"),
+			AuthorName:     os.Getenv("GIT_AUTHOR_NAME"),
+			AuthorEmail:    os.Getenv("GIT_AUTHOR_EMAIL"),
+		},
+	}
+}
+
+// getEnvInt returns an integer environment variable, or defaultValue if it is
+// unset or invalid.
+func getEnvInt(key string, defaultValue int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		setupLog.Info("Ignoring invalid integer", "env", key, "value", v)
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvDuration returns a duration environment variable, or defaultValue if
+// it is unset or invalid. In addition to Go durations ("36h"), a whole number
+// of days ("30d") is accepted.
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultValue
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Duration(n) * 24 * time.Hour
+		}
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		setupLog.Info("Ignoring invalid duration", "env", key, "value", v)
+		return defaultValue
+	}
+	return d
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
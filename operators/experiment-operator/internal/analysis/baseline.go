@@ -0,0 +1,584 @@
+// Package analysis produces deterministic, rule-based analysis of experiment
+// results: per-metric statistics and trends, winner tables across targets,
+// cost comparisons and a success-criteria narrative. Every stored summary
+// carries this baseline; the LLM analyzer, when it runs, merges its own
+// sections over it rather than starting from nothing.
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/illmadecoder/experiment-operator/internal/export"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+// Model is recorded in AnalysisResult.Model for baseline analysis.
+const Model = "rules"
+
+const (
+	// stableChange is the relative change between the first and last third of
+	// a range query below which its trend is reported as stable.
+	stableChange = 0.1
+	// hoursPerMonth is used for 24/7 cost projections.
+	hoursPerMonth = 730
+)
+
+// Baseline builds the rule-based analysis of s. It reads the metrics, the
+// evaluated success criteria and the cost estimate, so call it after
+// EvaluateSuccessCriteria and EstimateCost.
+func Baseline(s *metrics.ExperimentSummary) *metrics.AnalysisResult {
+	a := &metrics.AnalysisResult{
+		MetricInsights: make(map[string]string),
+		GeneratedAt:    s.CompletedAt,
+		Model:          Model,
+	}
+
+	stats := queryStats(s.Metrics)
+	for _, st := range stats {
+		a.MetricInsights[st.name] = st.insight()
+	}
+
+	var blocks []metrics.BodyBlock
+	if b, ok := criteriaBlocks(s.Hypothesis); ok {
+		blocks = append(blocks, b...)
+	}
+
+	targets := comparedTargets(stats)
+	if len(targets) > 1 {
+		rows, wins := winners(stats, targets)
+		if len(rows) > 0 {
+			blocks = append(blocks, metrics.BodyBlock{
+				Type:    "table",
+				Headers: append(append([]string{"Metric"}, targets...), "Best"),
+				Rows:    rows,
+				Caption: "Steady-state mean per target; lower is better except for throughput metrics.",
+			})
+		}
+		a.CapabilitiesMatrix = capabilities(s.Targets, stats, targets, wins)
+	}
+
+	if f, b := finops(s.CostEstimate); f != nil {
+		a.FinopsAnalysis = f
+		blocks = append(blocks, b)
+	}
+
+	a.Abstract = abstract(s, stats, a.CapabilitiesMatrix)
+	a.Summary = a.Abstract
+	if len(blocks) > 0 {
+		a.Body = &metrics.AnalysisBody{Blocks: blocks}
+	}
+	return a
+}
+
+// stat summarizes one query over its steady-state interval.
+type stat struct {
+	name  string
+	unit  string
+	err   string
+	n     int
+	min   float64
+	max   float64
+	mean  float64
+	trend float64 // relative change, first to last third; NaN if unknown
+	// byTarget is the mean per value of the target label, when present.
+	byTarget map[string]float64
+}
+
+// queryStats computes a stat for every query in result, ordered by name.
+func queryStats(result *metrics.MetricsResult) []stat {
+	if result == nil {
+		return nil
+	}
+	names := make([]string, 0, len(result.Queries))
+	for name := range result.Queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stats := make([]stat, 0, len(names))
+	for _, name := range names {
+		stats = append(stats, queryStat(name, result.Queries[name]))
+	}
+	return stats
+}
+
+func queryStat(name string, qr metrics.QueryResult) stat {
+	st := stat{name: name, unit: qr.Unit, err: qr.Error, trend: math.NaN()}
+	if qr.Error != "" {
+		return st
+	}
+
+	var points []metrics.DataPoint
+	for _, dp := range qr.Data {
+		if math.IsNaN(dp.Value) || math.IsInf(dp.Value, 0) {
+			continue
+		}
+		if ss := qr.SteadyState; ss != nil && (dp.Timestamp.Before(ss.Start) || dp.Timestamp.After(ss.End)) {
+			continue
+		}
+		points = append(points, dp)
+	}
+	if len(points) == 0 {
+		return st
+	}
+
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	st.min, st.max = points[0].Value, points[0].Value
+	var sum float64
+	for _, dp := range points {
+		sum += dp.Value
+		st.min = math.Min(st.min, dp.Value)
+		st.max = math.Max(st.max, dp.Value)
+		if t := dp.Labels[export.TargetLabel]; t != "" {
+			sums[t] += dp.Value
+			counts[t]++
+		}
+	}
+	st.n = len(points)
+	st.mean = sum / float64(st.n)
+	if len(sums) > 0 {
+		st.byTarget = make(map[string]float64, len(sums))
+		for t, v := range sums {
+			st.byTarget[t] = v / float64(counts[t])
+		}
+	}
+	if qr.Type == "range" {
+		st.trend = trend(points)
+	}
+	return st
+}
+
+// trend returns the relative change between the mean of the first and the
+// last third of points by time, or NaN if it cannot be computed.
+func trend(points []metrics.DataPoint) float64 {
+	start, end := points[0].Timestamp, points[0].Timestamp
+	for _, dp := range points {
+		if dp.Timestamp.Before(start) {
+			start = dp.Timestamp
+		}
+		if dp.Timestamp.After(end) {
+			end = dp.Timestamp
+		}
+	}
+	third := end.Sub(start) / 3
+	if third <= 0 {
+		return math.NaN()
+	}
+	var first, last float64
+	var nFirst, nLast int
+	for _, dp := range points {
+		switch {
+		case dp.Timestamp.Before(start.Add(third)):
+			first += dp.Value
+			nFirst++
+		case dp.Timestamp.After(end.Add(-third)):
+			last += dp.Value
+			nLast++
+		}
+	}
+	if nFirst == 0 || nLast == 0 {
+		return math.NaN()
+	}
+	first /= float64(nFirst)
+	last /= float64(nLast)
+	if first == 0 {
+		if last == 0 {
+			return 0
+		}
+		return math.NaN()
+	}
+	return (last - first) / math.Abs(first)
+}
+
+// insight renders st as a one-paragraph statement.
+func (st stat) insight() string {
+	if st.err != "" {
+		return "No data: " + st.err
+	}
+	if st.n == 0 {
+		return "No data was collected for this metric."
+	}
+	if st.n == 1 {
+		return "Measured " + formatValue(st.mean, st.unit) + "."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Mean %s (min %s, max %s) over %d samples",
+		formatValue(st.mean, st.unit), formatValue(st.min, st.unit), formatValue(st.max, st.unit), st.n)
+	switch {
+	case math.IsNaN(st.trend):
+	case math.Abs(st.trend) < stableChange:
+		b.WriteString("; stable through the run")
+	case st.trend > 0:
+		fmt.Fprintf(&b, "; rose %.0f%% from the first to the last third of the run", st.trend*100)
+	default:
+		fmt.Fprintf(&b, "; fell %.0f%% from the first to the last third of the run", -st.trend*100)
+	}
+	b.WriteString(".")
+
+	if len(st.byTarget) > 1 {
+		targets := sortedKeys(st.byTarget)
+		parts := make([]string, len(targets))
+		for i, t := range targets {
+			parts[i] = t + " " + formatValue(st.byTarget[t], st.unit)
+		}
+		fmt.Fprintf(&b, " By target: %s.", strings.Join(parts, ", "))
+	}
+	return b.String()
+}
+
+// comparedTargets returns the target label values seen in any query.
+func comparedTargets(stats []stat) []string {
+	seen := make(map[string]float64)
+	for _, st := range stats {
+		for t := range st.byTarget {
+			seen[t] = 0
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// higherIsBetter reports whether larger values of a metric are better. Only
+// throughput-like metrics are; latency, resource usage and error counts are
+// all lower-is-better.
+func higherIsBetter(name, unit string) bool {
+	if strings.HasSuffix(unit, "/s") {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, kw := range []string{"throughput", "qps", "rps", "iops", "ops_per", "requests_per"} {
+		if strings.Contains(name, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// winners builds one table row per metric measured on at least two targets,
+// and returns the best target per metric.
+func winners(stats []stat, targets []string) ([][]string, map[string]string) {
+	var rows [][]string
+	wins := make(map[string]string)
+	for _, st := range stats {
+		if len(st.byTarget) < 2 {
+			continue
+		}
+		row := []string{st.name}
+		best, tie := "", false
+		for _, t := range targets {
+			v, ok := st.byTarget[t]
+			if !ok {
+				row = append(row, "–")
+				continue
+			}
+			row = append(row, formatValue(v, st.unit))
+			switch {
+			case best == "":
+				best = t
+			case v == st.byTarget[best]:
+				tie = true
+			case (v > st.byTarget[best]) == higherIsBetter(st.name, st.unit):
+				best, tie = t, false
+			}
+		}
+		if tie {
+			row = append(row, "tie")
+		} else {
+			row = append(row, best)
+			wins[st.name] = best
+		}
+		rows = append(rows, row)
+	}
+	return rows, wins
+}
+
+// capabilities builds a matrix of infrastructure and measured values per target.
+func capabilities(summaries []metrics.TargetSummary, stats []stat, targets []string, wins map[string]string) *metrics.CapabilitiesMatrix {
+	byName := make(map[string]metrics.TargetSummary, len(summaries))
+	for _, t := range summaries {
+		byName[t.Name] = t
+	}
+	infra := metrics.CapabilitiesCategory{Name: "Infrastructure"}
+	for _, field := range []struct {
+		name  string
+		value func(metrics.TargetSummary) string
+	}{
+		{"Cluster type", func(t metrics.TargetSummary) string { return t.ClusterType }},
+		{"Machine type", func(t metrics.TargetSummary) string { return t.MachineType }},
+		{"Nodes", func(t metrics.TargetSummary) string {
+			if t.NodeCount == 0 {
+				return ""
+			}
+			return fmt.Sprint(t.NodeCount)
+		}},
+	} {
+		values := make(map[string]string)
+		for _, name := range targets {
+			if v := field.value(byName[name]); v != "" {
+				values[name] = v
+			}
+		}
+		if len(values) > 0 {
+			infra.Capabilities = append(infra.Capabilities, metrics.CapabilityEntry{Name: field.name, Values: values})
+		}
+	}
+
+	measured := metrics.CapabilitiesCategory{Name: "Measured"}
+	counts := make(map[string]int)
+	for _, st := range stats {
+		if len(st.byTarget) == 0 {
+			continue
+		}
+		values := make(map[string]string, len(st.byTarget))
+		for t, v := range st.byTarget {
+			values[t] = formatValue(v, st.unit)
+			if wins[st.name] == t {
+				values[t] += " (best)"
+			}
+		}
+		if w, ok := wins[st.name]; ok {
+			counts[w]++
+		}
+		measured.Capabilities = append(measured.Capabilities, metrics.CapabilityEntry{Name: st.name, Values: values})
+	}
+
+	m := &metrics.CapabilitiesMatrix{Technologies: targets}
+	for _, c := range []metrics.CapabilitiesCategory{infra, measured} {
+		if len(c.Capabilities) > 0 {
+			m.Categories = append(m.Categories, c)
+		}
+	}
+	if leader, n := leader(counts); leader != "" {
+		m.Summary = fmt.Sprintf("%s was best on %d of %d compared metrics.", leader, n, len(wins))
+	}
+	return m
+}
+
+// leader returns the target with the most wins, or "" on a tie or no wins.
+func leader(counts map[string]int) (string, int) {
+	best, n, tie := "", 0, false
+	for _, t := range sortedKeys(counts) {
+		switch c := counts[t]; {
+		case c > n:
+			best, n, tie = t, c, false
+		case c == n:
+			tie = true
+		}
+	}
+	if tie {
+		return "", 0
+	}
+	return best, n
+}
+
+// criteriaBlocks narrates the success-criteria evaluation as a callout and a
+// per-criterion table.
+func criteriaBlocks(h *metrics.HypothesisContext) ([]metrics.BodyBlock, bool) {
+	if h == nil || len(h.SuccessCriteria) == 0 {
+		return nil, false
+	}
+	rows := make([][]string, 0, len(h.SuccessCriteria))
+	for _, sc := range h.SuccessCriteria {
+		result, actual := "not evaluated", "–"
+		if sc.ActualValue != "" {
+			actual = sc.ActualValue
+		}
+		if sc.Passed != nil {
+			result = "fail"
+			if *sc.Passed {
+				result = "pass"
+			}
+		}
+		rows = append(rows, []string{sc.Metric, operatorSymbol(sc.Operator) + " " + sc.Value, actual, result})
+	}
+
+	variant := map[string]string{"validated": "success", "invalidated": "warning"}[h.MachineVerdict]
+	if variant == "" {
+		variant = "info"
+	}
+	return []metrics.BodyBlock{
+		{
+			Type:    "callout",
+			Variant: variant,
+			Title:   "Success criteria",
+			Content: criteriaNarrative(h),
+		},
+		{
+			Type:    "table",
+			Headers: []string{"Metric", "Threshold", "Actual", "Result"},
+			Rows:    rows,
+		},
+	}, true
+}
+
+// criteriaNarrative explains the machine verdict in a sentence or two.
+func criteriaNarrative(h *metrics.HypothesisContext) string {
+	var passed, failed, unknown []string
+	for _, sc := range h.SuccessCriteria {
+		switch {
+		case sc.Passed == nil:
+			unknown = append(unknown, sc.Metric)
+		case *sc.Passed:
+			passed = append(passed, sc.Metric)
+		default:
+			failed = append(failed, sc.Metric)
+		}
+	}
+	total := len(h.SuccessCriteria)
+
+	var b strings.Builder
+	switch h.MachineVerdict {
+	case "validated":
+		fmt.Fprintf(&b, "All %d success criteria passed, so the hypothesis is validated.", total)
+	case "invalidated":
+		fmt.Fprintf(&b, "%d of %d success criteria failed (%s), so the hypothesis is invalidated.",
+			len(failed), total, strings.Join(failed, ", "))
+	default:
+		fmt.Fprintf(&b, "The success criteria could not be evaluated conclusively (%d passed, %d failed).",
+			len(passed), len(failed))
+	}
+	if len(unknown) > 0 {
+		fmt.Fprintf(&b, " No usable data for %s.", strings.Join(unknown, ", "))
+	}
+	return b.String()
+}
+
+func operatorSymbol(op string) string {
+	switch op {
+	case "lt":
+		return "<"
+	case "lte":
+		return "≤"
+	case "gt":
+		return ">"
+	case "gte":
+		return "≥"
+	}
+	return op
+}
+
+// finops builds the cost section and a body topic comparing per-target cost.
+func finops(c *metrics.CostEstimate) (*metrics.FinopsAnalysis, metrics.BodyBlock) {
+	if c == nil || len(c.PerTarget) == 0 {
+		return nil, metrics.BodyBlock{}
+	}
+	f := &metrics.FinopsAnalysis{
+		Overview: fmt.Sprintf("The run cost an estimated $%.2f over %s across %d billed target(s).",
+			c.TotalUSD, formatDuration(c.DurationHrs), len(c.PerTarget)),
+	}
+
+	targets := sortedKeys(c.PerTarget)
+	sort.SliceStable(targets, func(i, j int) bool { return c.PerTarget[targets[i]] > c.PerTarget[targets[j]] })
+	items := make([]metrics.ComparisonItem, 0, len(targets))
+	for _, t := range targets {
+		cost := c.PerTarget[t]
+		driver := fmt.Sprintf("%s: $%.2f", t, cost)
+		if c.TotalUSD > 0 {
+			driver += fmt.Sprintf(" (%.0f%% of the total)", cost/c.TotalUSD*100)
+		}
+		f.CostDrivers = append(f.CostDrivers, driver)
+		items = append(items, metrics.ComparisonItem{Label: t, Value: fmt.Sprintf("$%.2f", cost)})
+	}
+
+	content := f.Overview
+	if c.DurationHrs > 0 {
+		monthly := c.TotalUSD / c.DurationHrs * hoursPerMonth
+		f.Projection = fmt.Sprintf("At the same hourly rate, running these targets 24/7 would cost about $%.0f per month (%d hours).",
+			monthly, hoursPerMonth)
+		content += " " + f.Projection
+	}
+	if c.Note != "" {
+		content += " " + c.Note
+	}
+
+	return f, metrics.BodyBlock{
+		Type:  "topic",
+		Title: "Cost",
+		Blocks: []metrics.BodyBlock{
+			{Type: "text", Content: content},
+			{Type: "comparison", Items: items},
+		},
+	}
+}
+
+// abstract summarizes the run: scope, metric coverage, verdict and leader.
+func abstract(s *metrics.ExperimentSummary, stats []stat, m *metrics.CapabilitiesMatrix) string {
+	name := s.Title
+	if name == "" {
+		name = s.Name
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s ran for %s on %d target(s)", name, formatDuration(s.DurationSec/3600), len(s.Targets))
+	if len(stats) > 0 {
+		collected := 0
+		for _, st := range stats {
+			if st.n > 0 {
+				collected++
+			}
+		}
+		fmt.Fprintf(&b, " and collected data for %d of %d metrics", collected, len(stats))
+	}
+	b.WriteString(".")
+	if h := s.Hypothesis; h != nil && len(h.SuccessCriteria) > 0 {
+		b.WriteString(" " + criteriaNarrative(h))
+	}
+	if m != nil && m.Summary != "" {
+		b.WriteString(" " + m.Summary)
+	}
+	return b.String()
+}
+
+// formatValue renders v with its unit, scaling bytes and seconds.
+func formatValue(v float64, unit string) string {
+	switch unit {
+	case "bytes":
+		const k = 1024
+		for _, suffix := range []string{"B", "KiB", "MiB", "GiB"} {
+			if math.Abs(v) < k {
+				return number(v) + " " + suffix
+			}
+			v /= k
+		}
+		return number(v) + " TiB"
+	case "seconds":
+		if v != 0 && math.Abs(v) < 1 {
+			return number(v*1000) + " ms"
+		}
+		return number(v) + " s"
+	case "%":
+		return number(v) + "%"
+	case "":
+		return number(v)
+	}
+	return number(v) + " " + unit
+}
+
+// number formats v to three significant digits without exponent notation.
+func number(v float64) string {
+	if math.Abs(v) >= 1000 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.3g", v)
+}
+
+func formatDuration(hours float64) string {
+	d := time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+	if d < time.Minute {
+		return "under a minute"
+	}
+	return strings.TrimSuffix(d.String(), "0s")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,155 @@
+package analysis
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+var t0 = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// rangeData returns one point per minute for each target, from values.
+func rangeData(values map[string][]float64) []metrics.DataPoint {
+	var data []metrics.DataPoint
+	for target, vs := range values {
+		for i, v := range vs {
+			data = append(data, metrics.DataPoint{
+				Labels:    map[string]string{"target": target},
+				Timestamp: t0.Add(time.Duration(i) * time.Minute),
+				Value:     v,
+			})
+		}
+	}
+	return data
+}
+
+func comparisonSummary() *metrics.ExperimentSummary {
+	pass, fail := true, false
+	return &metrics.ExperimentSummary{
+		SchemaVersion: metrics.SummarySchemaVersion,
+		Name:          "tsdb-b2c4d",
+		Namespace:     "experiments",
+		Phase:         "Complete",
+		CompletedAt:   t0.Add(time.Hour),
+		DurationSec:   3600,
+		Targets: []metrics.TargetSummary{
+			{Name: "loki", ClusterType: "gke", MachineType: "e2-standard-4", NodeCount: 3},
+			{Name: "elastic", ClusterType: "gke", MachineType: "e2-standard-8", NodeCount: 3},
+		},
+		Hypothesis: &metrics.HypothesisContext{
+			MachineVerdict: "invalidated",
+			SuccessCriteria: []metrics.SuccessCriterionSummary{
+				{Metric: "memory", Operator: "lt", Value: "1e9", Passed: &pass, ActualValue: "6e8"},
+				{Metric: "ingest_rate", Operator: "gt", Value: "1000", Passed: &fail, ActualValue: "750"},
+				{Metric: "p99", Operator: "lt", Value: "0.5"},
+			},
+		},
+		Metrics: &metrics.MetricsResult{Queries: map[string]metrics.QueryResult{
+			"memory": {Type: "range", Unit: "bytes", Data: rangeData(map[string][]float64{
+				"loki":    {4e8, 4e8, 4e8, 4e8, 4e8, 4e8},
+				"elastic": {6e8, 7e8, 8e8, 9e8, 1e9, 1.1e9},
+			})},
+			"ingest_rate": {Type: "range", Unit: "ops/s", Data: rangeData(map[string][]float64{
+				"loki":    {500, 500, 500},
+				"elastic": {1000, 1000, 1000},
+			})},
+			"p99": {Type: "instant", Error: "no data"},
+		}},
+		CostEstimate: &metrics.CostEstimate{
+			TotalUSD:    1.2,
+			DurationHrs: 1,
+			PerTarget:   map[string]float64{"loki": 0.4, "elastic": 0.8},
+		},
+	}
+}
+
+func TestBaselineComparison(t *testing.T) {
+	s := comparisonSummary()
+	a := Baseline(s)
+
+	if a.Model != Model || !a.GeneratedAt.Equal(s.CompletedAt) {
+		t.Errorf("model/generatedAt = %q/%v", a.Model, a.GeneratedAt)
+	}
+	if got := a.MetricInsights["memory"]; !strings.Contains(got, "rose") || !strings.Contains(got, "loki 381 MiB") {
+		t.Errorf("memory insight = %q, want a rising trend and per-target means", got)
+	}
+	if got := a.MetricInsights["p99"]; got != "No data: no data" {
+		t.Errorf("p99 insight = %q", got)
+	}
+
+	var winners *metrics.BodyBlock
+	for i, b := range a.Body.Blocks {
+		if b.Type == "table" && b.Headers[0] == "Metric" && b.Headers[len(b.Headers)-1] == "Best" {
+			winners = &a.Body.Blocks[i]
+		}
+	}
+	if winners == nil {
+		t.Fatalf("no winner table in %+v", a.Body.Blocks)
+	}
+	best := map[string]string{}
+	for _, row := range winners.Rows {
+		best[row[0]] = row[len(row)-1]
+	}
+	if best["memory"] != "loki" || best["ingest_rate"] != "elastic" {
+		t.Errorf("winners = %v, want loki on memory and elastic on throughput", best)
+	}
+
+	if m := a.CapabilitiesMatrix; m == nil || len(m.Technologies) != 2 || len(m.Categories) != 2 {
+		t.Errorf("capabilitiesMatrix = %+v", m)
+	}
+	if f := a.FinopsAnalysis; f == nil || !strings.HasPrefix(f.CostDrivers[0], "elastic: $0.80") || !strings.Contains(f.Projection, "$876") {
+		t.Errorf("finopsAnalysis = %+v", f)
+	}
+	if !strings.Contains(a.Abstract, "1 of 3 success criteria failed (ingest_rate)") ||
+		!strings.Contains(a.Abstract, "No usable data for p99") {
+		t.Errorf("abstract = %q", a.Abstract)
+	}
+	if a.HypothesisVerdict != "" {
+		t.Errorf("hypothesisVerdict = %q, want it left to the analyzer", a.HypothesisVerdict)
+	}
+
+	s.Analysis = a
+	if err := metrics.ValidateSummary(s); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := metrics.DecodeSummary(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBaselineSingleTarget(t *testing.T) {
+	s := &metrics.ExperimentSummary{
+		Name:        "fsync",
+		DurationSec: 600,
+		Targets:     []metrics.TargetSummary{{Name: "app", ClusterType: "kind"}},
+		Metrics: &metrics.MetricsResult{Queries: map[string]metrics.QueryResult{
+			"latency": {Type: "instant", Unit: "seconds", Data: []metrics.DataPoint{{Value: 0.0042}}},
+			"cpu": {Type: "range", Unit: "cores", Data: rangeData(map[string][]float64{
+				"": {9, 0.5, 0.5, 0.5, 0.5, 9},
+			}), SteadyState: &metrics.SteadyState{Start: t0.Add(time.Minute), End: t0.Add(4 * time.Minute), Points: 4, Mean: 0.5}},
+			"nan": {Type: "instant", Data: []metrics.DataPoint{{Value: math.NaN()}}},
+		}},
+	}
+	a := Baseline(s)
+
+	if got := a.MetricInsights["latency"]; got != "Measured 4.2 ms." {
+		t.Errorf("latency insight = %q", got)
+	}
+	if got := a.MetricInsights["cpu"]; !strings.HasPrefix(got, "Mean 0.5 cores (min 0.5 cores, max 0.5 cores)") || !strings.Contains(got, "stable") {
+		t.Errorf("cpu insight = %q, want steady-state statistics", got)
+	}
+	if a.CapabilitiesMatrix != nil || a.FinopsAnalysis != nil || a.Body != nil {
+		t.Errorf("single target without criteria or cost got comparison sections: %+v", a)
+	}
+	if want := "fsync ran for 10m on 1 target(s) and collected data for 2 of 3 metrics."; a.Abstract != want {
+		t.Errorf("abstract = %q, want %q", a.Abstract, want)
+	}
+}
@@ -3,7 +3,6 @@ package argocd
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -15,6 +14,7 @@ import (
 
 	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
 	"github.com/illmadecoder/experiment-operator/internal/components"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
 )
 
 var (
@@ -69,7 +69,7 @@ func (m *ApplicationManager) CreateApplication(ctx context.Context, experimentNa
 
 	// Auto-inject observability components when enabled
 	if target.Observability != nil && target.Observability.Enabled {
-		obsRefs := ObservabilityComponentRefs(target.Observability, experimentName, m.TailscaleClientID, m.TailscaleClientSecret)
+		obsRefs := deploy.ObservabilityComponentRefs(target.Observability, experimentName, m.TailscaleClientID, m.TailscaleClientSecret)
 		obsResolved, obsErr := m.Resolver.ResolveComponents(ctx, obsRefs)
 		if obsErr != nil {
 			log.Error(obsErr, "Failed to resolve observability components — continuing without them")
@@ -78,77 +78,12 @@ func (m *ApplicationManager) CreateApplication(ctx context.Context, experimentNa
 		}
 	}
 
-	// Build sources from resolved components
-	sources := []interface{}{}
-	for _, resolved := range resolvedComponents {
-		// Check if any source in this component uses $values references.
-		// If so, we need to add ref: "values" to the git source (non-chart source).
-		needsValuesRef := false
-		for _, source := range resolved.Sources {
-			if source.Helm != nil {
-				for _, vf := range source.Helm.ValuesFiles {
-					if strings.HasPrefix(vf, "$values") {
-						needsValuesRef = true
-						break
-					}
-				}
-			}
-		}
-
-		for _, source := range resolved.Sources {
-			argoSource := map[string]interface{}{
-				"repoURL":        source.RepoURL,
-				"targetRevision": source.TargetRevision,
-			}
-
-			// Use 'chart' for Helm repositories, 'path' for Git repositories
-			if source.Chart != "" {
-				argoSource["chart"] = source.Chart
-			} else if needsValuesRef && source.Helm == nil {
-				// This git source is used as a ref for $values — set ref instead of path
-				// to avoid ArgoCD trying to deploy component.yaml as a manifest.
-				argoSource["ref"] = "values"
-			} else {
-				argoSource["path"] = source.Path
-			}
-
-			// Add Helm configuration if present
-			if source.Helm != nil {
-				helmConfig := map[string]interface{}{}
-
-				if source.Helm.ReleaseName != "" {
-					helmConfig["releaseName"] = source.Helm.ReleaseName
-				}
-
-				if len(source.Helm.ValuesFiles) > 0 {
-					// Convert []string to []interface{} for unstructured deep copy compatibility
-					vf := make([]interface{}, len(source.Helm.ValuesFiles))
-					for i, f := range source.Helm.ValuesFiles {
-						vf[i] = f
-					}
-					helmConfig["valueFiles"] = vf
-				}
-
-				if len(source.Helm.Parameters) > 0 {
-					helmParams := []interface{}{}
-					for key, value := range source.Helm.Parameters {
-						helmParams = append(helmParams, map[string]interface{}{
-							"name":  key,
-							"value": value,
-						})
-					}
-					helmConfig["parameters"] = helmParams
-				}
-
-				if len(helmConfig) > 0 {
-					argoSource["helm"] = helmConfig
-				}
-			}
-
-			sources = append(sources, argoSource)
-		}
+	if target.ApplicationMode == experimentsv1alpha1.ApplicationModePerComponent {
+		return m.createComponentApplications(ctx, experimentName, target, clusterServer, resolvedComponents)
 	}
 
+	sources := argoSources(resolvedComponents)
+
 	// If no sources resolved, skip creating the application
 	if len(sources) == 0 {
 		log.Info("No components resolved for target, skipping application creation", "target", target.Name)
@@ -215,6 +150,10 @@ func (m *ApplicationManager) DeleteApplication(ctx context.Context, experimentNa
 
 	appName := fmt.Sprintf("%s-%s", experimentName, targetName)
 
+	if err := m.deleteComponentApplications(ctx, experimentName, targetName); err != nil {
+		return err
+	}
+
 	app := &unstructured.Unstructured{}
 	app.SetGroupVersionKind(applicationGVK)
 	app.SetName(appName)
@@ -232,167 +171,29 @@ func (m *ApplicationManager) DeleteApplication(ctx context.Context, experimentNa
 	return nil
 }
 
-// IsApplicationHealthy checks if an Application is healthy
+// IsApplicationHealthy checks if an Application is healthy. For a target
+// deployed with one Application per component, all of them must be healthy.
 func (m *ApplicationManager) IsApplicationHealthy(ctx context.Context, experimentName string, targetName string) (bool, error) {
-	appName := fmt.Sprintf("%s-%s", experimentName, targetName)
-
-	app := &unstructured.Unstructured{}
-	app.SetGroupVersionKind(applicationGVK)
-
-	if err := m.Get(ctx, client.ObjectKey{Name: appName, Namespace: "argocd"}, app); err != nil {
-		return false, fmt.Errorf("failed to get application: %w", err)
+	if found, ready, err := m.componentAppsReady(ctx, experimentName, targetName); err != nil || found {
+		return ready, err
 	}
 
-	// Check health status
-	healthStatus, found, err := unstructured.NestedString(app.Object, "status", "health", "status")
-	if err != nil || !found {
-		return false, nil // Not ready yet
-	}
-
-	// Check sync status — multi-source apps report "Unknown" which is acceptable
-	syncStatus, found, err := unstructured.NestedString(app.Object, "status", "sync", "status")
-	if err != nil || !found {
-		return false, nil
-	}
-
-	// Check for ComparisonError conditions — ArgoCD reports "Healthy" even when
-	// manifest generation fails (since no resources exist to be unhealthy).
-	conditions, condFound, _ := unstructured.NestedSlice(app.Object, "status", "conditions")
-	if condFound {
-		for _, c := range conditions {
-			cond, ok := c.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			condType, _, _ := unstructured.NestedString(cond, "type")
-			if condType == "ComparisonError" {
-				return false, nil
-			}
-		}
-	}
-
-	// Application is ready when core components are deployed. Complex stacks
-	// (e.g. Mimir distributed mode) may stay "Degraded" due to resource pressure,
-	// but monitoring services are typically available. Accept Healthy, Degraded,
-	// or Progressing — only reject Missing (nothing deployed) or Suspended.
-	acceptableHealth := healthStatus == "Healthy" || healthStatus == "Degraded" || healthStatus == "Progressing"
-	// Multi-source ArgoCD apps report "Unknown" sync status, which is acceptable.
-	acceptableSync := syncStatus == "Synced" || syncStatus == "Unknown" || syncStatus == "OutOfSync"
-	return acceptableHealth && acceptableSync, nil
-}
-
-// GetApplicationComponents returns the list of deployed components
-func (m *ApplicationManager) GetApplicationComponents(ctx context.Context, experimentName string, targetName string) ([]string, error) {
 	appName := fmt.Sprintf("%s-%s", experimentName, targetName)
 
 	app := &unstructured.Unstructured{}
 	app.SetGroupVersionKind(applicationGVK)
 
 	if err := m.Get(ctx, client.ObjectKey{Name: appName, Namespace: "argocd"}, app); err != nil {
-		return nil, fmt.Errorf("failed to get application: %w", err)
-	}
-
-	// Get resources from status
-	resources, found, err := unstructured.NestedSlice(app.Object, "status", "resources")
-	if err != nil || !found {
-		return []string{}, nil
-	}
-
-	components := []string{}
-	for _, res := range resources {
-		resource, ok := res.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		kind, _, _ := unstructured.NestedString(resource, "kind")
-		name, _, _ := unstructured.NestedString(resource, "name")
-		if kind != "" && name != "" {
-			components = append(components, fmt.Sprintf("%s/%s", kind, name))
-		}
-	}
-
-	return components, nil
-}
-
-// ObservabilityComponentRefs returns ComponentRefs for the observability stack
-// based on the target's ObservabilitySpec.
-func ObservabilityComponentRefs(obs *experimentsv1alpha1.ObservabilitySpec, experimentName, tsClientID, tsClientSecret string) []experimentsv1alpha1.ComponentRef {
-	refs := []experimentsv1alpha1.ComponentRef{
-		// VictoriaMetrics egress service (always needed)
-		{Config: "metrics-egress"},
-		// Metrics agent with experiment name as external label
-		{
-			App: "metrics-agent",
-			Params: map[string]string{
-				"alloy.extraEnv[0].value": experimentName,
-			},
-		},
-	}
-
-	// Tailscale operator for mesh transport
-	if obs.Transport == "tailscale" {
-		ref := experimentsv1alpha1.ComponentRef{
-			App: "tailscale-operator",
-		}
-		if tsClientID != "" && tsClientSecret != "" {
-			ref.Params = map[string]string{
-				"oauth.clientId":     tsClientID,
-				"oauth.clientSecret": tsClientSecret,
-			}
-		}
-		refs = append(refs, ref)
-	}
-
-	return refs
-}
-
-// LayerName constants for layered deployment ordering.
-const (
-	LayerInfra    = "infra"
-	LayerObs      = "obs"
-	LayerWorkload = "workload"
-)
-
-// ClassifiedComponents holds component refs split into deployment layers.
-type ClassifiedComponents struct {
-	Infra    []experimentsv1alpha1.ComponentRef
-	Obs      []experimentsv1alpha1.ComponentRef
-	Workload []experimentsv1alpha1.ComponentRef
-}
-
-// ClassifyComponents splits user and observability components into deployment layers.
-// Infra: tailscale-operator. Obs: metrics-egress, metrics-agent. Workload: user components.
-// When observability is disabled (no obsRefs), everything goes into Workload.
-func ClassifyComponents(userComponents []experimentsv1alpha1.ComponentRef, obsComponents []experimentsv1alpha1.ComponentRef) ClassifiedComponents {
-	result := ClassifiedComponents{
-		Workload: userComponents,
-	}
-
-	for _, ref := range obsComponents {
-		switch {
-		case ref.App == "tailscale-operator":
-			result.Infra = append(result.Infra, ref)
-		case ref.App == "metrics-agent" || ref.Config == "metrics-egress":
-			result.Obs = append(result.Obs, ref)
-		default:
-			// Unknown obs component — put in obs layer
-			result.Obs = append(result.Obs, ref)
-		}
+		return false, fmt.Errorf("failed to get application: %w", err)
 	}
 
-	return result
-}
-
-// HasLayers returns true if infra or obs layers have components (i.e., layering is needed).
-func (c ClassifiedComponents) HasLayers() bool {
-	return len(c.Infra) > 0 || len(c.Obs) > 0
+	return applicationReady(app), nil
 }
 
 // layerAppName returns the ArgoCD Application name for a given layer.
 // Workload layer uses {exp}-{target} for backward compatibility.
 func layerAppName(experimentName, targetName, layer string) string {
-	if layer == LayerWorkload {
+	if layer == deploy.LayerWorkload {
 		return fmt.Sprintf("%s-%s", experimentName, targetName)
 	}
 	return fmt.Sprintf("%s-%s-%s", experimentName, targetName, layer)
@@ -410,66 +211,12 @@ func (m *ApplicationManager) CreateLayeredApplication(ctx context.Context, exper
 		return fmt.Errorf("failed to resolve %s layer components: %w", layer, err)
 	}
 
-	// Build sources from resolved components
-	sources := []interface{}{}
-	for _, resolved := range resolvedComponents {
-		needsValuesRef := false
-		for _, source := range resolved.Sources {
-			if source.Helm != nil {
-				for _, vf := range source.Helm.ValuesFiles {
-					if strings.HasPrefix(vf, "$values") {
-						needsValuesRef = true
-						break
-					}
-				}
-			}
-		}
-
-		for _, source := range resolved.Sources {
-			argoSource := map[string]interface{}{
-				"repoURL":        source.RepoURL,
-				"targetRevision": source.TargetRevision,
-			}
-
-			if source.Chart != "" {
-				argoSource["chart"] = source.Chart
-			} else if needsValuesRef && source.Helm == nil {
-				argoSource["ref"] = "values"
-			} else {
-				argoSource["path"] = source.Path
-			}
-
-			if source.Helm != nil {
-				helmConfig := map[string]interface{}{}
-				if source.Helm.ReleaseName != "" {
-					helmConfig["releaseName"] = source.Helm.ReleaseName
-				}
-				if len(source.Helm.ValuesFiles) > 0 {
-					vf := make([]interface{}, len(source.Helm.ValuesFiles))
-					for i, f := range source.Helm.ValuesFiles {
-						vf[i] = f
-					}
-					helmConfig["valueFiles"] = vf
-				}
-				if len(source.Helm.Parameters) > 0 {
-					helmParams := []interface{}{}
-					for key, value := range source.Helm.Parameters {
-						helmParams = append(helmParams, map[string]interface{}{
-							"name":  key,
-							"value": value,
-						})
-					}
-					helmConfig["parameters"] = helmParams
-				}
-				if len(helmConfig) > 0 {
-					argoSource["helm"] = helmConfig
-				}
-			}
-
-			sources = append(sources, argoSource)
-		}
+	if layer == deploy.LayerWorkload && target.ApplicationMode == experimentsv1alpha1.ApplicationModePerComponent {
+		return m.createComponentApplications(ctx, experimentName, target, clusterServer, resolvedComponents)
 	}
 
+	sources := argoSources(resolvedComponents)
+
 	if len(sources) == 0 {
 		log.Info("No components resolved for layer, skipping", "target", target.Name, "layer", layer)
 		return nil
@@ -534,7 +281,15 @@ func (m *ApplicationManager) CreateLayeredApplication(ctx context.Context, exper
 }
 
 // IsLayerHealthy checks if an ArgoCD Application for a specific layer is healthy.
+// A workload layer deployed per component is healthy when all of its
+// component Applications are.
 func (m *ApplicationManager) IsLayerHealthy(ctx context.Context, experimentName, targetName, layer string) (bool, error) {
+	if layer == deploy.LayerWorkload {
+		if found, ready, err := m.componentAppsReady(ctx, experimentName, targetName); err != nil || found {
+			return ready, err
+		}
+	}
+
 	appName := layerAppName(experimentName, targetName, layer)
 
 	app := &unstructured.Unstructured{}
@@ -547,55 +302,34 @@ func (m *ApplicationManager) IsLayerHealthy(ctx context.Context, experimentName,
 		return false, fmt.Errorf("failed to get %s layer application: %w", layer, err)
 	}
 
-	healthStatus, found, err := unstructured.NestedString(app.Object, "status", "health", "status")
-	if err != nil || !found {
-		return false, nil
-	}
-
-	syncStatus, found, err := unstructured.NestedString(app.Object, "status", "sync", "status")
-	if err != nil || !found {
-		return false, nil
-	}
-
-	// Check for ComparisonError conditions
-	conditions, condFound, _ := unstructured.NestedSlice(app.Object, "status", "conditions")
-	if condFound {
-		for _, c := range conditions {
-			cond, ok := c.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			condType, _, _ := unstructured.NestedString(cond, "type")
-			if condType == "ComparisonError" {
-				return false, nil
-			}
-		}
-	}
-
-	acceptableHealth := healthStatus == "Healthy" || healthStatus == "Degraded" || healthStatus == "Progressing"
-	acceptableSync := syncStatus == "Synced" || syncStatus == "Unknown" || syncStatus == "OutOfSync"
-	return acceptableHealth && acceptableSync, nil
+	return applicationReady(app), nil
 }
 
-// DeleteLayeredApplications deletes all layer applications for a target (infra, obs, workload).
+// DeleteLayeredApplications deletes all layer applications for a target.
 func (m *ApplicationManager) DeleteLayeredApplications(ctx context.Context, experimentName, targetName string) error {
 	log := log.FromContext(ctx)
 
-	for _, layer := range []string{LayerInfra, LayerObs, LayerWorkload} {
-		appName := layerAppName(experimentName, targetName, layer)
-
-		app := &unstructured.Unstructured{}
-		app.SetGroupVersionKind(applicationGVK)
-		app.SetName(appName)
-		app.SetNamespace("argocd")
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(applicationGVK.GroupVersion().WithKind("ApplicationList"))
+	if err := m.List(ctx, list, client.InNamespace("argocd"),
+		client.MatchingLabels{
+			"experiments.illm.io/experiment": experimentName,
+			"experiments.illm.io/target":     targetName,
+		},
+		client.HasLabels{"experiments.illm.io/layer"}); err != nil {
+		return fmt.Errorf("failed to list layered applications: %w", err)
+	}
 
+	for i := range list.Items {
+		app := &list.Items[i]
+		layer := app.GetLabels()["experiments.illm.io/layer"]
 		if err := m.Delete(ctx, app); err != nil {
 			if errors.IsNotFound(err) {
-				continue // Already deleted or never created
+				continue // Already deleted
 			}
-			log.Error(err, "Failed to delete layered application", "name", appName, "layer", layer)
+			log.Error(err, "Failed to delete layered application", "name", app.GetName(), "layer", layer)
 		} else {
-			log.Info("Deleted layered ArgoCD Application", "name", appName, "layer", layer)
+			log.Info("Deleted layered ArgoCD Application", "name", app.GetName(), "layer", layer)
 		}
 	}
 
@@ -0,0 +1,47 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDeleteLayeredApplications(t *testing.T) {
+	ctx := context.Background()
+	var objs []client.Object
+	for name, labels := range map[string]map[string]string{
+		"tsdb-app-operators": {"experiments.illm.io/experiment": "tsdb", "experiments.illm.io/target": "app", "experiments.illm.io/layer": "operators"},
+		"tsdb-app":           {"experiments.illm.io/experiment": "tsdb", "experiments.illm.io/target": "app", "experiments.illm.io/layer": "workload"},
+		"tsdb-loadgen":       {"experiments.illm.io/experiment": "tsdb", "experiments.illm.io/target": "loadgen", "experiments.illm.io/layer": "workload"},
+	} {
+		app := &unstructured.Unstructured{}
+		app.SetGroupVersionKind(applicationGVK)
+		app.SetName(name)
+		app.SetNamespace("argocd")
+		app.SetLabels(labels)
+		objs = append(objs, app)
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	m := NewApplicationManager(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build())
+
+	if err := m.DeleteLayeredApplications(ctx, "tsdb", "app"); err != nil {
+		t.Fatal(err)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(applicationGVK.GroupVersion().WithKind("ApplicationList"))
+	if err := m.List(ctx, list, client.InNamespace("argocd")); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].GetName() != "tsdb-loadgen" {
+		t.Errorf("remaining applications = %d, want only tsdb-loadgen", len(list.Items))
+	}
+}
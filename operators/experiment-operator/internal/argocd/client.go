@@ -2,11 +2,12 @@ package argocd
 
 import (
 	"context"
-	"fmt"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
 )
 
 // Client provides ArgoCD integration
@@ -38,72 +39,72 @@ func WithTailscaleOAuth(clientID, clientSecret string) ClientOption {
 	}
 }
 
-// RegisterClusterAndCreateApps registers a cluster and creates apps for all components.
-// For targets with observability enabled, deploys infra+obs layers first (workload deferred to reconcileReady).
-// For targets without observability, deploys a single workload application.
-func (c *Client) RegisterClusterAndCreateApps(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, clusterName string, kubeconfig []byte, server string) error {
-	// Register cluster with ArgoCD
-	if err := RegisterCluster(ctx, c.Client, clusterName, kubeconfig, server); err != nil {
-		return err
-	}
+// Client implements deploy.Deployer with ArgoCD Applications.
+var _ deploy.Deployer = (*Client)(nil)
 
-	// Create ArgoCD Application for this target
-	if err := c.AppManager.CreateApplication(ctx, experimentName, target, server); err != nil {
-		return err
-	}
+// Layers plans the deployment layers of a target's components.
+func (c *Client) Layers(ctx context.Context, experimentName string, target experimentsv1alpha1.Target) ([]deploy.Layer, error) {
+	obsRefs := deploy.ObservabilityComponentRefs(target.Observability, experimentName,
+		c.AppManager.TailscaleClientID, c.AppManager.TailscaleClientSecret)
+	return deploy.PlanLayers(ctx, c.AppManager.Resolver, target, obsRefs)
+}
 
-	return nil
+// RegisterCluster registers a target cluster with ArgoCD.
+func (c *Client) RegisterCluster(ctx context.Context, clusterName string, kubeconfig []byte, server string) error {
+	return RegisterCluster(ctx, c.Client, clusterName, kubeconfig, server)
 }
 
-// RegisterClusterAndCreateLayeredApps registers a cluster and creates infra+obs layer apps.
-// Workload layer is deferred to reconcileReady after infra+obs are healthy.
-func (c *Client) RegisterClusterAndCreateLayeredApps(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, clusterName string, kubeconfig []byte, server string, classified ClassifiedComponents) ([]string, error) {
-	// Register cluster with ArgoCD
-	if err := RegisterCluster(ctx, c.Client, clusterName, kubeconfig, server); err != nil {
-		return nil, err
-	}
+// Deploy creates the target's Application(s) with all of its components.
+func (c *Client) Deploy(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, server string) error {
+	return c.AppManager.CreateApplication(ctx, experimentName, target, server)
+}
 
-	var deployedLayers []string
+// DeployLayer creates the Application(s) of one deployment layer.
+func (c *Client) DeployLayer(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, server string, layer deploy.Layer) error {
+	return c.AppManager.CreateLayeredApplication(ctx, experimentName, target, server, layer.Name, layer.Components)
+}
 
-	// Deploy infra layer (e.g., tailscale-operator)
-	if len(classified.Infra) > 0 {
-		if err := c.AppManager.CreateLayeredApplication(ctx, experimentName, target, server, LayerInfra, classified.Infra); err != nil {
-			return deployedLayers, fmt.Errorf("create infra layer: %w", err)
-		}
-		deployedLayers = append(deployedLayers, LayerInfra)
-	}
+// Healthy reports whether the target's Application(s) are ready.
+func (c *Client) Healthy(ctx context.Context, experimentName, targetName string) (bool, error) {
+	return c.AppManager.IsApplicationHealthy(ctx, experimentName, targetName)
+}
 
-	// Deploy obs layer (e.g., metrics-egress, metrics-agent) simultaneously
-	// Alloy's remote-write retries until Tailscale tunnel is up
-	if len(classified.Obs) > 0 {
-		if err := c.AppManager.CreateLayeredApplication(ctx, experimentName, target, server, LayerObs, classified.Obs); err != nil {
-			return deployedLayers, fmt.Errorf("create obs layer: %w", err)
-		}
-		deployedLayers = append(deployedLayers, LayerObs)
-	}
+// LayerHealthy reports whether a layer's Application(s) are ready.
+func (c *Client) LayerHealthy(ctx context.Context, experimentName, targetName, layer string) (bool, error) {
+	return c.AppManager.IsLayerHealthy(ctx, experimentName, targetName, layer)
+}
 
-	return deployedLayers, nil
+// ComponentStatuses returns the ArgoCD status of each component of target.
+func (c *Client) ComponentStatuses(ctx context.Context, experimentName string, target experimentsv1alpha1.Target) ([]experimentsv1alpha1.TargetComponentStatus, error) {
+	return c.AppManager.ComponentStatuses(ctx, experimentName, target)
 }
 
-// DeleteClusterAndApps unregisters a cluster and deletes all its applications.
+// TargetHealth reads the ArgoCD state of every Application of a target.
+func (c *Client) TargetHealth(ctx context.Context, experimentName, targetName string) (*deploy.TargetHealth, error) {
+	return c.AppManager.TargetHealth(ctx, experimentName, targetName)
+}
+
+// Undeploy deletes the targets' Applications and unregisters their clusters.
 // Handles both layered and non-layered applications.
-func (c *Client) DeleteClusterAndApps(ctx context.Context, experimentName string, targets []experimentsv1alpha1.Target, clusterNames []string) error {
+func (c *Client) Undeploy(ctx context.Context, experimentName string, targets []experimentsv1alpha1.Target, clusterNames []string) error {
+	log := log.FromContext(ctx)
+
 	// Delete all applications (layered + legacy single-app)
 	for _, target := range targets {
-		// Delete layered apps (infra, obs, workload)
-		c.AppManager.DeleteLayeredApplications(ctx, experimentName, target.Name)
+		// Delete layered apps
+		if err := c.AppManager.DeleteLayeredApplications(ctx, experimentName, target.Name); err != nil {
+			log.Error(err, "Failed to delete layered applications", "target", target.Name)
+		}
 		// Also try deleting the legacy single app name in case it exists
 		if err := c.AppManager.DeleteApplication(ctx, experimentName, target.Name); err != nil {
-			// Log but continue with other deletions
-			continue
+			log.Error(err, "Failed to delete application", "target", target.Name)
 		}
 	}
 
 	// Unregister clusters
 	for _, clusterName := range clusterNames {
 		if err := UnregisterCluster(ctx, c.Client, clusterName); err != nil {
-			// Log but continue
-			continue
+			log.Error(err, "Failed to unregister cluster from ArgoCD", "cluster", clusterName)
 		}
 	}
 
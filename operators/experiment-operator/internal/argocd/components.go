@@ -0,0 +1,324 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/components"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
+)
+
+// componentLabel marks Applications that deploy a single component of a
+// target in PerComponent mode; its value is the component name.
+const componentLabel = "experiments.illm.io/component"
+
+// componentAppName returns the ArgoCD Application name for one component.
+func componentAppName(experimentName, targetName, component string) string {
+	return fmt.Sprintf("%s-%s-%s", experimentName, targetName, component)
+}
+
+// argoSources converts resolved components into ArgoCD Application sources.
+func argoSources(resolvedComponents []*components.ResolvedComponent) []interface{} {
+	sources := []interface{}{}
+	for _, resolved := range resolvedComponents {
+		// Check if any source in this component uses $values references.
+		// If so, we need to add ref: "values" to the git source (non-chart source).
+		needsValuesRef := false
+		for _, source := range resolved.Sources {
+			if source.Helm != nil {
+				for _, vf := range source.Helm.ValuesFiles {
+					if strings.HasPrefix(vf, "$values") {
+						needsValuesRef = true
+						break
+					}
+				}
+			}
+		}
+
+		for _, source := range resolved.Sources {
+			argoSource := map[string]interface{}{
+				"repoURL":        source.RepoURL,
+				"targetRevision": source.TargetRevision,
+			}
+
+			// Use 'chart' for Helm repositories, 'path' for Git repositories
+			if source.Chart != "" {
+				argoSource["chart"] = source.Chart
+			} else if needsValuesRef && source.Helm == nil {
+				// This git source is used as a ref for $values — set ref instead of path
+				// to avoid ArgoCD trying to deploy component.yaml as a manifest.
+				argoSource["ref"] = "values"
+			} else {
+				argoSource["path"] = source.Path
+			}
+
+			if source.Helm != nil {
+				helmConfig := map[string]interface{}{}
+				if source.Helm.ReleaseName != "" {
+					helmConfig["releaseName"] = source.Helm.ReleaseName
+				}
+				if len(source.Helm.ValuesFiles) > 0 {
+					// Convert []string to []interface{} for unstructured deep copy compatibility
+					vf := make([]interface{}, len(source.Helm.ValuesFiles))
+					for i, f := range source.Helm.ValuesFiles {
+						vf[i] = f
+					}
+					helmConfig["valueFiles"] = vf
+				}
+				if len(source.Helm.Parameters) > 0 {
+					helmParams := []interface{}{}
+					for key, value := range source.Helm.Parameters {
+						helmParams = append(helmParams, map[string]interface{}{
+							"name":  key,
+							"value": value,
+						})
+					}
+					helmConfig["parameters"] = helmParams
+				}
+				if len(helmConfig) > 0 {
+					argoSource["helm"] = helmConfig
+				}
+			}
+
+			sources = append(sources, argoSource)
+		}
+	}
+	return sources
+}
+
+// createComponentApplications creates one ArgoCD Application per resolved
+// component, labelled with componentLabel so health checks and cleanup can
+// find them without the experiment spec.
+func (m *ApplicationManager) createComponentApplications(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, clusterServer string, resolvedComponents []*components.ResolvedComponent) error {
+	log := log.FromContext(ctx)
+
+	if len(resolvedComponents) == 0 {
+		log.Info("No components resolved for target, skipping application creation", "target", target.Name)
+		return nil
+	}
+
+	if err := m.ensureNamespace(ctx, experimentName); err != nil {
+		log.Error(err, "Failed to ensure namespace", "namespace", experimentName)
+	}
+
+	seen := map[string]int{}
+	for _, resolved := range resolvedComponents {
+		// The same component may be listed twice with different params
+		name := resolved.Name
+		if seen[resolved.Name]++; seen[resolved.Name] > 1 {
+			name = fmt.Sprintf("%s-%d", resolved.Name, seen[resolved.Name])
+		}
+		appName := componentAppName(experimentName, target.Name, name)
+
+		app := &unstructured.Unstructured{}
+		app.SetGroupVersionKind(applicationGVK)
+		app.SetName(appName)
+		app.SetNamespace("argocd")
+		app.SetLabels(map[string]string{
+			"app.kubernetes.io/managed-by":   "experiment-operator",
+			"experiments.illm.io/experiment": experimentName,
+			"experiments.illm.io/target":     target.Name,
+			componentLabel:                   name,
+		})
+
+		spec := map[string]interface{}{
+			"project": "default",
+			"sources": argoSources([]*components.ResolvedComponent{resolved}),
+			"destination": map[string]interface{}{
+				"server":    clusterServer,
+				"namespace": experimentName,
+			},
+			"syncPolicy": map[string]interface{}{
+				"automated": map[string]interface{}{
+					"prune":    true,
+					"selfHeal": true,
+				},
+				"syncOptions": []interface{}{
+					"CreateNamespace=true",
+					"ServerSideApply=true",
+				},
+			},
+		}
+		if err := unstructured.SetNestedMap(app.Object, spec, "spec"); err != nil {
+			return fmt.Errorf("failed to set application spec: %w", err)
+		}
+
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(applicationGVK)
+		err := m.Get(ctx, client.ObjectKey{Name: appName, Namespace: "argocd"}, existing)
+		if err == nil {
+			existing.Object["spec"] = app.Object["spec"]
+			if err := m.Update(ctx, existing); err != nil {
+				return fmt.Errorf("failed to update application for component %s: %w", name, err)
+			}
+			log.Info("Updated component ArgoCD Application", "name", appName, "component", name)
+			continue
+		}
+		if err := m.Create(ctx, app); err != nil {
+			return fmt.Errorf("failed to create application for component %s: %w", name, err)
+		}
+		log.Info("Created component ArgoCD Application", "name", appName, "component", name, "target", target.Name)
+	}
+	return nil
+}
+
+// listComponentApplications returns the per-component Applications of a target.
+func (m *ApplicationManager) listComponentApplications(ctx context.Context, experimentName, targetName string) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(applicationGVK.GroupVersion().WithKind("ApplicationList"))
+	if err := m.List(ctx, list,
+		client.InNamespace("argocd"),
+		client.MatchingLabels{
+			"experiments.illm.io/experiment": experimentName,
+			"experiments.illm.io/target":     targetName,
+		},
+		client.HasLabels{componentLabel},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list component applications: %w", err)
+	}
+	return list.Items, nil
+}
+
+// componentAppsReady reports whether the target has per-component
+// Applications and, if so, whether all of them are ready.
+func (m *ApplicationManager) componentAppsReady(ctx context.Context, experimentName, targetName string) (found, ready bool, err error) {
+	apps, err := m.listComponentApplications(ctx, experimentName, targetName)
+	if err != nil || len(apps) == 0 {
+		return false, false, err
+	}
+	for i := range apps {
+		if !applicationReady(&apps[i]) {
+			return true, false, nil
+		}
+	}
+	return true, true, nil
+}
+
+// deleteComponentApplications deletes the per-component Applications of a target.
+func (m *ApplicationManager) deleteComponentApplications(ctx context.Context, experimentName, targetName string) error {
+	log := log.FromContext(ctx)
+
+	apps, err := m.listComponentApplications(ctx, experimentName, targetName)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		if err := m.Delete(ctx, &apps[i]); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete application %s: %w", apps[i].GetName(), err)
+		}
+		log.Info("Deleted component ArgoCD Application", "name", apps[i].GetName())
+	}
+	return nil
+}
+
+// applicationReady reports whether an Application is ready enough to run an
+// experiment against.
+func applicationReady(app *unstructured.Unstructured) bool {
+	healthStatus, found, err := unstructured.NestedString(app.Object, "status", "health", "status")
+	if err != nil || !found {
+		return false // Not ready yet
+	}
+
+	// Check sync status — multi-source apps report "Unknown" which is acceptable
+	syncStatus, found, err := unstructured.NestedString(app.Object, "status", "sync", "status")
+	if err != nil || !found {
+		return false
+	}
+
+	// Check for ComparisonError conditions — ArgoCD reports "Healthy" even when
+	// manifest generation fails (since no resources exist to be unhealthy).
+	if applicationConditionMessage(app, "ComparisonError") != "" {
+		return false
+	}
+
+	// Application is ready when core components are deployed. Complex stacks
+	// (e.g. Mimir distributed mode) may stay "Degraded" due to resource pressure,
+	// but monitoring services are typically available. Accept Healthy, Degraded,
+	// or Progressing — only reject Missing (nothing deployed) or Suspended.
+	acceptableHealth := healthStatus == "Healthy" || healthStatus == "Degraded" || healthStatus == "Progressing"
+	// Multi-source ArgoCD apps report "Unknown" sync status, which is acceptable.
+	acceptableSync := syncStatus == "Synced" || syncStatus == "Unknown" || syncStatus == "OutOfSync"
+	return acceptableHealth && acceptableSync
+}
+
+// applicationConditionMessage returns the message of the first condition of
+// one of types, or "" if the Application has none.
+func applicationConditionMessage(app *unstructured.Unstructured, types ...string) string {
+	conditions, _, _ := unstructured.NestedSlice(app.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _, _ := unstructured.NestedString(cond, "type")
+		for _, t := range types {
+			if condType == t {
+				msg, _, _ := unstructured.NestedString(cond, "message")
+				if msg == "" {
+					msg = condType
+				}
+				return msg
+			}
+		}
+	}
+	return ""
+}
+
+// componentStatus reads the health and sync status of the Application that
+// deploys component.
+func componentStatus(component string, app *unstructured.Unstructured) experimentsv1alpha1.TargetComponentStatus {
+	st := experimentsv1alpha1.TargetComponentStatus{Name: component, Application: app.GetName()}
+	st.Health, _, _ = unstructured.NestedString(app.Object, "status", "health", "status")
+	st.Sync, _, _ = unstructured.NestedString(app.Object, "status", "sync", "status")
+
+	st.Message = applicationConditionMessage(app, "ComparisonError", "InvalidSpecError", "SyncError")
+	if st.Message == "" {
+		st.Message, _, _ = unstructured.NestedString(app.Object, "status", "health", "message")
+	}
+	if st.Message == "" {
+		phase, _, _ := unstructured.NestedString(app.Object, "status", "operationState", "phase")
+		if phase == "Failed" || phase == "Error" {
+			st.Message, _, _ = unstructured.NestedString(app.Object, "status", "operationState", "message")
+		}
+	}
+	return st
+}
+
+// ComponentStatuses returns the ArgoCD status of each component of target.
+// Per-component Applications report their own status; in Combined mode every
+// component shares the status of the target's workload Application.
+func (m *ApplicationManager) ComponentStatuses(ctx context.Context, experimentName string, target experimentsv1alpha1.Target) ([]experimentsv1alpha1.TargetComponentStatus, error) {
+	apps, err := m.listComponentApplications(ctx, experimentName, target.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(apps) > 0 {
+		statuses := make([]experimentsv1alpha1.TargetComponentStatus, 0, len(apps))
+		for i := range apps {
+			statuses = append(statuses, componentStatus(apps[i].GetLabels()[componentLabel], &apps[i]))
+		}
+		return statuses, nil
+	}
+
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(applicationGVK)
+	appName := layerAppName(experimentName, target.Name, deploy.LayerWorkload)
+	if err := m.Get(ctx, client.ObjectKey{Name: appName, Namespace: "argocd"}, app); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	var statuses []experimentsv1alpha1.TargetComponentStatus
+	for _, ref := range target.Components {
+		statuses = append(statuses, componentStatus(deploy.ComponentName(ref), app))
+	}
+	return statuses, nil
+}
@@ -0,0 +1,124 @@
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/components"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
+)
+
+func setAppStatus(t *testing.T, m *ApplicationManager, name string, status map[string]interface{}) {
+	t.Helper()
+	ctx := context.Background()
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(applicationGVK)
+	if err := m.Get(ctx, client.ObjectKey{Name: name, Namespace: "argocd"}, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Object["status"] = status
+	if err := m.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPerComponentApplications(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	m := NewApplicationManager(fake.NewClientBuilder().WithScheme(scheme).Build())
+	target := experimentsv1alpha1.Target{
+		Name:            "app",
+		ApplicationMode: experimentsv1alpha1.ApplicationModePerComponent,
+		Components:      []experimentsv1alpha1.ComponentRef{{App: "loki"}, {App: "minio"}},
+	}
+	resolved := []*components.ResolvedComponent{
+		{Name: "loki", Sources: []components.ResolvedSource{{RepoURL: "https://grafana.github.io/helm-charts", Chart: "loki"}}},
+		{Name: "minio", Sources: []components.ResolvedSource{{RepoURL: "https://charts.min.io", Chart: "minio"}}},
+	}
+	if err := m.createComponentApplications(ctx, "tsdb", target, "https://10.0.0.1", resolved); err != nil {
+		t.Fatal(err)
+	}
+
+	healthy := map[string]interface{}{
+		"health": map[string]interface{}{"status": "Healthy"},
+		"sync":   map[string]interface{}{"status": "Synced"},
+	}
+	setAppStatus(t, m, "tsdb-app-loki", healthy)
+	setAppStatus(t, m, "tsdb-app-minio", map[string]interface{}{
+		"health": map[string]interface{}{"status": "Healthy"},
+		"sync":   map[string]interface{}{"status": "Unknown"},
+		"conditions": []interface{}{map[string]interface{}{
+			"type": "ComparisonError", "message": "chart minio-9.9.9 not found",
+		}},
+	})
+
+	if ok, err := m.IsApplicationHealthy(ctx, "tsdb", "app"); err != nil || ok {
+		t.Errorf("IsApplicationHealthy = %v, %v; want false while minio fails", ok, err)
+	}
+	statuses, err := m.ComponentStatuses(ctx, "tsdb", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]experimentsv1alpha1.TargetComponentStatus{}
+	for _, s := range statuses {
+		byName[s.Name] = s
+	}
+	if s := byName["minio"]; s.Application != "tsdb-app-minio" || s.Message != "chart minio-9.9.9 not found" {
+		t.Errorf("minio status = %+v, want the comparison error", s)
+	}
+	if s := byName["loki"]; s.Health != "Healthy" || s.Sync != "Synced" || s.Message != "" {
+		t.Errorf("loki status = %+v", s)
+	}
+
+	setAppStatus(t, m, "tsdb-app-minio", healthy)
+	if ok, err := m.IsLayerHealthy(ctx, "tsdb", "app", deploy.LayerWorkload); err != nil || !ok {
+		t.Errorf("IsLayerHealthy(workload) = %v, %v; want true once all components are healthy", ok, err)
+	}
+
+	if err := m.DeleteApplication(ctx, "tsdb", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if apps, err := m.listComponentApplications(ctx, "tsdb", "app"); err != nil || len(apps) != 0 {
+		t.Errorf("component applications after delete = %d, %v", len(apps), err)
+	}
+}
+
+func TestCombinedComponentStatuses(t *testing.T) {
+	ctx := context.Background()
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(applicationGVK)
+	app.SetName("tsdb-app")
+	app.SetNamespace("argocd")
+	app.Object["status"] = map[string]interface{}{
+		"health": map[string]interface{}{"status": "Degraded", "message": "Deployment loki has 0 ready replicas"},
+		"sync":   map[string]interface{}{"status": "Synced"},
+	}
+	m := NewApplicationManager(fake.NewClientBuilder().WithObjects(app).Build())
+	target := experimentsv1alpha1.Target{
+		Name:       "app",
+		Components: []experimentsv1alpha1.ComponentRef{{App: "loki"}, {Config: "loki-dashboards"}},
+	}
+
+	statuses, err := m.ComponentStatuses(ctx, "tsdb", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[1].Name != "loki-dashboards" {
+		t.Fatalf("statuses = %+v, want one per component", statuses)
+	}
+	for _, s := range statuses {
+		if s.Application != "tsdb-app" || s.Health != "Degraded" || s.Message == "" {
+			t.Errorf("status = %+v, want the shared Application's", s)
+		}
+	}
+}
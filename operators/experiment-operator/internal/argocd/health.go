@@ -0,0 +1,71 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
+)
+
+// TargetHealth reads the health, sync status, last sync operation and
+// unhealthy resources of every Application deployed for a target: its
+// combined, layer and per-component Applications alike.
+func (m *ApplicationManager) TargetHealth(ctx context.Context, experimentName, targetName string) (*deploy.TargetHealth, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(applicationGVK.GroupVersion().WithKind("ApplicationList"))
+	if err := m.List(ctx, list, client.InNamespace("argocd"),
+		client.MatchingLabels{
+			"experiments.illm.io/experiment": experimentName,
+			"experiments.illm.io/target":     targetName,
+		}); err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	apps := list.Items
+	sort.Slice(apps, func(i, j int) bool { return apps[i].GetName() < apps[j].GetName() })
+
+	h := &deploy.TargetHealth{}
+	for i := range apps {
+		addApplicationHealth(h, &apps[i])
+	}
+	return h, nil
+}
+
+func addApplicationHealth(h *deploy.TargetHealth, app *unstructured.Unstructured) {
+	name := app.GetName()
+
+	health, _, _ := unstructured.NestedString(app.Object, "status", "health", "status")
+	sync, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
+	h.AddApplication(health, sync)
+
+	if msg := applicationConditionMessage(app, "ComparisonError", "InvalidSpecError", "SyncError"); msg != "" {
+		h.Messages = append(h.Messages, name+": "+msg)
+	}
+	phase, _, _ := unstructured.NestedString(app.Object, "status", "operationState", "phase")
+	if phase == "Failed" || phase == "Error" {
+		h.OperationFailed = true
+		msg, _, _ := unstructured.NestedString(app.Object, "status", "operationState", "message")
+		h.Messages = append(h.Messages, fmt.Sprintf("%s: sync %s: %s", name, strings.ToLower(phase), msg))
+	}
+
+	resources, _, _ := unstructured.NestedSlice(app.Object, "status", "resources")
+	for _, r := range resources {
+		res, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		u := experimentsv1alpha1.UnhealthyResource{Application: name}
+		u.Kind, _, _ = unstructured.NestedString(res, "kind")
+		u.Namespace, _, _ = unstructured.NestedString(res, "namespace")
+		u.Name, _, _ = unstructured.NestedString(res, "name")
+		u.Sync, _, _ = unstructured.NestedString(res, "status")
+		u.Health, _, _ = unstructured.NestedString(res, "health", "status")
+		u.Message, _, _ = unstructured.NestedString(res, "health", "message")
+		h.AddResource(u)
+	}
+}
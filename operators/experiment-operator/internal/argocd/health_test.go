@@ -0,0 +1,80 @@
+package argocd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testApplication(name, target string, status map[string]interface{}) *unstructured.Unstructured {
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(applicationGVK)
+	app.SetName(name)
+	app.SetNamespace("argocd")
+	app.SetLabels(map[string]string{
+		"experiments.illm.io/experiment": "tsdb",
+		"experiments.illm.io/target":     target,
+	})
+	app.Object["status"] = status
+	return app
+}
+
+func TestTargetHealth(t *testing.T) {
+	ctx := context.Background()
+	obs := testApplication("tsdb-app-obs", "app", map[string]interface{}{
+		"health": map[string]interface{}{"status": "Healthy"},
+		"sync":   map[string]interface{}{"status": "Synced"},
+		"resources": []interface{}{
+			map[string]interface{}{"kind": "ConfigMap", "namespace": "tsdb", "name": "alloy", "status": "Synced"},
+		},
+	})
+	workload := testApplication("tsdb-app", "app", map[string]interface{}{
+		"health": map[string]interface{}{"status": "Degraded"},
+		"sync":   map[string]interface{}{"status": "OutOfSync"},
+		"operationState": map[string]interface{}{
+			"phase":   "Failed",
+			"message": "one or more objects failed to apply",
+		},
+		"resources": []interface{}{
+			map[string]interface{}{
+				"kind": "Deployment", "namespace": "tsdb", "name": "loki", "status": "Synced",
+				"health": map[string]interface{}{"status": "Degraded", "message": "Deployment has 0/1 ready replicas"},
+			},
+			map[string]interface{}{"kind": "PersistentVolumeClaim", "namespace": "tsdb", "name": "data", "status": "OutOfSync"},
+		},
+	})
+	other := testApplication("tsdb-loadgen", "loadgen", map[string]interface{}{
+		"health": map[string]interface{}{"status": "Missing"},
+	})
+	m := NewApplicationManager(fake.NewClientBuilder().WithObjects(obs, workload, other).Build())
+
+	h, err := m.TargetHealth(ctx, "tsdb", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Applications != 2 || h.Health != "Degraded" || h.Sync != "OutOfSync" || !h.OperationFailed {
+		t.Errorf("TargetHealth = %+v", h)
+	}
+	if len(h.Unhealthy) != 2 || h.Unhealthy[0].Name != "loki" || h.Unhealthy[0].Application != "tsdb-app" {
+		t.Errorf("Unhealthy = %+v, want loki and data", h.Unhealthy)
+	}
+
+	cond := h.Condition(3)
+	if cond.Status != metav1.ConditionFalse || cond.Reason != "SyncFailed" || cond.ObservedGeneration != 3 {
+		t.Errorf("Condition = %+v", cond)
+	}
+	for _, want := range []string{
+		"health Degraded, sync OutOfSync",
+		"tsdb-app: sync failed: one or more objects failed to apply",
+		"Deployment tsdb/loki (Degraded, Synced): Deployment has 0/1 ready replicas",
+		"PersistentVolumeClaim tsdb/data (OutOfSync)",
+	} {
+		if !strings.Contains(cond.Message, want) {
+			t.Errorf("Condition message %q does not contain %q", cond.Message, want)
+		}
+	}
+}
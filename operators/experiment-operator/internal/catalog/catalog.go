@@ -0,0 +1,306 @@
+// Package catalog maintains a cross-experiment index of completed
+// experiments in the results store and serves it over a read-only HTTP API,
+// so past runs can be found without listing bucket prefixes or site/data.
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+const (
+	// IndexKey is where the index is stored. Underscore-prefixed top-level
+	// keys cannot collide with Experiment names and are skipped by retention.
+	IndexKey = "_index/experiments.json"
+	// IndexSchemaVersion is the layout version of the index object.
+	IndexSchemaVersion = 1
+)
+
+// Entry is one completed experiment in the index.
+type Entry struct {
+	Name        string    `json:"name"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Phase       string    `json:"phase"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	DurationSec float64   `json:"durationSeconds"`
+	CostUSD     float64   `json:"costUSD,omitempty"`
+	// MachineVerdict is the success-criteria verdict (validated, invalidated
+	// or insufficient); AnalysisVerdict is the analyzer's hypothesis verdict.
+	MachineVerdict  string `json:"machineVerdict,omitempty"`
+	AnalysisVerdict string `json:"analysisVerdict,omitempty"`
+	AnalysisPhase   string `json:"analysisPhase,omitempty"`
+	ReviewPhase     string `json:"reviewPhase,omitempty"`
+	// KeyMetrics holds one headline number per query (see KeyMetrics).
+	KeyMetrics   map[string]float64 `json:"keyMetrics,omitempty"`
+	ResultsURL   string             `json:"resultsURL,omitempty"`
+	Published    bool               `json:"published,omitempty"`
+	PublishPRURL string             `json:"publishPRURL,omitempty"`
+	// Archived is set when retention moved the results under its archive prefix.
+	Archived bool `json:"archived,omitempty"`
+}
+
+// Verdict is the analyzer's verdict when there is one, else the machine verdict.
+func (e *Entry) Verdict() string {
+	if e.AnalysisVerdict != "" {
+		return e.AnalysisVerdict
+	}
+	return e.MachineVerdict
+}
+
+// ApplyStatus copies the fields that change after collection (phase,
+// publish, analysis and review state) from the Experiment's status.
+func (e *Entry) ApplyStatus(exp *experimentsv1alpha1.Experiment) {
+	e.Namespace = exp.Namespace
+	e.Phase = string(exp.Status.Phase)
+	if exp.Status.HypothesisResult != "" {
+		e.MachineVerdict = exp.Status.HypothesisResult
+	}
+	e.AnalysisPhase = string(exp.Status.AnalysisPhase)
+	e.ReviewPhase = string(exp.Status.ReviewPhase)
+	if exp.Status.ResultsURL != "" && exp.Status.ResultsURL != "disabled" {
+		e.ResultsURL = exp.Status.ResultsURL
+	}
+	e.Published = exp.Status.Published
+	e.PublishPRURL = exp.Status.PublishPRURL
+}
+
+// EntryFromSummary builds an index entry from a stored summary.
+func EntryFromSummary(s *metrics.ExperimentSummary) Entry {
+	e := Entry{
+		Name:        s.Name,
+		Namespace:   s.Namespace,
+		Title:       s.Title,
+		Tags:        append([]string(nil), s.Tags...),
+		Phase:       s.Phase,
+		CreatedAt:   s.CreatedAt,
+		CompletedAt: s.CompletedAt,
+		DurationSec: s.DurationSec,
+		KeyMetrics:  KeyMetrics(s.Metrics),
+	}
+	if s.CostEstimate != nil {
+		e.CostUSD = s.CostEstimate.TotalUSD
+	}
+	if s.Hypothesis != nil {
+		e.MachineVerdict = s.Hypothesis.MachineVerdict
+	}
+	if s.Analysis != nil {
+		e.AnalysisVerdict = s.Analysis.HypothesisVerdict
+	}
+	return e
+}
+
+// KeyMetrics reduces each query to one number: the steady-state mean of a
+// range query, or the value of an instant query with a single series.
+// Queries that errored or return several instant series are left out.
+func KeyMetrics(result *metrics.MetricsResult) map[string]float64 {
+	if result == nil {
+		return nil
+	}
+	out := make(map[string]float64)
+	for name, qr := range result.Queries {
+		if qr.Error != "" || len(qr.Data) == 0 {
+			continue
+		}
+		var v float64
+		switch {
+		case qr.SteadyState != nil:
+			v = qr.SteadyState.Mean
+		case qr.Type == "instant" && len(qr.Data) == 1:
+			v = qr.Data[0].Value
+		default:
+			continue
+		}
+		// NaN and ±Inf have no JSON encoding.
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		out[name] = v
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// Index is the stored index object.
+type Index struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	// Experiments is ordered newest completion first.
+	Experiments []Entry `json:"experiments"`
+}
+
+// Get returns the entry for name, or nil.
+func (idx *Index) Get(name string) *Entry {
+	for i := range idx.Experiments {
+		if idx.Experiments[i].Name == name {
+			return &idx.Experiments[i]
+		}
+	}
+	return nil
+}
+
+func (idx *Index) sort() {
+	sort.SliceStable(idx.Experiments, func(i, j int) bool {
+		a, b := idx.Experiments[i], idx.Experiments[j]
+		if !a.CompletedAt.Equal(b.CompletedAt) {
+			return a.CompletedAt.After(b.CompletedAt)
+		}
+		return a.Name < b.Name
+	})
+}
+
+// Catalog reads and updates the index in a results store. Updates are
+// read-modify-write of a single object, serialized within the process; only
+// the leader (controller and retention) writes.
+type Catalog struct {
+	store storage.ResultsStore
+	mu    sync.Mutex
+}
+
+// New returns a Catalog backed by store.
+func New(store storage.ResultsStore) *Catalog {
+	return &Catalog{store: store}
+}
+
+// Store returns the results store the catalog indexes.
+func (c *Catalog) Store() storage.ResultsStore { return c.store }
+
+// Load reads the index. A missing index is empty, not an error.
+func (c *Catalog) Load(ctx context.Context) (*Index, error) {
+	idx := &Index{SchemaVersion: IndexSchemaVersion}
+	if err := storage.GetJSON(ctx, c.store, IndexKey, idx); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return &Index{SchemaVersion: IndexSchemaVersion}, nil
+		}
+		return nil, fmt.Errorf("load results index: %w", err)
+	}
+	return idx, nil
+}
+
+// modify applies fn to the index and saves it if fn reports a change.
+func (c *Catalog) modify(ctx context.Context, fn func(*Index) bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	idx, err := c.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if !fn(idx) {
+		return nil
+	}
+	idx.SchemaVersion = IndexSchemaVersion
+	idx.UpdatedAt = time.Now().UTC()
+	idx.sort()
+	if err := storage.PutJSON(ctx, c.store, IndexKey, idx); err != nil {
+		return fmt.Errorf("save results index: %w", err)
+	}
+	return nil
+}
+
+// Upsert adds e or replaces the entry with the same name.
+func (c *Catalog) Upsert(ctx context.Context, e Entry) error {
+	return c.modify(ctx, func(idx *Index) bool {
+		if cur := idx.Get(e.Name); cur != nil {
+			*cur = e
+		} else {
+			idx.Experiments = append(idx.Experiments, e)
+		}
+		return true
+	})
+}
+
+// Update applies fn to the named entry. Unknown names are ignored: only
+// experiments that stored results are indexed.
+func (c *Catalog) Update(ctx context.Context, name string, fn func(*Entry)) error {
+	return c.modify(ctx, func(idx *Index) bool {
+		e := idx.Get(name)
+		if e == nil {
+			return false
+		}
+		fn(e)
+		return true
+	})
+}
+
+// Remove drops the named entry.
+func (c *Catalog) Remove(ctx context.Context, name string) error {
+	return c.modify(ctx, func(idx *Index) bool {
+		for i := range idx.Experiments {
+			if idx.Experiments[i].Name == name {
+				idx.Experiments = append(idx.Experiments[:i], idx.Experiments[i+1:]...)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Backfill indexes promoted results that are missing from the index, e.g.
+// those stored before the index existed. Existing entries are left alone.
+func (c *Catalog) Backfill(ctx context.Context) (int, error) {
+	objects, err := c.store.List(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("list results: %w", err)
+	}
+	idx, err := c.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	logger := log.FromContext(ctx).WithName("catalog")
+	var missing []Entry
+	for _, o := range objects {
+		name, rest, ok := strings.Cut(o.Key, "/")
+		if !ok || rest != "summary.json" || strings.HasPrefix(name, "_") || idx.Get(name) != nil {
+			continue
+		}
+		var s metrics.ExperimentSummary
+		if err := storage.GetJSON(ctx, c.store, o.Key, &s); err != nil {
+			logger.Error(err, "Skipping unreadable summary", "key", o.Key)
+			continue
+		}
+		if s.Name == "" {
+			s.Name = name
+		}
+		e := EntryFromSummary(&s)
+		e.ResultsURL = c.store.URL(name + "/")
+		if m, err := storage.LoadManifest(ctx, c.store, name); err == nil && m.Published {
+			e.Published = true
+			e.PublishPRURL = m.PublishedURL
+		}
+		missing = append(missing, e)
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	added := 0
+	err = c.modify(ctx, func(idx *Index) bool {
+		for _, e := range missing {
+			// Recheck: the controller may have indexed it meanwhile.
+			if idx.Get(e.Name) == nil {
+				idx.Experiments = append(idx.Experiments, e)
+				added++
+			}
+		}
+		return added > 0
+	})
+	return added, err
+}
@@ -0,0 +1,204 @@
+package catalog
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+var t0 = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(store)
+}
+
+func TestKeyMetrics(t *testing.T) {
+	result := &metrics.MetricsResult{Queries: map[string]metrics.QueryResult{
+		"p99":      {Type: "instant", Data: []metrics.DataPoint{{Value: 0.25}}},
+		"by_pod":   {Type: "instant", Data: []metrics.DataPoint{{Value: 1}, {Value: 2}}},
+		"cpu":      {Type: "range", Data: []metrics.DataPoint{{Value: 1}}, SteadyState: &metrics.SteadyState{Mean: 0.5}},
+		"no_state": {Type: "range", Data: []metrics.DataPoint{{Value: 1}}},
+		"broken":   {Type: "instant", Error: "timeout"},
+		"inf":      {Type: "instant", Data: []metrics.DataPoint{{Value: math.Inf(1)}}},
+	}}
+	got := KeyMetrics(result)
+	want := map[string]float64{"p99": 0.25, "cpu": 0.5}
+	if len(got) != len(want) {
+		t.Fatalf("KeyMetrics = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("KeyMetrics[%s] = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestEntryFromSummaryAndStatus(t *testing.T) {
+	s := &metrics.ExperimentSummary{
+		Name:         "tsdb-b2c4d",
+		Tags:         []string{"tsdb"},
+		Phase:        "Complete",
+		CompletedAt:  t0,
+		DurationSec:  3600,
+		CostEstimate: &metrics.CostEstimate{TotalUSD: 1.5},
+		Hypothesis:   &metrics.HypothesisContext{MachineVerdict: "validated"},
+	}
+	e := EntryFromSummary(s)
+	if e.CostUSD != 1.5 || e.MachineVerdict != "validated" || e.Verdict() != "validated" {
+		t.Errorf("entry = %+v", e)
+	}
+
+	exp := &experimentsv1alpha1.Experiment{}
+	exp.Namespace = "experiments"
+	exp.Status.Phase = experimentsv1alpha1.PhaseFailed
+	exp.Status.ResultsURL = "disabled"
+	exp.Status.Published = true
+	exp.Status.PublishPRURL = "https://github.com/o/r/pull/7"
+	exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseFailed
+	e.ResultsURL = "s3://bucket/tsdb-b2c4d/"
+	e.ApplyStatus(exp)
+	if e.Phase != "Failed" || !e.Published || e.PublishPRURL == "" || e.AnalysisPhase != "Failed" {
+		t.Errorf("status not applied: %+v", e)
+	}
+	if e.ResultsURL != "s3://bucket/tsdb-b2c4d/" {
+		t.Errorf("ResultsURL = %q, want store URL kept over %q", e.ResultsURL, "disabled")
+	}
+
+	e.AnalysisVerdict = "invalidated"
+	if e.Verdict() != "invalidated" {
+		t.Errorf("Verdict = %q, want analyzer verdict to win", e.Verdict())
+	}
+}
+
+func TestCatalogUpsertUpdateRemove(t *testing.T) {
+	ctx := context.Background()
+	c := newCatalog(t)
+
+	idx, err := c.Load(ctx)
+	if err != nil || len(idx.Experiments) != 0 {
+		t.Fatalf("Load on empty store = %+v, %v; want empty index", idx, err)
+	}
+
+	for i, name := range []string{"a", "b", "c"} {
+		if err := c.Upsert(ctx, Entry{Name: name, CompletedAt: t0.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Upsert(ctx, Entry{Name: "a", Title: "replaced", CompletedAt: t0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(ctx, "b", func(e *Entry) { e.AnalysisVerdict = "validated" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(ctx, "missing", func(e *Entry) { t.Error("fn called for unindexed name") }); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Remove(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err = c.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Experiments) != 2 || idx.Experiments[0].Name != "b" || idx.Experiments[1].Name != "a" {
+		t.Fatalf("experiments = %+v, want b then a (newest first)", idx.Experiments)
+	}
+	if idx.Experiments[1].Title != "replaced" || idx.Experiments[0].AnalysisVerdict != "validated" {
+		t.Errorf("experiments = %+v", idx.Experiments)
+	}
+	if idx.SchemaVersion != IndexSchemaVersion || idx.UpdatedAt.IsZero() {
+		t.Errorf("index header = %d, %v", idx.SchemaVersion, idx.UpdatedAt)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	ctx := context.Background()
+	c := newCatalog(t)
+	store := c.Store()
+
+	summary := &metrics.ExperimentSummary{Name: "old-b2c4d", Phase: "Complete", CompletedAt: t0, Tags: []string{"logging"}}
+	if err := storage.PutJSON(ctx, store, "old-b2c4d/summary.json", summary); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.PutJSON(ctx, store, "_archive/gone/summary.json", summary); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Upsert(ctx, Entry{Name: "new-f5g6h", CompletedAt: t0.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := c.Backfill(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Backfill = %d, %v; want 1 added", n, err)
+	}
+	idx, _ := c.Load(ctx)
+	e := idx.Get("old-b2c4d")
+	if e == nil || e.ResultsURL == "" || len(e.Tags) != 1 {
+		t.Errorf("backfilled entry = %+v", e)
+	}
+
+	if n, err := c.Backfill(ctx); err != nil || n != 0 {
+		t.Errorf("second Backfill = %d, %v; want nothing added", n, err)
+	}
+}
+
+func TestParseFilterAndQuery(t *testing.T) {
+	idx := &Index{Experiments: []Entry{
+		{Name: "c", Tags: []string{"tsdb", "gke"}, Phase: "Complete", MachineVerdict: "validated", CompletedAt: t0, Published: true},
+		{Name: "b", Tags: []string{"tsdb"}, Phase: "Complete", MachineVerdict: "validated", AnalysisVerdict: "invalidated", CompletedAt: t0.Add(-48 * time.Hour)},
+		{Name: "a", Tags: []string{"logging"}, Phase: "Failed", CompletedAt: t0.Add(-240 * time.Hour)},
+	}}
+	now := t0.Add(time.Hour)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "c,b,a"},
+		{"tag=tsdb", "c,b"},
+		{"tag=tsdb,GKE", "c"},
+		{"tag=tsdb&tag=logging", ""},
+		{"verdict=validated", "c"},
+		{"verdict=invalidated", "b"},
+		{"phase=failed", "a"},
+		{"since=3d", "c,b"},
+		{"since=2026-04-25&until=2026-04-30T00:00:00Z", "b"},
+		{"published=false", "b,a"},
+		{"limit=2", "c,b"},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		f, err := ParseFilter(q, now)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.query, err)
+			continue
+		}
+		var names []string
+		for _, e := range idx.Query(f) {
+			names = append(names, e.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("Query(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"since=yesterday", "limit=-1", "published=maybe"} {
+		q, _ := url.ParseQuery(bad)
+		if _, err := ParseFilter(q, now); err == nil {
+			t.Errorf("ParseFilter(%q) accepted invalid input", bad)
+		}
+	}
+}
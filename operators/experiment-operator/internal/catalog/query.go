@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter selects index entries. Zero fields match everything.
+type Filter struct {
+	// Tags must all be present on an entry.
+	Tags []string
+	// Verdict matches Entry.Verdict().
+	Verdict string
+	// Phase matches the experiment phase (Complete or Failed).
+	Phase string
+	// Since and Until bound the completion time (inclusive).
+	Since, Until time.Time
+	// Published, when set, selects published or unpublished entries.
+	Published *bool
+	// Limit caps the number of entries returned (newest first).
+	Limit int
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e *Entry) bool {
+	for _, tag := range f.Tags {
+		found := false
+		for _, t := range e.Tags {
+			if strings.EqualFold(t, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Verdict != "" && !strings.EqualFold(e.Verdict(), f.Verdict) {
+		return false
+	}
+	if f.Phase != "" && !strings.EqualFold(e.Phase, f.Phase) {
+		return false
+	}
+	if !f.Since.IsZero() && e.CompletedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.CompletedAt.After(f.Until) {
+		return false
+	}
+	if f.Published != nil && e.Published != *f.Published {
+		return false
+	}
+	return true
+}
+
+// Query returns the matching entries, newest completion first.
+func (idx *Index) Query(f Filter) []Entry {
+	out := []Entry{}
+	for i := range idx.Experiments {
+		if !f.Match(&idx.Experiments[i]) {
+			continue
+		}
+		out = append(out, idx.Experiments[i])
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// ParseFilter reads a Filter from URL query parameters:
+//
+//	tag=a&tag=b (or tag=a,b)  all tags must match
+//	verdict=validated         analyzer or machine verdict
+//	phase=Complete
+//	since, until              RFC 3339 time, date (2006-01-02), or a duration
+//	                          ago such as 72h or 30d
+//	published=true|false
+//	limit=N
+func ParseFilter(q url.Values, now time.Time) (Filter, error) {
+	var f Filter
+	for _, v := range q["tag"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				f.Tags = append(f.Tags, t)
+			}
+		}
+	}
+	f.Verdict = q.Get("verdict")
+	f.Phase = q.Get("phase")
+
+	var err error
+	if v := q.Get("since"); v != "" {
+		if f.Since, err = parseTime(v, now); err != nil {
+			return f, fmt.Errorf("since: %w", err)
+		}
+	}
+	if v := q.Get("until"); v != "" {
+		if f.Until, err = parseTime(v, now); err != nil {
+			return f, fmt.Errorf("until: %w", err)
+		}
+	}
+	if v := q.Get("published"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, fmt.Errorf("published: %w", err)
+		}
+		f.Published = &b
+	}
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit < 0 {
+			return f, fmt.Errorf("limit: want a non-negative integer, got %q", v)
+		}
+	}
+	return f, nil
+}
+
+// parseTime accepts an RFC 3339 time, a date, or a duration before now
+// (Go syntax plus a "d" day suffix).
+func parseTime(v string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.Add(-time.Duration(n) * 24 * time.Hour), nil
+		}
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("want RFC 3339 time, date or duration, got %q", v)
+}
@@ -0,0 +1,179 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+// ArchivePrefix is where retention moves archived results; the summary of an
+// Archived entry is read from there.
+const ArchivePrefix = "_archive/"
+
+// Server serves the index over HTTP:
+//
+//	GET /api/v1/experiments                  list, filtered by ParseFilter params
+//	GET /api/v1/experiments/{name}           one entry
+//	GET /api/v1/experiments/{name}/summary   the stored summary.json
+//
+// It implements manager.Runnable and runs on every replica; the index is
+// read from the store on each request, so followers serve current data.
+//
+// The API has no authentication and must stay cluster-internal: expose it
+// only through the ClusterIP Service and the API server's service proxy,
+// which applies the caller's RBAC.
+type Server struct {
+	Catalog *Catalog
+	// Addr is the listen address, e.g. ":8082".
+	Addr string
+	// AllowedOrigins are the browser origins, e.g. the benchmark site, that
+	// may read the API cross-origin. "*" allows any origin. Empty sends no
+	// CORS headers, so browsers only allow same-origin reads.
+	AllowedOrigins []string
+}
+
+// NeedLeaderElection lets non-leader replicas serve reads too.
+func (s *Server) NeedLeaderElection() bool { return false }
+
+// Start serves until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("results-api")
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	logger.Info("Serving results API", "addr", s.Addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// Handler returns the API's HTTP handler.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/experiments", s.list)
+	mux.HandleFunc("GET /api/v1/experiments/{name}", s.get)
+	mux.HandleFunc("GET /api/v1/experiments/{name}/summary", s.summary)
+	return cors(s.AllowedOrigins, mux)
+}
+
+// ListResponse is the body of GET /api/v1/experiments.
+type ListResponse struct {
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Total       int       `json:"total"`
+	Experiments []Entry   `json:"experiments"`
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	f, err := ParseFilter(r.URL.Query(), time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	idx, err := s.Catalog.Load(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	entries := idx.Query(f)
+	writeJSON(w, http.StatusOK, ListResponse{UpdatedAt: idx.UpdatedAt, Total: len(entries), Experiments: entries})
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r)
+	if ok {
+		writeJSON(w, http.StatusOK, e)
+	}
+}
+
+func (s *Server) summary(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	key := e.Name + "/summary.json"
+	if e.Archived {
+		key = ArchivePrefix + key
+	}
+	rc, err := s.Catalog.Store().Get(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "summary not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	defer rc.Close()
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.Copy(w, rc)
+}
+
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (*Entry, bool) {
+	idx, err := s.Catalog.Load(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return nil, false
+	}
+	e := idx.Get(r.PathValue("name"))
+	if e == nil {
+		writeError(w, http.StatusNotFound, "experiment not indexed")
+		return nil, false
+	}
+	return e, true
+}
+
+// cors lets the allowed origins read the API from the browser.
+func cors(allowed []string, next http.Handler) http.Handler {
+	if len(allowed) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		switch {
+		case slices.Contains(allowed, "*"):
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case origin != "" && slices.Contains(allowed, origin):
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
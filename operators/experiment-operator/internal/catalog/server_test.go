@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	c := newCatalog(t)
+	for _, e := range []Entry{
+		{Name: "tsdb-b2c4d", Tags: []string{"tsdb"}, Phase: "Complete", CompletedAt: t0},
+		{Name: "logs-f5g6h", Tags: []string{"logging"}, Phase: "Complete", CompletedAt: t0, Archived: true},
+	} {
+		if err := c.Upsert(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := storage.PutJSON(ctx, c.Store(), "tsdb-b2c4d/summary.json", map[string]string{"name": "tsdb-b2c4d"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.PutJSON(ctx, c.Store(), ArchivePrefix+"logs-f5g6h/summary.json", map[string]string{"name": "logs-f5g6h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer((&Server{Catalog: c}).Handler())
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/api/v1/experiments?tag=tsdb")
+	var list ListResponse
+	if err := json.Unmarshal([]byte(body), &list); err != nil {
+		t.Fatalf("list body %q: %v", body, err)
+	}
+	if code != http.StatusOK || list.Total != 1 || list.Experiments[0].Name != "tsdb-b2c4d" {
+		t.Errorf("list = %d %+v", code, list)
+	}
+
+	if code, body := get("/api/v1/experiments/tsdb-b2c4d"); code != http.StatusOK || !strings.Contains(body, `"phase": "Complete"`) {
+		t.Errorf("get = %d %s", code, body)
+	}
+	if code, body := get("/api/v1/experiments/tsdb-b2c4d/summary"); code != http.StatusOK || !strings.Contains(body, "tsdb-b2c4d") {
+		t.Errorf("summary = %d %s", code, body)
+	}
+	if code, body := get("/api/v1/experiments/logs-f5g6h/summary"); code != http.StatusOK || !strings.Contains(body, "logs-f5g6h") {
+		t.Errorf("archived summary = %d %s", code, body)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/experiments/missing":       http.StatusNotFound,
+		"/api/v1/experiments?since=someday": http.StatusBadRequest,
+	} {
+		if code, _ := get(path); code != want {
+			t.Errorf("GET %s = %d, want %d", path, code, want)
+		}
+	}
+
+	resp, err := http.Post(srv.URL+"/api/v1/experiments", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want read-only API", resp.StatusCode)
+	}
+}
+
+func TestServerCORS(t *testing.T) {
+	c := newCatalog(t)
+	for _, tc := range []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    string
+	}{
+		{"disabled", nil, "https://site.example", ""},
+		{"any", []string{"*"}, "https://site.example", "*"},
+		{"listed", []string{"https://site.example"}, "https://site.example", "https://site.example"},
+		{"unlisted", []string{"https://site.example"}, "https://evil.example", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/experiments", nil)
+			req.Header.Set("Origin", tc.origin)
+			rec := httptest.NewRecorder()
+			(&Server{Catalog: c, AllowedOrigins: tc.allowed}).Handler().ServeHTTP(rec, req)
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tc.want)
+			}
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want 200", rec.Code)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -78,6 +79,34 @@ func (r *Resolver) ResolveComponentRef(ctx context.Context, ref experimentsv1alp
 	return r.resolveFromCR(component, ref.Params)
 }
 
+// ComponentLayer returns the deployment layer of ref: its own Layer, else the
+// Component CR's spec.layer. It returns "" for the default layer, including
+// when no Component CR exists; other lookup errors are returned.
+func (r *Resolver) ComponentLayer(ctx context.Context, ref experimentsv1alpha1.ComponentRef) (string, error) {
+	if ref.Layer != "" {
+		return ref.Layer, nil
+	}
+	name := ref.App
+	if name == "" {
+		name = ref.Workflow
+	}
+	if name == "" {
+		name = ref.Config
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	component := &experimentsv1alpha1.Component{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, component); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get component %s: %w", name, err)
+	}
+	return component.Spec.Layer, nil
+}
+
 // resolveFromCR resolves a component from its CR
 func (r *Resolver) resolveFromCR(component *experimentsv1alpha1.Component, params map[string]string) (*ResolvedComponent, error) {
 	resolved := &ResolvedComponent{
@@ -0,0 +1,372 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/publish"
+)
+
+// analyzerNamespace is where analyzer Jobs and their secrets live.
+const analyzerNamespace = "experiment-operator-system"
+
+// defaultAnalyzerProfile is the Claude Code CLI analyzer used when no
+// AnalyzerProfile is selected. Credentials come from the claude-auth secret
+// and are seeded once into a PVC the CLI refreshes them in.
+func defaultAnalyzerProfile() *experimentsv1alpha1.AnalyzerProfileSpec {
+	return &experimentsv1alpha1.AnalyzerProfileSpec{
+		Description:     "Claude Code CLI",
+		RequiredSecrets: []experimentsv1alpha1.SecretKeyRef{{Name: "claude-auth", Key: "credentials.json"}},
+		Volumes: []corev1.Volume{
+			{
+				Name: "claude-credentials",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "claude-auth",
+						Items: []corev1.KeyToPath{
+							{
+								Key:  "credentials.json",
+								Path: ".credentials.json",
+							},
+						},
+						DefaultMode: int32Ptr(0444),
+					},
+				},
+			},
+			{
+				Name: "claude-home",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "claude-credentials-pvc",
+					},
+				},
+			},
+		},
+		InitContainers: []corev1.Container{
+			{
+				Name:  "copy-credentials",
+				Image: "busybox:1.37",
+				Command: []string{
+					"sh", "-c",
+					"if [ ! -f /claude-home/.credentials.json ]; then cp /claude-secret/.credentials.json /claude-home/.credentials.json && chmod 600 /claude-home/.credentials.json && echo 'Seeded credentials from secret'; else echo 'Using existing credentials from PVC'; fi",
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "claude-credentials",
+						MountPath: "/claude-secret",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "claude-home",
+						MountPath: "/claude-home",
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "claude-home",
+				MountPath: "/home/node/.claude",
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+	}
+}
+
+// analyzerProfileName returns the AnalyzerProfile selected for exp, or "" for
+// the built-in default.
+func (r *ExperimentReconciler) analyzerProfileName(exp *experimentsv1alpha1.Experiment) string {
+	if exp.Spec.AnalyzerConfig != nil && exp.Spec.AnalyzerConfig.Profile != "" {
+		return exp.Spec.AnalyzerConfig.Profile
+	}
+	return r.AnalyzerProfile
+}
+
+// analyzerEnabled reports whether the manager runs analyzer Jobs at all: an
+// analyzer image or an AnalyzerProfile must be configured.
+func (r *ExperimentReconciler) analyzerEnabled(exp *experimentsv1alpha1.Experiment) bool {
+	return r.AnalyzerImage != "" || r.analyzerProfileName(exp) != ""
+}
+
+// resolveAnalyzerProfile loads the AnalyzerProfile selected for exp. The
+// returned name is "default" for the built-in profile.
+func (r *ExperimentReconciler) resolveAnalyzerProfile(ctx context.Context, exp *experimentsv1alpha1.Experiment) (string, *experimentsv1alpha1.AnalyzerProfileSpec, error) {
+	name := r.analyzerProfileName(exp)
+	if name == "" {
+		return "default", defaultAnalyzerProfile(), nil
+	}
+	profile := &experimentsv1alpha1.AnalyzerProfile{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, profile); err != nil {
+		return name, nil, err
+	}
+	return name, &profile.Spec, nil
+}
+
+// missingAnalyzerSecrets returns the required secret keys of profile that are
+// absent or empty, as "secret/key".
+func (r *ExperimentReconciler) missingAnalyzerSecrets(ctx context.Context, profile *experimentsv1alpha1.AnalyzerProfileSpec) ([]string, error) {
+	var missing []string
+	for _, ref := range profile.RequiredSecrets {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: analyzerNamespace}, secret)
+		if errors.IsNotFound(err) {
+			missing = append(missing, ref.Name+"/"+ref.Key)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get analyzer secret %s: %w", ref.Name, err)
+		}
+		if len(secret.Data[ref.Key]) == 0 {
+			missing = append(missing, ref.Name+"/"+ref.Key)
+		}
+	}
+	return missing, nil
+}
+
+// analysisJobName returns the analyzer Job name of exp, truncated to the
+// 63-character name limit. Re-runs get a -r<n> suffix so they never collide
+// with an earlier Job that has not been garbage-collected yet.
+func analysisJobName(exp *experimentsv1alpha1.Experiment) string {
+	jobName := fmt.Sprintf("experiment-analyzer-%s", exp.Name)
+	suffix := ""
+	if n := len(exp.Status.AnalysisHistory); n > 0 {
+		suffix = fmt.Sprintf("-r%d", n)
+	}
+	if len(jobName)+len(suffix) > 63 {
+		jobName = jobName[:63-len(suffix)]
+	}
+	return jobName + suffix
+}
+
+// skipAnalysis marks analysis skipped with an AnalysisComplete condition
+// explaining why. The experiment's own phase is left alone.
+func skipAnalysis(exp *experimentsv1alpha1.Experiment, reason, msg string) {
+	exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseSkipped
+	apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
+		Type:               "AnalysisComplete",
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		ObservedGeneration: exp.Generation,
+		Message:            msg,
+	})
+}
+
+// startAnalysis launches the analyzer Job for exp with its AnalyzerProfile.
+// A missing profile, image or credentials skip analysis rather than fail the
+// experiment.
+func (r *ExperimentReconciler) startAnalysis(ctx context.Context, exp *experimentsv1alpha1.Experiment) error {
+	log := logf.FromContext(ctx)
+
+	name, profile, err := r.resolveAnalyzerProfile(ctx, exp)
+	if errors.IsNotFound(err) {
+		log.Info("Skipping AI analysis — analyzer profile not found", "profile", name)
+		skipAnalysis(exp, "ProfileNotFound", fmt.Sprintf("AnalyzerProfile %q not found — analysis skipped", name))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get analyzer profile %s: %w", name, err)
+	}
+	if profile.Image == "" && r.AnalyzerImage == "" {
+		log.Info("Skipping AI analysis — analyzer profile has no image", "profile", name)
+		skipAnalysis(exp, "NoImage", fmt.Sprintf("AnalyzerProfile %q sets no image and ANALYZER_IMAGE is unset — analysis skipped", name))
+		return nil
+	}
+
+	missing, err := r.missingAnalyzerSecrets(ctx, profile)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		log.Info("Skipping AI analysis — analyzer credentials missing", "profile", name, "missing", missing)
+		skipAnalysis(exp, "CredentialsMissing", fmt.Sprintf(
+			"Analyzer profile %q needs %s in %s — analysis skipped", name, strings.Join(missing, ", "), analyzerNamespace))
+		return nil
+	}
+
+	if err := r.createAnalysisJob(ctx, exp, name, profile); err != nil {
+		return fmt.Errorf("failed to create analysis Job: %w", err)
+	}
+	exp.Status.AnalysisJobName = analysisJobName(exp)
+	exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhasePending
+	return nil
+}
+
+// createAnalysisJob creates a Kubernetes Job that runs the experiment analyzer
+// described by profile to generate AI analysis of the experiment results.
+func (r *ExperimentReconciler) createAnalysisJob(ctx context.Context, exp *experimentsv1alpha1.Experiment, profileName string, profile *experimentsv1alpha1.AnalyzerProfileSpec) error {
+	log := logf.FromContext(ctx)
+
+	jobName := analysisJobName(exp)
+
+	// Check if job already exists
+	existing := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: analyzerNamespace}, existing); err == nil {
+		if existing.DeletionTimestamp != nil {
+			return fmt.Errorf("previous analysis Job %s is still being deleted", jobName)
+		}
+		log.Info("Analysis Job already exists", "job", jobName)
+		return nil
+	}
+
+	// Strip protocol prefix — analyzer script prepends http:// itself
+	s3Endpoint := strings.TrimPrefix(r.S3Endpoint, "http://")
+	s3Endpoint = strings.TrimPrefix(s3Endpoint, "https://")
+
+	image := profile.Image
+	if image == "" {
+		image = r.AnalyzerImage
+	}
+	backoffLimit := int32Ptr(1)
+	if profile.BackoffLimit != nil {
+		backoffLimit = profile.BackoffLimit
+	}
+
+	// The experiment context comes first; profile variables may add to it
+	// but not redirect the analyzer to another experiment or branch.
+	env := []corev1.EnvVar{
+		{
+			Name:  "EXPERIMENT_NAME",
+			Value: exp.Name,
+		},
+		{
+			Name:  "S3_ENDPOINT",
+			Value: s3Endpoint,
+		},
+		{
+			Name: "GITHUB_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "github-api-token",
+					},
+					Key:      "token",
+					Optional: boolPtr(true),
+				},
+			},
+		},
+		{
+			Name:  "GITHUB_REPO",
+			Value: r.GitHubRepo,
+		},
+		{
+			// Safety invariant: always set GITHUB_BRANCH to the experiment
+			// branch. The analyzer refuses to commit if unset, preventing
+			// accidental commits to main.
+			Name:  "GITHUB_BRANCH",
+			Value: publish.BranchName(exp.Name),
+		},
+		{
+			// The operator pushes the validated summary onto the
+			// results PR itself when a results publisher is configured.
+			Name:  "GITHUB_COMMIT",
+			Value: strconv.FormatBool(r.GitClient == nil),
+		},
+	}
+	reserved := map[string]bool{}
+	for _, e := range env {
+		reserved[e.Name] = true
+	}
+	for _, e := range profile.Env {
+		if !reserved[e.Name] {
+			env = append(env, e)
+		}
+	}
+
+	labels := map[string]string{
+		"app":        "experiment-analyzer",
+		"experiment": exp.Name,
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: analyzerNamespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				"experiments.illm.io/analyzer-profile": profileName,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            backoffLimit,
+			TTLSecondsAfterFinished: int32Ptr(3600),
+			// A rate-limited analyzer would hit the same limit on a pod
+			// retry; fail the Job so the operator re-queues it with backoff.
+			PodFailurePolicy: &batchv1.PodFailurePolicy{
+				Rules: []batchv1.PodFailurePolicyRule{{
+					Action: batchv1.PodFailurePolicyActionFailJob,
+					OnExitCodes: &batchv1.PodFailurePolicyOnExitCodesRequirement{
+						ContainerName: strPtr("analyzer"),
+						Operator:      batchv1.PodFailurePolicyOnExitCodesOpIn,
+						Values:        []int32{analyzerRateLimitExitCode},
+					},
+				}},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: profile.ServiceAccountName,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: boolPtr(true),
+						RunAsUser:    int64Ptr(1000),
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Volumes:        profile.Volumes,
+					InitContainers: profile.InitContainers,
+					Containers: []corev1.Container{
+						{
+							Name:    "analyzer",
+							Image:   image,
+							Command: profile.Command,
+							Args:    profile.Args,
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: boolPtr(false),
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{"ALL"},
+								},
+							},
+							Env:          env,
+							EnvFrom:      profile.EnvFrom,
+							VolumeMounts: profile.VolumeMounts,
+							Resources:    profile.Resources,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Note: Owner references are not set because the Experiment CR is in a
+	// different namespace (experiments) than the Job (experiment-operator-system).
+	// The TTLSecondsAfterFinished field handles cleanup instead.
+
+	if err := r.Create(ctx, job); err != nil {
+		return fmt.Errorf("create analysis Job %s: %w", jobName, err)
+	}
+
+	log.Info("Created analysis Job", "job", jobName, "image", image, "profile", profileName)
+	return nil
+}
@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+const (
+	// analysisProgressKey is the progress marker the analyzer rewrites as
+	// each pass starts and finishes, relative to the experiment's prefix.
+	analysisProgressKey = "analysis/progress.json"
+	// analysisLogKey receives the log tail of a failed analyzer pod.
+	analysisLogKey = "analysis/failure.log"
+	// analyzerLogTailLines is how many log lines of a failed analyzer are kept.
+	analyzerLogTailLines = 50
+	// maxConditionLogBytes bounds the log tail copied into a condition message.
+	maxConditionLogBytes = 2048
+)
+
+// analyzerSectionPasses are the analyzer passes whose outputs are analysis
+// sections, in the order the analyzer merges them. Pass 1 (the plan) only
+// feeds later passes.
+var analyzerSectionPasses = []string{
+	"pass_2_core", "pass_3_finops_secops", "pass_4_capabilities", "pass_5_body_synthesis",
+}
+
+// analysisSections are the analysis keys selected by analyzerConfig.sections;
+// the analyzer drops them unless requested.
+var analysisSections = []string{
+	experimentsv1alpha1.AnalysisSectionAbstract,
+	experimentsv1alpha1.AnalysisSectionTargetAnalysis,
+	experimentsv1alpha1.AnalysisSectionPerformanceAnalysis,
+	experimentsv1alpha1.AnalysisSectionMetricInsights,
+	experimentsv1alpha1.AnalysisSectionFinopsAnalysis,
+	experimentsv1alpha1.AnalysisSectionSecopsAnalysis,
+	experimentsv1alpha1.AnalysisSectionBody,
+	experimentsv1alpha1.AnalysisSectionCapabilitiesMatrix,
+	experimentsv1alpha1.AnalysisSectionFeedback,
+	experimentsv1alpha1.AnalysisSectionArchitectureDiagram,
+	"glossary",
+}
+
+// analysisProgress is the analyzer's progress marker.
+type analysisProgress struct {
+	UpdatedAt time.Time                                `json:"updatedAt"`
+	Passes    []experimentsv1alpha1.AnalysisPassStatus `json:"passes"`
+}
+
+// syncAnalysisProgress copies the analyzer's progress marker into
+// status.analysisPasses. A missing marker leaves the status alone.
+func (r *ExperimentReconciler) syncAnalysisProgress(ctx context.Context, exp *experimentsv1alpha1.Experiment) {
+	if r.Store == nil {
+		return
+	}
+	var p analysisProgress
+	err := storage.GetJSON(ctx, r.Store, exp.Name+"/"+analysisProgressKey, &p)
+	if stderrors.Is(err, storage.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to read analyzer progress — non-fatal")
+		return
+	}
+	exp.Status.AnalysisPasses = p.Passes
+}
+
+// passesIn returns the names of the passes in phase.
+func passesIn(passes []experimentsv1alpha1.AnalysisPassStatus, phase experimentsv1alpha1.AnalysisPhase) []string {
+	var names []string
+	for _, p := range passes {
+		if p.Phase == phase {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// analyzerLogTail returns the last lines of the analyzer container log from
+// the Job's newest pod, or "" if no pod or log is available.
+func (r *ExperimentReconciler) analyzerLogTail(ctx context.Context, job *batchv1.Job) string {
+	if r.KubeClient == nil {
+		return ""
+	}
+	log := logf.FromContext(ctx)
+	pods, err := r.KubeClient.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "job-name=" + job.Name,
+	})
+	if err != nil {
+		log.Error(err, "Failed to list analyzer pods — non-fatal", "job", job.Name)
+		return ""
+	}
+	if len(pods.Items) == 0 {
+		return ""
+	}
+	newest := pods.Items[0]
+	for _, p := range pods.Items[1:] {
+		if p.CreationTimestamp.After(newest.CreationTimestamp.Time) {
+			newest = p
+		}
+	}
+
+	lines := int64(analyzerLogTailLines)
+	raw, err := r.KubeClient.CoreV1().Pods(job.Namespace).GetLogs(newest.Name, &corev1.PodLogOptions{
+		Container: "analyzer",
+		TailLines: &lines,
+	}).DoRaw(ctx)
+	if err != nil {
+		log.Error(err, "Failed to read analyzer logs — non-fatal", "pod", newest.Name)
+		return ""
+	}
+	return strings.TrimRight(string(raw), "\n")
+}
+
+// recordAnalyzerFailure uploads a failed analyzer's log tail to the results
+// store and returns a condition message suffix quoting its end.
+func (r *ExperimentReconciler) recordAnalyzerFailure(ctx context.Context, exp *experimentsv1alpha1.Experiment, job *batchv1.Job) string {
+	tail := r.analyzerLogTail(ctx, job)
+	if tail == "" {
+		return ""
+	}
+	if r.Store != nil {
+		key := exp.Name + "/" + analysisLogKey
+		if err := r.Store.Put(ctx, key, strings.NewReader(tail+"\n"), "text/plain; charset=utf-8"); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to upload analyzer log — non-fatal", "key", key)
+		}
+	}
+	if len(tail) > maxConditionLogBytes {
+		tail = "..." + tail[len(tail)-maxConditionLogBytes:]
+	}
+	return "\nLast analyzer log lines:\n" + tail
+}
+
+// keepPartialAnalysis merges the outputs of analyzer passes that finished
+// before the Job failed into the stored summary, so a failed late pass does
+// not discard the sections of earlier ones. It returns the sections kept.
+// The merged summary is validated before it replaces the stored one, and is
+// pushed to the results PR like a complete analysis.
+func (r *ExperimentReconciler) keepPartialAnalysis(ctx context.Context, exp *experimentsv1alpha1.Experiment) ([]string, error) {
+	if r.Store == nil {
+		return nil, nil
+	}
+	summaryKey := exp.Name + "/summary.json"
+	var doc map[string]any
+	if err := storage.GetJSON(ctx, r.Store, summaryKey, &doc); err != nil {
+		return nil, fmt.Errorf("read summary: %w", err)
+	}
+	analysis, _ := doc["analysis"].(map[string]any)
+	if analysis == nil {
+		analysis = make(map[string]any)
+	}
+
+	// The summary's analyzerConfig is what the analyzer ran with; a re-run
+	// may have asked for other sections than the spec.
+	requested := resolveAnalyzerSections(exp)
+	if cfg, ok := doc["analyzerConfig"].(map[string]any); ok {
+		if secs, ok := cfg["sections"].([]any); ok {
+			requested = nil
+			for _, sec := range secs {
+				if name, ok := sec.(string); ok {
+					requested = append(requested, name)
+				}
+			}
+		}
+	}
+	var kept []string
+	for _, pass := range analyzerSectionPasses {
+		var out map[string]any
+		err := storage.GetJSON(ctx, r.Store, exp.Name+"/analysis/"+pass+".json", &out)
+		if stderrors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s output: %w", pass, err)
+		}
+		for k, v := range out {
+			if slices.Contains(analysisSections, k) {
+				if !slices.Contains(requested, k) {
+					continue
+				}
+				if !slices.Contains(kept, k) {
+					kept = append(kept, k)
+				}
+			}
+			analysis[k] = mergeJSON(analysis[k], v)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, nil
+	}
+	sort.Strings(kept)
+	doc["analysis"] = analysis
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal summary: %w", err)
+	}
+	if _, err := metrics.DecodeSummary(data); err != nil {
+		return nil, fmt.Errorf("partial analysis: %w", err)
+	}
+	if err := r.Store.Put(ctx, summaryKey, bytes.NewReader(data), "application/json"); err != nil {
+		return nil, fmt.Errorf("store summary: %w", err)
+	}
+	if r.GitClient != nil && resultsPROpen(exp) {
+		if err := r.GitClient.PushAnalysis(ctx, exp.Status.PublishBranch, exp.Name, data); err != nil {
+			return nil, fmt.Errorf("push partial analysis: %w", err)
+		}
+	}
+	return kept, nil
+}
+
+// mergeJSON merges src over dst like jq's `*`: objects merge recursively,
+// anything else in src replaces dst.
+func mergeJSON(dst, src any) any {
+	d, ok1 := dst.(map[string]any)
+	s, ok2 := src.(map[string]any)
+	if !ok1 || !ok2 {
+		return src
+	}
+	for k, v := range s {
+		d[k] = mergeJSON(d[k], v)
+	}
+	return d
+}
+
+// analysisFailureMessage builds the AnalysisComplete message for a failed or
+// vanished analyzer Job: which passes did not finish, which sections were
+// kept from the ones that did (or, for a re-run, that the previous analysis
+// was restored), and the end of the pod log when job is set.
+func (r *ExperimentReconciler) analysisFailureMessage(ctx context.Context, exp *experimentsv1alpha1.Experiment, job *batchv1.Job, msg string) string {
+	r.syncAnalysisProgress(ctx, exp)
+	unfinished := append(passesIn(exp.Status.AnalysisPasses, experimentsv1alpha1.AnalysisPhaseRunning),
+		passesIn(exp.Status.AnalysisPasses, experimentsv1alpha1.AnalysisPhaseFailed)...)
+	if len(unfinished) > 0 {
+		msg += fmt.Sprintf(" Unfinished passes: %s.", strings.Join(unfinished, ", "))
+	}
+
+	// A failed re-run leaves the analysis it was meant to replace; a first
+	// run keeps what its completed passes produced.
+	if isReanalysis(exp) {
+		if err := r.restorePreviousAnalysis(ctx, exp); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to restore previous analysis — non-fatal")
+		} else {
+			msg += " The previous analysis was restored."
+		}
+	} else {
+		kept, err := r.keepPartialAnalysis(ctx, exp)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to keep partial analysis — non-fatal")
+		}
+		if len(kept) > 0 {
+			msg += fmt.Sprintf(" Sections from completed passes were kept: %s.", strings.Join(kept, ", "))
+		}
+	}
+
+	if job != nil {
+		msg += r.recordAnalyzerFailure(ctx, exp, job)
+	}
+	return msg
+}
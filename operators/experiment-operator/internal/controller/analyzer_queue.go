@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+const (
+	// analyzerRateLimitExitCode is the analyzer's exit code when the AI API
+	// rate-limited it (EX_TEMPFAIL). The Job's pod failure policy fails the
+	// Job at once on it, and the operator re-queues the analysis.
+	analyzerRateLimitExitCode = 75
+	// maxAnalyzerBackoff caps the doubling rate-limit backoff.
+	maxAnalyzerBackoff = time.Hour
+)
+
+// queueAnalysis puts exp in the analyzer queue. checkAnalysisJob starts the
+// Job once admitAnalysis finds a free slot.
+func queueAnalysis(exp *experimentsv1alpha1.Experiment, msg string) {
+	now := metav1.Now()
+	exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseQueued
+	exp.Status.AnalysisQueuedAt = &now
+	exp.Status.AnalysisJobName = ""
+	exp.Status.AnalysisRetries = 0
+	exp.Status.AnalysisNotBefore = nil
+	apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
+		Type:               "AnalysisComplete",
+		Status:             metav1.ConditionFalse,
+		Reason:             "Queued",
+		ObservedGeneration: exp.Generation,
+		Message:            msg,
+	})
+}
+
+// analysisQueueLess orders queued experiments: higher spec.analyzerConfig.priority
+// first, then the earliest queued, then by name.
+func analysisQueueLess(a, b *experimentsv1alpha1.Experiment) bool {
+	if pa, pb := analysisPriority(a), analysisPriority(b); pa != pb {
+		return pa > pb
+	}
+	qa, qb := a.Status.AnalysisQueuedAt, b.Status.AnalysisQueuedAt
+	if qa != nil && qb != nil && !qa.Equal(qb) {
+		return qa.Before(qb)
+	}
+	if (qa == nil) != (qb == nil) {
+		return qa != nil
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+func analysisPriority(exp *experimentsv1alpha1.Experiment) int32 {
+	if exp.Spec.AnalyzerConfig == nil {
+		return 0
+	}
+	return exp.Spec.AnalyzerConfig.Priority
+}
+
+// analysisBackingOff reports whether a re-queued analysis still waits out its
+// rate-limit backoff.
+func analysisBackingOff(exp *experimentsv1alpha1.Experiment, now time.Time) bool {
+	nb := exp.Status.AnalysisNotBefore
+	return nb != nil && now.Before(nb.Time)
+}
+
+// admitAnalysis starts the analyzer Job of a queued experiment when fewer than
+// MaxConcurrentAnalyzers Jobs are pending or running and no queued experiment
+// ahead of it is ready to run. Experiments reconcile one at a time, so the
+// slot count read here is not raced by another admission.
+func (r *ExperimentReconciler) admitAnalysis(ctx context.Context, exp *experimentsv1alpha1.Experiment) error {
+	now := time.Now()
+	if analysisBackingOff(exp, now) {
+		return nil
+	}
+
+	if r.MaxConcurrentAnalyzers > 0 {
+		var list experimentsv1alpha1.ExperimentList
+		if err := r.List(ctx, &list); err != nil {
+			return fmt.Errorf("list experiments: %w", err)
+		}
+		active := 0
+		queue := []*experimentsv1alpha1.Experiment{exp}
+		for i := range list.Items {
+			other := &list.Items[i]
+			if other.Namespace == exp.Namespace && other.Name == exp.Name {
+				continue
+			}
+			switch other.Status.AnalysisPhase {
+			case experimentsv1alpha1.AnalysisPhasePending, experimentsv1alpha1.AnalysisPhaseRunning:
+				active++
+			case experimentsv1alpha1.AnalysisPhaseQueued:
+				if !analysisBackingOff(other, now) {
+					queue = append(queue, other)
+				}
+			}
+		}
+		sort.Slice(queue, func(i, j int) bool { return analysisQueueLess(queue[i], queue[j]) })
+		position := 0
+		for queue[position] != exp {
+			position++
+		}
+		if active+position >= r.MaxConcurrentAnalyzers {
+			apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
+				Type:               "AnalysisComplete",
+				Status:             metav1.ConditionFalse,
+				Reason:             "Queued",
+				ObservedGeneration: exp.Generation,
+				Message: fmt.Sprintf("Waiting for an analyzer slot: %d of %d in use, %d queued ahead",
+					active, r.MaxConcurrentAnalyzers, position),
+			})
+			return nil
+		}
+	}
+
+	exp.Status.AnalysisNotBefore = nil
+	return r.startAnalysis(ctx, exp)
+}
+
+// retryRateLimitedAnalysis re-queues an analysis whose Job failed on the
+// analyzer's rate-limit exit code, with a backoff doubling per retry. It
+// returns false, leaving the failure to the caller, for other failures or
+// once AnalyzerMaxRetries is used up.
+func (r *ExperimentReconciler) retryRateLimitedAnalysis(ctx context.Context, exp *experimentsv1alpha1.Experiment, job *batchv1.Job, cond batchv1.JobCondition) bool {
+	if cond.Reason != batchv1.JobReasonPodFailurePolicy || int(exp.Status.AnalysisRetries) >= r.AnalyzerMaxRetries {
+		return false
+	}
+	log := logf.FromContext(ctx)
+
+	// The retry reuses the Job name; createAnalysisJob waits for this one
+	// to be gone.
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "Failed to delete rate-limited analyzer Job — non-fatal", "job", job.Name)
+	}
+
+	exp.Status.AnalysisRetries++
+	backoff := r.AnalyzerRetryBackoff << (exp.Status.AnalysisRetries - 1)
+	if backoff > maxAnalyzerBackoff || backoff < 0 {
+		backoff = maxAnalyzerBackoff
+	}
+	notBefore := metav1.NewTime(time.Now().Add(backoff))
+	exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseQueued
+	exp.Status.AnalysisNotBefore = &notBefore
+	exp.Status.AnalysisJobName = ""
+	exp.Status.AnalysisPasses = nil
+	apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
+		Type:               "AnalysisComplete",
+		Status:             metav1.ConditionFalse,
+		Reason:             "RateLimited",
+		ObservedGeneration: exp.Generation,
+		Message: fmt.Sprintf("Analyzer hit the AI API rate limit; retry %d of %d after %s",
+			exp.Status.AnalysisRetries, r.AnalyzerMaxRetries, notBefore.UTC().Format(time.RFC3339)),
+	})
+	log.Info("Analyzer rate-limited — re-queued", "job", job.Name,
+		"retry", exp.Status.AnalysisRetries, "notBefore", notBefore.Time)
+	return true
+}
@@ -0,0 +1,490 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+func newAnalyzerReconciler(t *testing.T, objs ...client.Object) *ExperimentReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := experimentsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&experimentsv1alpha1.Experiment{}).Build()
+	return &ExperimentReconciler{
+		Client:        c,
+		Scheme:        scheme,
+		AnalyzerImage: "analyzer:latest",
+		S3Endpoint:    "http://s3:8333",
+	}
+}
+
+func TestStartAnalysisSkipsWithoutCredentials(t *testing.T) {
+	r := newAnalyzerReconciler(t)
+	exp := &experimentsv1alpha1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: "tsdb", Namespace: "experiments"}}
+
+	if err := r.startAnalysis(context.Background(), exp); err != nil {
+		t.Fatal(err)
+	}
+	if exp.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseSkipped {
+		t.Errorf("AnalysisPhase = %q, want Skipped", exp.Status.AnalysisPhase)
+	}
+	cond := apimeta.FindStatusCondition(exp.Status.Conditions, "AnalysisComplete")
+	if cond == nil || cond.Reason != "CredentialsMissing" {
+		t.Errorf("AnalysisComplete condition = %+v, want reason CredentialsMissing", cond)
+	}
+}
+
+func TestStartAnalysisUsesProfile(t *testing.T) {
+	profile := &experimentsv1alpha1.AnalyzerProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-llm"},
+		Spec: experimentsv1alpha1.AnalyzerProfileSpec{
+			Image: "ollama-analyzer:1",
+			Env: []corev1.EnvVar{
+				{Name: "MODEL_URL", Value: "http://ollama:11434"},
+				{Name: "EXPERIMENT_NAME", Value: "other"},
+			},
+			RequiredSecrets: []experimentsv1alpha1.SecretKeyRef{{Name: "llm-key", Key: "token"}},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "llm-key", Namespace: analyzerNamespace},
+		Data:       map[string][]byte{"token": []byte("t")},
+	}
+	r := newAnalyzerReconciler(t, profile, secret)
+	exp := &experimentsv1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "tsdb", Namespace: "experiments"},
+		Spec: experimentsv1alpha1.ExperimentSpec{
+			AnalyzerConfig: &experimentsv1alpha1.AnalyzerConfig{Profile: "local-llm"},
+		},
+	}
+	ctx := context.Background()
+
+	if err := r.startAnalysis(ctx, exp); err != nil {
+		t.Fatal(err)
+	}
+	if exp.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhasePending {
+		t.Fatalf("AnalysisPhase = %q, want Pending", exp.Status.AnalysisPhase)
+	}
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: exp.Status.AnalysisJobName, Namespace: analyzerNamespace}, job); err != nil {
+		t.Fatal(err)
+	}
+	c := job.Spec.Template.Spec.Containers[0]
+	if c.Image != "ollama-analyzer:1" {
+		t.Errorf("image = %q, want the profile image", c.Image)
+	}
+	env := map[string]string{}
+	for _, e := range c.Env {
+		if _, dup := env[e.Name]; dup {
+			t.Errorf("env %s set twice", e.Name)
+		}
+		env[e.Name] = e.Value
+	}
+	if env["MODEL_URL"] != "http://ollama:11434" || env["EXPERIMENT_NAME"] != "tsdb" {
+		t.Errorf("env = %v, want profile env plus the experiment context", env)
+	}
+	if len(job.Spec.Template.Spec.InitContainers) != 0 {
+		t.Errorf("profile without init containers got %d", len(job.Spec.Template.Spec.InitContainers))
+	}
+}
+
+func TestCheckAnalysisJobKeepsPartialAnalysis(t *testing.T) {
+	ctx := context.Background()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "analyze-tsdb", Namespace: analyzerNamespace},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+		}},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "analyze-tsdb-x1", Namespace: analyzerNamespace, Labels: map[string]string{"job-name": job.Name},
+	}}
+	r := newAnalyzerReconciler(t, job)
+	r.KubeClient = k8sfake.NewSimpleClientset(pod)
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Store = store
+
+	put := func(key string, v any) {
+		t.Helper()
+		if err := storage.PutJSON(ctx, store, key, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("tsdb/summary.json", &metrics.ExperimentSummary{
+		SchemaVersion: metrics.SummarySchemaVersion, Name: "tsdb", Namespace: "experiments", Phase: "Complete",
+		Analysis: &metrics.AnalysisResult{Model: "rules", MetricInsights: map[string]string{"cpu": "baseline", "mem": "baseline"}},
+	})
+	put("tsdb/analysis/pass_2_core.json", map[string]any{
+		"abstract":       "From pass 2.",
+		"metricInsights": map[string]string{"cpu": "from pass 2"},
+		"targetAnalysis": map[string]string{"overview": "not requested"},
+	})
+	put("tsdb/"+analysisProgressKey, analysisProgress{Passes: []experimentsv1alpha1.AnalysisPassStatus{
+		{Name: "pass_2_core", Phase: experimentsv1alpha1.AnalysisPhaseSucceeded, Sections: []string{"abstract", "metricInsights"}},
+		{Name: "pass_5_body_synthesis", Phase: experimentsv1alpha1.AnalysisPhaseRunning},
+	}})
+
+	exp := &experimentsv1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "tsdb", Namespace: "experiments"},
+		Spec: experimentsv1alpha1.ExperimentSpec{
+			AnalyzerConfig: &experimentsv1alpha1.AnalyzerConfig{Sections: []string{"abstract", "metricInsights", "body"}},
+		},
+		Status: experimentsv1alpha1.ExperimentStatus{AnalysisJobName: job.Name},
+	}
+	r.checkAnalysisJob(ctx, exp)
+
+	if exp.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseFailed {
+		t.Errorf("AnalysisPhase = %q, want Failed", exp.Status.AnalysisPhase)
+	}
+	if len(exp.Status.AnalysisPasses) != 2 {
+		t.Errorf("AnalysisPasses = %+v, want the progress marker's passes", exp.Status.AnalysisPasses)
+	}
+	cond := apimeta.FindStatusCondition(exp.Status.Conditions, "AnalysisComplete")
+	for _, want := range []string{"BackoffLimitExceeded", "Unfinished passes: pass_5_body_synthesis", "kept: abstract, metricInsights", "fake logs"} {
+		if cond == nil || !strings.Contains(cond.Message, want) {
+			t.Errorf("AnalysisComplete message = %q, want it to mention %q", cond.Message, want)
+		}
+	}
+
+	var got map[string]any
+	if err := storage.GetJSON(ctx, store, "tsdb/summary.json", &got); err != nil {
+		t.Fatal(err)
+	}
+	analysis := got["analysis"].(map[string]any)
+	insights := analysis["metricInsights"].(map[string]any)
+	if analysis["abstract"] != "From pass 2." || insights["cpu"] != "from pass 2" || insights["mem"] != "baseline" {
+		t.Errorf("analysis = %v, want pass 2 merged over the baseline", analysis)
+	}
+	if _, ok := analysis["targetAnalysis"]; ok {
+		t.Error("unrequested section targetAnalysis was kept")
+	}
+
+	rc, err := store.Get(ctx, "tsdb/"+analysisLogKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if log, _ := io.ReadAll(rc); !strings.Contains(string(log), "fake logs") {
+		t.Errorf("uploaded log = %q", log)
+	}
+}
+
+func TestReconcileReanalyze(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "claude-auth", Namespace: analyzerNamespace},
+		Data:       map[string][]byte{"credentials.json": []byte("{}")},
+	}
+	exp := &experimentsv1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tsdb", Namespace: "experiments",
+			Annotations: map[string]string{experimentsv1alpha1.AnnotationReanalyze: "abstract, body"},
+		},
+		Spec: experimentsv1alpha1.ExperimentSpec{Publish: true},
+		Status: experimentsv1alpha1.ExperimentStatus{
+			Phase:            experimentsv1alpha1.PhaseComplete,
+			ResourcesCleaned: true,
+			AnalysisJobName:  "experiment-analyzer-tsdb",
+			AnalysisPhase:    experimentsv1alpha1.AnalysisPhaseSucceeded,
+			ReviewPhase:      experimentsv1alpha1.ReviewPhaseApproved,
+		},
+	}
+	r := newAnalyzerReconciler(t, exp, secret)
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Store = store
+	if err := storage.PutJSON(ctx, store, "tsdb/summary.json", &metrics.ExperimentSummary{
+		SchemaVersion: metrics.SummarySchemaVersion, Name: "tsdb", Namespace: "experiments", Phase: "Complete",
+		AnalyzerConfig: &metrics.AnalyzerConfigJSON{Sections: []string{"abstract"}},
+		Analysis:       &metrics.AnalysisResult{Model: "claude", Abstract: "Old prompt."},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.PutJSON(ctx, store, "tsdb/"+analysisProgressKey, analysisProgress{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.reconcileComplete(ctx, exp); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &experimentsv1alpha1.Experiment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "tsdb", Namespace: "experiments"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Annotations[experimentsv1alpha1.AnnotationReanalyze]; ok {
+		t.Error("reanalyze annotation was not removed")
+	}
+	if got.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseQueued {
+		t.Errorf("AnalysisPhase = %q, want Queued", got.Status.AnalysisPhase)
+	}
+
+	// The next reconcile admits the queued run, though review has ended.
+	if _, err := r.reconcileComplete(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Name: "tsdb", Namespace: "experiments"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhasePending || got.Status.AnalysisJobName != "experiment-analyzer-tsdb-r1" {
+		t.Errorf("analysis = %s/%s, want a new Pending Job", got.Status.AnalysisJobName, got.Status.AnalysisPhase)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Name: "experiment-analyzer-tsdb-r1", Namespace: analyzerNamespace}, &batchv1.Job{}); err != nil {
+		t.Errorf("reanalysis Job: %v", err)
+	}
+	hist := got.Status.AnalysisHistory
+	if len(hist) != 1 || hist[0].JobName != "experiment-analyzer-tsdb" || hist[0].Phase != experimentsv1alpha1.AnalysisPhaseSucceeded {
+		t.Fatalf("AnalysisHistory = %+v, want the previous run", hist)
+	}
+
+	var archived metrics.AnalysisResult
+	if err := storage.GetJSON(ctx, store, hist[0].ResultsKey, &archived); err != nil || archived.Abstract != "Old prompt." {
+		t.Errorf("archived analysis = %+v (%v)", archived, err)
+	}
+	var summary metrics.ExperimentSummary
+	if err := storage.GetJSON(ctx, store, "tsdb/summary.json", &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Analysis == nil || summary.Analysis.Model != "rules" {
+		t.Errorf("analysis = %+v, want the baseline", summary.Analysis)
+	}
+	if s := summary.AnalyzerConfig.Sections; strings.Join(s, ",") != "abstract,body" {
+		t.Errorf("sections = %v", s)
+	}
+	if _, err := store.Get(ctx, "tsdb/"+analysisProgressKey); err == nil {
+		t.Error("stale progress marker was kept")
+	}
+	var kept metrics.ExperimentSummary
+	if err := storage.GetJSON(ctx, store, "tsdb/"+analysisPreviousKey, &kept); err != nil || kept.Analysis == nil || kept.Analysis.Abstract != "Old prompt." {
+		t.Errorf("previous summary = %+v (%v), want the replaced analysis kept for restore", kept.Analysis, err)
+	}
+}
+
+func TestStartReanalysisKeepsUnknownFields(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ExperimentReconciler{Store: store}
+	exp := &experimentsv1alpha1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: "tsdb"}}
+	exp.Status.AnalysisJobName = "experiment-analyzer-tsdb"
+	exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseSucceeded
+	summary := `{"schemaVersion":1,"name":"tsdb","phase":"Complete",
+		"analyzerConfig":{"sections":["abstract"]},
+		"analysis":{"model":"claude","abstract":"Old prompt.","siteNotes":"kept"},
+		"series":{"cpu_total":[1,2,3]}}`
+	if err := store.Put(ctx, "tsdb/summary.json", strings.NewReader(summary), "application/json"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.startReanalysis(ctx, exp, []string{"abstract", "body"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := storage.GetJSON(ctx, store, "tsdb/summary.json", &doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc["series"]; !ok {
+		t.Errorf("summary = %v, want the unmodelled series section kept", doc)
+	}
+	if a, _ := doc["analysis"].(map[string]any); a["model"] != "rules" {
+		t.Errorf("analysis = %v, want the baseline", doc["analysis"])
+	}
+	var archived map[string]any
+	if err := storage.GetJSON(ctx, store, exp.Status.AnalysisHistory[0].ResultsKey, &archived); err != nil {
+		t.Fatal(err)
+	}
+	if archived["siteNotes"] != "kept" {
+		t.Errorf("archived analysis = %v, want unmodelled fields kept", archived)
+	}
+}
+
+func TestAdmitAnalysisHonoursLimitAndPriority(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "claude-auth", Namespace: analyzerNamespace},
+		Data:       map[string][]byte{"credentials.json": []byte("{}")},
+	}
+	queuedAt := metav1.Now()
+	newExp := func(name string, phase experimentsv1alpha1.AnalysisPhase, priority int32) *experimentsv1alpha1.Experiment {
+		return &experimentsv1alpha1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "experiments"},
+			Spec: experimentsv1alpha1.ExperimentSpec{
+				AnalyzerConfig: &experimentsv1alpha1.AnalyzerConfig{Priority: priority},
+			},
+			Status: experimentsv1alpha1.ExperimentStatus{AnalysisPhase: phase, AnalysisQueuedAt: &queuedAt},
+		}
+	}
+	running := newExp("running", experimentsv1alpha1.AnalysisPhaseRunning, 0)
+	low := newExp("low", experimentsv1alpha1.AnalysisPhaseQueued, 0)
+	high := newExp("high", experimentsv1alpha1.AnalysisPhaseQueued, 5)
+	r := newAnalyzerReconciler(t, secret, running, low, high)
+	r.MaxConcurrentAnalyzers = 2
+
+	if err := r.admitAnalysis(ctx, low); err != nil {
+		t.Fatal(err)
+	}
+	if low.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseQueued {
+		t.Errorf("low priority AnalysisPhase = %q, want it to wait behind high", low.Status.AnalysisPhase)
+	}
+	if cond := apimeta.FindStatusCondition(low.Status.Conditions, "AnalysisComplete"); cond == nil || !strings.Contains(cond.Message, "1 queued ahead") {
+		t.Errorf("AnalysisComplete condition = %+v", cond)
+	}
+
+	if err := r.admitAnalysis(ctx, high); err != nil {
+		t.Fatal(err)
+	}
+	if high.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhasePending || high.Status.AnalysisJobName == "" {
+		t.Errorf("high priority analysis = %s/%s, want its Job started", high.Status.AnalysisJobName, high.Status.AnalysisPhase)
+	}
+}
+
+func TestCheckAnalysisJobRequeuesRateLimited(t *testing.T) {
+	ctx := context.Background()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "experiment-analyzer-tsdb", Namespace: analyzerNamespace},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+			Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: batchv1.JobReasonPodFailurePolicy,
+			Message: "Container analyzer for pod x failed with exit code 75 matching FailJob rule at index 0",
+		}}},
+	}
+	r := newAnalyzerReconciler(t, job)
+	r.AnalyzerRetryBackoff = time.Minute
+	r.AnalyzerMaxRetries = 2
+	exp := &experimentsv1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "tsdb", Namespace: "experiments"},
+		Status: experimentsv1alpha1.ExperimentStatus{
+			AnalysisJobName: job.Name,
+			AnalysisPhase:   experimentsv1alpha1.AnalysisPhaseRunning,
+			AnalysisRetries: 1,
+		},
+	}
+
+	r.checkAnalysisJob(ctx, exp)
+
+	if exp.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseQueued || exp.Status.AnalysisRetries != 2 {
+		t.Fatalf("analysis = %s after %d retries, want Queued after 2", exp.Status.AnalysisPhase, exp.Status.AnalysisRetries)
+	}
+	if nb := exp.Status.AnalysisNotBefore; nb == nil || time.Until(nb.Time) < time.Minute {
+		t.Errorf("AnalysisNotBefore = %v, want the doubled backoff", nb)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: analyzerNamespace}, &batchv1.Job{}); err == nil {
+		t.Error("rate-limited Job was not deleted")
+	}
+
+	// Once retries are used up the failure sticks.
+	job.ResourceVersion = ""
+	if err := r.Create(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	exp.Status.AnalysisJobName = job.Name
+	exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseRunning
+	r.checkAnalysisJob(ctx, exp)
+	if exp.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseFailed {
+		t.Errorf("AnalysisPhase = %q, want Failed after the last retry", exp.Status.AnalysisPhase)
+	}
+}
+
+func TestCheckAnalysisJobReanalysis(t *testing.T) {
+	ctx := context.Background()
+	previous := &metrics.ExperimentSummary{
+		SchemaVersion: metrics.SummarySchemaVersion, Name: "tsdb", Namespace: "experiments", Phase: "Complete",
+		Analysis: &metrics.AnalysisResult{Model: "claude", Abstract: "Good analysis."},
+	}
+	setup := func(t *testing.T, cond batchv1.JobConditionType, phase experimentsv1alpha1.ExperimentPhase, prev experimentsv1alpha1.AnalysisPhase) (*ExperimentReconciler, *experimentsv1alpha1.Experiment) {
+		t.Helper()
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "experiment-analyzer-tsdb-r1", Namespace: analyzerNamespace},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: cond, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+			}},
+		}
+		r := newAnalyzerReconciler(t, job)
+		r.KubeClient = k8sfake.NewSimpleClientset()
+		store, err := storage.NewLocalStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Store = store
+		if err := storage.PutJSON(ctx, store, "tsdb/"+analysisPreviousKey, previous); err != nil {
+			t.Fatal(err)
+		}
+		return r, &experimentsv1alpha1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{Name: "tsdb", Namespace: "experiments"},
+			Spec:       experimentsv1alpha1.ExperimentSpec{Publish: true},
+			Status: experimentsv1alpha1.ExperimentStatus{
+				Phase:           phase,
+				AnalysisJobName: job.Name,
+				AnalysisHistory: []experimentsv1alpha1.AnalysisRun{{JobName: "experiment-analyzer-tsdb", Phase: prev}},
+			},
+		}
+	}
+
+	t.Run("failed run keeps the phase and the previous analysis", func(t *testing.T) {
+		r, exp := setup(t, batchv1.JobFailed, experimentsv1alpha1.PhaseComplete, experimentsv1alpha1.AnalysisPhaseSucceeded)
+		if err := r.Store.Put(ctx, "tsdb/summary.json", strings.NewReader("{not json"), "application/json"); err != nil {
+			t.Fatal(err)
+		}
+		r.checkAnalysisJob(ctx, exp)
+
+		if exp.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseFailed {
+			t.Errorf("AnalysisPhase = %q, want Failed", exp.Status.AnalysisPhase)
+		}
+		if exp.Status.Phase != experimentsv1alpha1.PhaseComplete {
+			t.Errorf("Phase = %q, want a failed re-run to leave it Complete", exp.Status.Phase)
+		}
+		var got metrics.ExperimentSummary
+		if err := storage.GetJSON(ctx, r.Store, "tsdb/summary.json", &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Analysis == nil || got.Analysis.Abstract != "Good analysis." {
+			t.Errorf("analysis = %+v, want the previous analysis restored", got.Analysis)
+		}
+	})
+
+	t.Run("successful run completes an experiment its last analysis failed", func(t *testing.T) {
+		r, exp := setup(t, batchv1.JobComplete, experimentsv1alpha1.PhaseFailed, experimentsv1alpha1.AnalysisPhaseFailed)
+		if err := storage.PutJSON(ctx, r.Store, "tsdb/summary.json", previous); err != nil {
+			t.Fatal(err)
+		}
+		r.checkAnalysisJob(ctx, exp)
+
+		if exp.Status.AnalysisPhase != experimentsv1alpha1.AnalysisPhaseSucceeded {
+			t.Errorf("AnalysisPhase = %q, want Succeeded", exp.Status.AnalysisPhase)
+		}
+		if exp.Status.Phase != experimentsv1alpha1.PhaseComplete {
+			t.Errorf("Phase = %q, want Complete", exp.Status.Phase)
+		}
+	})
+}
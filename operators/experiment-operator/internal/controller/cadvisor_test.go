@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+func TestLoadCadvisorSamples(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ExperimentReconciler{Store: store}
+	exp := &experimentsv1alpha1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: "tsdb"}}
+	t0 := time.Unix(1700000000, 0).UTC()
+
+	for i := 0; i < 3; i++ {
+		ts := t0.Add(time.Duration(i) * time.Minute)
+		key := cadvisorSamplesPrefix(exp, "app") + ts.Format("20060102T150405.000Z") + ".json"
+		if err := storage.PutJSON(ctx, store, key, metrics.CadvisorSample{Timestamp: ts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Another target's samples share the prefix up to the name.
+	if err := storage.PutJSON(ctx, store, cadvisorSamplesPrefix(exp, "app-2")+"x.json", metrics.CadvisorSample{}); err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := r.loadCadvisorSamples(ctx, exp, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 || !samples[2].Timestamp.Equal(t0.Add(2*time.Minute)) {
+		t.Errorf("loadCadvisorSamples() = %v, want the three sample objects", samples)
+	}
+}
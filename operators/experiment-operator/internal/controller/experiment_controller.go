@@ -18,16 +18,18 @@ package controller
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -38,15 +40,21 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"golang.org/x/oauth2/google"
 
 	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
-	"github.com/illmadecoder/experiment-operator/internal/argocd"
+	"github.com/illmadecoder/experiment-operator/internal/analysis"
+	"github.com/illmadecoder/experiment-operator/internal/catalog"
 	"github.com/illmadecoder/experiment-operator/internal/crossplane"
-	ghclient "github.com/illmadecoder/experiment-operator/internal/github"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
+	"github.com/illmadecoder/experiment-operator/internal/export"
 	"github.com/illmadecoder/experiment-operator/internal/metrics"
+	"github.com/illmadecoder/experiment-operator/internal/publish"
 	"github.com/illmadecoder/experiment-operator/internal/storage"
 	"github.com/illmadecoder/experiment-operator/internal/workflow"
 )
@@ -60,14 +68,37 @@ type ExperimentReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
 	ClusterManager *crossplane.ClusterManager
-	ArgoCD         *argocd.Client
+	Deployer       deploy.Deployer
 	Workflow       *workflow.Manager
-	S3Client       *storage.Client
-	GitClient      *ghclient.Client
+	Store          storage.ResultsStore
+	Catalog        *catalog.Catalog
+	GitClient      *publish.Client
+	KubeClient     kubernetes.Interface
 	MetricsURL     string
 	AnalyzerImage  string
 	S3Endpoint     string
 	GitHubRepo     string
+
+	// AnalyzerProfile is the default AnalyzerProfile name; "" uses the
+	// built-in Claude profile.
+	AnalyzerProfile string
+
+	// MaxConcurrentAnalyzers caps pending and running analyzer Jobs across
+	// experiments; 0 means no limit.
+	MaxConcurrentAnalyzers int
+	// AnalyzerRetryBackoff is the first backoff after a rate-limited analyzer
+	// run; it doubles with every retry.
+	AnalyzerRetryBackoff time.Duration
+	// AnalyzerMaxRetries is how often a rate-limited analysis is re-queued
+	// before it fails.
+	AnalyzerMaxRetries int
+
+	// ReviewEvents, if set, enqueues Experiments whose results PR changed on
+	// GitHub (see NotifyPR) so the review gate reacts before its next poll.
+	ReviewEvents chan event.GenericEvent
+
+	// probes runs spec.probes batches in the background.
+	probes probeRunner
 }
 
 // +kubebuilder:rbac:groups=experiments.illm.io,resources=experiments,verbs=get;list;watch;create;update;patch;delete
@@ -80,8 +111,11 @@ type ExperimentReconciler struct {
 // +kubebuilder:rbac:groups=argoproj.io,resources=workflows,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=argoproj.io,resources=workflowtemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=experiments.illm.io,resources=components,verbs=get;list;watch
+// +kubebuilder:rbac:groups=experiments.illm.io,resources=analyzerprofiles,verbs=get;list;watch
 // +kubebuilder:rbac:groups=illm.io,resources=gkeclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -139,8 +173,8 @@ func (r *ExperimentReconciler) handleDeletion(ctx context.Context, exp *experime
 		log.Info("Cleaning up experiment resources")
 
 		// Close orphaned PR if review is still pending
-		if exp.Status.ReviewPhase == experimentsv1alpha1.ReviewPhasePending &&
-			r.GitClient != nil && exp.Status.PublishPRNumber > 0 {
+		if isReviewOpen(exp) &&
+			r.GitClient != nil && exp.Status.PublishBranch != "" {
 			if err := r.GitClient.ClosePR(ctx, exp.Status.PublishPRNumber, exp.Status.PublishBranch); err != nil {
 				log.Error(err, "Failed to close orphaned PR during deletion", "pr", exp.Status.PublishPRNumber)
 			} else {
@@ -177,8 +211,14 @@ func (r *ExperimentReconciler) handleDeletion(ctx context.Context, exp *experime
 func (r *ExperimentReconciler) reconcileComplete(ctx context.Context, exp *experimentsv1alpha1.Experiment) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// Terminal: review resolved (or never requested) — nothing more to do
-	if isReviewTerminal(exp) {
+	if _, ok := exp.Annotations[experimentsv1alpha1.AnnotationReanalyze]; ok &&
+		exp.Status.ResourcesCleaned && !isAnalysisActive(exp) {
+		return r.reconcileReanalyze(ctx, exp)
+	}
+
+	// Terminal: review resolved (or never requested) — nothing more to do,
+	// unless a re-run of the analyzer is still being tracked
+	if isReviewTerminal(exp) && !isAnalysisActive(exp) {
 		return ctrl.Result{}, nil
 	}
 
@@ -290,12 +330,16 @@ func (r *ExperimentReconciler) reconcileComplete(ctx context.Context, exp *exper
 		if !isAnalysisTerminal(exp) {
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 		}
+		r.syncCatalog(ctx, exp)
+		r.refreshResultsPR(ctx, exp)
 	}
 
 	// ── Stage 3: Review Gate (polls every 60s) ──────────────────────────
 	// Initialize review gate when analysis resolves
 	if isAnalysisTerminal(exp) && exp.Status.ReviewPhase == "" {
-		if exp.Spec.Publish && exp.Status.PublishPRNumber > 0 {
+		// Plain git publishes a branch without a PR number; the annotation still
+		// drives its review.
+		if exp.Spec.Publish && exp.Status.PublishBranch != "" {
 			exp.Status.ReviewPhase = experimentsv1alpha1.ReviewPhasePending
 			apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
 				Type:               "ReviewGate",
@@ -323,7 +367,7 @@ func (r *ExperimentReconciler) reconcileComplete(ctx context.Context, exp *exper
 	}
 
 	// Poll for review annotation
-	if exp.Status.ReviewPhase == experimentsv1alpha1.ReviewPhasePending {
+	if isReviewOpen(exp) {
 		result, err := r.reconcileReviewGate(ctx, exp)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -335,6 +379,7 @@ func (r *ExperimentReconciler) reconcileComplete(ctx context.Context, exp *exper
 
 	// Review resolved — remove finalizer
 	if isReviewTerminal(exp) && controllerutil.ContainsFinalizer(exp, experimentFinalizer) {
+		r.syncCatalog(ctx, exp)
 		controllerutil.RemoveFinalizer(exp, experimentFinalizer)
 		if err := r.Update(ctx, exp); err != nil {
 			log.Error(err, "Failed to remove finalizer after review complete")
@@ -347,27 +392,39 @@ func (r *ExperimentReconciler) reconcileComplete(ctx context.Context, exp *exper
 	return ctrl.Result{}, nil
 }
 
-// collectAndStoreResults gathers experiment metrics and uploads them to S3.
+// collectAndStoreResults gathers experiment metrics and uploads them to the
+// results store.
 func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *experimentsv1alpha1.Experiment) error {
 	log := logf.FromContext(ctx)
 
-	if r.S3Client == nil {
-		log.Info("S3 client not configured, skipping results collection")
+	if r.Store == nil {
+		log.Info("Results store not configured, skipping results collection")
 		exp.Status.ResultsURL = "disabled"
 		return nil
 	}
 
 	prefix := exp.Name
 
-	// Determine current quality gate iteration for $DURATION adjustment
+	// Quality gate remedies from earlier iterations: the window step selects the
+	// $DURATION and query window; the preferred source pins the metrics backend.
 	currentIteration := 0
-	if exp.Status.IterationStatus != nil {
-		currentIteration = exp.Status.IterationStatus.CurrentIteration
+	preferHub, preferTarget := false, false
+	if is := exp.Status.IterationStatus; is != nil {
+		currentIteration = is.WindowStep
+		preferHub = is.PreferredSource == "hub" && r.MetricsURL != ""
+		preferTarget = is.PreferredSource == "target"
 	}
 
 	// Build summary
 	summary := metrics.CollectSummary(exp)
 
+	// Resolve code snippets through the results publisher's forge.
+	if r.GitClient != nil && len(exp.Spec.CodeSnippets) > 0 {
+		summary.CodeSnippets = metrics.FetchCodeSnippets(exp.Spec.CodeSnippets, func(repo, path, ref string) (string, error) {
+			return r.GitClient.FetchFileContent(ctx, repo, path, ref)
+		})
+	}
+
 	// Phase 1: Try collecting metrics from target cluster monitoring stacks.
 	// Target clusters (GKE) often have Prometheus/VictoriaMetrics deployed as components.
 	// Combined discover+collect retry loop: re-discovers endpoints on each attempt so that
@@ -399,7 +456,11 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 				log.Error(err, "Failed to get kubeconfig for cadvisor metrics", "cluster", clusterName)
 				continue
 			}
-			cadvisorResult, err := metrics.CollectCadvisorMetrics(ctx, kubeconfig, exp)
+			samples, err := r.loadCadvisorSamples(ctx, exp, target.Name)
+			if err != nil {
+				log.Info("No recorded cadvisor samples, using the final scrape only", "cluster", clusterName, "reason", err.Error())
+			}
+			cadvisorResult, err := metrics.CollectCadvisorMetrics(ctx, kubeconfig, exp, samples)
 			if err != nil {
 				log.Error(err, "Cadvisor metrics collection failed", "cluster", clusterName)
 			} else if cadvisorResult != nil && !metrics.AllQueriesEmpty(cadvisorResult) {
@@ -415,16 +476,19 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 				var customMerged bool
 
 				// Try local Prometheus first — it has ServiceMonitor scrape data
-				endpoints, discErr := metrics.DiscoverMonitoringServices(ctx, kubeconfig, exp.Name)
+				// (unless a switchBackend remedy pinned collection to the hub)
+				var endpoints []metrics.MonitoringEndpoint
+				var discErr error
+				if !preferHub {
+					endpoints, discErr = metrics.DiscoverMonitoringServices(ctx, kubeconfig, exp.Name)
+				}
 				if discErr == nil && len(endpoints) > 0 {
 					log.Info("Discovered local Prometheus on tailscale target, querying custom metrics",
 						"cluster", clusterName, "endpoints", len(endpoints))
 					localResult, collectErr := metrics.CollectMetricsFromTarget(ctx, kubeconfig, endpoints, exp, currentIteration)
 					if collectErr == nil && localResult != nil && !metrics.AllQueriesEmpty(localResult) {
 						if metricsResult != nil {
-							for k, v := range localResult.Queries {
-								metricsResult.Queries[k] = v
-							}
+							metricsResult.Merge(localResult)
 						} else {
 							metricsResult = localResult
 						}
@@ -442,7 +506,7 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 				}
 
 				// Fall back to hub VM if local Prometheus didn't yield custom metrics
-				if !customMerged && r.MetricsURL != "" {
+				if !customMerged && !preferTarget && r.MetricsURL != "" {
 					log.Info("Falling back to hub VM for custom metrics",
 						"cluster", clusterName, "queryCount", len(exp.Spec.Metrics))
 					hubResult, hubErr := metrics.CollectMetricsSnapshot(ctx, r.MetricsURL, exp, currentIteration)
@@ -450,9 +514,7 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 						log.Error(hubErr, "Hub VM custom metrics query failed", "cluster", clusterName)
 					} else if hubResult != nil && !metrics.AllQueriesEmpty(hubResult) {
 						if metricsResult != nil {
-							for k, v := range hubResult.Queries {
-								metricsResult.Queries[k] = v
-							}
+							metricsResult.Merge(hubResult)
 							log.Info("Merged hub VM custom queries into result",
 								"cluster", clusterName, "hubQueries", len(hubResult.Queries))
 						} else {
@@ -467,6 +529,11 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 			continue
 		}
 
+		if preferHub {
+			log.Info("Quality gate prefers hub metrics, skipping target discovery", "cluster", clusterName)
+			continue
+		}
+
 		kubeconfig, err := r.ClusterManager.GetClusterKubeconfig(ctx, clusterName, target.Cluster.Type)
 		if err != nil {
 			log.Error(err, "Failed to get kubeconfig for target metrics", "cluster", clusterName)
@@ -517,7 +584,7 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 	}
 
 	// Phase 2: Fall back to hub VictoriaMetrics if target/cadvisor collection returned empty.
-	if metricsResult == nil || metrics.AllQueriesEmpty(metricsResult) {
+	if (metricsResult == nil || metrics.AllQueriesEmpty(metricsResult)) && !preferTarget {
 		hubResult, err := metrics.CollectMetricsSnapshot(ctx, r.MetricsURL, exp, currentIteration)
 		if err != nil {
 			log.Error(err, "Hub metrics snapshot failed — continuing without metrics")
@@ -528,6 +595,9 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 		}
 	}
 
+	// Detect steady state on range series so success criteria average the
+	// plateau rather than the warmup ramp and cooldown tail.
+	metrics.ApplySteadyState(metricsResult, exp.Spec.Metrics)
 	summary.Metrics = metricsResult
 
 	// ── Quality gate evaluation ──────────────────────────────────────────
@@ -561,12 +631,17 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 			verdict := metrics.EvaluateSuccessCriteria(summary)
 			if verdict == "insufficient" {
 				qr.Passed = false
-				qr.Remedy = "Metrics coverage passed but hypothesis criteria are insufficient — " +
-					"the specific metrics needed for success criteria returned no data. " +
-					"Will re-collect with shorter $DURATION."
 			}
 		}
 
+		// Diagnose why data is missing and pick a remedy that addresses it,
+		// rather than blindly shrinking the window.
+		if !qr.Passed {
+			qr.Diagnoses = metrics.DiagnoseMissingMetrics(ctx, metricsResult, qr.MissingMetrics, metricsResult.TimeRange.End)
+			qr.Action = metrics.ChooseRemedy(qr)
+			qr.Remedy = metrics.DescribeRemedy(qr)
+		}
+
 		exp.Status.IterationStatus.QualityResults = append(
 			exp.Status.IterationStatus.QualityResults, qr)
 
@@ -578,15 +653,32 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 				"metricsWithData", qr.MetricsWithData,
 				"total", qr.TotalMetrics)
 		} else if exp.Status.IterationStatus.CurrentIteration < maxIter {
-			// Re-collect with shorter $DURATION
+			// Re-collect with the remedy applied
 			exp.Status.IterationStatus.CurrentIteration++
 			exp.Status.IterationStatus.Phase = experimentsv1alpha1.IterationPhaseRecollecting
+			switch qr.Action {
+			case experimentsv1alpha1.RemedyShortenWindow:
+				exp.Status.IterationStatus.WindowStep++
+			case experimentsv1alpha1.RemedySwitchBackend:
+				if metricsResult.Backend() == "hub" {
+					exp.Status.IterationStatus.PreferredSource = "target"
+				} else {
+					exp.Status.IterationStatus.PreferredSource = "hub"
+				}
+			}
 			log.Info("Quality gate failed — scheduling re-collection",
 				"iteration", exp.Status.IterationStatus.CurrentIteration,
 				"coverage", fmt.Sprintf("%.0f%%", qr.Coverage*100),
 				"missing", qr.MissingMetrics,
+				"missingRequired", qr.MissingRequired,
+				"action", qr.Action,
 				"remedy", qr.Remedy)
-			// Don't upload or publish yet — return so reconcileComplete can requeue
+			// Keep this attempt's data for auditing, but don't promote or
+			// publish it — return so reconcileComplete can requeue
+			summary.IterationStatus = exp.Status.IterationStatus
+			if err := r.storeRun(ctx, exp, qr.Iteration, storage.RunOutcomeRecollecting, &qr, summary, metricsResult); err != nil {
+				log.Error(err, "Failed to store run artifacts — non-fatal", "iteration", qr.Iteration)
+			}
 			return nil
 		} else {
 			exp.Status.IterationStatus.Phase = experimentsv1alpha1.IterationPhaseExhausted
@@ -610,24 +702,48 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 	// Estimate cost
 	summary.CostEstimate = metrics.EstimateCost(exp)
 
-	// Upload summary
-	if err := r.S3Client.PutJSON(ctx, prefix+"/summary.json", summary); err != nil {
-		return fmt.Errorf("upload summary.json: %w", err)
+	// Rule-based baseline analysis for every experiment; the analyzer Job, if
+	// it runs, merges its sections over it.
+	summary.Analysis = analysis.Baseline(summary)
+
+	exhausted := exp.Status.IterationStatus != nil &&
+		exp.Status.IterationStatus.Phase == experimentsv1alpha1.IterationPhaseExhausted
+
+	// Resolve analyzer sections before storing so the stored summary is final.
+	// Default sections: publish=true with nil analyzerConfig uses defaults.
+	// Explicit empty sections (analyzerConfig.sections=[]) skips analysis.
+	analyzerSections := resolveAnalyzerSections(exp)
+	if exp.Spec.Publish && exp.Status.Phase == experimentsv1alpha1.PhaseComplete && !exhausted &&
+		r.analyzerEnabled(exp) && len(analyzerSections) > 0 && summary.AnalyzerConfig == nil {
+		summary.AnalyzerConfig = &metrics.AnalyzerConfigJSON{Sections: analyzerSections}
 	}
 
-	// Upload metrics snapshot separately for easier tooling consumption
-	if metricsResult != nil {
-		if err := r.S3Client.PutJSON(ctx, prefix+"/metrics-snapshot.json", metricsResult); err != nil {
-			log.Error(err, "Failed to upload metrics-snapshot.json — non-fatal")
+	// Store this attempt as an immutable run and promote it to the top-level
+	// summary.json / metrics-snapshot.json read by the site and analyzer.
+	iteration, outcome := 0, storage.RunOutcomeUngated
+	var quality *experimentsv1alpha1.QualityResult
+	if is := exp.Status.IterationStatus; is != nil && len(is.QualityResults) > 0 {
+		quality = &is.QualityResults[len(is.QualityResults)-1]
+		iteration = quality.Iteration
+		outcome = storage.RunOutcomePassed
+		if exhausted {
+			outcome = storage.RunOutcomeExhausted
 		}
 	}
+	if err := r.storeRun(ctx, exp, iteration, outcome, quality, summary, metricsResult); err != nil {
+		return fmt.Errorf("store run %d: %w", iteration, err)
+	}
+	if _, err := storage.PromoteRun(ctx, r.Store, prefix, iteration); err != nil {
+		return fmt.Errorf("promote run %d: %w", iteration, err)
+	}
 
-	exp.Status.ResultsURL = fmt.Sprintf("s3://experiment-results/%s/", prefix)
-	log.Info("Experiment results stored", "url", exp.Status.ResultsURL)
+	exp.Status.ResultsURL = r.Store.URL(prefix + "/")
+	log.Info("Experiment results stored", "url", exp.Status.ResultsURL, "finalIteration", iteration)
+	// Index once publish and analysis state below is settled.
+	defer r.indexResults(ctx, exp, summary)
 
 	// If quality gate exhausted, skip publish — results are in S3 only
-	if exp.Status.IterationStatus != nil &&
-		exp.Status.IterationStatus.Phase == experimentsv1alpha1.IterationPhaseExhausted {
+	if exhausted {
 		log.Info("Skipping publish — quality gate exhausted")
 		exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseSkipped
 		exp.Status.ReviewPhase = experimentsv1alpha1.ReviewPhaseSkipped
@@ -639,9 +755,9 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 	if exp.Spec.Publish && exp.Status.Phase == experimentsv1alpha1.PhaseComplete {
 		// Publish results via PR for review before going live on the benchmark site.
 		if r.GitClient != nil {
-			var publishOpts []ghclient.PublishOption
+			publishOpts := []publish.PublishOption{publish.WithReviewContext(reviewContext(exp))}
 			if exp.Spec.Title != "" {
-				publishOpts = append(publishOpts, ghclient.WithTitle(exp.Spec.Title))
+				publishOpts = append(publishOpts, publish.WithTitle(exp.Spec.Title))
 			}
 			branch, prNum, prURL, err := r.GitClient.PublishExperimentResult(ctx, exp.Name, summary, publishOpts...)
 			if err != nil {
@@ -651,45 +767,25 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 				exp.Status.PublishBranch = branch
 				exp.Status.PublishPRNumber = prNum
 				exp.Status.PublishPRURL = prURL
-				log.Info("Experiment results PR created", "pr", prURL, "branch", branch)
+				log.Info("Experiment results PR published", "pr", prURL, "branch", branch)
+				if err := r.GitClient.LabelPR(ctx, prNum, publish.PRLabels(summary)); err != nil {
+					log.Error(err, "Failed to label results PR — non-fatal", "pr", prNum)
+				}
+				if err := storage.MarkPublished(ctx, r.Store, prefix, prURL); err != nil {
+					log.Error(err, "Failed to mark results published in manifest — non-fatal")
+				}
 			}
 		}
 
 		// Launch AI analysis Job.
-		// Default sections: publish=true with nil analyzerConfig uses defaults.
-		// Explicit empty sections (analyzerConfig.sections=[]) skips analysis.
-		analyzerSections := resolveAnalyzerSections(exp)
-		if r.AnalyzerImage != "" && len(analyzerSections) > 0 {
-			// Ensure summary has the resolved sections for the analyzer to read
-			if summary.AnalyzerConfig == nil {
-				summary.AnalyzerConfig = &metrics.AnalyzerConfigJSON{Sections: analyzerSections}
-				// Re-upload summary with resolved sections
-				if uploadErr := r.S3Client.PutJSON(ctx, prefix+"/summary.json", summary); uploadErr != nil {
-					log.Error(uploadErr, "Failed to re-upload summary with default sections — non-fatal")
-				}
-			}
-
-			// Pre-validate Claude credentials before creating analyzer job.
-			secret := &corev1.Secret{}
-			secretKey := types.NamespacedName{Name: "claude-auth", Namespace: "experiment-operator-system"}
-			if err := r.Get(ctx, secretKey, secret); err != nil {
-				return fmt.Errorf("published experiment requires claude-auth secret for analysis: %w", err)
-			}
-			creds, ok := secret.Data["credentials.json"]
-			if !ok || len(creds) == 0 {
-				return fmt.Errorf("claude-auth secret missing or empty credentials.json key — analysis cannot run")
-			}
-
-			if err := r.createAnalysisJob(ctx, exp); err != nil {
-				return fmt.Errorf("failed to create analysis Job: %w", err)
-			}
-			jobName := fmt.Sprintf("experiment-analyzer-%s", exp.Name)
-			if len(jobName) > 63 {
-				jobName = jobName[:63]
-			}
-			exp.Status.AnalysisJobName = jobName
-			exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhasePending
-		} else if r.AnalyzerImage != "" {
+		if r.analyzerEnabled(exp) && len(analyzerSections) > 0 && r.S3Endpoint == "" {
+			// The analyzer Job reads summary.json over S3; other stores have no
+			// endpoint it can reach.
+			log.Info("Skipping AI analysis — results store is not S3", "experiment", exp.Name)
+			exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseSkipped
+		} else if r.analyzerEnabled(exp) && len(analyzerSections) > 0 {
+			queueAnalysis(exp, "Waiting for an analyzer slot")
+		} else if r.analyzerEnabled(exp) {
 			log.Info("Skipping AI analysis — no sections configured", "experiment", exp.Name)
 			exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseSkipped
 		} else {
@@ -708,181 +804,6 @@ func (r *ExperimentReconciler) collectAndStoreResults(ctx context.Context, exp *
 	return nil
 }
 
-// createAnalysisJob creates a Kubernetes Job that runs the experiment analyzer
-// (Claude Code CLI) to generate AI analysis of the experiment results.
-func (r *ExperimentReconciler) createAnalysisJob(ctx context.Context, exp *experimentsv1alpha1.Experiment) error {
-	log := logf.FromContext(ctx)
-
-	// Truncate job name to 63 chars (K8s name limit)
-	jobName := fmt.Sprintf("experiment-analyzer-%s", exp.Name)
-	if len(jobName) > 63 {
-		jobName = jobName[:63]
-	}
-
-	namespace := "experiment-operator-system"
-
-	// Check if job already exists
-	existing := &batchv1.Job{}
-	if err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: namespace}, existing); err == nil {
-		log.Info("Analysis Job already exists", "job", jobName)
-		return nil
-	}
-
-	// Strip protocol prefix — analyzer script prepends http:// itself
-	s3Endpoint := strings.TrimPrefix(r.S3Endpoint, "http://")
-	s3Endpoint = strings.TrimPrefix(s3Endpoint, "https://")
-
-	job := &batchv1.Job{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      jobName,
-			Namespace: namespace,
-			Labels: map[string]string{
-				"app":        "experiment-analyzer",
-				"experiment": exp.Name,
-			},
-		},
-		Spec: batchv1.JobSpec{
-			BackoffLimit:            int32Ptr(1),
-			TTLSecondsAfterFinished: int32Ptr(3600),
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app":        "experiment-analyzer",
-						"experiment": exp.Name,
-					},
-				},
-				Spec: corev1.PodSpec{
-					RestartPolicy: corev1.RestartPolicyNever,
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsNonRoot: boolPtr(true),
-						RunAsUser:    int64Ptr(1000),
-						SeccompProfile: &corev1.SeccompProfile{
-							Type: corev1.SeccompProfileTypeRuntimeDefault,
-						},
-					},
-					Volumes: []corev1.Volume{
-						{
-							Name: "claude-credentials",
-							VolumeSource: corev1.VolumeSource{
-								Secret: &corev1.SecretVolumeSource{
-									SecretName: "claude-auth",
-									Items: []corev1.KeyToPath{
-										{
-											Key:  "credentials.json",
-											Path: ".credentials.json",
-										},
-									},
-									DefaultMode: int32Ptr(0444),
-								},
-							},
-						},
-						{
-							Name: "claude-home",
-							VolumeSource: corev1.VolumeSource{
-								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-									ClaimName: "claude-credentials-pvc",
-								},
-							},
-						},
-					},
-					InitContainers: []corev1.Container{
-						{
-							Name:  "copy-credentials",
-							Image: "busybox:1.37",
-							Command: []string{
-								"sh", "-c",
-								"if [ ! -f /claude-home/.credentials.json ]; then cp /claude-secret/.credentials.json /claude-home/.credentials.json && chmod 600 /claude-home/.credentials.json && echo 'Seeded credentials from secret'; else echo 'Using existing credentials from PVC'; fi",
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "claude-credentials",
-									MountPath: "/claude-secret",
-									ReadOnly:  true,
-								},
-								{
-									Name:      "claude-home",
-									MountPath: "/claude-home",
-								},
-							},
-						},
-					},
-					Containers: []corev1.Container{
-						{
-							Name:  "analyzer",
-							Image: r.AnalyzerImage,
-							SecurityContext: &corev1.SecurityContext{
-								AllowPrivilegeEscalation: boolPtr(false),
-								Capabilities: &corev1.Capabilities{
-									Drop: []corev1.Capability{"ALL"},
-								},
-							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "EXPERIMENT_NAME",
-									Value: exp.Name,
-								},
-								{
-									Name:  "S3_ENDPOINT",
-									Value: s3Endpoint,
-								},
-								{
-									Name: "GITHUB_TOKEN",
-									ValueFrom: &corev1.EnvVarSource{
-										SecretKeyRef: &corev1.SecretKeySelector{
-											LocalObjectReference: corev1.LocalObjectReference{
-												Name: "github-api-token",
-											},
-											Key:      "token",
-											Optional: boolPtr(true),
-										},
-									},
-								},
-								{
-									Name:  "GITHUB_REPO",
-									Value: r.GitHubRepo,
-								},
-								{
-									// Safety invariant: always set GITHUB_BRANCH to the experiment
-									// branch. The analyzer refuses to commit if unset, preventing
-									// accidental commits to main.
-									Name:  "GITHUB_BRANCH",
-									Value: fmt.Sprintf("experiment/%s", exp.Name),
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "claude-home",
-									MountPath: "/home/node/.claude",
-								},
-							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("100m"),
-									corev1.ResourceMemory: resource.MustParse("256Mi"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceMemory: resource.MustParse("512Mi"),
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// Note: Owner references are not set because the Experiment CR is in a
-	// different namespace (experiments) than the Job (experiment-operator-system).
-	// The TTLSecondsAfterFinished field handles cleanup instead.
-
-	if err := r.Create(ctx, job); err != nil {
-		return fmt.Errorf("create analysis Job %s: %w", jobName, err)
-	}
-
-	log.Info("Created analysis Job", "job", jobName, "image", r.AnalyzerImage)
-	return nil
-}
-
 // isAnalysisTerminal returns true if the analysis phase is in a terminal state.
 func isAnalysisTerminal(exp *experimentsv1alpha1.Experiment) bool {
 	switch exp.Status.AnalysisPhase {
@@ -894,6 +815,24 @@ func isAnalysisTerminal(exp *experimentsv1alpha1.Experiment) bool {
 	return false
 }
 
+// isAnalysisActive reports whether an analysis is queued, or its analyzer Job
+// pending or running.
+func isAnalysisActive(exp *experimentsv1alpha1.Experiment) bool {
+	switch exp.Status.AnalysisPhase {
+	case experimentsv1alpha1.AnalysisPhaseQueued,
+		experimentsv1alpha1.AnalysisPhasePending,
+		experimentsv1alpha1.AnalysisPhaseRunning:
+		return true
+	}
+	return false
+}
+
+// isReviewOpen reports whether the review gate is waiting on a decision.
+func isReviewOpen(exp *experimentsv1alpha1.Experiment) bool {
+	return exp.Status.ReviewPhase == experimentsv1alpha1.ReviewPhasePending ||
+		exp.Status.ReviewPhase == experimentsv1alpha1.ReviewPhaseChangesRequested
+}
+
 // isReviewTerminal returns true if the review phase is in a terminal state.
 func isReviewTerminal(exp *experimentsv1alpha1.Experiment) bool {
 	switch exp.Status.ReviewPhase {
@@ -915,7 +854,7 @@ func (r *ExperimentReconciler) reconcileReviewGate(ctx context.Context, exp *exp
 	switch annotation {
 	case "approved":
 		log.Info("Review approved — merging PR", "pr", exp.Status.PublishPRNumber)
-		if r.GitClient != nil && exp.Status.PublishPRNumber > 0 {
+		if r.GitClient != nil && exp.Status.PublishBranch != "" {
 			if err := r.GitClient.MergePR(ctx, exp.Status.PublishPRNumber, exp.Status.PublishBranch); err != nil {
 				log.Error(err, "Failed to merge PR — will retry", "pr", exp.Status.PublishPRNumber)
 				apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
@@ -946,7 +885,7 @@ func (r *ExperimentReconciler) reconcileReviewGate(ctx context.Context, exp *exp
 
 	case "rejected":
 		log.Info("Review rejected — closing PR", "pr", exp.Status.PublishPRNumber)
-		if r.GitClient != nil && exp.Status.PublishPRNumber > 0 {
+		if r.GitClient != nil && exp.Status.PublishBranch != "" {
 			if err := r.GitClient.ClosePR(ctx, exp.Status.PublishPRNumber, exp.Status.PublishBranch); err != nil {
 				log.Error(err, "Failed to close PR — will retry", "pr", exp.Status.PublishPRNumber)
 				apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
@@ -976,15 +915,105 @@ func (r *ExperimentReconciler) reconcileReviewGate(ctx context.Context, exp *exp
 		return ctrl.Result{}, nil
 
 	default:
-		// No annotation or unrecognized value — keep waiting
+		// No annotation or unrecognized value — follow the results PR
+		return r.syncReviewFromPR(ctx, exp)
+	}
+}
+
+// syncReviewFromPR moves the review gate along with the results PR (or merge
+// request) on whichever forge publishes results: merged means approved,
+// closed means rejected, and an outstanding "changes requested" review parks
+// the gate in ChangesRequested until it is dismissed or superseded by an
+// approval.
+func (r *ExperimentReconciler) syncReviewFromPR(ctx context.Context, exp *experimentsv1alpha1.Experiment) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	if r.GitClient == nil || exp.Status.PublishPRNumber == 0 {
+		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+	}
+	pr, err := r.GitClient.GetPRStatus(ctx, exp.Status.PublishPRNumber)
+	if err != nil {
+		log.Error(err, "Failed to read PR state — will retry", "pr", exp.Status.PublishPRNumber)
+		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+	}
+
+	phase := experimentsv1alpha1.ReviewPhasePending
+	cond := metav1.Condition{
+		Type:               "ReviewGate",
+		Status:             metav1.ConditionFalse,
+		Reason:             "AwaitingReview",
+		ObservedGeneration: exp.Generation,
+		Message: fmt.Sprintf(
+			"Human review required. Approve: kubectl annotate experiment %s -n %s %s=approved",
+			exp.Name, exp.Namespace, experimentsv1alpha1.AnnotationReview),
+	}
+	switch {
+	case pr.Merged:
+		phase = experimentsv1alpha1.ReviewPhaseApproved
+		cond.Status, cond.Reason = metav1.ConditionTrue, "ResultsMerged"
+		cond.Message = fmt.Sprintf("Results PR #%d merged", pr.Number)
+	case !pr.Open:
+		phase = experimentsv1alpha1.ReviewPhaseRejected
+		cond.Status, cond.Reason = metav1.ConditionTrue, "ResultsClosed"
+		cond.Message = fmt.Sprintf("Results PR #%d closed without merging", pr.Number)
+	case len(pr.ChangesRequestedBy) > 0:
+		phase = experimentsv1alpha1.ReviewPhaseChangesRequested
+		cond.Reason = "ChangesRequested"
+		cond.Message = fmt.Sprintf("Changes requested on PR #%d by %s",
+			pr.Number, strings.Join(pr.ChangesRequestedBy, ", "))
+	}
+
+	if phase == exp.Status.ReviewPhase {
 		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
 	}
+	log.Info("Review gate follows PR state", "pr", pr.Number, "from", exp.Status.ReviewPhase, "to", phase)
+	exp.Status.ReviewPhase = phase
+	apimeta.SetStatusCondition(&exp.Status.Conditions, cond)
+	if err := r.Status().Update(ctx, exp); err != nil {
+		return ctrl.Result{}, err
+	}
+	if isReviewTerminal(exp) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+}
+
+// NotifyPR enqueues the Experiment whose results PR is prNumber. It is the
+// GitHub webhook's callback; unknown PRs are ignored.
+func (r *ExperimentReconciler) NotifyPR(ctx context.Context, prNumber int) {
+	if r.ReviewEvents == nil {
+		return
+	}
+	log := logf.FromContext(ctx)
+	var list experimentsv1alpha1.ExperimentList
+	if err := r.List(ctx, &list); err != nil {
+		log.Error(err, "Failed to list experiments for PR event", "pr", prNumber)
+		return
+	}
+	for i := range list.Items {
+		exp := &list.Items[i]
+		if exp.Status.PublishPRNumber != prNumber || !isReviewOpen(exp) {
+			continue
+		}
+		select {
+		case r.ReviewEvents <- event.GenericEvent{Object: exp}:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 // checkAnalysisJob looks up the analyzer Job and maps its status to AnalysisPhase.
 func (r *ExperimentReconciler) checkAnalysisJob(ctx context.Context, exp *experimentsv1alpha1.Experiment) {
 	log := logf.FromContext(ctx)
 
+	if exp.Status.AnalysisPhase == experimentsv1alpha1.AnalysisPhaseQueued {
+		if err := r.admitAnalysis(ctx, exp); err != nil {
+			// Transient — stay queued and try again next poll
+			log.Error(err, "Failed to start queued analysis")
+		}
+		return
+	}
+
 	if exp.Status.AnalysisJobName == "" {
 		exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseSkipped
 		return
@@ -993,7 +1022,7 @@ func (r *ExperimentReconciler) checkAnalysisJob(ctx context.Context, exp *experi
 	job := &batchv1.Job{}
 	err := r.Get(ctx, types.NamespacedName{
 		Name:      exp.Status.AnalysisJobName,
-		Namespace: "experiment-operator-system",
+		Namespace: analyzerNamespace,
 	}, job)
 
 	if errors.IsNotFound(err) {
@@ -1005,10 +1034,11 @@ func (r *ExperimentReconciler) checkAnalysisJob(ctx context.Context, exp *experi
 			Status:             metav1.ConditionFalse,
 			Reason:             "JobNotFound",
 			ObservedGeneration: exp.Generation,
-			Message:            "Analyzer Job was deleted (TTL) before completion could be verified",
+			Message:            r.analysisFailureMessage(ctx, exp, nil, "Analyzer Job was deleted (TTL) before completion could be verified."),
 		})
-		// Published experiments should not appear Complete when analysis failed
-		if exp.Spec.Publish && exp.Status.Phase == experimentsv1alpha1.PhaseComplete {
+		// Published experiments should not appear Complete when analysis
+		// failed; a failed re-run keeps the previous analysis, and the phase.
+		if exp.Spec.Publish && exp.Status.Phase == experimentsv1alpha1.PhaseComplete && !isReanalysis(exp) {
 			exp.Status.Phase = experimentsv1alpha1.PhaseFailed
 		}
 		return
@@ -1019,10 +1049,43 @@ func (r *ExperimentReconciler) checkAnalysisJob(ctx context.Context, exp *experi
 		log.Error(err, "Failed to get analyzer Job", "job", exp.Status.AnalysisJobName)
 		return
 	}
+	r.syncAnalysisProgress(ctx, exp)
 
 	// Map Job status
 	for _, cond := range job.Status.Conditions {
 		if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
+			invalid, err := r.verifyAnalyzedSummary(ctx, exp)
+			if err != nil {
+				// Transient — leave the phase alone and check again next poll
+				log.Error(err, "Failed to verify analyzer output", "job", exp.Status.AnalysisJobName)
+				return
+			}
+			if invalid != nil {
+				msg := invalid.Error()
+				if len(msg) > 1024 {
+					msg = msg[:1024] + "..."
+				}
+				restored := "pre-analysis results"
+				if isReanalysis(exp) {
+					restored = "previous analysis"
+				}
+				exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseFailed
+				apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
+					Type:               "AnalysisComplete",
+					Status:             metav1.ConditionFalse,
+					Reason:             "InvalidOutput",
+					ObservedGeneration: exp.Generation,
+					Message:            "Analyzer output failed schema validation; " + restored + " restored: " + msg,
+				})
+				if exp.Spec.Publish && exp.Status.Phase == experimentsv1alpha1.PhaseComplete && !isReanalysis(exp) {
+					exp.Status.Phase = experimentsv1alpha1.PhaseFailed
+				}
+				log.Info("Analyzer output invalid — restored "+restored, "job", exp.Status.AnalysisJobName, "problems", msg)
+				return
+			}
+			if failedByAnalysis(exp) {
+				exp.Status.Phase = experimentsv1alpha1.PhaseComplete
+			}
 			exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseSucceeded
 			apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
 				Type:               "AnalysisComplete",
@@ -1035,16 +1098,20 @@ func (r *ExperimentReconciler) checkAnalysisJob(ctx context.Context, exp *experi
 			return
 		}
 		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			if r.retryRateLimitedAnalysis(ctx, exp, job, cond) {
+				return
+			}
 			exp.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseFailed
 			apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
 				Type:               "AnalysisComplete",
 				Status:             metav1.ConditionFalse,
 				Reason:             "Failed",
 				ObservedGeneration: exp.Generation,
-				Message:            fmt.Sprintf("Analyzer Job failed: %s", cond.Message),
+				Message:            r.analysisFailureMessage(ctx, exp, job, fmt.Sprintf("Analyzer Job failed: %s.", cond.Message)),
 			})
-			// Published experiments should not appear Complete when analysis failed
-			if exp.Spec.Publish && exp.Status.Phase == experimentsv1alpha1.PhaseComplete {
+			// Published experiments should not appear Complete when analysis
+			// failed; a failed re-run keeps the previous analysis, and the phase.
+			if exp.Spec.Publish && exp.Status.Phase == experimentsv1alpha1.PhaseComplete && !isReanalysis(exp) {
 				exp.Status.Phase = experimentsv1alpha1.PhaseFailed
 			}
 			log.Info("Analyzer Job failed", "job", exp.Status.AnalysisJobName, "message", cond.Message)
@@ -1065,17 +1132,7 @@ func (r *ExperimentReconciler) cleanupResources(ctx context.Context, exp *experi
 	log := logf.FromContext(ctx)
 	var clusterDeleteErr error
 
-	// Delete ArgoCD Applications (layered + legacy single-app)
-	for _, target := range exp.Spec.Targets {
-		// Delete all layer apps (infra, obs, workload)
-		r.ArgoCD.AppManager.DeleteLayeredApplications(ctx, exp.Name, target.Name)
-		// Also try the legacy single app name
-		if err := r.ArgoCD.AppManager.DeleteApplication(ctx, exp.Name, target.Name); err != nil {
-			log.Error(err, "Failed to delete application", "target", target.Name)
-		}
-	}
-
-	// Unregister clusters from ArgoCD
+	// Undeploy components and unregister clusters
 	clusterNames := []string{}
 	for i := range exp.Spec.Targets {
 		if i >= len(exp.Status.Targets) || exp.Status.Targets[i].ClusterName == "" {
@@ -1083,8 +1140,8 @@ func (r *ExperimentReconciler) cleanupResources(ctx context.Context, exp *experi
 		}
 		clusterNames = append(clusterNames, exp.Status.Targets[i].ClusterName)
 	}
-	if err := r.ArgoCD.DeleteClusterAndApps(ctx, exp.Name, exp.Spec.Targets, clusterNames); err != nil {
-		log.Error(err, "Failed to unregister clusters from ArgoCD")
+	if err := r.Deployer.Undeploy(ctx, exp.Name, exp.Spec.Targets, clusterNames); err != nil {
+		log.Error(err, "Failed to undeploy targets")
 	}
 
 	// Delete clusters — this is the expensive resource, errors are fatal
@@ -1252,19 +1309,21 @@ func (r *ExperimentReconciler) reconcileProvisioning(ctx context.Context, exp *e
 		}
 
 		clusterName := exp.Status.Targets[i].ClusterName
-		endpoint := exp.Status.Targets[i].Endpoint
+		server := targetServer(target, exp.Status.Targets[i])
 
-		// For hub cluster, use in-cluster server; for GKE, prefix with https://
-		var server string
-		if target.Cluster.Type == "hub" {
-			server = "https://kubernetes.default.svc"
-		} else {
-			server = "https://" + endpoint
+		// Layered deployment: when components span more than one layer, deploy
+		// the first layer now and roll out the rest in reconcileReady, each
+		// once the layer before it is healthy.
+		layers, err := r.targetLayers(ctx, exp, target)
+		if err != nil {
+			log.Error(err, "Failed to plan deployment layers", "target", target.Name)
+			continue
 		}
+		layered := len(layers) > 1
 
-		// Get kubeconfig for non-hub clusters
+		// Register non-hub clusters with the deployer. The hub is reachable
+		// in-cluster via https://kubernetes.default.svc without registration.
 		var kubeconfig []byte
-		var err error
 		if target.Cluster.Type != "hub" {
 			kubeconfig, err = r.ClusterManager.GetClusterKubeconfig(ctx, clusterName, target.Cluster.Type)
 			if err != nil {
@@ -1272,55 +1331,40 @@ func (r *ExperimentReconciler) reconcileProvisioning(ctx context.Context, exp *e
 				// Use a placeholder kubeconfig for now
 				kubeconfig = []byte("# Placeholder kubeconfig")
 			}
-		} else {
-			// Hub cluster: ArgoCD already has in-cluster access via https://kubernetes.default.svc.
-			// Skip cluster secret registration — just create the ArgoCD Application.
-			if err := r.ArgoCD.AppManager.CreateApplication(ctx, exp.Name, target, server); err != nil {
-				log.Error(err, "Failed to create application for hub target", "target", target.Name)
-				continue
+
+			// Bootstrap RBAC on the target cluster (grant client cert user cluster-admin)
+			gcpKey := r.getGCPProviderKey(ctx)
+			if err := bootstrapClusterRBAC(ctx, kubeconfig, gcpKey); err != nil {
+				log.Error(err, "Failed to bootstrap RBAC on target cluster", "cluster", clusterName)
+				// Continue anyway — the cluster may already have RBAC configured
 			}
-			exp.Status.Targets[i].AppsCreated = true
-			log.Info("Created ArgoCD Application for hub target (no cluster registration needed)", "target", target.Name)
-			continue
-		}
 
-		// Bootstrap RBAC on the target cluster (grant client cert user cluster-admin)
-		gcpKey := r.getGCPProviderKey(ctx)
-		if err := bootstrapClusterRBAC(ctx, kubeconfig, gcpKey); err != nil {
-			log.Error(err, "Failed to bootstrap RBAC on target cluster", "cluster", clusterName)
-			// Continue anyway — the cluster may already have RBAC configured
+			if err := r.Deployer.RegisterCluster(ctx, clusterName, kubeconfig, server); err != nil {
+				log.Error(err, "Failed to register cluster", "cluster", clusterName)
+				continue
+			}
 		}
 
-		// Layered deployment: when observability is enabled, deploy infra+obs layers first
-		// and defer workload layer to reconcileReady (after infra+obs are healthy).
-		if target.Observability != nil && target.Observability.Enabled {
-			obsRefs := argocd.ObservabilityComponentRefs(target.Observability, exp.Name,
-				r.ArgoCD.AppManager.TailscaleClientID, r.ArgoCD.AppManager.TailscaleClientSecret)
-			classified := argocd.ClassifyComponents(target.Components, obsRefs)
-
-			if classified.HasLayers() {
-				deployedLayers, layerErr := r.ArgoCD.RegisterClusterAndCreateLayeredApps(
-					ctx, exp.Name, target, clusterName, kubeconfig, server, classified)
-				if layerErr != nil {
-					log.Error(layerErr, "Failed to create layered apps", "cluster", clusterName)
-					continue
-				}
-				exp.Status.Targets[i].DeployedLayers = deployedLayers
-				exp.Status.Targets[i].AppsCreated = true
-				log.Info("Created layered ArgoCD Applications (infra+obs), workload deferred",
-					"cluster", clusterName, "layers", deployedLayers)
+		if layered {
+			if err := r.Deployer.DeployLayer(ctx, exp.Name, target, server, layers[0]); err != nil {
+				log.Error(err, "Failed to deploy first layer", "target", target.Name, "layer", layers[0].Name)
 				continue
 			}
+			exp.Status.Targets[i].DeployedLayers = []string{layers[0].Name}
+			exp.Status.Targets[i].AppsCreated = true
+			log.Info("Deployed first layer, later layers deferred",
+				"target", target.Name, "layer", layers[0].Name, "layers", len(layers))
+			continue
 		}
 
-		// Non-layered path: single application with all components (no observability or no layers)
-		if err := r.ArgoCD.RegisterClusterAndCreateApps(ctx, exp.Name, target, clusterName, kubeconfig, server); err != nil {
-			log.Error(err, "Failed to register cluster and create apps", "cluster", clusterName)
+		// Non-layered path: all components at once
+		if err := r.Deployer.Deploy(ctx, exp.Name, target, server); err != nil {
+			log.Error(err, "Failed to deploy components", "target", target.Name)
 			continue
 		}
 
 		exp.Status.Targets[i].AppsCreated = true
-		log.Info("Registered cluster with ArgoCD and created applications", "cluster", clusterName)
+		log.Info("Deployed target components", "target", target.Name, "cluster", clusterName)
 	}
 
 	// Copy Tailscale OAuth secret to target clusters that need tailscale transport
@@ -1392,7 +1436,7 @@ func (r *ExperimentReconciler) reconcileProvisioning(ctx context.Context, exp *e
 }
 
 // reconcileReady handles the Ready phase - waits for apps to be healthy, then submits workflow.
-// For layered deployments, gates workload deployment on infra+obs health.
+// For layered deployments, gates each layer's deployment on the health of the layer before it.
 func (r *ExperimentReconciler) reconcileReady(ctx context.Context, exp *experimentsv1alpha1.Experiment) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 	log.Info("Reconciling Ready phase")
@@ -1405,74 +1449,26 @@ func (r *ExperimentReconciler) reconcileReady(ctx context.Context, exp *experime
 			// No components and no observability, nothing to check
 			continue
 		}
+		if r.syncComponentStatuses(ctx, exp, i, target) {
+			statusUpdated = true
+		}
+		if r.syncTargetHealth(ctx, exp, i, target) {
+			statusUpdated = true
+		}
 
-		// Layered deployment: check infra+obs health, then deploy workload
-		if hasLayer(exp.Status.Targets[i].DeployedLayers, argocd.LayerInfra) ||
-			hasLayer(exp.Status.Targets[i].DeployedLayers, argocd.LayerObs) {
-
-			// Check infra layer health
-			if hasLayer(exp.Status.Targets[i].DeployedLayers, argocd.LayerInfra) {
-				healthy, err := r.ArgoCD.AppManager.IsLayerHealthy(ctx, exp.Name, target.Name, argocd.LayerInfra)
-				if err != nil {
-					log.Error(err, "Failed to check infra layer health", "target", target.Name)
-					allHealthy = false
-					continue
-				}
-				if !healthy {
-					log.Info("Infra layer not healthy yet", "target", target.Name)
-					allHealthy = false
-					continue
-				}
-			}
-
-			// Check obs layer health
-			if hasLayer(exp.Status.Targets[i].DeployedLayers, argocd.LayerObs) {
-				healthy, err := r.ArgoCD.AppManager.IsLayerHealthy(ctx, exp.Name, target.Name, argocd.LayerObs)
-				if err != nil {
-					log.Error(err, "Failed to check obs layer health", "target", target.Name)
-					allHealthy = false
-					continue
-				}
-				if !healthy {
-					log.Info("Obs layer not healthy yet", "target", target.Name)
-					allHealthy = false
-					continue
-				}
-			}
-
-			// Infra+obs healthy — deploy workload layer if not yet deployed
-			if !hasLayer(exp.Status.Targets[i].DeployedLayers, argocd.LayerWorkload) {
-				log.Info("Infra+obs layers healthy, deploying workload layer", "target", target.Name)
-
-				endpoint := exp.Status.Targets[i].Endpoint
-				server := "https://" + endpoint
-
-				obsRefs := argocd.ObservabilityComponentRefs(target.Observability, exp.Name,
-					r.ArgoCD.AppManager.TailscaleClientID, r.ArgoCD.AppManager.TailscaleClientSecret)
-				classified := argocd.ClassifyComponents(target.Components, obsRefs)
-
-				if err := r.ArgoCD.AppManager.CreateLayeredApplication(
-					ctx, exp.Name, target, server, argocd.LayerWorkload, classified.Workload); err != nil {
-					log.Error(err, "Failed to create workload layer", "target", target.Name)
-					allHealthy = false
-					continue
-				}
-
-				exp.Status.Targets[i].DeployedLayers = append(exp.Status.Targets[i].DeployedLayers, argocd.LayerWorkload)
+		// Layered deployment: roll out the remaining layers in order, each
+		// once the layer before it is healthy
+		if len(exp.Status.Targets[i].DeployedLayers) > 0 {
+			ready, updated, err := r.rolloutLayers(ctx, exp, i, target)
+			if updated {
 				statusUpdated = true
-				allHealthy = false // Requeue to check workload health next cycle
-				continue
 			}
-
-			// Workload layer deployed — check its health
-			healthy, err := r.ArgoCD.AppManager.IsLayerHealthy(ctx, exp.Name, target.Name, argocd.LayerWorkload)
 			if err != nil {
-				log.Error(err, "Failed to check workload layer health", "target", target.Name)
+				log.Error(err, "Failed to roll out layers", "target", target.Name)
 				allHealthy = false
 				continue
 			}
-			if !healthy {
-				log.Info("Workload layer not healthy yet", "target", target.Name)
+			if !ready {
 				allHealthy = false
 				continue
 			}
@@ -1482,7 +1478,7 @@ func (r *ExperimentReconciler) reconcileReady(ctx context.Context, exp *experime
 		}
 
 		// Non-layered path: single application health check (original behavior)
-		healthy, err := r.ArgoCD.AppManager.IsApplicationHealthy(ctx, exp.Name, target.Name)
+		healthy, err := r.Deployer.Healthy(ctx, exp.Name, target.Name)
 		if err != nil {
 			log.Error(err, "Failed to check application health", "target", target.Name)
 			allHealthy = false
@@ -1495,14 +1491,7 @@ func (r *ExperimentReconciler) reconcileReady(ctx context.Context, exp *experime
 			continue
 		}
 
-		// Get deployed components
-		components, err := r.ArgoCD.AppManager.GetApplicationComponents(ctx, exp.Name, target.Name)
-		if err != nil {
-			log.Error(err, "Failed to get application components", "target", target.Name)
-		} else {
-			exp.Status.Targets[i].Components = components
-			log.Info("Application is healthy", "target", target.Name, "components", len(components))
-		}
+		log.Info("Application is healthy", "target", target.Name, "components", len(exp.Status.Targets[i].Components))
 	}
 
 	if !allHealthy {
@@ -1606,6 +1595,31 @@ func (r *ExperimentReconciler) reconcileRunning(ctx context.Context, exp *experi
 	if result.FinishedAt != nil {
 		exp.Status.WorkflowStatus.FinishedAt = result.FinishedAt
 	}
+	if len(exp.Spec.Workflow.PhaseWindows) > 0 {
+		exp.Status.WorkflowStatus.Phases = workflow.ResolvePhaseWindows(exp.Spec.Workflow.PhaseWindows, result.Nodes)
+	}
+
+	// Replay spec.probes against target backends while the workload is live.
+	// Once the workflow is terminal, any probes still waiting on their delay
+	// start now, and Running waits for them so every probe has a result
+	// before the phase moves on.
+	probesPending := false
+	if len(exp.Spec.Probes) > 0 {
+		probesPending = r.runDueProbes(ctx, exp, workflow.IsTerminal(result.Phase))
+	}
+
+	// Sample cadvisor on targets without a Prometheus so CPU rates and memory
+	// peaks can be computed at collection time.
+	r.sampleCadvisor(ctx, exp)
+
+	if workflow.IsTerminal(result.Phase) && probesPending {
+		log.Info("Workflow finished, waiting for probes", "workflow", exp.Status.WorkflowStatus.Name)
+		if err := r.Status().Update(ctx, exp); err != nil {
+			log.Error(err, "Failed to update workflow status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
 
 	// Check if workflow reached a terminal state
 	if workflow.IsTerminal(result.Phase) {
@@ -1638,6 +1652,350 @@ func (r *ExperimentReconciler) reconcileRunning(ctx context.Context, exp *experi
 	return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
 }
 
+// probeBatchTimeout bounds one background probe batch. Probes it cuts off
+// get a result recording that they did not finish, so a hung backend cannot
+// hold the experiment in Running.
+const probeBatchTimeout = 10 * time.Minute
+
+// runDueProbes collects the results of finished probe batches into status and
+// starts a background batch per target for each (probe, target) pair from
+// spec.probes that has no result yet and whose delay has elapsed since the
+// workflow started. Probes run off the reconcile path so a slow backend does
+// not tie up a reconcile worker. When force is set, delays are ignored. It
+// reports whether any batch for exp is still in flight.
+func (r *ExperimentReconciler) runDueProbes(ctx context.Context, exp *experimentsv1alpha1.Experiment, force bool) bool {
+	log := logf.FromContext(ctx)
+
+	for _, pr := range r.probes.take(exp.UID) {
+		if !hasProbeResult(exp.Status.ProbeResults, pr.Name, pr.Target) {
+			exp.Status.ProbeResults = append(exp.Status.ProbeResults, pr)
+		}
+	}
+
+	var startedAt time.Time
+	if exp.Status.WorkflowStatus != nil && exp.Status.WorkflowStatus.StartedAt != nil {
+		startedAt = exp.Status.WorkflowStatus.StartedAt.Time
+	}
+	if startedAt.IsZero() && !force {
+		return r.probes.pending(exp.UID)
+	}
+
+	for i, target := range exp.Spec.Targets {
+		if target.Cluster.Type == "hub" {
+			continue
+		}
+		if i >= len(exp.Status.Targets) || exp.Status.Targets[i].ClusterName == "" {
+			continue
+		}
+		if r.probes.running(exp.UID, target.Name) {
+			continue
+		}
+
+		var due []experimentsv1alpha1.ProbeSpec
+		for _, p := range exp.Spec.Probes {
+			if p.Target != "" && p.Target != target.Name {
+				continue
+			}
+			if hasProbeResult(exp.Status.ProbeResults, p.Name, target.Name) {
+				continue
+			}
+			if !force && time.Since(startedAt) < time.Duration(p.DelaySeconds)*time.Second {
+				continue
+			}
+			due = append(due, p)
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		clusterName := exp.Status.Targets[i].ClusterName
+		clusterType := target.Cluster.Type
+		targetName := target.Name
+		snapshot := exp.DeepCopy()
+		batchCtx := context.WithoutCancel(ctx)
+		log.Info("Starting probes", "target", targetName, "count", len(due))
+		r.probes.start(exp.UID, targetName, func() []experimentsv1alpha1.ProbeResult {
+			return r.runProbeBatch(batchCtx, snapshot, clusterName, clusterType, targetName, due)
+		})
+	}
+	return r.probes.pending(exp.UID)
+}
+
+// runProbeBatch runs probes against one target within probeBatchTimeout.
+// Errors reaching the cluster are logged and leave the probes without a
+// result, to be retried on a later reconcile.
+func (r *ExperimentReconciler) runProbeBatch(ctx context.Context, exp *experimentsv1alpha1.Experiment, clusterName, clusterType, targetName string, probes []experimentsv1alpha1.ProbeSpec) []experimentsv1alpha1.ProbeResult {
+	log := logf.FromContext(ctx)
+	ctx, cancel := context.WithTimeout(ctx, probeBatchTimeout)
+	defer cancel()
+
+	kubeconfig, err := r.ClusterManager.GetClusterKubeconfig(ctx, clusterName, clusterType)
+	if err != nil {
+		log.Error(err, "Failed to get kubeconfig for probes — non-fatal", "cluster", clusterName)
+		return nil
+	}
+	results, err := metrics.RunProbes(ctx, kubeconfig, exp, targetName, probes)
+	if err != nil {
+		log.Error(err, "Failed to run probes — non-fatal", "target", targetName)
+		return nil
+	}
+	if cut := len(probes) - len(results); cut > 0 {
+		log.Info("Probe batch timed out", "target", targetName, "unfinished", cut, "timeout", probeBatchTimeout)
+		for _, p := range probes[len(results):] {
+			results = append(results, metrics.FailedProbeResult(exp, targetName, p,
+				fmt.Sprintf("not finished within the probe batch timeout of %s", probeBatchTimeout)))
+		}
+	}
+	return results
+}
+
+// sampleCadvisor scrapes cadvisor on each tailscale-transport target whose
+// last sample is older than CadvisorSampleInterval and stores the sample as
+// its own object under the target's sample prefix, so each interval is one
+// small write. Errors are logged and retried on the next reconcile.
+func (r *ExperimentReconciler) sampleCadvisor(ctx context.Context, exp *experimentsv1alpha1.Experiment) {
+	log := logf.FromContext(ctx)
+
+	if r.Store == nil {
+		return
+	}
+
+	for i, target := range exp.Spec.Targets {
+		if target.Cluster.Type == "hub" {
+			continue
+		}
+		if target.Observability == nil || !target.Observability.Enabled ||
+			target.Observability.Transport != "tailscale" {
+			continue
+		}
+		if i >= len(exp.Status.Targets) || exp.Status.Targets[i].ClusterName == "" {
+			continue
+		}
+		ts := &exp.Status.Targets[i]
+		if ts.LastCadvisorSampleAt != nil && time.Since(ts.LastCadvisorSampleAt.Time) < metrics.CadvisorSampleInterval {
+			continue
+		}
+
+		kubeconfig, err := r.ClusterManager.GetClusterKubeconfig(ctx, ts.ClusterName, target.Cluster.Type)
+		if err != nil {
+			log.Error(err, "Failed to get kubeconfig for cadvisor sample — non-fatal", "cluster", ts.ClusterName)
+			continue
+		}
+		sample, err := metrics.ScrapeCadvisor(ctx, kubeconfig)
+		if err != nil {
+			log.Error(err, "Cadvisor sample failed — non-fatal", "cluster", ts.ClusterName)
+			continue
+		}
+
+		key := cadvisorSamplesPrefix(exp, target.Name) + sample.Timestamp.UTC().Format("20060102T150405.000Z") + ".json"
+		if err := storage.PutJSON(ctx, r.Store, key, sample); err != nil {
+			log.Error(err, "Failed to store cadvisor sample — non-fatal", "key", key)
+			continue
+		}
+		ts.CadvisorSamples++
+		ts.LastCadvisorSampleAt = &metav1.Time{Time: sample.Timestamp}
+	}
+}
+
+// storeRun writes a collection attempt's summary and metrics snapshot under
+// {exp}/runs/{iteration}/ and records it in the experiment's manifest. An
+// iteration that was already stored (e.g. a retry after a failed status
+// update) is left as first written. The summary is validated first so a
+// malformed one is never uploaded or published.
+func (r *ExperimentReconciler) storeRun(ctx context.Context, exp *experimentsv1alpha1.Experiment, iteration int, outcome string,
+	quality *experimentsv1alpha1.QualityResult, summary *metrics.ExperimentSummary, metricsResult *metrics.MetricsResult) error {
+	if err := metrics.ValidateSummary(summary); err != nil {
+		return err
+	}
+	data := map[string]any{"summary.json": summary}
+	if metricsResult != nil {
+		data["metrics-snapshot.json"] = metricsResult
+		// CSV, Parquet and OpenMetrics copies for notebooks and BI tools
+		files, err := export.Files(exp.Name, metricsResult, metricsTarget(exp))
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to render metrics exports — storing JSON only")
+		}
+		for _, f := range files {
+			data[f.Name] = storage.Raw{Body: f.Body, ContentType: f.ContentType}
+		}
+	}
+	entry := storage.RunEntry{Iteration: iteration, Outcome: outcome, Quality: quality}
+	_, err := storage.WriteRun(ctx, r.Store, exp.Name, entry, data)
+	if stderrors.Is(err, storage.ErrRunExists) {
+		logf.FromContext(ctx).Info("Run already stored, keeping original artifacts", "iteration", iteration)
+		return nil
+	}
+	return err
+}
+
+// metricsTarget names the target collected metrics belong to: the only
+// non-hub target, or "" when an experiment has several.
+func metricsTarget(exp *experimentsv1alpha1.Experiment) string {
+	name := ""
+	for _, t := range exp.Spec.Targets {
+		if t.Cluster.Type == "hub" {
+			continue
+		}
+		if name != "" {
+			return ""
+		}
+		name = t.Name
+	}
+	return name
+}
+
+// verifyAnalyzedSummary validates the summary.json the analyzer Job wrote
+// back. A valid one is pushed onto the results PR as a follow-up commit, as
+// written so sections ExperimentSummary does not model survive; a
+// malformed one is replaced by the promoted pre-analysis run, in the store and
+// on the publish branch, so it never reaches the site build. A malformed
+// reanalysis is replaced by the analysis it was meant to supersede instead.
+// It returns the validation problem, or err if the check itself failed.
+func (r *ExperimentReconciler) verifyAnalyzedSummary(ctx context.Context, exp *experimentsv1alpha1.Experiment) (invalid, err error) {
+	rc, err := r.Store.Get(ctx, exp.Name+"/summary.json")
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read analyzed summary: %w", err)
+	}
+	analyzed, invalid := metrics.DecodeSummary(data)
+	if invalid == nil {
+		if r.GitClient != nil && resultsPROpen(exp) {
+			if err := r.GitClient.PushAnalysis(ctx, exp.Status.PublishBranch, exp.Name, data); err != nil {
+				return nil, fmt.Errorf("push analyzed summary: %w", err)
+			}
+		}
+		if analyzed.Analysis != nil && r.Catalog != nil {
+			verdict := analyzed.Analysis.HypothesisVerdict
+			if err := r.Catalog.Update(ctx, exp.Name, func(e *catalog.Entry) { e.AnalysisVerdict = verdict }); err != nil {
+				logf.FromContext(ctx).Error(err, "Failed to record analysis verdict in results index — non-fatal")
+			}
+		}
+		return nil, nil
+	}
+
+	if isReanalysis(exp) {
+		if err := r.restorePreviousAnalysis(ctx, exp); err != nil {
+			return nil, err
+		}
+		return invalid, nil
+	}
+	m, err := storage.LoadManifest(ctx, r.Store, exp.Name)
+	if err != nil {
+		return nil, err
+	}
+	if m.FinalIteration == nil {
+		return nil, fmt.Errorf("restore summary: no promoted run in manifest")
+	}
+	if _, err := storage.PromoteRun(ctx, r.Store, exp.Name, *m.FinalIteration); err != nil {
+		return nil, fmt.Errorf("restore summary: %w", err)
+	}
+	if r.GitClient != nil && resultsPROpen(exp) {
+		var original metrics.ExperimentSummary
+		key := storage.RunPrefix(exp.Name, *m.FinalIteration) + "summary.json"
+		if err := storage.GetJSON(ctx, r.Store, key, &original); err != nil {
+			return nil, fmt.Errorf("restore summary: %w", err)
+		}
+		if err := r.GitClient.UpdateExperimentResult(ctx, exp.Status.PublishBranch, exp.Name, &original); err != nil {
+			return nil, fmt.Errorf("restore published summary: %w", err)
+		}
+	}
+	return invalid, nil
+}
+
+// indexResults records an experiment's promoted results in the
+// cross-experiment index. Failures are logged; the index is a convenience and
+// Backfill repairs missing entries on the next start.
+func (r *ExperimentReconciler) indexResults(ctx context.Context, exp *experimentsv1alpha1.Experiment, summary *metrics.ExperimentSummary) {
+	if r.Catalog == nil {
+		return
+	}
+	e := catalog.EntryFromSummary(summary)
+	e.ApplyStatus(exp)
+	if err := r.Catalog.Upsert(ctx, e); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to index experiment results — non-fatal")
+	}
+}
+
+// reviewContext is the experiment state shown next to its summary in the
+// results PR body.
+func reviewContext(exp *experimentsv1alpha1.Experiment) publish.ReviewContext {
+	return publish.ReviewContext{
+		ResultsURL:    exp.Status.ResultsURL,
+		AnalysisPhase: exp.Status.AnalysisPhase,
+		AnalysisJob:   exp.Status.AnalysisJobName,
+	}
+}
+
+// refreshResultsPR re-renders the results PR body and labels from the
+// current summary once analysis has resolved, so the verdict and analyzer
+// status reviewers see on the forge are final.
+func (r *ExperimentReconciler) refreshResultsPR(ctx context.Context, exp *experimentsv1alpha1.Experiment) {
+	if r.GitClient == nil || r.Store == nil || exp.Status.PublishPRNumber == 0 {
+		return
+	}
+	log := logf.FromContext(ctx)
+	var summary metrics.ExperimentSummary
+	if err := storage.GetJSON(ctx, r.Store, exp.Name+"/summary.json", &summary); err != nil {
+		log.Error(err, "Failed to load summary for results PR — non-fatal")
+		return
+	}
+	pr := exp.Status.PublishPRNumber
+	if err := r.GitClient.UpdatePRBody(ctx, pr, exp.Name, &summary, publish.WithReviewContext(reviewContext(exp))); err != nil {
+		log.Error(err, "Failed to update results PR body — non-fatal", "pr", pr)
+	}
+	if err := r.GitClient.LabelPR(ctx, pr, publish.PRLabels(&summary)); err != nil {
+		log.Error(err, "Failed to label results PR — non-fatal", "pr", pr)
+	}
+}
+
+// syncCatalog refreshes the index entry's phase, publish, analysis and review
+// state after the experiment moves on.
+func (r *ExperimentReconciler) syncCatalog(ctx context.Context, exp *experimentsv1alpha1.Experiment) {
+	if r.Catalog == nil {
+		return
+	}
+	if err := r.Catalog.Update(ctx, exp.Name, func(e *catalog.Entry) { e.ApplyStatus(exp) }); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update results index — non-fatal")
+	}
+}
+
+// cadvisorSamplesPrefix is the key prefix of a target's cadvisor samples,
+// one object per sample.
+func cadvisorSamplesPrefix(exp *experimentsv1alpha1.Experiment, target string) string {
+	return fmt.Sprintf("%s/cadvisor-samples/%s/", exp.Name, target)
+}
+
+// loadCadvisorSamples reads the cadvisor samples recorded for a target.
+func (r *ExperimentReconciler) loadCadvisorSamples(ctx context.Context, exp *experimentsv1alpha1.Experiment, target string) ([]metrics.CadvisorSample, error) {
+	objects, err := r.Store.List(ctx, cadvisorSamplesPrefix(exp, target))
+	if err != nil {
+		return nil, err
+	}
+	var samples []metrics.CadvisorSample
+	for _, obj := range objects {
+		var sample metrics.CadvisorSample
+		if err := storage.GetJSON(ctx, r.Store, obj.Key, &sample); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// hasProbeResult reports whether a result exists for the named probe on a target.
+func hasProbeResult(results []experimentsv1alpha1.ProbeResult, name, target string) bool {
+	for _, pr := range results {
+		if pr.Name == name && pr.Target == target {
+			return true
+		}
+	}
+	return false
+}
+
 // copyKubeconfigSecrets copies kubeconfig secrets from crossplane-system to experiments namespace
 // so that labctl can read them without needing access to crossplane-system.
 func (r *ExperimentReconciler) copyKubeconfigSecrets(ctx context.Context, exp *experimentsv1alpha1.Experiment) error {
@@ -1911,14 +2269,21 @@ func (r *ExperimentReconciler) areDependenciesHealthy(
 
 		// Check if dependency's ArgoCD apps are healthy
 		target := exp.Spec.Targets[depIdx]
-		if target.Observability != nil && target.Observability.Enabled &&
-			len(depStatus.DeployedLayers) > 0 {
-			// Layered: check all deployed layers
-			for _, layer := range depStatus.DeployedLayers {
-				healthy, err := r.ArgoCD.AppManager.IsLayerHealthy(ctx, exp.Name, depName, layer)
+		if len(depStatus.DeployedLayers) > 0 {
+			// Layered: every layer must be deployed and healthy
+			layers, err := r.targetLayers(ctx, exp, target)
+			if err != nil {
+				log.Error(err, "Failed to plan dependency layers", "depends", depName)
+				return false
+			}
+			for _, layer := range layers {
+				if !hasLayer(depStatus.DeployedLayers, layer.Name) {
+					return false
+				}
+				healthy, err := r.Deployer.LayerHealthy(ctx, exp.Name, depName, layer.Name)
 				if err != nil {
 					log.Error(err, "Failed to check dependency layer health",
-						"depends", depName, "layer", layer)
+						"depends", depName, "layer", layer.Name)
 					return false
 				}
 				if !healthy {
@@ -1927,7 +2292,7 @@ func (r *ExperimentReconciler) areDependenciesHealthy(
 			}
 		} else {
 			// Non-layered: single app check
-			healthy, err := r.ArgoCD.AppManager.IsApplicationHealthy(ctx, exp.Name, depName)
+			healthy, err := r.Deployer.Healthy(ctx, exp.Name, depName)
 			if err != nil {
 				log.Error(err, "Failed to check dependency app health", "depends", depName)
 				return false
@@ -2139,6 +2504,10 @@ func int64Ptr(i int64) *int64 {
 	return &i
 }
 
+func strPtr(s string) *string {
+	return &s
+}
+
 // resolveAnalyzerSections returns the analysis sections to use for the experiment.
 // - If analyzerConfig is set with sections, use those.
 // - If analyzerConfig is nil and publish is true, use defaults.
@@ -2169,6 +2538,108 @@ func (r *ExperimentReconciler) shouldRunQualityGate(exp *experimentsv1alpha1.Exp
 	return len(exp.Spec.Metrics) > 0
 }
 
+// syncComponentStatuses refreshes status.targets[i].componentStatuses (and the
+// component names in components) from the deployer, so an unhealthy target
+// shows which component failed. It reports whether the status changed.
+func (r *ExperimentReconciler) syncComponentStatuses(ctx context.Context, exp *experimentsv1alpha1.Experiment, i int, target experimentsv1alpha1.Target) bool {
+	statuses, err := r.Deployer.ComponentStatuses(ctx, exp.Name, target)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to read component status — non-fatal", "target", target.Name)
+		return false
+	}
+	if equality.Semantic.DeepEqual(statuses, exp.Status.Targets[i].ComponentStatuses) {
+		return false
+	}
+	names := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		names = append(names, st.Name)
+	}
+	exp.Status.Targets[i].ComponentStatuses = statuses
+	exp.Status.Targets[i].Components = names
+	return true
+}
+
+// targetConditionArgoCDHealthy is the name DeploymentHealthy had before
+// deployers other than ArgoCD; syncTargetHealth drops it from old statuses.
+const targetConditionArgoCDHealthy = "ArgoCDHealthy"
+
+// syncTargetHealth refreshes the target's DeploymentHealthy condition and
+// unhealthy resources from what the deployer reports, so a target stuck in
+// Ready shows why. It reports whether the status changed.
+func (r *ExperimentReconciler) syncTargetHealth(ctx context.Context, exp *experimentsv1alpha1.Experiment, i int, target experimentsv1alpha1.Target) bool {
+	log := logf.FromContext(ctx)
+	health, err := r.Deployer.TargetHealth(ctx, exp.Name, target.Name)
+	if err != nil {
+		log.Error(err, "Failed to read deployment health — non-fatal", "target", target.Name)
+		return false
+	}
+
+	status := &exp.Status.Targets[i]
+	cond := health.Condition(exp.Generation)
+	changed := apimeta.SetStatusCondition(&status.Conditions, cond)
+	if apimeta.RemoveStatusCondition(&status.Conditions, targetConditionArgoCDHealthy) {
+		changed = true
+	}
+	if changed && cond.Status != metav1.ConditionTrue {
+		log.Info("Deployment of target not healthy", "target", target.Name,
+			"reason", cond.Reason, "message", cond.Message)
+	}
+	if !equality.Semantic.DeepEqual(health.Unhealthy, status.UnhealthyResources) {
+		status.UnhealthyResources = health.Unhealthy
+		changed = true
+	}
+	return changed
+}
+
+// targetLayers plans the deployment layers of a target's components,
+// including its observability components when enabled.
+func (r *ExperimentReconciler) targetLayers(ctx context.Context, exp *experimentsv1alpha1.Experiment, target experimentsv1alpha1.Target) ([]deploy.Layer, error) {
+	return r.Deployer.Layers(ctx, exp.Name, target)
+}
+
+// rolloutLayers advances the layered deployment of target i: it creates the
+// Application of the first layer not yet deployed once every layer before it
+// is healthy. It reports whether all layers are deployed and healthy, and
+// whether the status changed.
+func (r *ExperimentReconciler) rolloutLayers(ctx context.Context, exp *experimentsv1alpha1.Experiment, i int, target experimentsv1alpha1.Target) (ready, updated bool, err error) {
+	log := logf.FromContext(ctx)
+	status := &exp.Status.Targets[i]
+
+	layers, err := r.targetLayers(ctx, exp, target)
+	if err != nil {
+		return false, false, fmt.Errorf("plan layers: %w", err)
+	}
+	for _, layer := range layers {
+		if !hasLayer(status.DeployedLayers, layer.Name) {
+			log.Info("Previous layers healthy, deploying layer", "target", target.Name, "layer", layer.Name)
+			if err := r.Deployer.DeployLayer(ctx, exp.Name, target, targetServer(target, *status), layer); err != nil {
+				return false, false, fmt.Errorf("deploy %s layer: %w", layer.Name, err)
+			}
+			status.DeployedLayers = append(status.DeployedLayers, layer.Name)
+			return false, true, nil // Requeue to check its health next cycle
+		}
+
+		healthy, err := r.Deployer.LayerHealthy(ctx, exp.Name, target.Name, layer.Name)
+		if err != nil {
+			return false, false, fmt.Errorf("check %s layer health: %w", layer.Name, err)
+		}
+		if !healthy {
+			log.Info("Layer not healthy yet", "target", target.Name, "layer", layer.Name)
+			return false, false, nil
+		}
+	}
+	return true, false, nil
+}
+
+// targetServer returns the API server a target's components are deployed
+// to: in-cluster for the hub, the cluster endpoint otherwise.
+func targetServer(target experimentsv1alpha1.Target, status experimentsv1alpha1.TargetStatus) string {
+	if target.Cluster.Type == "hub" {
+		return deploy.HubServer
+	}
+	return "https://" + status.Endpoint
+}
+
 // hasLayer checks if a layer name is present in the deployed layers list.
 func hasLayer(layers []string, layer string) bool {
 	for _, l := range layers {
@@ -2181,8 +2652,11 @@ func hasLayer(layers []string, layer string) bool {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ExperimentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&experimentsv1alpha1.Experiment{}).
-		Named("experiment").
-		Complete(r)
+		Named("experiment")
+	if r.ReviewEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.ReviewEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
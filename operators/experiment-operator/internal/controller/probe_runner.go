@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+// probeRunner runs probe batches off the reconcile path. A batch is keyed by
+// experiment and target; its results are held until the next reconcile of
+// the experiment collects them with take. State is in memory only: a batch
+// lost to a manager restart has no result in status and simply runs again.
+type probeRunner struct {
+	mu       sync.Mutex
+	inflight map[types.UID]map[string]bool
+	results  map[types.UID][]experimentsv1alpha1.ProbeResult
+}
+
+// start runs fn in the background for the experiment's target unless a batch
+// for that target is already in flight, and reports whether it started one.
+func (p *probeRunner) start(uid types.UID, target string, fn func() []experimentsv1alpha1.ProbeResult) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inflight == nil {
+		p.inflight = map[types.UID]map[string]bool{}
+		p.results = map[types.UID][]experimentsv1alpha1.ProbeResult{}
+	}
+	if p.inflight[uid][target] {
+		return false
+	}
+	if p.inflight[uid] == nil {
+		p.inflight[uid] = map[string]bool{}
+	}
+	p.inflight[uid][target] = true
+
+	go func() {
+		results := fn()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.inflight[uid], target)
+		if len(p.inflight[uid]) == 0 {
+			delete(p.inflight, uid)
+		}
+		p.results[uid] = append(p.results[uid], results...)
+	}()
+	return true
+}
+
+// running reports whether a batch for the experiment's target is in flight.
+func (p *probeRunner) running(uid types.UID, target string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.inflight[uid][target]
+}
+
+// pending reports whether any batch for the experiment is in flight.
+func (p *probeRunner) pending(uid types.UID) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.inflight[uid]) > 0
+}
+
+// take returns and forgets the results of the experiment's finished batches.
+func (p *probeRunner) take(uid types.UID) []experimentsv1alpha1.ProbeResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	results := p.results[uid]
+	delete(p.results, uid)
+	return results
+}
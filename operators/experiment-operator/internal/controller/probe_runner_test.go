@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+func TestProbeRunner(t *testing.T) {
+	var p probeRunner
+	uid := types.UID("exp")
+	release := make(chan struct{})
+	batch := func() []experimentsv1alpha1.ProbeResult {
+		<-release
+		return []experimentsv1alpha1.ProbeResult{{Name: "p", Target: "app"}}
+	}
+
+	if !p.start(uid, "app", batch) {
+		t.Fatal("start() = false, want the first batch to start")
+	}
+	if p.start(uid, "app", batch) {
+		t.Error("start() = true, want a second batch for the same target refused")
+	}
+	if !p.running(uid, "app") || !p.pending(uid) {
+		t.Error("batch not reported in flight")
+	}
+	if got := p.take(uid); len(got) != 0 {
+		t.Errorf("take() = %+v before the batch finished, want none", got)
+	}
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for p.pending(uid) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if p.pending(uid) {
+		t.Fatal("batch still in flight")
+	}
+	if got := p.take(uid); len(got) != 1 || got[0].Name != "p" {
+		t.Errorf("take() = %+v, want the batch's result", got)
+	}
+	if got := p.take(uid); len(got) != 0 {
+		t.Errorf("second take() = %+v, want none", got)
+	}
+}
@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/analysis"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+// analysisHistoryPrefix holds the analysis each superseded run produced, as
+// <job name>.json, relative to the experiment's prefix.
+const analysisHistoryPrefix = "analysis/history/"
+
+// analysisPreviousKey holds the summary.json the running reanalysis started
+// from, relative to the experiment's prefix, so a failed re-run can put the
+// last good analysis back.
+const analysisPreviousKey = "analysis/previous-summary.json"
+
+// isReanalysis reports whether the current analyzer run replaces an earlier
+// analysis of the experiment.
+func isReanalysis(exp *experimentsv1alpha1.Experiment) bool {
+	return len(exp.Status.AnalysisHistory) > 0
+}
+
+// failedByAnalysis reports whether a published experiment is Failed only
+// because its previous analyzer run failed, so a successful reanalysis can
+// mark it Complete again.
+func failedByAnalysis(exp *experimentsv1alpha1.Experiment) bool {
+	if !exp.Spec.Publish || exp.Status.Phase != experimentsv1alpha1.PhaseFailed || !isReanalysis(exp) {
+		return false
+	}
+	prev := exp.Status.AnalysisHistory[len(exp.Status.AnalysisHistory)-1]
+	return prev.Phase == experimentsv1alpha1.AnalysisPhaseFailed
+}
+
+// resultsPROpen reports whether analysis changes should still be pushed onto
+// the results branch: it exists and its review has not been resolved.
+func resultsPROpen(exp *experimentsv1alpha1.Experiment) bool {
+	return exp.Status.PublishBranch != "" && !isReviewTerminal(exp)
+}
+
+// reanalyzeSections parses the reanalyze annotation value: a comma-separated
+// list of analysis sections, or "true"/empty for the experiment's own.
+func reanalyzeSections(exp *experimentsv1alpha1.Experiment, value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "true" {
+		sections := resolveAnalyzerSections(exp)
+		if len(sections) == 0 {
+			sections = metrics.DefaultAnalyzerSections
+		}
+		return sections, nil
+	}
+	var sections, unknown []string
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" || slices.Contains(sections, s) {
+			continue
+		}
+		if !slices.Contains(analysisSections, s) {
+			unknown = append(unknown, s)
+			continue
+		}
+		sections = append(sections, s)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown analysis sections: %s", strings.Join(unknown, ", "))
+	}
+	return sections, nil
+}
+
+// reconcileReanalyze handles the reanalyze annotation on a completed
+// experiment: it archives the current analysis into status.analysisHistory,
+// resets the stored summary to the rule-based baseline with the requested
+// sections, and queues a new analyzer run. Stage 2 then starts and tracks the
+// Job and pushes its output onto the results PR while the review is still open.
+func (r *ExperimentReconciler) reconcileReanalyze(ctx context.Context, exp *experimentsv1alpha1.Experiment) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	value := exp.Annotations[experimentsv1alpha1.AnnotationReanalyze]
+
+	// Consume the annotation first so a failure below cannot start the
+	// analyzer twice; the condition tells the user to annotate again.
+	status := exp.Status.DeepCopy()
+	delete(exp.Annotations, experimentsv1alpha1.AnnotationReanalyze)
+	if err := r.Update(ctx, exp); err != nil {
+		return ctrl.Result{}, err
+	}
+	exp.Status = *status
+
+	sections, err := reanalyzeSections(exp, value)
+	if err == nil && r.Store == nil {
+		err = fmt.Errorf("results store not configured")
+	}
+	if err == nil {
+		err = r.startReanalysis(ctx, exp, sections)
+	}
+	if err != nil {
+		log.Error(err, "Reanalysis not started")
+		apimeta.SetStatusCondition(&exp.Status.Conditions, metav1.Condition{
+			Type:               "AnalysisComplete",
+			Status:             metav1.ConditionFalse,
+			Reason:             "ReanalyzeFailed",
+			ObservedGeneration: exp.Generation,
+			Message:            fmt.Sprintf("Reanalysis not started: %v", err),
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, exp)
+	}
+
+	log.Info("Reanalysis queued", "sections", sections)
+	if err := r.Status().Update(ctx, exp); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// startReanalysis archives the previous run and queues the analyzer against
+// the stored summary.json with sections.
+func (r *ExperimentReconciler) startReanalysis(ctx context.Context, exp *experimentsv1alpha1.Experiment, sections []string) error {
+	summaryKey := exp.Name + "/summary.json"
+	rc, err := r.Store.Get(ctx, summaryKey)
+	if err != nil {
+		return fmt.Errorf("read summary: %w", err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return fmt.Errorf("read summary: %w", err)
+	}
+	// Edit the document as a map so sections the Go types don't model
+	// survive; the typed view only feeds the baseline.
+	migrated, err := metrics.MigrateSummary(data)
+	if err != nil {
+		return fmt.Errorf("read summary: %w", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(migrated, &doc); err != nil {
+		return fmt.Errorf("decode summary: %w", err)
+	}
+	var summary metrics.ExperimentSummary
+	if err := json.Unmarshal(migrated, &summary); err != nil {
+		return fmt.Errorf("decode summary: %w", err)
+	}
+
+	now := metav1.Now()
+	prev := experimentsv1alpha1.AnalysisRun{
+		JobName:     exp.Status.AnalysisJobName,
+		Phase:       exp.Status.AnalysisPhase,
+		CompletedAt: &now,
+	}
+	if prev.Phase == "" {
+		prev.Phase = experimentsv1alpha1.AnalysisPhaseSkipped
+	}
+	if summary.AnalyzerConfig != nil {
+		prev.Sections = summary.AnalyzerConfig.Sections
+	}
+	if doc["analysis"] != nil {
+		name := prev.JobName
+		if name == "" {
+			name = fmt.Sprintf("run-%d", len(exp.Status.AnalysisHistory))
+		}
+		prev.ResultsKey = exp.Name + "/" + analysisHistoryPrefix + name + ".json"
+		if err := storage.PutJSON(ctx, r.Store, prev.ResultsKey, doc["analysis"]); err != nil {
+			return fmt.Errorf("archive analysis: %w", err)
+		}
+	}
+
+	// Keep the summary being replaced for restorePreviousAnalysis.
+	if err := copyObject(ctx, r.Store, summaryKey, exp.Name+"/"+analysisPreviousKey); err != nil {
+		return fmt.Errorf("keep previous summary: %w", err)
+	}
+
+	// The analyzer merges over whatever analysis the summary holds, so start
+	// it from the baseline rather than from the run being replaced.
+	doc["analysis"] = analysis.Baseline(&summary)
+	cfg, _ := doc["analyzerConfig"].(map[string]any)
+	if cfg == nil {
+		cfg = make(map[string]any)
+	}
+	cfg["sections"] = sections
+	doc["analyzerConfig"] = cfg
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshal summary: %w", err)
+	}
+	if err := r.Store.Put(ctx, summaryKey, bytes.NewReader(out), "application/json"); err != nil {
+		return fmt.Errorf("store summary: %w", err)
+	}
+	stale := []string{analysisProgressKey, analysisLogKey}
+	for _, pass := range append([]string{"pass_1_plan"}, analyzerSectionPasses...) {
+		stale = append(stale, "analysis/"+pass+".json")
+	}
+	for _, key := range stale {
+		if err := r.Store.Delete(ctx, exp.Name+"/"+key); err != nil {
+			return fmt.Errorf("clear previous analyzer output: %w", err)
+		}
+	}
+
+	// The history length also numbers the new Job (see analysisJobName).
+	exp.Status.AnalysisHistory = append(exp.Status.AnalysisHistory, prev)
+	exp.Status.AnalysisPasses = nil
+	queueAnalysis(exp, fmt.Sprintf("Re-running analysis for sections %s (previous run archived); waiting for an analyzer slot",
+		strings.Join(sections, ", ")))
+	return nil
+}
+
+// restorePreviousAnalysis puts back the summary.json the current reanalysis
+// started from, so a failed or invalid re-run leaves the last good analysis
+// in place. The results PR still carries that analysis: a failed run never
+// pushes.
+func (r *ExperimentReconciler) restorePreviousAnalysis(ctx context.Context, exp *experimentsv1alpha1.Experiment) error {
+	if err := copyObject(ctx, r.Store, exp.Name+"/"+analysisPreviousKey, exp.Name+"/summary.json"); err != nil {
+		return fmt.Errorf("restore previous analysis: %w", err)
+	}
+	return nil
+}
+
+// copyObject copies the JSON object at src in the results store to dst.
+func copyObject(ctx context.Context, s storage.ResultsStore, src, dst string) error {
+	rc, err := s.Get(ctx, src)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return s.Put(ctx, dst, rc, "application/json")
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/deploy"
+)
+
+// healthDeployer reports a fixed TargetHealth.
+type healthDeployer struct {
+	deploy.Deployer
+	health *deploy.TargetHealth
+}
+
+func (d healthDeployer) TargetHealth(context.Context, string, string) (*deploy.TargetHealth, error) {
+	return d.health, nil
+}
+
+func TestSyncTargetHealthDropsArgoCDHealthy(t *testing.T) {
+	r := &ExperimentReconciler{Deployer: healthDeployer{health: &deploy.TargetHealth{
+		Applications: 1, Health: "Healthy", Sync: "Synced",
+	}}}
+	exp := &experimentsv1alpha1.Experiment{Status: experimentsv1alpha1.ExperimentStatus{
+		Targets: []experimentsv1alpha1.TargetStatus{{Conditions: []metav1.Condition{
+			{Type: targetConditionArgoCDHealthy, Status: metav1.ConditionFalse, Reason: "Progressing"},
+		}}},
+	}}
+
+	if !r.syncTargetHealth(context.Background(), exp, 0, experimentsv1alpha1.Target{Name: "app"}) {
+		t.Error("syncTargetHealth() = false, want the status changed")
+	}
+	conds := exp.Status.Targets[0].Conditions
+	if apimeta.FindStatusCondition(conds, targetConditionArgoCDHealthy) != nil {
+		t.Errorf("conditions = %+v, want ArgoCDHealthy removed", conds)
+	}
+	if cond := apimeta.FindStatusCondition(conds, experimentsv1alpha1.TargetConditionDeploymentHealthy); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("DeploymentHealthy = %+v, want True", cond)
+	}
+}
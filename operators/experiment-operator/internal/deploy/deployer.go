@@ -0,0 +1,186 @@
+// Package deploy defines how the operator deploys a target's components to
+// its cluster. Backends implement Deployer: the ArgoCD backend
+// (internal/argocd) creates Applications on the hub, and Direct renders the
+// components itself and server-side applies them to the target cluster.
+package deploy
+
+import (
+	"context"
+	"slices"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/components"
+)
+
+// Backend names accepted by the DEPLOYER setting.
+const (
+	BackendArgoCD = "argocd"
+	BackendDirect = "direct"
+)
+
+// Deployer deploys a target's components and reports on them. The controller
+// drives every backend the same way: register the cluster, deploy the first
+// layer (or everything at once when there is a single layer), then deploy each
+// following layer once the one before it is healthy.
+type Deployer interface {
+	// Layers plans the deployment layers of a target's components, its
+	// observability components included, in rollout order.
+	Layers(ctx context.Context, experimentName string, target experimentsv1alpha1.Target) ([]Layer, error)
+
+	// RegisterCluster makes a target cluster reachable at server. Hub
+	// targets are reachable already and are not registered.
+	RegisterCluster(ctx context.Context, clusterName string, kubeconfig []byte, server string) error
+
+	// Deploy deploys all of a target's components at once.
+	Deploy(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, server string) error
+
+	// DeployLayer deploys one layer of a target's components.
+	DeployLayer(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, server string, layer Layer) error
+
+	// Healthy reports whether a target deployed with Deploy is ready to run
+	// the experiment against.
+	Healthy(ctx context.Context, experimentName, targetName string) (bool, error)
+
+	// LayerHealthy reports whether one deployed layer of a target is ready.
+	LayerHealthy(ctx context.Context, experimentName, targetName, layer string) (bool, error)
+
+	// ComponentStatuses returns the deployment status of each component of a
+	// target.
+	ComponentStatuses(ctx context.Context, experimentName string, target experimentsv1alpha1.Target) ([]experimentsv1alpha1.TargetComponentStatus, error)
+
+	// TargetHealth summarises the state of everything deployed for a target.
+	TargetHealth(ctx context.Context, experimentName, targetName string) (*TargetHealth, error)
+
+	// Undeploy removes everything deployed for the targets and unregisters
+	// their clusters. Failures are logged, not returned, so cleanup carries on.
+	Undeploy(ctx context.Context, experimentName string, targets []experimentsv1alpha1.Target, clusterNames []string) error
+}
+
+// Layer names with built-in meaning. Workload holds every component without
+// a layer of its own and deploys last unless Target.Layers says otherwise.
+const (
+	LayerInfra    = "infra"
+	LayerObs      = "obs"
+	LayerWorkload = "workload"
+)
+
+// Layer is one step of a layered rollout: components deployed together once
+// the layer before it is healthy.
+type Layer struct {
+	Name       string
+	Components []experimentsv1alpha1.ComponentRef
+}
+
+// PlanLayers splits a target's components and its observability components
+// into deployment layers in rollout order. A user component goes in its
+// ComponentRef.Layer, else its Component's spec.layer, else workload.
+// Observability components go in infra (tailscale-operator) or obs. Layers
+// without components are left out.
+func PlanLayers(ctx context.Context, resolver *components.Resolver, target experimentsv1alpha1.Target, obsComponents []experimentsv1alpha1.ComponentRef) ([]Layer, error) {
+	byLayer := map[string][]experimentsv1alpha1.ComponentRef{}
+	var used []string
+	add := func(layer string, ref experimentsv1alpha1.ComponentRef) {
+		if _, ok := byLayer[layer]; !ok {
+			used = append(used, layer)
+		}
+		byLayer[layer] = append(byLayer[layer], ref)
+	}
+
+	for _, ref := range obsComponents {
+		if ref.App == "tailscale-operator" {
+			add(LayerInfra, ref)
+		} else {
+			add(LayerObs, ref)
+		}
+	}
+	for _, ref := range target.Components {
+		layer, err := resolver.ComponentLayer(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if layer == "" {
+			layer = LayerWorkload
+		}
+		add(layer, ref)
+	}
+
+	var layers []Layer
+	for _, name := range layerOrder(target.Layers, used) {
+		if refs := byLayer[name]; len(refs) > 0 {
+			layers = append(layers, Layer{Name: name, Components: refs})
+		}
+	}
+	return layers, nil
+}
+
+// layerOrder returns the rollout order of layers: the target's own order if
+// it sets one, with workload last unless listed. Layers in use but not
+// listed go just before workload: infra, obs, then the rest in order of
+// first use.
+func layerOrder(order, used []string) []string {
+	defaults := []string{LayerInfra, LayerObs}
+	for _, layer := range used {
+		if !slices.Contains(defaults, layer) && layer != LayerWorkload {
+			defaults = append(defaults, layer)
+		}
+	}
+
+	result := slices.Clone(order)
+	if !slices.Contains(result, LayerWorkload) {
+		result = append(result, LayerWorkload)
+	}
+	for _, layer := range defaults {
+		if !slices.Contains(result, layer) {
+			result = slices.Insert(result, slices.Index(result, LayerWorkload), layer)
+		}
+	}
+	return result
+}
+
+// ObservabilityComponentRefs returns ComponentRefs for the observability stack
+// based on the target's ObservabilitySpec, or nil when it is disabled.
+func ObservabilityComponentRefs(obs *experimentsv1alpha1.ObservabilitySpec, experimentName, tsClientID, tsClientSecret string) []experimentsv1alpha1.ComponentRef {
+	if obs == nil || !obs.Enabled {
+		return nil
+	}
+
+	refs := []experimentsv1alpha1.ComponentRef{
+		// VictoriaMetrics egress service (always needed)
+		{Config: "metrics-egress"},
+		// Metrics agent with experiment name as external label
+		{
+			App: "metrics-agent",
+			Params: map[string]string{
+				"alloy.extraEnv[0].value": experimentName,
+			},
+		},
+	}
+
+	// Tailscale operator for mesh transport
+	if obs.Transport == "tailscale" {
+		ref := experimentsv1alpha1.ComponentRef{
+			App: "tailscale-operator",
+		}
+		if tsClientID != "" && tsClientSecret != "" {
+			ref.Params = map[string]string{
+				"oauth.clientId":     tsClientID,
+				"oauth.clientSecret": tsClientSecret,
+			}
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// ComponentName returns the component name a ComponentRef points at.
+func ComponentName(ref experimentsv1alpha1.ComponentRef) string {
+	switch {
+	case ref.App != "":
+		return ref.App
+	case ref.Workflow != "":
+		return ref.Workflow
+	default:
+		return ref.Config
+	}
+}
@@ -0,0 +1,95 @@
+package deploy
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/components"
+)
+
+func TestLayerOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []string
+		used  []string
+		want  []string
+	}{
+		{"default", nil, []string{"workload", "obs", "operators", "infra"},
+			[]string{"infra", "obs", "operators", "workload"}},
+		{"explicit", []string{"crds", "operators"}, []string{"operators", "crds", "workload"},
+			[]string{"crds", "operators", "infra", "obs", "workload"}},
+		{"unlisted before workload", []string{"operators"}, []string{"infra", "chaos", "operators"},
+			[]string{"operators", "infra", "obs", "chaos", "workload"}},
+		{"workload listed", []string{"workload", "chaos"}, []string{"chaos", "workload", "obs"},
+			[]string{"infra", "obs", "workload", "chaos"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := layerOrder(tt.order, tt.used); !slices.Equal(got, tt.want) {
+				t.Errorf("layerOrder(%v, %v) = %v, want %v", tt.order, tt.used, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanLayers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := experimentsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	strimzi := &experimentsv1alpha1.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "strimzi-operator"},
+		Spec:       experimentsv1alpha1.ComponentSpec{Type: "app", Layer: "operators"},
+	}
+	resolver := components.NewResolver(fake.NewClientBuilder().WithScheme(scheme).WithObjects(strimzi).Build())
+
+	target := experimentsv1alpha1.Target{
+		Name: "app",
+		Components: []experimentsv1alpha1.ComponentRef{
+			{App: "kafka"},
+			{App: "strimzi-operator"},
+			{App: "chaos-mesh", Layer: "chaos"},
+			{Config: "kafka-topics"},
+		},
+		Layers: []string{"operators", "workload", "chaos"},
+	}
+	obs := []experimentsv1alpha1.ComponentRef{{App: "tailscale-operator"}, {App: "metrics-agent"}}
+
+	layers, err := PlanLayers(context.Background(), resolver, target, obs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, layer := range layers {
+		for _, ref := range layer.Components {
+			got = append(got, layer.Name+"/"+ComponentName(ref))
+		}
+	}
+	want := []string{
+		"operators/strimzi-operator",
+		"infra/tailscale-operator",
+		"obs/metrics-agent",
+		"workload/kafka",
+		"workload/kafka-topics",
+		"chaos/chaos-mesh",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("PlanLayers = %v, want %v", got, want)
+	}
+}
+
+func TestPlanLayersLookupError(t *testing.T) {
+	// Component is not in the scheme, so the lookup fails with an error
+	// other than NotFound, which must not put the component in workload.
+	resolver := components.NewResolver(fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build())
+	target := experimentsv1alpha1.Target{Name: "app", Components: []experimentsv1alpha1.ComponentRef{{App: "strimzi-operator"}}}
+	if _, err := PlanLayers(context.Background(), resolver, target, nil); err == nil {
+		t.Error("PlanLayers() succeeded, want the lookup error")
+	}
+}
@@ -0,0 +1,733 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/components"
+)
+
+const (
+	// HubServer is the server hub targets are deployed to.
+	HubServer = "https://kubernetes.default.svc"
+
+	// fieldOwner is the server-side apply field manager of applied objects.
+	fieldOwner = "experiment-operator"
+
+	// clusterLabel marks a cluster Secret of the direct backend with the
+	// cluster's name.
+	clusterLabel = "experiments.illm.io/deploy-cluster"
+	// layerLabel marks an inventory with its layer.
+	layerLabel = "experiments.illm.io/layer"
+	// componentLabel marks an applied object with the component it belongs to.
+	componentLabel = "experiments.illm.io/component"
+)
+
+// DirectConfig configures the direct backend.
+type DirectConfig struct {
+	// Namespace on the hub holding the inventories and cluster Secrets.
+	Namespace string
+	// HubConfig reaches the hub cluster, where hub targets are deployed.
+	HubConfig *rest.Config
+	// TailscaleClientID and TailscaleClientSecret are passed to the
+	// tailscale-operator of targets with tailscale transport.
+	TailscaleClientID     string
+	TailscaleClientSecret string
+	// WorkDir holds git checkouts. Defaults to a temporary directory.
+	WorkDir string
+}
+
+// Direct deploys without ArgoCD: it renders each layer's components with
+// helm, kustomize or as plain manifests and server-side applies them to the
+// target cluster. What a layer applied is recorded in an inventory Secret on
+// the hub (component params may carry credentials), which takes the place of
+// an ArgoCD Application: redeploying prunes objects no longer rendered,
+// health is computed from the recorded objects with kstatus, and Undeploy
+// deletes them. helm, kustomize and git must exist in the manager image;
+// NewDirect fails when one is missing.
+type Direct struct {
+	client.Client
+	Resolver *components.Resolver
+
+	cfg    DirectConfig
+	render *renderer
+
+	// newClient connects to a cluster; replaced in tests.
+	newClient func(*rest.Config) (client.Client, error)
+
+	mu      sync.Mutex
+	clients map[string]client.Client
+}
+
+// Direct implements Deployer by applying manifests itself.
+var _ Deployer = (*Direct)(nil)
+
+// NewDirect creates a direct backend keeping its state in cfg.Namespace.
+func NewDirect(c client.Client, cfg DirectConfig) (*Direct, error) {
+	if cfg.Namespace == "" {
+		return nil, fmt.Errorf("direct deployer: namespace is required")
+	}
+	if err := checkTools(); err != nil {
+		return nil, fmt.Errorf("direct deployer: %w", err)
+	}
+	dir := cfg.WorkDir
+	if dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "deploy-"); err != nil {
+			return nil, fmt.Errorf("direct deployer: create work dir: %w", err)
+		}
+	}
+	return &Direct{
+		Client:   c,
+		Resolver: components.NewResolver(c),
+		cfg:      cfg,
+		render:   &renderer{dir: dir},
+		newClient: func(config *rest.Config) (client.Client, error) {
+			return client.New(config, client.Options{})
+		},
+		clients: map[string]client.Client{},
+	}, nil
+}
+
+// Layers plans the deployment layers of a target's components.
+func (d *Direct) Layers(ctx context.Context, experimentName string, target experimentsv1alpha1.Target) ([]Layer, error) {
+	obsRefs := ObservabilityComponentRefs(target.Observability, experimentName, d.cfg.TailscaleClientID, d.cfg.TailscaleClientSecret)
+	return PlanLayers(ctx, d.Resolver, target, obsRefs)
+}
+
+// RegisterCluster stores the cluster's kubeconfig in a Secret on the hub so
+// the layers deployed to server can reach it after a restart.
+func (d *Direct) RegisterCluster(ctx context.Context, clusterName string, kubeconfig []byte, server string) error {
+	if _, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig); err != nil {
+		return fmt.Errorf("failed to parse kubeconfig for cluster %s: %w", clusterName, err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deploy-cluster-" + clusterName,
+			Namespace: d.cfg.Namespace,
+		},
+	}
+	existing := secret.DeepCopy()
+	err := d.Get(ctx, client.ObjectKeyFromObject(secret), existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get cluster secret: %w", err)
+	}
+	if err == nil {
+		secret = existing
+	}
+	secret.Labels = map[string]string{
+		"app.kubernetes.io/managed-by": "experiment-operator",
+		"experiments.illm.io/cluster":  clusterName,
+		clusterLabel:                   clusterName,
+	}
+	secret.Type = corev1.SecretTypeOpaque
+	secret.Data = map[string][]byte{
+		"server":     []byte(server),
+		"kubeconfig": kubeconfig,
+	}
+	if err == nil {
+		if err := d.Update(ctx, secret); err != nil {
+			return fmt.Errorf("failed to update cluster secret: %w", err)
+		}
+	} else if err := d.Create(ctx, secret); err != nil {
+		return fmt.Errorf("failed to create cluster secret: %w", err)
+	}
+
+	d.mu.Lock()
+	delete(d.clients, server)
+	d.mu.Unlock()
+	log.FromContext(ctx).Info("Registered cluster for direct deployment", "cluster", clusterName, "server", server)
+	return nil
+}
+
+// Deploy applies all of a target's components, recorded as its workload
+// layer.
+func (d *Direct) Deploy(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, server string) error {
+	refs := slices.Clone(target.Components)
+	refs = append(refs, ObservabilityComponentRefs(target.Observability, experimentName,
+		d.cfg.TailscaleClientID, d.cfg.TailscaleClientSecret)...)
+	return d.deploy(ctx, experimentName, target.Name, server, LayerWorkload, refs)
+}
+
+// DeployLayer applies one layer of a target's components.
+func (d *Direct) DeployLayer(ctx context.Context, experimentName string, target experimentsv1alpha1.Target, server string, layer Layer) error {
+	return d.deploy(ctx, experimentName, target.Name, server, layer.Name, layer.Components)
+}
+
+// deploy records what a layer deploys in its inventory and syncs it.
+func (d *Direct) deploy(ctx context.Context, experimentName, targetName, server, layer string, refs []experimentsv1alpha1.ComponentRef) error {
+	refsJSON, err := json.Marshal(refs)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s layer components: %w", layer, err)
+	}
+
+	inv := &corev1.Secret{}
+	err = d.Get(ctx, client.ObjectKey{Name: inventoryName(experimentName, targetName, layer), Namespace: d.cfg.Namespace}, inv)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get %s layer inventory: %w", layer, err)
+	}
+	if errors.IsNotFound(err) {
+		inv = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      inventoryName(experimentName, targetName, layer),
+				Namespace: d.cfg.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by":   "experiment-operator",
+					"experiments.illm.io/experiment": experimentName,
+					"experiments.illm.io/target":     targetName,
+					layerLabel:                       layer,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+	}
+	if inv.Data == nil {
+		inv.Data = map[string][]byte{}
+	}
+	inv.Data["server"] = []byte(server)
+	inv.Data["components"] = refsJSON
+	return d.sync(ctx, inv)
+}
+
+// inventoryName returns the inventory name of a layer. The workload layer
+// uses {exp}-{target}, like the ArgoCD backend's Application.
+func inventoryName(experimentName, targetName, layer string) string {
+	if layer == LayerWorkload {
+		return fmt.Sprintf("%s-%s", experimentName, targetName)
+	}
+	return fmt.Sprintf("%s-%s-%s", experimentName, targetName, layer)
+}
+
+// inventoryObject identifies an applied object.
+type inventoryObject struct {
+	Component  string `json:"component"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (o inventoryObject) key() string {
+	gv, _ := schema.ParseGroupVersion(o.APIVersion)
+	return strings.Join([]string{gv.Group, o.Kind, o.Namespace, o.Name}, "/")
+}
+
+func (o inventoryObject) unstructured() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(o.APIVersion)
+	u.SetKind(o.Kind)
+	u.SetNamespace(o.Namespace)
+	u.SetName(o.Name)
+	return u
+}
+
+// inventoryObjects decodes the objects recorded in an inventory.
+func inventoryObjects(inv *corev1.Secret) []inventoryObject {
+	var objects []inventoryObject
+	if data := inv.Data["objects"]; len(data) > 0 {
+		_ = json.Unmarshal(data, &objects)
+	}
+	return objects
+}
+
+// syncError returns the error of the inventory's last sync, or "".
+func syncError(inv *corev1.Secret) string {
+	return string(inv.Data["error"])
+}
+
+// sync renders and applies an inventory's components, prunes what it applied
+// before but no longer renders, and saves the result. Rendering and apply
+// failures are recorded in the inventory, as ArgoCD records them in an
+// Application, and retried by LayerHealthy; only failures to reach the hub
+// are returned.
+func (d *Direct) sync(ctx context.Context, inv *corev1.Secret) error {
+	log := log.FromContext(ctx)
+	experimentName := inv.Labels["experiments.illm.io/experiment"]
+
+	previous := inventoryObjects(inv)
+	objects, syncErr := d.apply(ctx, inv, experimentName)
+	if syncErr == nil {
+		syncErr = d.prune(ctx, string(inv.Data["server"]), previous, objects)
+	} else {
+		// Keep track of everything that may still exist
+		objects = mergeObjects(previous, objects)
+	}
+
+	objectsJSON, err := json.Marshal(objects)
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	inv.Data["objects"] = objectsJSON
+	delete(inv.Data, "error")
+	if syncErr != nil {
+		inv.Data["error"] = []byte(syncErr.Error())
+		log.Error(syncErr, "Direct deployment failed — will retry", "inventory", inv.Name)
+	}
+
+	if inv.ResourceVersion == "" {
+		if err := d.Create(ctx, inv); err != nil {
+			return fmt.Errorf("failed to create inventory %s: %w", inv.Name, err)
+		}
+		log.Info("Created direct deployment inventory", "name", inv.Name, "objects", len(objects))
+		return nil
+	}
+	if err := d.Update(ctx, inv); err != nil {
+		return fmt.Errorf("failed to update inventory %s: %w", inv.Name, err)
+	}
+	log.Info("Updated direct deployment inventory", "name", inv.Name, "objects", len(objects))
+	return nil
+}
+
+// apply renders the inventory's components and server-side applies them to
+// its server, into the experiment namespace unless they set their own. CRDs
+// and Namespaces go first so the objects that need them can be applied in the
+// same pass. It returns the objects it applied.
+func (d *Direct) apply(ctx context.Context, inv *corev1.Secret, namespace string) ([]inventoryObject, error) {
+	var refs []experimentsv1alpha1.ComponentRef
+	if err := json.Unmarshal(inv.Data["components"], &refs); err != nil {
+		return nil, fmt.Errorf("decode components: %w", err)
+	}
+	resolved, err := d.Resolver.ResolveComponents(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+	target, err := d.clientFor(ctx, string(inv.Data["server"]))
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered []*unstructured.Unstructured
+	seen := map[string]int{}
+	for _, rc := range resolved {
+		// The same component may be listed twice with different params
+		name := rc.Name
+		if seen[rc.Name]++; seen[rc.Name] > 1 {
+			name = fmt.Sprintf("%s-%d", rc.Name, seen[rc.Name])
+		}
+		objs, err := d.render.render(ctx, rc, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			labels := obj.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels["app.kubernetes.io/managed-by"] = "experiment-operator"
+			labels["experiments.illm.io/experiment"] = namespace
+			labels["experiments.illm.io/target"] = inv.Labels["experiments.illm.io/target"]
+			labels[componentLabel] = name
+			obj.SetLabels(labels)
+		}
+		rendered = append(rendered, objs...)
+	}
+	sort.SliceStable(rendered, func(i, j int) bool { return applyOrder(rendered[i]) < applyOrder(rendered[j]) })
+
+	ns := &unstructured.Unstructured{}
+	ns.SetAPIVersion("v1")
+	ns.SetKind("Namespace")
+	ns.SetName(namespace)
+	ns.SetLabels(map[string]string{
+		"app.kubernetes.io/managed-by":               "experiment-operator",
+		"pod-security.kubernetes.io/enforce":         "privileged",
+		"pod-security.kubernetes.io/enforce-version": "latest",
+	})
+	if err := applyObject(ctx, target, ns); err != nil {
+		return nil, fmt.Errorf("ensure namespace %s: %w", namespace, err)
+	}
+
+	var applied []inventoryObject
+	var errs []error
+	for _, obj := range rendered {
+		if obj.GetNamespace() == "" {
+			namespaced, err := target.IsObjectNamespaced(obj)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", obj.GetKind(), obj.GetName(), err))
+				continue
+			}
+			if namespaced {
+				obj.SetNamespace(namespace)
+			}
+		}
+		if err := applyObject(ctx, target, obj); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", obj.GetKind(), obj.GetName(), err))
+			continue
+		}
+		applied = append(applied, inventoryObject{
+			Component:  obj.GetLabels()[componentLabel],
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+	}
+	return applied, stderrors.Join(errs...)
+}
+
+// applyOrder sorts CRDs first, then Namespaces, then everything else.
+func applyOrder(obj *unstructured.Unstructured) int {
+	switch obj.GroupVersionKind().GroupKind().String() {
+	case "CustomResourceDefinition.apiextensions.k8s.io":
+		return 0
+	case "Namespace":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func applyObject(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	return c.Apply(ctx, client.ApplyConfigurationFromUnstructured(obj), client.FieldOwner(fieldOwner), client.ForceOwnership)
+}
+
+// prune deletes the previously applied objects that are no longer rendered.
+func (d *Direct) prune(ctx context.Context, server string, previous, current []inventoryObject) error {
+	keep := map[string]bool{}
+	for _, o := range current {
+		keep[o.key()] = true
+	}
+	var stale []inventoryObject
+	for _, o := range previous {
+		if !keep[o.key()] {
+			stale = append(stale, o)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	target, err := d.clientFor(ctx, server)
+	if err != nil {
+		return err
+	}
+	return deleteObjects(ctx, target, stale)
+}
+
+// deleteObjects deletes objects in reverse order, so Namespaces and CRDs go
+// after what they hold.
+func deleteObjects(ctx context.Context, c client.Client, objects []inventoryObject) error {
+	var errs []error
+	for i := len(objects) - 1; i >= 0; i-- {
+		o := objects[i]
+		if err := c.Delete(ctx, o.unstructured()); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("delete %s %s: %w", o.Kind, o.Name, err))
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// mergeObjects returns previous followed by the objects of current it lacks.
+func mergeObjects(previous, current []inventoryObject) []inventoryObject {
+	merged := slices.Clone(previous)
+	seen := map[string]bool{}
+	for _, o := range previous {
+		seen[o.key()] = true
+	}
+	for _, o := range current {
+		if !seen[o.key()] {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+// Healthy reports whether every layer deployed for the target is ready.
+func (d *Direct) Healthy(ctx context.Context, experimentName, targetName string) (bool, error) {
+	invs, err := d.listInventories(ctx, experimentName, targetName)
+	if err != nil {
+		return false, err
+	}
+	if len(invs) == 0 {
+		return false, nil
+	}
+	for i := range invs {
+		if ready, err := d.inventoryReady(ctx, &invs[i]); err != nil || !ready {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// LayerHealthy reports whether a deployed layer is ready.
+func (d *Direct) LayerHealthy(ctx context.Context, experimentName, targetName, layer string) (bool, error) {
+	inv := &corev1.Secret{}
+	if err := d.Get(ctx, client.ObjectKey{Name: inventoryName(experimentName, targetName, layer), Namespace: d.cfg.Namespace}, inv); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil // Layer not deployed yet
+		}
+		return false, fmt.Errorf("failed to get %s layer inventory: %w", layer, err)
+	}
+	return d.inventoryReady(ctx, inv)
+}
+
+// inventoryReady retries a failed sync, then reports whether every object
+// the inventory records is Current.
+func (d *Direct) inventoryReady(ctx context.Context, inv *corev1.Secret) (bool, error) {
+	if syncError(inv) != "" {
+		if err := d.sync(ctx, inv); err != nil {
+			return false, err
+		}
+		if syncError(inv) != "" {
+			return false, nil
+		}
+	}
+	statuses, err := d.objectStatuses(ctx, inv)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range statuses {
+		if s.status != status.CurrentStatus {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// objectStatus is the status of one inventory object.
+type objectStatus struct {
+	inventoryObject
+	status  status.Status
+	message string
+}
+
+// objectStatuses reads the status of every object an inventory records.
+func (d *Direct) objectStatuses(ctx context.Context, inv *corev1.Secret) ([]objectStatus, error) {
+	objects := inventoryObjects(inv)
+	if len(objects) == 0 {
+		return nil, nil
+	}
+	target, err := d.clientFor(ctx, string(inv.Data["server"]))
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]objectStatus, 0, len(objects))
+	for _, o := range objects {
+		s := objectStatus{inventoryObject: o}
+		obj := o.unstructured()
+		if err := target.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if errors.IsNotFound(err) {
+				s.status, s.message = status.NotFoundStatus, "Resource not found"
+			} else {
+				s.status, s.message = status.UnknownStatus, err.Error()
+			}
+		} else {
+			s.status, s.message = ObjectStatus(obj)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// ComponentStatuses returns the status of each component of the target's
+// deployed layers: the worst health of its objects, and OutOfSync while its
+// layer's last sync failed.
+func (d *Direct) ComponentStatuses(ctx context.Context, experimentName string, target experimentsv1alpha1.Target) ([]experimentsv1alpha1.TargetComponentStatus, error) {
+	invs, err := d.listInventories(ctx, experimentName, target.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []experimentsv1alpha1.TargetComponentStatus
+	for i := range invs {
+		inv := &invs[i]
+		statuses, err := d.objectStatuses(ctx, inv)
+		if err != nil {
+			return nil, err
+		}
+
+		var refs []experimentsv1alpha1.ComponentRef
+		_ = json.Unmarshal(inv.Data["components"], &refs)
+		seen := map[string]int{}
+		for _, ref := range refs {
+			name := ComponentName(ref)
+			if seen[name]++; seen[name] > 1 {
+				name = fmt.Sprintf("%s-%d", name, seen[name])
+			}
+			st := experimentsv1alpha1.TargetComponentStatus{
+				Name:        name,
+				Application: inv.Name,
+				Health:      "Healthy",
+				Sync:        "Synced",
+			}
+			if syncError(inv) != "" {
+				st.Sync = "OutOfSync"
+				st.Message = syncError(inv)
+			}
+			for _, s := range statuses {
+				if s.Component != name || s.status == status.CurrentStatus {
+					continue
+				}
+				if health := healthOf(s.status); severity(healthSeverity, health) > severity(healthSeverity, st.Health) {
+					st.Health = health
+					if syncError(inv) == "" {
+						st.Message = fmt.Sprintf("%s %s: %s", s.Kind, s.Name, s.message)
+					}
+				}
+			}
+			result = append(result, st)
+		}
+	}
+	return result, nil
+}
+
+// TargetHealth summarises every layer deployed for the target; each layer
+// counts as one Application.
+func (d *Direct) TargetHealth(ctx context.Context, experimentName, targetName string) (*TargetHealth, error) {
+	invs, err := d.listInventories(ctx, experimentName, targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &TargetHealth{}
+	for i := range invs {
+		inv := &invs[i]
+		statuses, err := d.objectStatuses(ctx, inv)
+		if err != nil {
+			return nil, err
+		}
+
+		health, sync := "Healthy", "Synced"
+		if msg := syncError(inv); msg != "" {
+			sync = "OutOfSync"
+			h.OperationFailed = true
+			h.Messages = append(h.Messages, inv.Name+": sync failed: "+msg)
+		}
+		for _, s := range statuses {
+			if severity(healthSeverity, healthOf(s.status)) > severity(healthSeverity, health) {
+				health = healthOf(s.status)
+			}
+			h.AddResource(experimentsv1alpha1.UnhealthyResource{
+				Application: inv.Name,
+				Kind:        s.Kind,
+				Namespace:   s.Namespace,
+				Name:        s.Name,
+				Health:      healthOf(s.status),
+				Message:     s.message,
+			})
+		}
+		h.AddApplication(health, sync)
+	}
+	return h, nil
+}
+
+// Undeploy deletes the objects applied for the targets, their inventories
+// and the clusters' Secrets.
+func (d *Direct) Undeploy(ctx context.Context, experimentName string, targets []experimentsv1alpha1.Target, clusterNames []string) error {
+	log := log.FromContext(ctx)
+
+	for _, target := range targets {
+		invs, err := d.listInventories(ctx, experimentName, target.Name)
+		if err != nil {
+			log.Error(err, "Failed to list direct deployment inventories", "target", target.Name)
+			continue
+		}
+		// Later layers first, so infra outlives what runs on it
+		for i := len(invs) - 1; i >= 0; i-- {
+			inv := &invs[i]
+			if objects := inventoryObjects(inv); len(objects) > 0 {
+				c, err := d.clientFor(ctx, string(inv.Data["server"]))
+				if err == nil {
+					err = deleteObjects(ctx, c, objects)
+				}
+				if err != nil {
+					log.Error(err, "Failed to delete deployed objects", "inventory", inv.Name)
+				}
+			}
+			if err := d.Delete(ctx, inv); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to delete inventory", "inventory", inv.Name)
+				continue
+			}
+			log.Info("Deleted direct deployment", "inventory", inv.Name)
+		}
+	}
+
+	for _, clusterName := range clusterNames {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deploy-cluster-" + clusterName, Namespace: d.cfg.Namespace}}
+		if err := d.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to delete cluster secret", "cluster", clusterName)
+		}
+	}
+
+	d.mu.Lock()
+	for server := range d.clients {
+		if server != HubServer {
+			delete(d.clients, server)
+		}
+	}
+	d.mu.Unlock()
+	return nil
+}
+
+// listInventories returns the inventories of a target in name order.
+func (d *Direct) listInventories(ctx context.Context, experimentName, targetName string) ([]corev1.Secret, error) {
+	list := &corev1.SecretList{}
+	if err := d.List(ctx, list, client.InNamespace(d.cfg.Namespace),
+		client.MatchingLabels{
+			"experiments.illm.io/experiment": experimentName,
+			"experiments.illm.io/target":     targetName,
+		},
+		client.HasLabels{layerLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list inventories: %w", err)
+	}
+	invs := list.Items
+	sort.Slice(invs, func(i, j int) bool { return invs[i].Name < invs[j].Name })
+	return invs, nil
+}
+
+// clientFor returns a client for server: the hub for HubServer, otherwise
+// the cluster registered with it.
+func (d *Direct) clientFor(ctx context.Context, server string) (client.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c, ok := d.clients[server]; ok {
+		return c, nil
+	}
+
+	config := d.cfg.HubConfig
+	if server != HubServer {
+		list := &corev1.SecretList{}
+		if err := d.List(ctx, list, client.InNamespace(d.cfg.Namespace), client.HasLabels{clusterLabel}); err != nil {
+			return nil, fmt.Errorf("failed to list cluster secrets: %w", err)
+		}
+		config = nil
+		for _, s := range list.Items {
+			if string(s.Data["server"]) != server {
+				continue
+			}
+			var err error
+			if config, err = clientcmd.RESTConfigFromKubeConfig(s.Data["kubeconfig"]); err != nil {
+				return nil, fmt.Errorf("failed to parse kubeconfig for %s: %w", server, err)
+			}
+			break
+		}
+	}
+	if config == nil {
+		return nil, fmt.Errorf("no cluster registered for %s", server)
+	}
+
+	c, err := d.newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	d.clients[server] = c
+	return c, nil
+}
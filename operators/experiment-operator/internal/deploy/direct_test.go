@@ -0,0 +1,245 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+// newTestDirect returns a direct backend whose hub and target are fake
+// clients, deploying the demo component from repo.
+func newTestDirect(t *testing.T, repo string) (*Direct, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := experimentsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	demo := &experimentsv1alpha1.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo"},
+		Spec: experimentsv1alpha1.ComponentSpec{
+			Type:    "app",
+			Sources: []experimentsv1alpha1.ComponentSource{{RepoURL: repo, Path: "demo"}},
+		},
+	}
+	hub := fake.NewClientBuilder().WithScheme(scheme).WithObjects(demo).Build()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	target := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithRESTMapper(mapper).Build()
+
+	// The tests render plain manifests, which only need git.
+	lookPath = func(string) (string, error) { return "", nil }
+	t.Cleanup(func() { lookPath = exec.LookPath })
+	d, err := NewDirect(hub, DirectConfig{Namespace: "experiments", HubConfig: &rest.Config{}, WorkDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.newClient = func(*rest.Config) (client.Client, error) { return target, nil }
+	return d, target
+}
+
+func TestDirectDeploy(t *testing.T) {
+	ctx := context.Background()
+	repo := gitRepo(t, map[string]string{
+		"demo/config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo-config\ndata:\n  key: value\n",
+		"demo/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: demo
+spec:
+  selector:
+    matchLabels:
+      app: demo
+  template:
+    metadata:
+      labels:
+        app: demo
+    spec:
+      containers:
+      - name: demo
+        image: nginx
+`,
+	})
+	d, target := newTestDirect(t, repo)
+	spec := experimentsv1alpha1.Target{Name: "app", Components: []experimentsv1alpha1.ComponentRef{{App: "demo"}}}
+
+	if err := d.Deploy(ctx, "exp", spec, HubServer); err != nil {
+		t.Fatal(err)
+	}
+
+	inv := &corev1.Secret{}
+	if err := d.Get(ctx, client.ObjectKey{Name: "exp-app", Namespace: "experiments"}, inv); err != nil {
+		t.Fatalf("inventory not created: %v", err)
+	}
+	if msg := syncError(inv); msg != "" {
+		t.Fatalf("sync failed: %s", msg)
+	}
+	if got := len(inventoryObjects(inv)); got != 2 {
+		t.Errorf("inventory records %d objects, want 2", got)
+	}
+
+	deploy := &appsv1.Deployment{}
+	if err := target.Get(ctx, client.ObjectKey{Name: "demo", Namespace: "exp"}, deploy); err != nil {
+		t.Fatalf("deployment not applied into the experiment namespace: %v", err)
+	}
+	if got := deploy.Labels[componentLabel]; got != "demo" {
+		t.Errorf("component label = %q, want demo", got)
+	}
+	if err := target.Get(ctx, client.ObjectKey{Name: "exp"}, &corev1.Namespace{}); err != nil {
+		t.Errorf("experiment namespace not created: %v", err)
+	}
+
+	// The Deployment has not rolled out
+	healthy, err := d.Healthy(ctx, "exp", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if healthy {
+		t.Error("Healthy() = true before the deployment is available")
+	}
+	health, err := d.TargetHealth(ctx, "exp", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.Health != "Progressing" || len(health.Unhealthy) != 1 || health.Unhealthy[0].Kind != "Deployment" {
+		t.Errorf("TargetHealth() = %+v, want the Deployment Progressing", health)
+	}
+
+	deploy.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1, ReadyReplicas: 1,
+		Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}}
+	if err := target.Status().Update(ctx, deploy); err != nil {
+		t.Fatal(err)
+	}
+	healthy, err = d.LayerHealthy(ctx, "exp", "app", LayerWorkload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !healthy {
+		t.Error("LayerHealthy() = false once the deployment is available")
+	}
+	statuses, err := d.ComponentStatuses(ctx, "exp", spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].Health != "Healthy" || statuses[0].Application != "exp-app" {
+		t.Errorf("ComponentStatuses() = %+v, want demo Healthy in exp-app", statuses)
+	}
+
+	if err := d.Undeploy(ctx, "exp", []experimentsv1alpha1.Target{spec}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.Get(ctx, client.ObjectKey{Name: "demo", Namespace: "exp"}, &appsv1.Deployment{}); !errors.IsNotFound(err) {
+		t.Errorf("deployment not deleted: %v", err)
+	}
+	if err := d.Get(ctx, client.ObjectKeyFromObject(inv), &corev1.Secret{}); !errors.IsNotFound(err) {
+		t.Errorf("inventory not deleted: %v", err)
+	}
+}
+
+func TestDirectPrune(t *testing.T) {
+	ctx := context.Background()
+	repo := gitRepo(t, map[string]string{
+		"demo/a.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+		"demo/b.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+	})
+	d, target := newTestDirect(t, repo)
+	spec := experimentsv1alpha1.Target{Name: "app", Components: []experimentsv1alpha1.ComponentRef{{App: "demo"}}}
+	layer := Layer{Name: "config", Components: spec.Components}
+
+	if err := d.DeployLayer(ctx, "exp", spec, HubServer, layer); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop b.yaml upstream and redeploy
+	if err := os.Remove(filepath.Join(repo, "demo", "b.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runTool(ctx, repo, "git", "-c", "user.name=test", "-c", "user.email=test@localhost",
+		"commit", "--quiet", "-am", "drop b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DeployLayer(ctx, "exp", spec, HubServer, layer); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := target.Get(ctx, client.ObjectKey{Name: "a", Namespace: "exp"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("a deleted: %v", err)
+	}
+	if err := target.Get(ctx, client.ObjectKey{Name: "b", Namespace: "exp"}, &corev1.ConfigMap{}); !errors.IsNotFound(err) {
+		t.Errorf("b not pruned: %v", err)
+	}
+	inv := &corev1.Secret{}
+	if err := d.Get(ctx, client.ObjectKey{Name: "exp-app-config", Namespace: "experiments"}, inv); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(inventoryObjects(inv)); got != 1 {
+		t.Errorf("inventory records %d objects, want 1", got)
+	}
+}
+
+func TestDirectRecordsSyncError(t *testing.T) {
+	ctx := context.Background()
+	repo := gitRepo(t, map[string]string{"other/a.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n"})
+	d, _ := newTestDirect(t, repo)
+	spec := experimentsv1alpha1.Target{Name: "app", Components: []experimentsv1alpha1.ComponentRef{{App: "demo"}}}
+
+	// demo/ does not exist in the repository
+	if err := d.Deploy(ctx, "exp", spec, HubServer); err != nil {
+		t.Fatalf("Deploy() returned the render error: %v", err)
+	}
+	healthy, err := d.Healthy(ctx, "exp", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if healthy {
+		t.Error("Healthy() = true after a failed sync")
+	}
+	health, err := d.TargetHealth(ctx, "exp", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond := health.Condition(1); cond.Reason != "SyncFailed" {
+		t.Errorf("condition reason = %s, want SyncFailed (%s)", cond.Reason, cond.Message)
+	}
+}
+
+func TestNewDirectRequiresRenderTools(t *testing.T) {
+	lookPath = func(name string) (string, error) {
+		if name == "helm" {
+			return "", exec.ErrNotFound
+		}
+		return "/usr/bin/" + name, nil
+	}
+	t.Cleanup(func() { lookPath = exec.LookPath })
+	_, err := NewDirect(nil, DirectConfig{Namespace: "experiments", WorkDir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "helm") {
+		t.Errorf("NewDirect() error = %v, want helm reported missing", err)
+	}
+}
+
+func TestDirectClientForUnregisteredCluster(t *testing.T) {
+	d, _ := newTestDirect(t, t.TempDir())
+	if _, err := d.clientFor(context.Background(), "https://10.0.0.1"); err == nil {
+		t.Error("expected an error for a cluster that was never registered")
+	}
+}
@@ -0,0 +1,165 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+const (
+	// maxUnhealthyResources caps the resources copied into TargetStatus.
+	maxUnhealthyResources = 20
+	// maxConditionResources caps the resources named in the condition message.
+	maxConditionResources = 5
+)
+
+// healthSeverity ranks health statuses, worst highest. Statuses not listed
+// rank as Unknown. Both backends report ArgoCD's health names.
+var healthSeverity = map[string]int{
+	"Healthy":     0,
+	"Suspended":   1,
+	"Progressing": 2,
+	"Unknown":     3,
+	"Missing":     4,
+	"Degraded":    5,
+}
+
+// syncSeverity ranks sync statuses, worst highest.
+var syncSeverity = map[string]int{
+	"Synced":    0,
+	"Unknown":   1,
+	"OutOfSync": 2,
+}
+
+func severity(ranks map[string]int, status string) int {
+	if rank, ok := ranks[status]; ok {
+		return rank
+	}
+	return ranks["Unknown"]
+}
+
+// TargetHealth is the state of everything deployed for a target, in
+// ArgoCD's terms: an Application is an ArgoCD Application or, for the direct
+// backend, one applied layer.
+type TargetHealth struct {
+	// Applications is the number of Applications found.
+	Applications int
+	// Health is the worst health status the Applications report.
+	Health string
+	// Sync is the worst sync status the Applications report.
+	Sync string
+	// OperationFailed is set when an Application's last sync operation
+	// failed or errored.
+	OperationFailed bool
+	// Messages explains Application errors and failed sync operations, as
+	// "<application>: <message>".
+	Messages []string
+	// Unhealthy lists the resources reported as not Healthy or not Synced,
+	// up to maxUnhealthyResources.
+	Unhealthy []experimentsv1alpha1.UnhealthyResource
+	// MoreUnhealthy counts the unhealthy resources beyond Unhealthy.
+	MoreUnhealthy int
+}
+
+// AddApplication records one Application's health and sync status.
+func (h *TargetHealth) AddApplication(health, sync string) {
+	h.Applications++
+	if health == "" {
+		health = "Unknown"
+	}
+	if h.Health == "" || severity(healthSeverity, health) > severity(healthSeverity, h.Health) {
+		h.Health = health
+	}
+	if sync == "" {
+		sync = "Unknown"
+	}
+	if h.Sync == "" || severity(syncSeverity, sync) > severity(syncSeverity, h.Sync) {
+		h.Sync = sync
+	}
+}
+
+// AddResource records a resource if it is not Healthy or not Synced.
+// Resources without a health check (ConfigMaps, RBAC) report none.
+func (h *TargetHealth) AddResource(r experimentsv1alpha1.UnhealthyResource) {
+	if (r.Health == "" || r.Health == "Healthy") && (r.Sync == "" || r.Sync == "Synced") {
+		return
+	}
+	if len(h.Unhealthy) < maxUnhealthyResources {
+		h.Unhealthy = append(h.Unhealthy, r)
+	} else {
+		h.MoreUnhealthy++
+	}
+}
+
+// Condition returns the target's DeploymentHealthy condition. It is True
+// once every Application is Healthy and none is OutOfSync; otherwise the
+// reason is the most pressing problem and the message lists what the
+// backend reports.
+func (h *TargetHealth) Condition(generation int64) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               experimentsv1alpha1.TargetConditionDeploymentHealthy,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+	}
+	if h.Applications == 0 {
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = "NotDeployed"
+		cond.Message = "Nothing deployed for the target yet"
+		return cond
+	}
+
+	switch {
+	case h.OperationFailed:
+		cond.Reason = "SyncFailed"
+	case len(h.Messages) > 0:
+		cond.Reason = "ApplicationError"
+	case h.Health != "Healthy":
+		cond.Reason = h.Health
+	case h.Sync == "OutOfSync":
+		cond.Reason = "OutOfSync"
+	default:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Healthy"
+	}
+
+	parts := []string{fmt.Sprintf("health %s, sync %s", h.Health, h.Sync)}
+	parts = append(parts, h.Messages...)
+	if len(h.Unhealthy) > 0 {
+		var names []string
+		for i, u := range h.Unhealthy {
+			if i == maxConditionResources {
+				break
+			}
+			names = append(names, unhealthyResourceString(u))
+		}
+		if more := len(h.Unhealthy) + h.MoreUnhealthy - len(names); more > 0 {
+			names = append(names, fmt.Sprintf("and %d more", more))
+		}
+		parts = append(parts, "unhealthy resources: "+strings.Join(names, ", "))
+	}
+	cond.Message = strings.Join(parts, "; ")
+	return cond
+}
+
+// unhealthyResourceString formats a resource as "Kind namespace/name
+// (Health, Sync): message", leaving out what the backend did not report.
+func unhealthyResourceString(u experimentsv1alpha1.UnhealthyResource) string {
+	name := u.Name
+	if u.Namespace != "" {
+		name = u.Namespace + "/" + name
+	}
+	var state []string
+	for _, s := range []string{u.Health, u.Sync} {
+		if s != "" {
+			state = append(state, s)
+		}
+	}
+	out := fmt.Sprintf("%s %s (%s)", u.Kind, name, strings.Join(state, ", "))
+	if u.Message != "" {
+		out += ": " + u.Message
+	}
+	return out
+}
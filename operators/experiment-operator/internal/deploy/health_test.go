@@ -0,0 +1,32 @@
+package deploy
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTargetHealthCondition(t *testing.T) {
+	tests := []struct {
+		name   string
+		health TargetHealth
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{"not deployed", TargetHealth{}, metav1.ConditionUnknown, "NotDeployed"},
+		{"healthy", TargetHealth{Applications: 1, Health: "Healthy", Sync: "Synced"}, metav1.ConditionTrue, "Healthy"},
+		{"multi-source unknown sync", TargetHealth{Applications: 1, Health: "Healthy", Sync: "Unknown"}, metav1.ConditionTrue, "Healthy"},
+		{"progressing", TargetHealth{Applications: 2, Health: "Progressing", Sync: "Synced"}, metav1.ConditionFalse, "Progressing"},
+		{"out of sync", TargetHealth{Applications: 1, Health: "Healthy", Sync: "OutOfSync"}, metav1.ConditionFalse, "OutOfSync"},
+		{"comparison error", TargetHealth{Applications: 1, Health: "Healthy", Sync: "Unknown",
+			Messages: []string{"tsdb-app: chart not found"}}, metav1.ConditionFalse, "ApplicationError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := tt.health.Condition(1)
+			if cond.Status != tt.status || cond.Reason != tt.reason {
+				t.Errorf("Condition = %s/%s, want %s/%s", cond.Status, cond.Reason, tt.status, tt.reason)
+			}
+		})
+	}
+}
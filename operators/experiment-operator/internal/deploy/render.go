@@ -0,0 +1,309 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/illmadecoder/experiment-operator/internal/components"
+)
+
+// valuesRef prefixes Helm values files read from the component's git source,
+// as in ArgoCD's multi-source Applications.
+const valuesRef = "$values/"
+
+// renderTools are the binaries the renderer shells out to.
+var renderTools = []string{"git", "helm", "kustomize"}
+
+// lookPath finds a binary on PATH; replaced in tests.
+var lookPath = exec.LookPath
+
+// checkTools returns an error naming the render tools missing from PATH.
+func checkTools() error {
+	var missing []string
+	for _, name := range renderTools {
+		if _, err := lookPath(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s not found on PATH", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// renderer turns resolved components into manifests the way ArgoCD's repo
+// server would, by shelling out to helm, kustomize and git. These must exist
+// in the manager image, which the Dockerfile installs them into. Git sources
+// are shallow-fetched into checkouts under dir.
+type renderer struct {
+	dir string
+
+	// mu serializes checkouts; sources of one repository share a checkout.
+	mu sync.Mutex
+}
+
+// render renders every source of a resolved component for release into
+// namespace. A git source that only provides $values files is not rendered
+// itself.
+func (r *renderer) render(ctx context.Context, resolved *components.ResolvedComponent, namespace string) ([]*unstructured.Unstructured, error) {
+	var valuesSource *components.ResolvedSource
+	for i, source := range resolved.Sources {
+		if source.Chart == "" && source.Helm == nil {
+			valuesSource = &resolved.Sources[i]
+		}
+	}
+	usesValuesRef := false
+	for _, source := range resolved.Sources {
+		if source.Helm != nil {
+			for _, vf := range source.Helm.ValuesFiles {
+				usesValuesRef = usesValuesRef || strings.HasPrefix(vf, valuesRef)
+			}
+		}
+	}
+
+	var valuesDir string
+	if usesValuesRef && valuesSource != nil {
+		dir, err := r.checkout(ctx, valuesSource.RepoURL, valuesSource.TargetRevision)
+		if err != nil {
+			return nil, err
+		}
+		valuesDir = dir
+	}
+
+	var objects []*unstructured.Unstructured
+	for i, source := range resolved.Sources {
+		if usesValuesRef && &resolved.Sources[i] == valuesSource {
+			continue
+		}
+		release := resolved.Name
+		if source.Helm != nil && source.Helm.ReleaseName != "" {
+			release = source.Helm.ReleaseName
+		}
+
+		var out []byte
+		var err error
+		if source.Chart != "" {
+			out, err = r.renderChart(ctx, source, release, namespace, valuesDir)
+		} else {
+			out, err = r.renderPath(ctx, source, release, namespace, valuesDir)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", resolved.Name, err)
+		}
+		objs, err := decodeManifests(out)
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", resolved.Name, err)
+		}
+		objects = append(objects, objs...)
+	}
+	return objects, nil
+}
+
+// renderChart templates a chart from a Helm or OCI repository.
+func (r *renderer) renderChart(ctx context.Context, source components.ResolvedSource, release, namespace, valuesDir string) ([]byte, error) {
+	args := []string{"template", release}
+	if strings.HasPrefix(source.RepoURL, "oci://") {
+		args = append(args, strings.TrimSuffix(source.RepoURL, "/")+"/"+source.Chart)
+	} else {
+		args = append(args, source.Chart, "--repo", source.RepoURL)
+	}
+	if source.TargetRevision != "" && source.TargetRevision != "HEAD" {
+		args = append(args, "--version", source.TargetRevision)
+	}
+	args = append(args, helmArgs(source.Helm, namespace, valuesDir)...)
+	return runTool(ctx, "", "helm", args...)
+}
+
+// renderPath renders a directory of a git repository: a Helm chart if it has
+// a Chart.yaml, a Kustomization if it has a kustomization file, and the plain
+// manifests in it otherwise.
+func (r *renderer) renderPath(ctx context.Context, source components.ResolvedSource, release, namespace, valuesDir string) ([]byte, error) {
+	repo, err := r.checkout(ctx, source.RepoURL, source.TargetRevision)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(repo, filepath.FromSlash(source.Path))
+	if rel, err := filepath.Rel(repo, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("path %q is outside the repository", source.Path)
+	}
+
+	if fileExists(filepath.Join(dir, "Chart.yaml")) {
+		args := append([]string{"template", release, ".", "--dependency-update"}, helmArgs(source.Helm, namespace, valuesDir)...)
+		return runTool(ctx, dir, "helm", args...)
+	}
+	for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+		if fileExists(filepath.Join(dir, name)) {
+			return runTool(ctx, dir, "kustomize", "build", ".")
+		}
+	}
+	return readManifests(dir)
+}
+
+// helmArgs returns the namespace, values and parameter flags of a helm
+// template command. Parameters are passed in name order.
+func helmArgs(helm *components.HelmConfig, namespace, valuesDir string) []string {
+	args := []string{"--namespace", namespace, "--include-crds"}
+	if helm == nil {
+		return args
+	}
+	for _, vf := range helm.ValuesFiles {
+		if strings.HasPrefix(vf, valuesRef) && valuesDir != "" {
+			vf = filepath.Join(valuesDir, filepath.FromSlash(strings.TrimPrefix(vf, valuesRef)))
+		}
+		args = append(args, "--values", vf)
+	}
+	names := make([]string, 0, len(helm.Parameters))
+	for name := range helm.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--set", name+"="+helm.Parameters[name])
+	}
+	return args
+}
+
+// checkout fetches revision of a git repository and returns the directory it
+// is checked out in.
+func (r *renderer) checkout(ctx context.Context, repoURL, revision string) (string, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+	sum := sha256.Sum256([]byte(repoURL + "@" + revision))
+	dir := filepath.Join(r.dir, hex.EncodeToString(sum[:8]))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !fileExists(filepath.Join(dir, ".git")) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", fmt.Errorf("create checkout: %w", err)
+		}
+		if _, err := runTool(ctx, dir, "git", "init", "--quiet"); err != nil {
+			return "", err
+		}
+		if _, err := runTool(ctx, dir, "git", "remote", "add", "origin", repoURL); err != nil {
+			return "", err
+		}
+	}
+	if _, err := runTool(ctx, dir, "git", "fetch", "--quiet", "--depth", "1", "origin", revision); err != nil {
+		return "", err
+	}
+	if _, err := runTool(ctx, dir, "git", "checkout", "--quiet", "--force", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// readManifests reads the YAML and JSON manifests directly in dir, as ArgoCD
+// does for a non-recursive directory source.
+func readManifests(dir string) ([]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+	var out bytes.Buffer
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", e.Name(), err)
+		}
+		out.WriteString("\n---\n")
+		out.Write(data)
+	}
+	return out.Bytes(), nil
+}
+
+// decodeManifests splits a YAML or JSON stream into objects. Empty documents,
+// documents without apiVersion and kind (Helm leaves some behind), List
+// wrappers and Component definitions are skipped or unwrapped.
+func decodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var objects []*unstructured.Unstructured
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("decode manifests: %w", err)
+		}
+		if u.Object == nil || u.GetAPIVersion() == "" || u.GetKind() == "" {
+			continue
+		}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("decode manifests: %w", err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+		if u.GroupVersionKind().Group == "experiments.illm.io" && u.GetKind() == "Component" {
+			continue
+		}
+		objects = append(objects, u)
+	}
+}
+
+// runTool runs a rendering tool in dir and returns its standard output.
+func runTool(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &toolError{Name: name, Args: args, Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.Bytes(), nil
+}
+
+// toolError is a failed helm, kustomize or git command. Only the
+// subcommand is reported: helm arguments can carry secrets.
+type toolError struct {
+	Name   string
+	Args   []string
+	Err    error
+	Stderr string
+}
+
+func (e *toolError) Error() string {
+	cmd := e.Name
+	if len(e.Args) > 0 {
+		cmd += " " + e.Args[0]
+	}
+	return fmt.Sprintf("%s: %v: %s", cmd, e.Err, e.Stderr)
+}
+
+func (e *toolError) Unwrap() error { return e.Err }
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,138 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/illmadecoder/experiment-operator/internal/components"
+)
+
+// gitRepo creates a local git repository with files and returns its path.
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@localhost", "commit", "--quiet", "-m", "init"},
+	} {
+		if _, err := runTool(context.Background(), dir, "git", args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRenderPlainManifests(t *testing.T) {
+	repo := gitRepo(t, map[string]string{
+		"components/apps/demo/component.yaml": `apiVersion: experiments.illm.io/v1alpha1
+kind: Component
+metadata:
+  name: demo
+`,
+		"components/apps/demo/demo.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: demo-config
+---
+# comment only
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: demo
+`,
+		"components/apps/demo/README.md":           "not a manifest",
+		"components/apps/demo/nested/ignored.yaml": "apiVersion: v1\nkind: Secret\nmetadata:\n  name: nested\n",
+		"components/apps/demo/deployment.json":     `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "demo"}}`,
+	})
+
+	r := &renderer{dir: t.TempDir()}
+	objects, err := r.render(context.Background(), &components.ResolvedComponent{
+		Name: "demo",
+		Sources: []components.ResolvedSource{
+			{RepoURL: repo, TargetRevision: "HEAD", Path: "components/apps/demo"},
+		},
+	}, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, o := range objects {
+		got = append(got, o.GetKind()+"/"+o.GetName())
+	}
+	want := []string{"ConfigMap/demo-config", "Service/demo", "Deployment/demo"}
+	if !slices.Equal(got, want) {
+		t.Errorf("rendered %v, want %v", got, want)
+	}
+}
+
+func TestRenderPathOutsideRepository(t *testing.T) {
+	repo := gitRepo(t, map[string]string{"a.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n"})
+	r := &renderer{dir: t.TempDir()}
+	checkout, err := r.checkout(context.Background(), repo, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A sibling sharing the checkout's name as a prefix is outside it too.
+	sibling := checkout + "-sibling"
+	if err := os.MkdirAll(sibling, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"../..", "../" + filepath.Base(sibling)} {
+		_, err := r.render(context.Background(), &components.ResolvedComponent{
+			Name:    "escape",
+			Sources: []components.ResolvedSource{{RepoURL: repo, Path: path}},
+		}, "exp")
+		if err == nil || !strings.Contains(err.Error(), "outside the repository") {
+			t.Errorf("render(%q) error = %v, want the path rejected", path, err)
+		}
+	}
+}
+
+func TestHelmArgs(t *testing.T) {
+	got := helmArgs(&components.HelmConfig{
+		ValuesFiles: []string{"$values/components/apps/demo/values.yaml", "values-extra.yaml"},
+		Parameters:  map[string]string{"b": "2", "a": "1"},
+	}, "exp", "/checkout")
+	want := []string{
+		"--namespace", "exp", "--include-crds",
+		"--values", "/checkout/components/apps/demo/values.yaml",
+		"--values", "values-extra.yaml",
+		"--set", "a=1", "--set", "b=2",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("helmArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestToolErrorHidesArguments(t *testing.T) {
+	_, err := runTool(context.Background(), "", "helm-not-installed", "template", "--set", "oauth.clientSecret=hunter2")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if msg := err.Error(); strings.Contains(msg, "hunter2") {
+		t.Errorf("error leaks arguments: %s", msg)
+	}
+}
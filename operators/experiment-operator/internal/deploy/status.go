@@ -0,0 +1,34 @@
+package deploy
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+)
+
+// healthOf maps a kstatus status to the ArgoCD health name the status and
+// conditions report.
+func healthOf(s status.Status) string {
+	switch s {
+	case status.CurrentStatus:
+		return "Healthy"
+	case status.InProgressStatus, status.TerminatingStatus:
+		return "Progressing"
+	case status.FailedStatus:
+		return "Degraded"
+	case status.NotFoundStatus:
+		return "Missing"
+	default:
+		return "Unknown"
+	}
+}
+
+// ObjectStatus computes an object's status with kstatus: Current once the
+// object has reconciled to its spec, InProgress until then, Failed when it
+// cannot.
+func ObjectStatus(obj *unstructured.Unstructured) (status.Status, string) {
+	res, err := status.Compute(obj)
+	if err != nil {
+		return status.UnknownStatus, err.Error()
+	}
+	return res.Status, res.Message
+}
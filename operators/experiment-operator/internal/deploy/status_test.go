@@ -0,0 +1,117 @@
+package deploy
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+)
+
+func obj(apiVersion, kind string, fields map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: fields}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetName("test")
+	return u
+}
+
+func TestObjectStatus(t *testing.T) {
+	deleting := obj("v1", "ConfigMap", map[string]interface{}{})
+	now := metav1.Now()
+	deleting.SetDeletionTimestamp(&now)
+	stale := obj("apps/v1", "Deployment", map[string]interface{}{
+		"status": map[string]interface{}{"observedGeneration": int64(1)},
+	})
+	stale.SetGeneration(2)
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want status.Status
+	}{
+		{"no status", obj("v1", "ConfigMap", map[string]interface{}{}), status.CurrentStatus},
+		{"deleting", deleting, status.TerminatingStatus},
+		{"generation not observed", stale, status.InProgressStatus},
+		{"stalled", obj("example.com/v1", "Widget", map[string]interface{}{
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Stalled", "status": "True"},
+			}},
+		}), status.FailedStatus},
+		{"deployment rolling out", obj("apps/v1", "Deployment", map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(2)},
+			"status": map[string]interface{}{"replicas": int64(2), "updatedReplicas": int64(1)},
+		}), status.InProgressStatus},
+		{"deployment available", obj("apps/v1", "Deployment", map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(2)},
+			"status": map[string]interface{}{"replicas": int64(2), "updatedReplicas": int64(2),
+				"availableReplicas": int64(2), "readyReplicas": int64(2), "conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True"},
+				}},
+		}), status.CurrentStatus},
+		{"deployment deadline exceeded", obj("apps/v1", "Deployment", map[string]interface{}{
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"},
+			}},
+		}), status.FailedStatus},
+		{"statefulset not ready", obj("apps/v1", "StatefulSet", map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(1)},
+		}), status.InProgressStatus},
+		{"daemonset scheduled", obj("apps/v1", "DaemonSet", map[string]interface{}{
+			"metadata": map[string]interface{}{"generation": int64(1)},
+			"status": map[string]interface{}{"observedGeneration": int64(1), "desiredNumberScheduled": int64(2), "currentNumberScheduled": int64(2),
+				"updatedNumberScheduled": int64(2), "numberAvailable": int64(2), "numberReady": int64(2)},
+		}), status.CurrentStatus},
+		{"pod crash looping", obj("v1", "Pod", map[string]interface{}{
+			"status": map[string]interface{}{"phase": "Running", "containerStatuses": []interface{}{
+				map[string]interface{}{"name": "app", "state": map[string]interface{}{
+					"waiting": map[string]interface{}{"reason": "CrashLoopBackOff"},
+				}},
+			}},
+		}), status.FailedStatus},
+		{"job failed", obj("batch/v1", "Job", map[string]interface{}{
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Failed", "status": "True", "message": "BackoffLimitExceeded"},
+			}},
+		}), status.FailedStatus},
+		{"pvc pending", obj("v1", "PersistentVolumeClaim", map[string]interface{}{
+			"status": map[string]interface{}{"phase": "Pending"},
+		}), status.InProgressStatus},
+		{"load balancer without address", obj("v1", "Service", map[string]interface{}{
+			"spec": map[string]interface{}{"type": "LoadBalancer"},
+		}), status.InProgressStatus},
+		{"crd established", obj("apiextensions.k8s.io/v1", "CustomResourceDefinition", map[string]interface{}{
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Established", "status": "True"},
+			}},
+		}), status.CurrentStatus},
+		{"custom resource not ready", obj("example.com/v1", "Widget", map[string]interface{}{
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "message": "waiting"},
+			}},
+		}), status.InProgressStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, msg := ObjectStatus(tt.obj); got != tt.want {
+				t.Errorf("ObjectStatus() = %s (%s), want %s", got, msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthOf(t *testing.T) {
+	for s, want := range map[status.Status]string{
+		status.CurrentStatus:     "Healthy",
+		status.InProgressStatus:  "Progressing",
+		status.TerminatingStatus: "Progressing",
+		status.FailedStatus:      "Degraded",
+		status.NotFoundStatus:    "Missing",
+		status.UnknownStatus:     "Unknown",
+	} {
+		if got := healthOf(s); got != want {
+			t.Errorf("healthOf(%s) = %s, want %s", s, got, want)
+		}
+	}
+}
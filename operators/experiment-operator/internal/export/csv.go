@@ -0,0 +1,53 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CSV renders one query's rows as timestamp,value,target followed by one
+// column per label key seen in the query. Missing labels are empty cells.
+func CSV(rows []Row, query string) ([]byte, error) {
+	keySet := make(map[string]struct{})
+	var selected []Row
+	for _, r := range rows {
+		if r.Query != query {
+			continue
+		}
+		selected = append(selected, r)
+		for k := range r.Labels {
+			if k != TargetLabel {
+				keySet[k] = struct{}{}
+			}
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(append([]string{"timestamp", "value", "target"}, keys...)); err != nil {
+		return nil, err
+	}
+	for _, r := range selected {
+		record := []string{
+			r.Point.Timestamp.UTC().Format(time.RFC3339Nano),
+			strconv.FormatFloat(r.Point.Value, 'g', -1, 64),
+			r.Target,
+		}
+		for _, k := range keys {
+			record = append(record, r.Labels[k])
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,179 @@
+// Package export renders a MetricsResult into formats for notebooks and BI
+// tools: per-query CSV, a single Parquet table of every data point, and
+// OpenMetrics text. The files are stored next to metrics-snapshot.json.
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+// Export formats, as accepted by `labctl results export --format`.
+const (
+	FormatCSV         = "csv"
+	FormatParquet     = "parquet"
+	FormatOpenMetrics = "openmetrics"
+)
+
+// File names (relative to the experiment or run prefix).
+const (
+	CSVDir          = "csv/"
+	ParquetFile     = "metrics.parquet"
+	OpenMetricsFile = "metrics.openmetrics"
+)
+
+// Content types of the exported files.
+const (
+	ContentTypeCSV         = "text/csv; charset=utf-8"
+	ContentTypeParquet     = "application/vnd.apache.parquet"
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// TargetLabel is the label that, when present on a data point, overrides the
+// default target in the target column.
+const TargetLabel = "target"
+
+// File is one rendered export.
+type File struct {
+	Name        string
+	ContentType string
+	Body        []byte
+}
+
+// Files renders every export format for result. defaultTarget fills the
+// target column for points without a target label (the experiment's only
+// non-hub target, or "" when results span several).
+func Files(experiment string, result *metrics.MetricsResult, defaultTarget string) ([]File, error) {
+	rows := Rows(experiment, result, defaultTarget)
+
+	var files []File
+	names := queryNames(result)
+	csvFiles := CSVFiles(names)
+	for _, name := range names {
+		body, err := CSV(rows, name)
+		if err != nil {
+			return nil, fmt.Errorf("csv %s: %w", name, err)
+		}
+		files = append(files, File{Name: csvFiles[name], ContentType: ContentTypeCSV, Body: body})
+	}
+
+	pq, err := Parquet(rows)
+	if err != nil {
+		return nil, fmt.Errorf("parquet: %w", err)
+	}
+	files = append(files, File{Name: ParquetFile, ContentType: ContentTypeParquet, Body: pq})
+	files = append(files, File{Name: OpenMetricsFile, ContentType: ContentTypeOpenMetrics, Body: OpenMetrics(rows, result)})
+	return files, nil
+}
+
+// CSVFiles returns the CSV export file name of each query. Characters unsafe
+// in file names become "_"; a query whose name then collides with another's
+// gets a "-2", "-3", ... suffix, in sorted query order. Queries whose names
+// are already safe keep them unchanged.
+func CSVFiles(queries []string) map[string]string {
+	sorted := append([]string(nil), queries...)
+	sort.Strings(sorted)
+
+	files := make(map[string]string, len(sorted))
+	taken := make(map[string]bool, len(sorted))
+	for _, q := range sorted {
+		if !unsafeFileChars.MatchString(q) {
+			files[q] = CSVDir + q + ".csv"
+			taken[q] = true
+		}
+	}
+	for _, q := range sorted {
+		if _, ok := files[q]; ok {
+			continue
+		}
+		base := unsafeFileChars.ReplaceAllString(q, "_")
+		name := base
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		files[q] = CSVDir + name + ".csv"
+		taken[name] = true
+	}
+	return files
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// Row is one data point in long format, shared by all exporters.
+type Row struct {
+	Experiment string
+	Query      string
+	Unit       string
+	Target     string
+	Labels     map[string]string
+	Point      metrics.DataPoint
+}
+
+// Rows flattens result into rows ordered by query, series and timestamp.
+// Queries that errored contribute no rows.
+func Rows(experiment string, result *metrics.MetricsResult, defaultTarget string) []Row {
+	var rows []Row
+	for _, name := range queryNames(result) {
+		qr := result.Queries[name]
+		start := len(rows)
+		for _, dp := range qr.Data {
+			target := defaultTarget
+			if t, ok := dp.Labels[TargetLabel]; ok {
+				target = t
+			}
+			rows = append(rows, Row{
+				Experiment: experiment,
+				Query:      name,
+				Unit:       qr.Unit,
+				Target:     target,
+				Labels:     dp.Labels,
+				Point:      dp,
+			})
+		}
+		q := rows[start:]
+		sort.SliceStable(q, func(i, j int) bool {
+			if ki, kj := seriesKey(q[i].Labels), seriesKey(q[j].Labels); ki != kj {
+				return ki < kj
+			}
+			return q[i].Point.Timestamp.Before(q[j].Point.Timestamp)
+		})
+	}
+	return rows
+}
+
+func queryNames(result *metrics.MetricsResult) []string {
+	if result == nil {
+		return nil
+	}
+	names := make([]string, 0, len(result.Queries))
+	for name := range result.Queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func labelKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// seriesKey is a stable identity for a label set.
+func seriesKey(labels map[string]string) string {
+	var b strings.Builder
+	for _, k := range labelKeys(labels) {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(labels[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
@@ -0,0 +1,343 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func sampleResult() *metrics.MetricsResult {
+	return &metrics.MetricsResult{Queries: map[string]metrics.QueryResult{
+		"cpu_by_pod": {Type: "range", Unit: "cores", Description: "CPU usage by pod", Data: []metrics.DataPoint{
+			{Labels: map[string]string{"pod": "b"}, Timestamp: t0, Value: 0.5},
+			{Labels: map[string]string{"pod": "a", "namespace": "app"}, Timestamp: t0.Add(time.Minute), Value: 0.25},
+			{Labels: map[string]string{"pod": "a", "namespace": "app"}, Timestamp: t0, Value: 0.125},
+		}},
+		"ingest rate": {Type: "instant", Data: []metrics.DataPoint{
+			{Labels: map[string]string{TargetLabel: "loki", "le": "0.5"}, Timestamp: t0, Value: math.Inf(1)},
+		}},
+		"broken": {Type: "instant", Error: "timeout"},
+	}}
+}
+
+func TestRows(t *testing.T) {
+	rows := Rows("exp-a", sampleResult(), "app")
+	if len(rows) != 4 {
+		t.Fatalf("rows = %d, want 4", len(rows))
+	}
+	// Grouped by query, then series, then time.
+	if rows[0].Labels["pod"] != "a" || !rows[0].Point.Timestamp.Equal(t0) || rows[1].Labels["pod"] != "a" || rows[2].Labels["pod"] != "b" {
+		t.Errorf("cpu_by_pod rows out of order: %+v", rows[:3])
+	}
+	if rows[0].Target != "app" || rows[3].Target != "loki" {
+		t.Errorf("targets = %q, %q, want default app and label override loki", rows[0].Target, rows[3].Target)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	body, err := CSV(Rows("exp-a", sampleResult(), "app"), "cpu_by_pod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "timestamp,value,target,namespace,pod\n" +
+		"2026-03-01T12:00:00Z,0.125,app,app,a\n" +
+		"2026-03-01T12:01:00Z,0.25,app,app,a\n" +
+		"2026-03-01T12:00:00Z,0.5,app,,b\n"
+	if string(body) != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", body, want)
+	}
+}
+
+func TestCSVFiles(t *testing.T) {
+	got := CSVFiles([]string{"a:b", "ingest rate", "a/b", "a_b", "a_b-2x"})
+	want := map[string]string{
+		"a_b":         "csv/a_b.csv",
+		"a/b":         "csv/a_b-2.csv",
+		"a:b":         "csv/a_b-3.csv",
+		"a_b-2x":      "csv/a_b-2x.csv",
+		"ingest rate": "csv/ingest_rate.csv",
+	}
+	for q, w := range want {
+		if got[q] != w {
+			t.Errorf("CSVFiles()[%q] = %q, want %q", q, got[q], w)
+		}
+	}
+}
+
+func TestOpenMetrics(t *testing.T) {
+	res := sampleResult()
+	out := string(OpenMetrics(Rows("exp-a", res, "app"), res))
+
+	for _, want := range []string{
+		"# TYPE cpu_by_pod gauge\n# HELP cpu_by_pod CPU usage by pod\n",
+		`cpu_by_pod{experiment="exp-a",target="app",namespace="app",pod="a"} 0.125 1772366400` + "\n",
+		`ingest_rate{experiment="exp-a",target="loki",le="0.5"} +Inf 1772366400` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("OpenMetrics missing %q in\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("OpenMetrics not terminated by # EOF")
+	}
+	if strings.Count(out, "# TYPE cpu_by_pod") != 1 {
+		t.Error("metric family split across TYPE lines")
+	}
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	rows := Rows("exp-a", sampleResult(), "app")
+	body, err := Parquet(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(body, []byte("PAR1")) || !bytes.HasSuffix(body, []byte("PAR1")) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(body[len(body)-8:]))
+	if footerLen >= len(body)-12 {
+		t.Fatalf("footer length %d exceeds file size %d", footerLen, len(body))
+	}
+	footerStart := len(body) - 8 - footerLen
+	r := &thriftReader{buf: body[footerStart : len(body)-8]}
+	meta := r.readStruct()
+	if r.err != nil || r.pos != footerLen {
+		t.Fatalf("decode footer: %v (read %d of %d bytes)", r.err, r.pos, footerLen)
+	}
+
+	// FileMetaData: 2 schema, 3 num_rows, 4 row_groups.
+	if meta[3] != int64(len(rows)) {
+		t.Errorf("num_rows = %v, want %d", meta[3], len(rows))
+	}
+	wantCols := []struct {
+		name     string
+		physical int64
+	}{
+		{"experiment", parquetByteArray}, {"query", parquetByteArray}, {"unit", parquetByteArray},
+		{"target", parquetByteArray}, {"timestamp", parquetInt64}, {"value", parquetDouble},
+		{"labels", parquetByteArray},
+	}
+	schema := meta[2].([]any)
+	if len(schema) != len(wantCols)+1 || schema[0].(map[int16]any)[5] != int64(len(wantCols)) {
+		t.Fatalf("schema = %v, want a root with %d leaves", schema, len(wantCols))
+	}
+	for i, want := range wantCols {
+		// SchemaElement: 1 type, 3 repetition_type, 4 name.
+		el := schema[i+1].(map[int16]any)
+		if string(el[4].([]byte)) != want.name || el[1] != want.physical || el[3] != int64(repetitionRequired) {
+			t.Errorf("schema[%d] = %v, want required %s of type %d", i+1, el, want.name, want.physical)
+		}
+	}
+
+	groups := meta[4].([]any)
+	if len(groups) != 1 {
+		t.Fatalf("row groups = %d, want 1", len(groups))
+	}
+	// RowGroup: 1 columns, 3 num_rows.
+	group := groups[0].(map[int16]any)
+	chunks := group[1].([]any)
+	if len(chunks) != len(wantCols) || group[3] != int64(len(rows)) {
+		t.Fatalf("row group = %d columns / %v rows, want %d / %d", len(chunks), group[3], len(wantCols), len(rows))
+	}
+
+	columns := make([][]any, len(chunks))
+	for i, c := range chunks {
+		// ColumnMetaData: 1 type, 4 codec, 5 num_values, 7 total_compressed_size, 9 data_page_offset.
+		md := c.(map[int16]any)[3].(map[int16]any)
+		if md[1] != wantCols[i].physical || md[4] != int64(codecNone) || md[5] != int64(len(rows)) {
+			t.Errorf("column %s metadata = %v", wantCols[i].name, md)
+		}
+		offset, size := int(md[9].(int64)), int(md[7].(int64))
+		if offset < 4 || offset+size > footerStart {
+			t.Fatalf("column %s chunk [%d, %d) outside the data section", wantCols[i].name, offset, offset+size)
+		}
+
+		// PageHeader: 1 type, 3 compressed_page_size, 5 data_page_header
+		// (1 num_values, 2 encoding).
+		pr := &thriftReader{buf: body[offset : offset+size]}
+		page := pr.readStruct()
+		if pr.err != nil {
+			t.Fatalf("column %s page header: %v", wantCols[i].name, pr.err)
+		}
+		dp := page[5].(map[int16]any)
+		if page[1] != int64(pageTypeData) || dp[1] != int64(len(rows)) || dp[2] != int64(encodingPlain) {
+			t.Errorf("column %s page header = %v", wantCols[i].name, page)
+		}
+		values := body[offset+pr.pos : offset+size]
+		if int64(len(values)) != page[3] {
+			t.Fatalf("column %s page body = %d bytes, header says %v", wantCols[i].name, len(values), page[3])
+		}
+		columns[i] = decodePlain(t, values, wantCols[i].physical, len(rows))
+	}
+
+	for i, row := range rows {
+		labels, _ := json.Marshal(row.Labels)
+		want := []any{row.Experiment, row.Query, row.Unit, row.Target, row.Point.Timestamp.UnixMilli(), row.Point.Value, string(labels)}
+		for c := range want {
+			if columns[c][i] != want[c] {
+				t.Errorf("row %d %s = %v, want %v", i, wantCols[c].name, columns[c][i], want[c])
+			}
+		}
+	}
+}
+
+// decodePlain decodes n PLAIN-encoded values of a required column.
+func decodePlain(t *testing.T, b []byte, physical int64, n int) []any {
+	t.Helper()
+	var out []any
+	for len(out) < n {
+		switch physical {
+		case parquetByteArray:
+			if len(b) < 4 || len(b) < 4+int(binary.LittleEndian.Uint32(b)) {
+				t.Fatal("truncated BYTE_ARRAY value")
+			}
+			l := int(binary.LittleEndian.Uint32(b))
+			out = append(out, string(b[4:4+l]))
+			b = b[4+l:]
+		case parquetInt64, parquetDouble:
+			if len(b) < 8 {
+				t.Fatal("truncated 8-byte value")
+			}
+			v := binary.LittleEndian.Uint64(b)
+			if physical == parquetInt64 {
+				out = append(out, int64(v))
+			} else {
+				out = append(out, math.Float64frombits(v))
+			}
+			b = b[8:]
+		}
+	}
+	if len(b) != 0 {
+		t.Fatalf("%d bytes left after %d values", len(b), n)
+	}
+	return out
+}
+
+// thriftReader decodes thrift compact protocol structs into maps from field
+// id to value: int64 for integers, bool, []byte for binary, []any for lists
+// and map[int16]any for nested structs. It is written from the thrift spec,
+// independently of the writer under test.
+type thriftReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (r *thriftReader) byte() byte {
+	if r.pos >= len(r.buf) {
+		r.err = errors.New("unexpected end of input")
+		return 0
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[min(r.pos, len(r.buf)):])
+	if n <= 0 {
+		r.err = errors.New("bad varint")
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) readStruct() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for r.err == nil {
+		b := r.byte()
+		if b == 0 {
+			break
+		}
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		last = id
+		switch typ := b & 0x0f; typ {
+		case 1, 2:
+			fields[id] = typ == 1
+		default:
+			fields[id] = r.readValue(typ)
+		}
+	}
+	return fields
+}
+
+func (r *thriftReader) readValue(typ byte) any {
+	switch typ {
+	case 1, 2: // bool inside a list is one byte
+		return r.byte() == 1
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, 5, 6:
+		return r.zigzag()
+	case 7:
+		if r.pos+8 > len(r.buf) {
+			r.err = errors.New("truncated double")
+			return nil
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf[r.pos:]))
+		r.pos += 8
+		return v
+	case 8:
+		n := int(r.uvarint())
+		if r.pos+n > len(r.buf) {
+			r.err = errors.New("truncated binary")
+			return nil
+		}
+		v := r.buf[r.pos : r.pos+n]
+		r.pos += n
+		return v
+	case 9, 10:
+		h := r.byte()
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, 0, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			list = append(list, r.readValue(h&0x0f))
+		}
+		return list
+	case 12:
+		return r.readStruct()
+	}
+	r.err = fmt.Errorf("unsupported thrift type %d", typ)
+	return nil
+}
+
+func TestFiles(t *testing.T) {
+	files, err := Files("exp-a", sampleResult(), "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+		if len(f.Body) == 0 || f.ContentType == "" {
+			t.Errorf("%s: empty body or content type", f.Name)
+		}
+	}
+	want := "csv/broken.csv,csv/cpu_by_pod.csv,csv/ingest_rate.csv,metrics.parquet,metrics.openmetrics"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("files = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+var (
+	invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+	invalidLabelChars  = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// OpenMetrics renders rows as OpenMetrics text: one gauge family per query,
+// every sample carrying experiment and target labels and its timestamp.
+func OpenMetrics(rows []Row, result *metrics.MetricsResult) []byte {
+	var buf bytes.Buffer
+	current := ""
+	for _, r := range rows {
+		name := metricName(r.Query)
+		if r.Query != current {
+			current = r.Query
+			fmt.Fprintf(&buf, "# TYPE %s gauge\n", name)
+			if desc := result.Queries[r.Query].Description; desc != "" {
+				fmt.Fprintf(&buf, "# HELP %s %s\n", name, escapeHelp(desc))
+			}
+		}
+
+		labels := []string{
+			`experiment="` + escapeLabelValue(r.Experiment) + `"`,
+		}
+		if r.Target != "" {
+			labels = append(labels, `target="`+escapeLabelValue(r.Target)+`"`)
+		}
+		for _, k := range labelKeys(r.Labels) {
+			ln := labelName(k)
+			if ln == "experiment" || ln == TargetLabel {
+				continue
+			}
+			labels = append(labels, ln+`="`+escapeLabelValue(r.Labels[k])+`"`)
+		}
+		ts := float64(r.Point.Timestamp.UnixMilli()) / 1000
+		fmt.Fprintf(&buf, "%s{%s} %s %s\n", name, strings.Join(labels, ","),
+			formatSample(r.Point.Value), strconv.FormatFloat(ts, 'f', -1, 64))
+	}
+	buf.WriteString("# EOF\n")
+	return buf.Bytes()
+}
+
+func metricName(query string) string {
+	name := invalidMetricChars.ReplaceAllString(query, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func labelName(key string) string {
+	name := invalidLabelChars.ReplaceAllString(key, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func formatSample(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,289 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+)
+
+// Parquet writes rows as a single-row-group Parquet file with the columns
+//
+//	experiment, query, unit, target: string
+//	timestamp: int64 TIMESTAMP(MILLIS, UTC)
+//	value: double
+//	labels: string (JSON object)
+//
+// All columns are required and PLAIN-encoded without compression, which
+// every reader (pandas/pyarrow, DuckDB, Spark) accepts. Labels are a JSON
+// column because label keys differ between queries.
+func Parquet(rows []Row) ([]byte, error) {
+	cols := []*parquetColumn{
+		{name: "experiment", physical: parquetByteArray, str: true},
+		{name: "query", physical: parquetByteArray, str: true},
+		{name: "unit", physical: parquetByteArray, str: true},
+		{name: "target", physical: parquetByteArray, str: true},
+		{name: "timestamp", physical: parquetInt64, timestamp: true},
+		{name: "value", physical: parquetDouble},
+		{name: "labels", physical: parquetByteArray, str: true},
+	}
+	for _, r := range rows {
+		labels := r.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		lj, err := json.Marshal(labels)
+		if err != nil {
+			return nil, err
+		}
+		cols[0].putString(r.Experiment)
+		cols[1].putString(r.Query)
+		cols[2].putString(r.Unit)
+		cols[3].putString(r.Target)
+		cols[4].putInt64(r.Point.Timestamp.UnixMilli())
+		cols[5].putDouble(r.Point.Value)
+		cols[6].putString(string(lj))
+	}
+
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+	for _, c := range cols {
+		c.writeChunk(&file, len(rows))
+	}
+
+	footer := fileMetaData(cols, len(rows))
+	file.Write(footer)
+	_ = binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(parquetMagic)
+	return file.Bytes(), nil
+}
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types, encodings and other thrift enum values used here
+// (see parquet-format's parquet.thrift).
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	repetitionRequired = 0
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	pageTypeData = 0
+	codecNone    = 0
+)
+
+type parquetColumn struct {
+	name      string
+	physical  int32
+	str       bool
+	timestamp bool
+
+	values bytes.Buffer
+
+	// Set by writeChunk.
+	offset, size int64
+}
+
+func (c *parquetColumn) putString(s string) {
+	_ = binary.Write(&c.values, binary.LittleEndian, uint32(len(s)))
+	c.values.WriteString(s)
+}
+
+func (c *parquetColumn) putInt64(v int64) {
+	_ = binary.Write(&c.values, binary.LittleEndian, v)
+}
+
+func (c *parquetColumn) putDouble(v float64) {
+	_ = binary.Write(&c.values, binary.LittleEndian, math.Float64bits(v))
+}
+
+// writeChunk writes the column as one v1 data page. Required top-level
+// columns have no repetition or definition levels, so the page body is just
+// the PLAIN values.
+func (c *parquetColumn) writeChunk(file *bytes.Buffer, numRows int) {
+	w := &thriftWriter{}
+	w.begin()
+	w.i32(1, pageTypeData)
+	w.i32(2, int32(c.values.Len()))
+	w.i32(3, int32(c.values.Len()))
+	w.field(5, thriftStruct)
+	w.begin()
+	w.i32(1, int32(numRows))
+	w.i32(2, encodingPlain)
+	w.i32(3, encodingRLE)
+	w.i32(4, encodingRLE)
+	w.end()
+	w.end()
+
+	c.offset = int64(file.Len())
+	file.Write(w.buf.Bytes())
+	file.Write(c.values.Bytes())
+	c.size = int64(file.Len()) - c.offset
+}
+
+func fileMetaData(cols []*parquetColumn, numRows int) []byte {
+	w := &thriftWriter{}
+	w.begin()
+	w.i32(1, 1) // version
+
+	// Schema: the root group followed by one leaf per column.
+	w.list(2, thriftStruct, len(cols)+1)
+	w.begin()
+	w.binary(4, "schema")
+	w.i32(5, int32(len(cols)))
+	w.end()
+	for _, c := range cols {
+		w.begin()
+		w.i32(1, c.physical)
+		w.i32(3, repetitionRequired)
+		w.binary(4, c.name)
+		switch {
+		case c.str:
+			w.i32(6, convertedUTF8)
+			w.field(10, thriftStruct) // LogicalType
+			w.begin()
+			w.field(1, thriftStruct) // STRING
+			w.begin()
+			w.end()
+			w.end()
+		case c.timestamp:
+			w.i32(6, convertedTimestampMillis)
+			w.field(10, thriftStruct) // LogicalType
+			w.begin()
+			w.field(8, thriftStruct) // TIMESTAMP
+			w.begin()
+			w.boolean(1, true)       // isAdjustedToUTC
+			w.field(2, thriftStruct) // unit
+			w.begin()
+			w.field(1, thriftStruct) // MILLIS
+			w.begin()
+			w.end()
+			w.end()
+			w.end()
+			w.end()
+		}
+		w.end()
+	}
+
+	w.i64(3, int64(numRows))
+
+	// One row group holding every column chunk.
+	var total int64
+	for _, c := range cols {
+		total += c.size
+	}
+	w.list(4, thriftStruct, 1)
+	w.begin()
+	w.list(1, thriftStruct, len(cols))
+	for _, c := range cols {
+		w.begin()
+		w.i64(2, c.offset) // file_offset
+		w.field(3, thriftStruct)
+		w.begin()
+		w.i32(1, c.physical)
+		w.list(2, thriftI32, 1)
+		w.varint(encodingPlain)
+		w.list(3, thriftBinary, 1)
+		w.rawBinary(c.name)
+		w.i32(4, codecNone)
+		w.i64(5, int64(numRows))
+		w.i64(6, c.size)
+		w.i64(7, c.size)
+		w.i64(9, c.offset)
+		w.end()
+		w.end()
+	}
+	w.i64(2, total)
+	w.i64(3, int64(numRows))
+	w.end()
+
+	w.binary(6, "experiment-operator")
+	w.end()
+	return w.buf.Bytes()
+}
+
+// Thrift compact protocol type ids.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter is the subset of the thrift compact protocol needed for the
+// Parquet footer and page headers.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // last field id written, per open struct
+}
+
+func (w *thriftWriter) begin() { w.last = append(w.last, 0) }
+
+func (w *thriftWriter) end() {
+	w.buf.WriteByte(0) // stop field
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	top := &w.last[len(w.last)-1]
+	if delta := id - *top; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	*top = id
+}
+
+func (w *thriftWriter) varint(v int64) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		w.field(id, thriftTrue)
+	} else {
+		w.field(id, thriftFalse)
+	}
+}
+
+func (w *thriftWriter) binary(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.rawBinary(s)
+}
+
+func (w *thriftWriter) rawBinary(s string) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	w.buf.WriteString(s)
+}
+
+// list writes a list field header; the caller writes n elements.
+func (w *thriftWriter) list(id int16, elem byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elem)
+	} else {
+		w.buf.WriteByte(0xf0 | elem)
+		w.buf.Write(binary.AppendUvarint(nil, uint64(n)))
+	}
+}
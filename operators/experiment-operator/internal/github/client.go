@@ -1,278 +0,0 @@
-package github
-
-import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"strings"
-
-	"golang.org/x/oauth2"
-
-	gh "github.com/google/go-github/v68/github"
-)
-
-// Client wraps the GitHub Contents API for committing experiment results.
-type Client struct {
-	client *gh.Client
-	owner  string
-	repo   string
-	branch string
-	path   string // e.g. "site/data"
-}
-
-// NewClient creates a GitHub client for committing experiment results.
-// owner and repo are parsed from the "owner/repo" format.
-func NewClient(token, owner, repo, branch, path string) *Client {
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(context.Background(), ts)
-	return &Client{
-		client: gh.NewClient(tc),
-		owner:  owner,
-		repo:   repo,
-		branch: branch,
-		path:   path,
-	}
-}
-
-// RepoPath returns "owner/repo" for logging.
-func (c *Client) RepoPath() string {
-	return c.owner + "/" + c.repo
-}
-
-// CreateBranch creates a new branch from the current HEAD of the configured base branch.
-func (c *Client) CreateBranch(ctx context.Context, branchName string) error {
-	// Get the SHA of the base branch
-	baseRef, _, err := c.client.Git.GetRef(ctx, c.owner, c.repo, "refs/heads/"+c.branch)
-	if err != nil {
-		return fmt.Errorf("get base branch %s ref: %w", c.branch, err)
-	}
-
-	// Create the new branch pointing at the same SHA
-	newRef := &gh.Reference{
-		Ref:    gh.Ptr("refs/heads/" + branchName),
-		Object: baseRef.Object,
-	}
-	_, _, err = c.client.Git.CreateRef(ctx, c.owner, c.repo, newRef)
-	if err != nil {
-		return fmt.Errorf("create branch %s: %w", branchName, err)
-	}
-
-	return nil
-}
-
-// CreatePR creates a pull request from branchName into the base branch.
-// Returns the PR number and HTML URL.
-func (c *Client) CreatePR(ctx context.Context, title, body, branchName string) (int, string, error) {
-	pr, _, err := c.client.PullRequests.Create(ctx, c.owner, c.repo, &gh.NewPullRequest{
-		Title: &title,
-		Body:  &body,
-		Head:  &branchName,
-		Base:  &c.branch,
-	})
-	if err != nil {
-		return 0, "", fmt.Errorf("create PR from %s to %s: %w", branchName, c.branch, err)
-	}
-
-	return pr.GetNumber(), pr.GetHTMLURL(), nil
-}
-
-// PublishExperimentResult creates a branch, commits results to it, and opens a PR.
-// Returns the branch name, PR number, and PR URL.
-// If expTitle is non-empty, it is used in the PR title for human readability.
-func (c *Client) PublishExperimentResult(ctx context.Context, expName string, summary any, opts ...PublishOption) (string, int, string, error) {
-	branchName := "experiment/" + expName
-
-	// Create the experiment branch from the base branch
-	if err := c.CreateBranch(ctx, branchName); err != nil {
-		return "", 0, "", fmt.Errorf("create experiment branch: %w", err)
-	}
-
-	// Commit results to the experiment branch
-	if err := c.commitToBranch(ctx, branchName, expName, summary); err != nil {
-		return "", 0, "", fmt.Errorf("commit results to branch: %w", err)
-	}
-
-	// Open a PR — use title if provided for human-readable PR names
-	var po publishOptions
-	for _, opt := range opts {
-		opt(&po)
-	}
-	title := fmt.Sprintf("data: Add %s experiment results", expName)
-	if po.title != "" {
-		title = fmt.Sprintf("data: %s", po.title)
-	}
-	body := fmt.Sprintf("Experiment `%s` completed. Results committed to `%s` for review.\n\nPreview locally:\n```\ngit fetch && git checkout %s\ncd site && npm run dev\n```", expName, c.path+"/"+expName+".json", branchName)
-	prNum, prURL, err := c.CreatePR(ctx, title, body, branchName)
-	if err != nil {
-		return branchName, 0, "", fmt.Errorf("create PR: %w", err)
-	}
-
-	return branchName, prNum, prURL, nil
-}
-
-// commitToBranch commits an experiment summary JSON to the given branch.
-func (c *Client) commitToBranch(ctx context.Context, branch, expName string, summary any) error {
-	body, err := json.MarshalIndent(summary, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshal summary JSON: %w", err)
-	}
-
-	filePath := c.path + "/" + expName + ".json"
-	commitMsg := fmt.Sprintf("data: Add %s experiment results", expName)
-
-	// Check if file already exists on the target branch (need SHA for updates)
-	existing, _, resp, err := c.client.Repositories.GetContents(ctx, c.owner, c.repo, filePath, &gh.RepositoryContentGetOptions{
-		Ref: branch,
-	})
-	if err != nil && (resp == nil || resp.StatusCode != 404) {
-		return fmt.Errorf("check existing file %s: %w", filePath, err)
-	}
-
-	opts := &gh.RepositoryContentFileOptions{
-		Message: &commitMsg,
-		Content: body,
-		Branch:  &branch,
-	}
-
-	// If file exists, include SHA for update
-	if existing != nil {
-		sha := existing.GetSHA()
-		opts.SHA = &sha
-		commitMsg = fmt.Sprintf("data: Update %s experiment results", expName)
-		opts.Message = &commitMsg
-	}
-
-	_, _, err = c.client.Repositories.CreateFile(ctx, c.owner, c.repo, filePath, opts)
-	if err != nil {
-		return fmt.Errorf("commit %s: %w", filePath, err)
-	}
-
-	return nil
-}
-
-// publishOptions holds optional parameters for PublishExperimentResult.
-type publishOptions struct {
-	title string
-}
-
-// PublishOption configures PublishExperimentResult behavior.
-type PublishOption func(*publishOptions)
-
-// WithTitle sets a human-readable title for the PR.
-func WithTitle(title string) PublishOption {
-	return func(o *publishOptions) {
-		o.title = title
-	}
-}
-
-// FetchFileContent fetches a file's content from the configured repo (or a different repo).
-// If repo is empty, the client's configured owner/repo is used.
-// If ref is empty, the repo's default branch is used.
-func (c *Client) FetchFileContent(ctx context.Context, repo, path, ref string) (string, error) {
-	owner, repoName := c.owner, c.repo
-	if repo != "" {
-		parts := strings.SplitN(repo, "/", 2)
-		if len(parts) == 2 {
-			owner, repoName = parts[0], parts[1]
-		}
-	}
-
-	opts := &gh.RepositoryContentGetOptions{}
-	if ref != "" {
-		opts.Ref = ref
-	}
-
-	fileContent, _, _, err := c.client.Repositories.GetContents(ctx, owner, repoName, path, opts)
-	if err != nil {
-		return "", fmt.Errorf("get contents %s/%s:%s: %w", owner, repoName, path, err)
-	}
-	if fileContent == nil {
-		return "", fmt.Errorf("path %s is a directory, not a file", path)
-	}
-
-	content, err := fileContent.GetContent()
-	if err != nil {
-		return "", fmt.Errorf("decode content %s: %w", path, err)
-	}
-
-	return content, nil
-}
-
-// MergePR squash-merges a pull request and deletes the source branch.
-func (c *Client) MergePR(ctx context.Context, prNumber int, branchName string) error {
-	_, _, err := c.client.PullRequests.Merge(ctx, c.owner, c.repo, prNumber, "", &gh.PullRequestOptions{
-		MergeMethod: "squash",
-	})
-	if err != nil {
-		return fmt.Errorf("merge PR #%d: %w", prNumber, err)
-	}
-
-	// Delete the source branch
-	_, err = c.client.Git.DeleteRef(ctx, c.owner, c.repo, "refs/heads/"+branchName)
-	if err != nil {
-		// Non-fatal: PR is merged, branch cleanup is best-effort
-		return fmt.Errorf("delete branch %s after merge: %w", branchName, err)
-	}
-
-	return nil
-}
-
-// ClosePR closes a pull request without merging and deletes the source branch.
-func (c *Client) ClosePR(ctx context.Context, prNumber int, branchName string) error {
-	closed := "closed"
-	_, _, err := c.client.PullRequests.Edit(ctx, c.owner, c.repo, prNumber, &gh.PullRequest{
-		State: &closed,
-	})
-	if err != nil {
-		return fmt.Errorf("close PR #%d: %w", prNumber, err)
-	}
-
-	// Delete the source branch
-	_, err = c.client.Git.DeleteRef(ctx, c.owner, c.repo, "refs/heads/"+branchName)
-	if err != nil {
-		return fmt.Errorf("delete branch %s after close: %w", branchName, err)
-	}
-
-	return nil
-}
-
-// CommitResult commits an experiment summary JSON to the configured repo path.
-// It creates or updates site/data/{expName}.json with indented JSON.
-func (c *Client) CommitResult(ctx context.Context, expName string, summary any) error {
-	body, err := json.MarshalIndent(summary, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshal summary JSON: %w", err)
-	}
-
-	filePath := c.path + "/" + expName + ".json"
-	commitMsg := fmt.Sprintf("data: Add %s experiment results", expName)
-
-	// Check if file already exists (need SHA for updates)
-	existing, _, resp, err := c.client.Repositories.GetContents(ctx, c.owner, c.repo, filePath, &gh.RepositoryContentGetOptions{
-		Ref: c.branch,
-	})
-	if err != nil && (resp == nil || resp.StatusCode != 404) {
-		return fmt.Errorf("check existing file %s: %w", filePath, err)
-	}
-
-	opts := &gh.RepositoryContentFileOptions{
-		Message: &commitMsg,
-		Content: body,
-		Branch:  &c.branch,
-	}
-
-	// If file exists, include SHA for update
-	if existing != nil {
-		sha := existing.GetSHA()
-		opts.SHA = &sha
-		commitMsg = fmt.Sprintf("data: Update %s experiment results", expName)
-		opts.Message = &commitMsg
-	}
-
-	_, _, err = c.client.Repositories.CreateFile(ctx, c.owner, c.repo, filePath, opts)
-	if err != nil {
-		return fmt.Errorf("commit %s: %w", filePath, err)
-	}
-
-	return nil
-}
@@ -0,0 +1,330 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CadvisorSampleInterval is how often the controller scrapes cadvisor on
+// targets without a Prometheus while the workload runs.
+const CadvisorSampleInterval = 60 * time.Second
+
+// PodUsage is one pod's cumulative CPU and current memory at a sample.
+type PodUsage struct {
+	Namespace   string  `json:"namespace"`
+	Pod         string  `json:"pod"`
+	CPUSeconds  float64 `json:"cpuSeconds"`
+	MemoryBytes float64 `json:"memoryBytes"`
+}
+
+// CadvisorSample is a single cadvisor scrape across all nodes of a target.
+type CadvisorSample struct {
+	Timestamp time.Time  `json:"timestamp"`
+	Pods      []PodUsage `json:"pods"`
+}
+
+// ScrapeCadvisor scrapes cadvisor on every node of the cluster through the API
+// server node proxy and returns per-pod usage for workload pods.
+func ScrapeCadvisor(ctx context.Context, kubeconfig []byte) (CadvisorSample, error) {
+	logger := log.FromContext(ctx)
+
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return CadvisorSample{}, fmt.Errorf("parse kubeconfig: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return CadvisorSample{}, fmt.Errorf("create clientset: %w", err)
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return CadvisorSample{}, fmt.Errorf("list nodes: %w", err)
+	}
+	if len(nodes.Items) == 0 {
+		return CadvisorSample{}, fmt.Errorf("no nodes found")
+	}
+
+	// Aggregate CPU (counter seconds) and memory (gauge bytes) by namespace/pod
+	cpuByPod := make(map[podRef]float64)
+	memByPod := make(map[podRef]float64)
+	restClient := clientset.CoreV1().RESTClient()
+	now := time.Now()
+
+	for _, node := range nodes.Items {
+		scrapeCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		raw, err := restClient.Get().
+			Resource("nodes").
+			Name(node.Name).
+			SubResource("proxy", "metrics", "cadvisor").
+			Do(scrapeCtx).
+			Raw()
+		cancel()
+		if err != nil {
+			logger.Error(err, "Failed to scrape cadvisor", "node", node.Name)
+			continue
+		}
+		parseCadvisorMetrics(string(raw), cpuByPod, memByPod)
+	}
+
+	if len(cpuByPod) == 0 && len(memByPod) == 0 {
+		return CadvisorSample{}, fmt.Errorf("no cadvisor metrics found on any node")
+	}
+
+	refs := make(map[podRef]bool)
+	for ref := range cpuByPod {
+		refs[ref] = true
+	}
+	for ref := range memByPod {
+		refs[ref] = true
+	}
+	sample := CadvisorSample{Timestamp: now.UTC()}
+	for ref := range refs {
+		sample.Pods = append(sample.Pods, PodUsage{
+			Namespace:   ref.Namespace,
+			Pod:         ref.Pod,
+			CPUSeconds:  cpuByPod[ref],
+			MemoryBytes: memByPod[ref],
+		})
+	}
+	sort.Slice(sample.Pods, func(i, j int) bool {
+		if sample.Pods[i].Namespace != sample.Pods[j].Namespace {
+			return sample.Pods[i].Namespace < sample.Pods[j].Namespace
+		}
+		return sample.Pods[i].Pod < sample.Pods[j].Pod
+	})
+	logger.Info("Scraped cadvisor metrics", "nodes", len(nodes.Items), "pods", len(sample.Pods))
+	return sample, nil
+}
+
+// podRef identifies a pod across samples.
+type podRef struct {
+	Namespace string
+	Pod       string
+}
+
+// cadvisorResult turns a sample history into range queries: CPU rates (cores)
+// from consecutive counter deltas and memory working set (bytes), each by pod,
+// by namespace and in total, plus peak/average memory per pod and namespace.
+//
+// A CPU counter that went backwards means the pod's containers restarted; the
+// new value is then the usage since the restart, as with PromQL rate(). Pods
+// absent from the previous sample contribute no rate for that interval.
+//
+// CPU rates need two samples and memory one; queries left without data for
+// lack of samples carry an insufficient-data error rather than being dropped.
+func cadvisorResult(samples []CadvisorSample, start time.Time) *MetricsResult {
+	sorted := append([]CadvisorSample(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+	last := start
+	if len(sorted) > 0 {
+		last = sorted[len(sorted)-1].Timestamp
+	}
+	insufficient := fmt.Sprintf("insufficient data: %d cadvisor sample(s) in the workload window", len(sorted))
+
+	result := &MetricsResult{
+		CollectedAt: time.Now().UTC(),
+		Source:      "target:cadvisor",
+		TimeRange: TimeRange{
+			Start:    start,
+			End:      last,
+			Duration: last.Sub(start).String(),
+			StepSec:  int(CadvisorSampleInterval.Seconds()),
+		},
+		Queries: make(map[string]QueryResult),
+	}
+
+	var cpuPod, cpuNS, cpuTotal []DataPoint
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		dt := cur.Timestamp.Sub(prev.Timestamp).Seconds()
+		if dt <= 0 {
+			continue
+		}
+		before := make(map[podRef]float64, len(prev.Pods))
+		for _, p := range prev.Pods {
+			before[podRef{p.Namespace, p.Pod}] = p.CPUSeconds
+		}
+
+		byNS := make(map[string]float64)
+		var total float64
+		found := false
+		for _, p := range cur.Pods {
+			was, ok := before[podRef{p.Namespace, p.Pod}]
+			if !ok {
+				continue
+			}
+			delta := p.CPUSeconds - was
+			if delta < 0 {
+				delta = p.CPUSeconds
+			}
+			rate := delta / dt
+			cpuPod = append(cpuPod, DataPoint{
+				Labels:    map[string]string{"namespace": p.Namespace, "pod": p.Pod},
+				Timestamp: cur.Timestamp,
+				Value:     rate,
+			})
+			byNS[p.Namespace] += rate
+			total += rate
+			found = true
+		}
+		if !found {
+			continue
+		}
+		cpuNS = append(cpuNS, namespacePoints(byNS, cur.Timestamp)...)
+		cpuTotal = append(cpuTotal, DataPoint{
+			Labels:    map[string]string{"scope": "total"},
+			Timestamp: cur.Timestamp,
+			Value:     total,
+		})
+	}
+
+	var memPod, memNS, memTotal []DataPoint
+	podStats := make(map[podRef]*peakAvg)
+	nsStats := make(map[string]*peakAvg)
+	for _, s := range sorted {
+		byNS := make(map[string]float64)
+		var total float64
+		for _, p := range s.Pods {
+			if p.MemoryBytes == 0 {
+				continue
+			}
+			memPod = append(memPod, DataPoint{
+				Labels:    map[string]string{"namespace": p.Namespace, "pod": p.Pod},
+				Timestamp: s.Timestamp,
+				Value:     p.MemoryBytes,
+			})
+			ref := podRef{p.Namespace, p.Pod}
+			if podStats[ref] == nil {
+				podStats[ref] = &peakAvg{}
+			}
+			podStats[ref].add(p.MemoryBytes)
+			byNS[p.Namespace] += p.MemoryBytes
+			total += p.MemoryBytes
+		}
+		if len(byNS) == 0 {
+			continue
+		}
+		for ns, v := range byNS {
+			if nsStats[ns] == nil {
+				nsStats[ns] = &peakAvg{}
+			}
+			nsStats[ns].add(v)
+		}
+		memNS = append(memNS, namespacePoints(byNS, s.Timestamp)...)
+		memTotal = append(memTotal, DataPoint{
+			Labels:    map[string]string{"scope": "total"},
+			Timestamp: s.Timestamp,
+			Value:     total,
+		})
+	}
+
+	// addRange records a range query; minSamples is how many samples it needs.
+	addRange := func(name, query, unit, desc string, data []DataPoint, minSamples int) {
+		qr := QueryResult{Query: query, Type: "range", Unit: unit, Description: desc, Data: data}
+		if len(data) == 0 {
+			if len(sorted) >= minSamples {
+				return
+			}
+			qr.Error = fmt.Sprintf("%s, need at least %d", insufficient, minSamples)
+		}
+		result.Queries[name] = qr
+	}
+	addRange("cpu_by_pod", "rate(container_cpu_usage_seconds_total) by pod (cadvisor)", "cores",
+		"CPU usage by pod", cpuPod, 2)
+	addRange("cpu_by_namespace", "rate(container_cpu_usage_seconds_total) by namespace (cadvisor)", "cores",
+		"CPU usage by namespace", cpuNS, 2)
+	addRange("cpu_total", "sum(rate(container_cpu_usage_seconds_total)) (cadvisor)", "cores",
+		"Total CPU usage", cpuTotal, 2)
+	addRange("memory_by_pod", "container_memory_working_set_bytes by pod (cadvisor)", "bytes",
+		"Memory working set by pod", memPod, 1)
+	addRange("memory_by_namespace", "container_memory_working_set_bytes by namespace (cadvisor)", "bytes",
+		"Memory working set by namespace", memNS, 1)
+	addRange("memory_total", "sum(container_memory_working_set_bytes) (cadvisor)", "bytes",
+		"Total memory working set", memTotal, 1)
+
+	if len(podStats) > 0 {
+		var peak, avg []DataPoint
+		for ref, st := range podStats {
+			labels := map[string]string{"namespace": ref.Namespace, "pod": ref.Pod}
+			peak = append(peak, DataPoint{Labels: labels, Timestamp: last, Value: st.peak})
+			avg = append(avg, DataPoint{Labels: labels, Timestamp: last, Value: st.mean()})
+		}
+		sortPoints(peak)
+		sortPoints(avg)
+		result.Queries["memory_peak_by_pod"] = QueryResult{
+			Query: "max_over_time(container_memory_working_set_bytes) by pod (cadvisor)", Type: "instant",
+			Unit: "bytes", Description: "Peak memory working set by pod", Data: peak,
+		}
+		result.Queries["memory_avg_by_pod"] = QueryResult{
+			Query: "avg_over_time(container_memory_working_set_bytes) by pod (cadvisor)", Type: "instant",
+			Unit: "bytes", Description: "Average memory working set by pod", Data: avg,
+		}
+	}
+	if len(nsStats) > 0 {
+		var peak, avg []DataPoint
+		for ns, st := range nsStats {
+			labels := map[string]string{"namespace": ns}
+			peak = append(peak, DataPoint{Labels: labels, Timestamp: last, Value: st.peak})
+			avg = append(avg, DataPoint{Labels: labels, Timestamp: last, Value: st.mean()})
+		}
+		sortPoints(peak)
+		sortPoints(avg)
+		result.Queries["memory_peak_by_namespace"] = QueryResult{
+			Query: "max_over_time(sum(container_memory_working_set_bytes) by namespace) (cadvisor)", Type: "instant",
+			Unit: "bytes", Description: "Peak memory working set by namespace", Data: peak,
+		}
+		result.Queries["memory_avg_by_namespace"] = QueryResult{
+			Query: "avg_over_time(sum(container_memory_working_set_bytes) by namespace) (cadvisor)", Type: "instant",
+			Unit: "bytes", Description: "Average memory working set by namespace", Data: avg,
+		}
+	}
+
+	return result
+}
+
+// peakAvg accumulates the maximum and mean of a gauge.
+type peakAvg struct {
+	peak, sum float64
+	n         int
+}
+
+func (p *peakAvg) add(v float64) {
+	if p.n == 0 || v > p.peak {
+		p.peak = v
+	}
+	p.sum += v
+	p.n++
+}
+
+func (p *peakAvg) mean() float64 {
+	return p.sum / float64(p.n)
+}
+
+// namespacePoints converts per-namespace sums into data points in stable order.
+func namespacePoints(byNS map[string]float64, ts time.Time) []DataPoint {
+	points := make([]DataPoint, 0, len(byNS))
+	for ns, v := range byNS {
+		points = append(points, DataPoint{
+			Labels:    map[string]string{"namespace": ns},
+			Timestamp: ts,
+			Value:     v,
+		})
+	}
+	sortPoints(points)
+	return points
+}
+
+// sortPoints orders data points by their label set for deterministic output.
+func sortPoints(points []DataPoint) {
+	sort.Slice(points, func(i, j int) bool {
+		return seriesKey(points[i].Labels) < seriesKey(points[j].Labels)
+	})
+}
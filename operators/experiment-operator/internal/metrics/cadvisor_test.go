@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCadvisorResultRates(t *testing.T) {
+	t0 := time.Unix(1700000000, 0)
+	samples := []CadvisorSample{
+		{Timestamp: t0.Add(120 * time.Second), Pods: []PodUsage{
+			{Namespace: "exp", Pod: "api", CPUSeconds: 5, MemoryBytes: 300},
+			{Namespace: "exp", Pod: "db", CPUSeconds: 150, MemoryBytes: 500},
+		}},
+		{Timestamp: t0, Pods: []PodUsage{
+			{Namespace: "exp", Pod: "api", CPUSeconds: 100, MemoryBytes: 100},
+			{Namespace: "exp", Pod: "db", CPUSeconds: 50, MemoryBytes: 400},
+		}},
+		{Timestamp: t0.Add(60 * time.Second), Pods: []PodUsage{
+			{Namespace: "exp", Pod: "api", CPUSeconds: 130, MemoryBytes: 200},
+			{Namespace: "exp", Pod: "db", CPUSeconds: 110, MemoryBytes: 600},
+			{Namespace: "other", Pod: "new", CPUSeconds: 7, MemoryBytes: 50},
+		}},
+	}
+
+	r := cadvisorResult(samples, t0)
+
+	cpuTotal := r.Queries["cpu_total"]
+	if cpuTotal.Type != "range" || cpuTotal.Unit != "cores" {
+		t.Fatalf("cpu_total type/unit = %s/%s, want range/cores", cpuTotal.Type, cpuTotal.Unit)
+	}
+	// t0→60s: api 30s + db 60s over 60s = 1.5 cores ("new" has no previous sample).
+	// 60s→120s: api restarted (5/60) + db 40/60 = 0.75 cores.
+	want := []float64{1.5, 0.75}
+	if len(cpuTotal.Data) != len(want) {
+		t.Fatalf("cpu_total points = %d, want %d", len(cpuTotal.Data), len(want))
+	}
+	for i, w := range want {
+		if got := cpuTotal.Data[i].Value; got < w-1e-9 || got > w+1e-9 {
+			t.Errorf("cpu_total[%d] = %v, want %v", i, got, w)
+		}
+	}
+
+	if n := len(r.Queries["cpu_by_pod"].Data); n != 4 {
+		t.Errorf("cpu_by_pod points = %d, want 4", n)
+	}
+	if n := len(r.Queries["memory_total"].Data); n != 3 {
+		t.Errorf("memory_total points = %d, want 3", n)
+	}
+
+	peaks := map[string]float64{}
+	for _, dp := range r.Queries["memory_peak_by_pod"].Data {
+		peaks[dp.Labels["pod"]] = dp.Value
+	}
+	if peaks["api"] != 300 || peaks["db"] != 600 || peaks["new"] != 50 {
+		t.Errorf("memory_peak_by_pod = %v, want api=300 db=600 new=50", peaks)
+	}
+	avgNS := map[string]float64{}
+	for _, dp := range r.Queries["memory_avg_by_namespace"].Data {
+		avgNS[dp.Labels["namespace"]] = dp.Value
+	}
+	if avgNS["exp"] != 700 || avgNS["other"] != 50 {
+		t.Errorf("memory_avg_by_namespace = %v, want exp=700 other=50", avgNS)
+	}
+
+	if !r.TimeRange.End.Equal(t0.Add(120 * time.Second)) {
+		t.Errorf("TimeRange.End = %s, want last sample", r.TimeRange.End)
+	}
+}
+
+func TestCadvisorResultSingleSample(t *testing.T) {
+	r := cadvisorResult([]CadvisorSample{{
+		Timestamp: time.Unix(1700000000, 0),
+		Pods:      []PodUsage{{Namespace: "exp", Pod: "api", CPUSeconds: 100, MemoryBytes: 100}},
+	}}, time.Unix(1700000000, 0))
+
+	if qr := r.Queries["cpu_total"]; len(qr.Data) != 0 || !strings.HasPrefix(qr.Error, "insufficient data") {
+		t.Errorf("cpu_total = %+v, want no rate and an insufficient-data error", qr)
+	}
+	if qr := r.Queries["memory_total"]; len(qr.Data) != 1 || qr.Error != "" {
+		t.Errorf("memory_total = %+v, want the single sample", qr)
+	}
+}
+
+func TestCadvisorResultNoSamples(t *testing.T) {
+	r := cadvisorResult(nil, time.Unix(1700000000, 0))
+	for _, name := range []string{"cpu_total", "memory_total"} {
+		if qr := r.Queries[name]; !strings.HasPrefix(qr.Error, "insufficient data") {
+			t.Errorf("%s = %+v, want an insufficient-data error", name, qr)
+		}
+	}
+	if !AllQueriesEmpty(r) {
+		t.Error("AllQueriesEmpty() = false, want no data without samples")
+	}
+}
+
+func TestParseCadvisorMetrics(t *testing.T) {
+	text := `# HELP container_cpu_usage_seconds_total Cumulative cpu time consumed
+container_cpu_usage_seconds_total{container="api",namespace="exp",pod="api-0"} 12.5 1700000000000
+container_cpu_usage_seconds_total{container="sidecar",namespace="exp",pod="api-0"} 2.5
+container_cpu_usage_seconds_total{container="POD",namespace="exp",pod="api-0"} 1
+container_cpu_usage_seconds_total{container="coredns",namespace="kube-system",pod="coredns-1"} 9
+container_cpu_usage_seconds_total{container="alloy",namespace="exp",pod="alloy-xyz"} 3
+container_memory_working_set_bytes{container="api",namespace="exp",pod="api-0"} 1024
+container_memory_working_set_bytes{container="api",namespace="other",pod="api-0"} 2048
+`
+	cpu := make(map[podRef]float64)
+	mem := make(map[podRef]float64)
+	parseCadvisorMetrics(text, cpu, mem)
+
+	if len(cpu) != 1 || cpu[podRef{"exp", "api-0"}] != 15 {
+		t.Errorf("cpu = %v, want exp/api-0=15", cpu)
+	}
+	if len(mem) != 2 || mem[podRef{"exp", "api-0"}] != 1024 || mem[podRef{"other", "api-0"}] != 2048 {
+		t.Errorf("mem = %v, want same-named pods kept apart by namespace", mem)
+	}
+}
+
+func TestClipSamples(t *testing.T) {
+	t0 := time.Unix(1700000000, 0)
+	var samples []CadvisorSample
+	for i := -1; i <= 3; i++ {
+		samples = append(samples, CadvisorSample{Timestamp: t0.Add(time.Duration(i) * time.Minute)})
+	}
+	got := clipSamples(samples, t0, t0.Add(2*time.Minute))
+	if len(got) != 3 || !got[0].Timestamp.Equal(t0) || !got[2].Timestamp.Equal(t0.Add(2*time.Minute)) {
+		t.Errorf("clipSamples() = %v, want the samples at 0, 1 and 2 minutes", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +22,33 @@ type MetricsResult struct {
 	Source      string                 `json:"source,omitempty"`
 	TimeRange   TimeRange              `json:"timeRange"`
 	Queries     map[string]QueryResult `json:"queries"`
+
+	// querier is the backend the queries ran against, kept so the quality gate
+	// can run diagnostic queries. Nil for cadvisor scrapes.
+	querier Querier
+}
+
+// Merge copies other's queries into m, replacing queries with the same name.
+// The diagnostic backend follows the merged queries.
+func (m *MetricsResult) Merge(other *MetricsResult) {
+	if m.Queries == nil {
+		m.Queries = make(map[string]QueryResult)
+	}
+	for k, v := range other.Queries {
+		m.Queries[k] = v
+	}
+	if other.querier != nil {
+		m.querier = other.querier
+	}
+}
+
+// Backend reports which kind of backend served the queries: "hub", "target",
+// or "" when no queryable backend was involved.
+func (m *MetricsResult) Backend() string {
+	if m == nil || m.querier == nil {
+		return ""
+	}
+	return m.querier.Backend()
 }
 
 // TimeRange captures the experiment time window used for queries.
@@ -33,12 +61,15 @@ type TimeRange struct {
 
 // QueryResult holds the result of a single PromQL query, flattened for Vega-Lite.
 type QueryResult struct {
-	Query       string      `json:"query"`
-	Type        string      `json:"type"`
-	Unit        string      `json:"unit,omitempty"`
-	Description string      `json:"description,omitempty"`
-	Error       string      `json:"error,omitempty"`
-	Data        []DataPoint `json:"data,omitempty"`
+	Query       string       `json:"query"`
+	Type        string       `json:"type"`
+	Unit        string       `json:"unit,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Required    bool         `json:"required,omitempty"`
+	Window      string       `json:"window,omitempty"`
+	SteadyState *SteadyState `json:"steadyState,omitempty"`
+	Data        []DataPoint  `json:"data,omitempty"`
 }
 
 // DataPoint is a single row in the flat tabular output.
@@ -74,7 +105,10 @@ type AnalyzerConfigJSON struct {
 }
 
 // ExperimentSummary is the top-level results object stored in S3.
+// Its JSON Schema is generated by SummaryJSONSchema; bump SummarySchemaVersion
+// and add a migration step when changing the shape incompatibly.
 type ExperimentSummary struct {
+	SchemaVersion  int                 `json:"schemaVersion"`
 	Name           string              `json:"name"`
 	Title          string              `json:"title,omitempty"`
 	Namespace      string              `json:"namespace"`
@@ -92,6 +126,7 @@ type ExperimentSummary struct {
 	CodeSnippets    map[string]CodeSnippetResult            `json:"codeSnippets,omitempty"`
 	CostEstimate    *CostEstimate                          `json:"costEstimate,omitempty"`
 	IterationStatus *experimentsv1alpha1.IterationStatus   `json:"iterationStatus,omitempty"`
+	Probes          []experimentsv1alpha1.ProbeResult      `json:"probes,omitempty"`
 	Analysis        *AnalysisResult                        `json:"analysis,omitempty"`
 }
 
@@ -155,7 +190,8 @@ type CodeSnippetResult struct {
 	Code        string   `json:"code"`
 }
 
-// AnalysisResult holds AI-generated analysis of experiment results.
+// AnalysisResult holds the analysis of experiment results: the rule-based
+// baseline from the analysis package, with the analyzer's sections merged over it.
 type AnalysisResult struct {
 	// Backward-compatible fields
 	Summary        string            `json:"summary"`
@@ -171,9 +207,12 @@ type AnalysisResult struct {
 	// Structured analysis sections
 	Abstract            string              `json:"abstract,omitempty"`
 	CapabilitiesMatrix  *CapabilitiesMatrix `json:"capabilitiesMatrix,omitempty"`
+	FinopsAnalysis      *FinopsAnalysis     `json:"finopsAnalysis,omitempty"`
 	Body                *AnalysisBody       `json:"body,omitempty"`
 	Feedback            *AnalysisFeedback   `json:"feedback,omitempty"`
 	ArchitectureDiagram string              `json:"architectureDiagram,omitempty"`
+	// ArchitectureDiagramFormat is "ascii" or "mermaid".
+	ArchitectureDiagramFormat string `json:"architectureDiagramFormat,omitempty"`
 	Glossary            []GlossaryEntry     `json:"glossary,omitempty"`
 }
 
@@ -196,15 +235,78 @@ type CapabilityEntry struct {
 	Values map[string]string `json:"values"`
 }
 
+// FinopsAnalysis is the cost section: an overview, what drove the cost, a
+// 24/7 production projection and optimization suggestions.
+type FinopsAnalysis struct {
+	Overview      string   `json:"overview,omitempty"`
+	CostDrivers   []string `json:"costDrivers,omitempty"`
+	Projection    string   `json:"projection,omitempty"`
+	Optimizations []string `json:"optimizations,omitempty"`
+}
+
 // AnalysisBody contains the rich narrative body as an ordered array of typed blocks.
-// The analyzer generates blocks; the operator only needs JSON round-tripping.
+// The operator's baseline emits a few tables and callouts; the analyzer
+// replaces them with its own narrative when it runs.
 type AnalysisBody struct {
 	Blocks []BodyBlock `json:"blocks,omitempty"`
 }
 
-// BodyBlock is a discriminated union of content blocks. The operator never generates
-// blocks (the analyzer does), so we use a generic map for JSON round-tripping.
-type BodyBlock map[string]interface{}
+// BodyBlock is a discriminated union of content blocks keyed by Type.
+// bodyBlockTypes lists which fields each type requires and allows, and
+// ValidateSummary enforces it.
+type BodyBlock struct {
+	Type string `json:"type"`
+
+	// text, callout
+	Content string `json:"content,omitempty"`
+	// topic, callout, recommendation
+	Title string `json:"title,omitempty"`
+	// topic, row
+	Blocks []BodyBlock `json:"blocks,omitempty"`
+	// metric, code: key into metrics.queries / codeSnippets
+	Key     string `json:"key,omitempty"`
+	Insight string `json:"insight,omitempty"`
+	// metric: "large" or "small"
+	Size string `json:"size,omitempty"`
+	// comparison
+	Items []ComparisonItem `json:"items,omitempty"`
+	// capabilityRow
+	Capability string            `json:"capability,omitempty"`
+	Values     map[string]string `json:"values,omitempty"`
+	// table
+	Headers []string   `json:"headers,omitempty"`
+	Rows    [][]string `json:"rows,omitempty"`
+	// table, architecture
+	Caption string `json:"caption,omitempty"`
+	// architecture
+	Diagram string `json:"diagram,omitempty"`
+	Format  string `json:"format,omitempty"`
+	// callout: info, warning, success or finding
+	Variant string `json:"variant,omitempty"`
+	// recommendation
+	Priority    string `json:"priority,omitempty"`
+	Description string `json:"description,omitempty"`
+	Effort      string `json:"effort,omitempty"`
+	// code
+	Annotations []CodeAnnotation `json:"annotations,omitempty"`
+}
+
+// ComparisonItem is one row of a comparison block.
+type ComparisonItem struct {
+	Label       string `json:"label"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// CodeAnnotation highlights a line range of a code snippet.
+type CodeAnnotation struct {
+	// FromLine is a 1-based offset within the snippet.
+	FromLine int    `json:"fromLine"`
+	ToLine   int    `json:"toLine,omitempty"`
+	Category string `json:"category"`
+	Label    string `json:"label"`
+	Content  string `json:"content"`
+}
 
 // AnalysisFeedback provides actionable recommendations and experiment design improvements.
 type AnalysisFeedback struct {
@@ -238,13 +340,14 @@ const preemptibleDiscount = 0.20
 // CollectSummary builds an ExperimentSummary from an Experiment CR.
 func CollectSummary(exp *experimentsv1alpha1.Experiment) *ExperimentSummary {
 	s := &ExperimentSummary{
-		Name:        exp.Name,
-		Title:       exp.Spec.Title,
-		Namespace:   exp.Namespace,
-		Description: exp.Spec.Description,
-		CreatedAt:   exp.CreationTimestamp.Time,
-		Phase:       string(exp.Status.Phase),
-		Tags:        exp.Spec.Tags,
+		SchemaVersion: SummarySchemaVersion,
+		Name:          exp.Name,
+		Title:         exp.Spec.Title,
+		Namespace:     exp.Namespace,
+		Description:   exp.Spec.Description,
+		CreatedAt:     exp.CreationTimestamp.Time,
+		Phase:         string(exp.Status.Phase),
+		Tags:          exp.Spec.Tags,
 	}
 
 	// Hypothesis context — pass through to summary for AI analyzer
@@ -308,6 +411,9 @@ func CollectSummary(exp *experimentsv1alpha1.Experiment) *ExperimentSummary {
 		}
 	}
 
+	// Probe results were recorded during the Running phase
+	s.Probes = exp.Status.ProbeResults
+
 	return s
 }
 
@@ -323,10 +429,22 @@ func defaultQueries() []experimentsv1alpha1.MetricsQuery {
 	}
 }
 
-// substituteVars replaces $EXPERIMENT, $NAMESPACE, and $DURATION placeholders in a query.
+// substituteVars replaces $VARIABLE placeholders in a query (see queryVars).
+// Longer names are replaced first so that $DURATION does not clobber the
+// suffix of $WORKLOAD_DURATION.
 func substituteVars(query string, vars map[string]string) string {
-	for k, v := range vars {
-		query = strings.ReplaceAll(query, k, v)
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		query = strings.ReplaceAll(query, k, vars[k])
 	}
 	return query
 }
@@ -362,18 +480,19 @@ func promDuration(d time.Duration) string {
 
 // CollectMetricsSnapshot queries VictoriaMetrics for metrics defined in the experiment
 // spec (or defaults) and returns structured, chart-ready results.
-// The iteration parameter controls $DURATION substitution: iteration 0 uses the full
-// experiment duration, later iterations use progressively shorter windows.
+// The iteration parameter is the quality gate window step: step 0 uses the full
+// experiment duration, later steps use progressively shorter windows anchored
+// to the workload window.
 func CollectMetricsSnapshot(ctx context.Context, metricsURL string, exp *experimentsv1alpha1.Experiment, iteration ...int) (*MetricsResult, error) {
 	if metricsURL == "" {
 		return nil, nil
 	}
 
-	start := exp.CreationTimestamp.Time
-	// Always use time.Now() as end time. This function is called while resources
-	// are still alive (cleanup happens after it returns), so Now() captures data
-	// that Alloy wrote after workflow completion but before results collection.
-	end := time.Now()
+	iter := 0
+	if len(iteration) > 0 {
+		iter = iteration[0]
+	}
+	start, end := WorkloadWindow(exp)
 
 	duration := end.Sub(start)
 
@@ -391,19 +510,6 @@ func CollectMetricsSnapshot(ctx context.Context, metricsURL string, exp *experim
 		queries = defaultQueries()
 	}
 
-	// Build substitution variables — use iteration-aware $DURATION
-	iter := 0
-	if len(iteration) > 0 {
-		iter = iteration[0]
-	}
-	durationForQuery := IterationDuration(iter, duration)
-
-	vars := map[string]string{
-		"$EXPERIMENT": exp.Name,
-		"$NAMESPACE":  exp.Namespace,
-		"$DURATION":   promDuration(durationForQuery),
-	}
-
 	result := &MetricsResult{
 		CollectedAt: time.Now().UTC(),
 		TimeRange: TimeRange{
@@ -413,30 +519,40 @@ func CollectMetricsSnapshot(ctx context.Context, metricsURL string, exp *experim
 			StepSec:  stepSec,
 		},
 		Queries: make(map[string]QueryResult),
+		querier: httpQuerier{url: metricsURL},
 	}
 
 	for _, mq := range queries {
-		resolvedQuery := substituteVars(mq.Query, vars)
 		queryType := mq.Type
 		if queryType == "" {
 			queryType = "instant"
 		}
 
 		qr := QueryResult{
-			Query:       resolvedQuery,
+			Query:       mq.Query,
 			Type:        queryType,
 			Unit:        mq.Unit,
 			Description: mq.Description,
+			Required:    mq.Required,
+			Window:      mq.Window,
 		}
 
+		qStart, qEnd, rateDuration, err := ResolveWindow(exp, mq.Window, iter)
+		if err != nil {
+			qr.Error = err.Error()
+			result.Queries[mq.Name] = qr
+			continue
+		}
+		resolvedQuery := substituteVars(mq.Query, queryVars(exp, exp.Namespace, qStart, qEnd, rateDuration))
+		qr.Query = resolvedQuery
+
 		var data []DataPoint
-		var err error
 
 		switch queryType {
 		case "instant":
-			data, err = queryMetricsInstant(ctx, metricsURL, resolvedQuery, end)
+			data, err = queryMetricsInstant(ctx, metricsURL, resolvedQuery, qEnd)
 		case "range":
-			data, err = queryMetricsRange(ctx, metricsURL, resolvedQuery, start, end)
+			data, err = queryMetricsRange(ctx, metricsURL, resolvedQuery, qStart, qEnd)
 		default:
 			err = fmt.Errorf("unknown query type: %s", queryType)
 		}
@@ -657,16 +773,12 @@ func EvaluateSuccessCriteria(summary *ExperimentSummary) string {
 		}
 
 		// Calculate the value to compare: for instant queries use the single value,
-		// for range queries use the mean.
+		// for range queries use the mean over the steady-state interval.
 		var actual float64
 		if qr.Type == "instant" && len(qr.Data) == 1 {
 			actual = qr.Data[0].Value
 		} else {
-			var sum float64
-			for _, dp := range qr.Data {
-				sum += dp.Value
-			}
-			actual = sum / float64(len(qr.Data))
+			actual = steadyMean(qr)
 		}
 
 		threshold, err := strconv.ParseFloat(sc.Value, 64)
@@ -711,6 +823,9 @@ func EvaluateSuccessCriteria(summary *ExperimentSummary) string {
 
 // EvaluateMetricsQuality checks how many defined metrics returned non-empty data.
 // Returns a QualityResult with coverage fraction and list of missing metrics.
+// The result fails if coverage is below minCoverage or any required metric is
+// missing. Diagnosis and remedy selection are left to DiagnoseMissingMetrics
+// and ChooseRemedy, which need a live backend.
 func EvaluateMetricsQuality(summary *ExperimentSummary, minCoverage float64) experimentsv1alpha1.QualityResult {
 	result := experimentsv1alpha1.QualityResult{}
 
@@ -722,42 +837,119 @@ func EvaluateMetricsQuality(summary *ExperimentSummary, minCoverage float64) exp
 	for name, qr := range summary.Metrics.Queries {
 		if qr.Error != "" || len(qr.Data) == 0 {
 			result.MissingMetrics = append(result.MissingMetrics, name)
+			if qr.Required {
+				result.MissingRequired = append(result.MissingRequired, name)
+			}
 		} else {
 			result.MetricsWithData++
 		}
 	}
+	sort.Strings(result.MissingMetrics)
+	sort.Strings(result.MissingRequired)
 
 	if result.TotalMetrics > 0 {
 		result.Coverage = float64(result.MetricsWithData) / float64(result.TotalMetrics)
 	}
-	result.Passed = result.Coverage >= minCoverage
+	result.Passed = result.Coverage >= minCoverage && len(result.MissingRequired) == 0
 
 	if !result.Passed {
 		result.Remedy = fmt.Sprintf(
-			"Coverage %.0f%% < %.0f%% threshold (%d/%d metrics with data). "+
-				"Will re-collect with shorter $DURATION to avoid rate() dilution.",
+			"Coverage %.0f%% (threshold %.0f%%, %d/%d metrics with data), %d required metric(s) missing.",
 			result.Coverage*100, minCoverage*100,
 			result.MetricsWithData, result.TotalMetrics,
+			len(result.MissingRequired),
 		)
 	}
 
 	return result
 }
 
-// IterationDuration returns the $DURATION value to use for a given iteration.
-// Iteration 0 uses the full experiment duration (current behavior).
-// Later iterations use progressively shorter windows to avoid rate() dilution
-// from the pre-benchmark idle period.
-func IterationDuration(iteration int, fullDuration time.Duration) time.Duration {
-	switch iteration {
+// IterationDuration returns the $DURATION value for a given window step.
+// Step 0 uses the full window. Each shortenWindow remedy advances the step to
+// a progressively shorter window (10m → 5m → 2m), never longer than the full
+// window, to avoid rate() dilution from idle periods.
+func IterationDuration(step int, fullDuration time.Duration) time.Duration {
+	var d time.Duration
+	switch step {
 	case 0:
 		return fullDuration
 	case 1:
-		return 10 * time.Minute
+		d = 10 * time.Minute
 	case 2:
-		return 5 * time.Minute
+		d = 5 * time.Minute
 	default:
-		return 2 * time.Minute
+		d = 2 * time.Minute
+	}
+	if fullDuration > 0 && fullDuration < d {
+		return fullDuration
+	}
+	return d
+}
+
+// WorkloadWindow returns the period the workload actually ran: the workflow's
+// StartedAt..FinishedAt. This excludes cluster provisioning and Helm installs,
+// which would otherwise dilute rates. Missing timestamps fall back to the CR
+// creation time and the current time respectively.
+func WorkloadWindow(exp *experimentsv1alpha1.Experiment) (start, end time.Time) {
+	start = exp.CreationTimestamp.Time
+	end = time.Now()
+	if ws := exp.Status.WorkflowStatus; ws != nil {
+		if ws.StartedAt != nil {
+			start = ws.StartedAt.Time
+		}
+		if ws.FinishedAt != nil && ws.FinishedAt.Time.After(start) {
+			end = ws.FinishedAt.Time
+		}
+	}
+	return start, end
+}
+
+// ResolveWindow returns the bounds of the named metrics window and the
+// $DURATION for a quality gate window step. An empty name is the workload
+// window; other names refer to phase windows recorded from the workflow in
+// status.workflowStatus.phases. A phase that has started but not finished
+// ends at the workload window end.
+func ResolveWindow(exp *experimentsv1alpha1.Experiment, name string, step int) (start, end time.Time, rateDuration time.Duration, err error) {
+	start, end = WorkloadWindow(exp)
+	if name != "" {
+		var found bool
+		if ws := exp.Status.WorkflowStatus; ws != nil {
+			for _, p := range ws.Phases {
+				if p.Name != name || p.StartedAt == nil {
+					continue
+				}
+				start = p.StartedAt.Time
+				if p.FinishedAt != nil && p.FinishedAt.Time.After(start) {
+					end = p.FinishedAt.Time
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return start, end, 0, fmt.Errorf("phase window %q not recorded by workflow", name)
+		}
+	}
+	return start, end, IterationDuration(step, end.Sub(start)), nil
+}
+
+// queryVars returns the substitution variables for a query evaluated over
+// [start, end]:
+//
+//	$EXPERIMENT         experiment name
+//	$NAMESPACE          namespace the workload's series are labeled with
+//	$DURATION           query window as a Prometheus duration (shortened by quality gate steps)
+//	$WORKLOAD_DURATION  full workload window as a Prometheus duration
+//	$START, $END        query window bounds as Unix seconds (for the @ modifier)
+func queryVars(exp *experimentsv1alpha1.Experiment, namespace string, start, end time.Time, rateDuration time.Duration) map[string]string {
+	ws, we := WorkloadWindow(exp)
+	return map[string]string{
+		"$EXPERIMENT":        exp.Name,
+		"$NAMESPACE":         namespace,
+		"$DURATION":          promDuration(rateDuration),
+		"$WORKLOAD_DURATION": promDuration(we.Sub(ws)),
+		"$START":             strconv.FormatInt(start.Unix(), 10),
+		"$END":               strconv.FormatInt(end.Unix(), 10),
 	}
 }
 
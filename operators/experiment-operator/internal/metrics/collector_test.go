@@ -7,37 +7,61 @@ import (
 )
 
 func TestSubstituteVars(t *testing.T) {
-	vars := map[string]string{
-		"$EXPERIMENT": "tsdb-comparison-abc",
-		"$NAMESPACE":  "experiments",
-		"$DURATION":   "30m",
+	// Each case gets its own map so one case's extra vars cannot leak into
+	// another's.
+	baseVars := func(extra map[string]string) map[string]string {
+		vars := map[string]string{
+			"$EXPERIMENT": "tsdb-comparison-abc",
+			"$NAMESPACE":  "experiments",
+			"$DURATION":   "30m",
+		}
+		for k, v := range extra {
+			vars[k] = v
+		}
+		return vars
 	}
 
 	tests := []struct {
 		name  string
+		vars  map[string]string
 		query string
 		want  string
 	}{
 		{
 			name:  "all three vars",
+			vars:  baseVars(nil),
 			query: `sum(rate(cpu{experiment="$EXPERIMENT", namespace="$NAMESPACE"}[$DURATION]))`,
 			want:  `sum(rate(cpu{experiment="tsdb-comparison-abc", namespace="experiments"}[30m]))`,
 		},
 		{
 			name:  "no vars",
+			vars:  baseVars(nil),
 			query: `up`,
 			want:  `up`,
 		},
 		{
 			name:  "repeated var",
+			vars:  baseVars(nil),
 			query: `$EXPERIMENT-$EXPERIMENT`,
 			want:  `tsdb-comparison-abc-tsdb-comparison-abc`,
 		},
+		{
+			name:  "overlapping var names",
+			vars:  baseVars(map[string]string{"$WORKLOAD_DURATION": "45m"}),
+			query: `rate(x[$DURATION]) / rate(x[$WORKLOAD_DURATION])`,
+			want:  `rate(x[30m]) / rate(x[45m])`,
+		},
+		{
+			name:  "unset var left alone",
+			vars:  baseVars(nil),
+			query: `rate(x[$WORKLOAD_DURATION])`,
+			want:  `rate(x[$WORKLOAD_DURATION])`,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := substituteVars(tt.query, vars)
+			got := substituteVars(tt.query, tt.vars)
 			if got != tt.want {
 				t.Errorf("substituteVars() = %q, want %q", got, tt.want)
 			}
@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"k8s.io/client-go/rest"
+)
+
+// Querier runs instant queries against the Prometheus-compatible backend a
+// MetricsResult was collected from, so missing data can be diagnosed in place.
+type Querier interface {
+	QueryInstant(ctx context.Context, query string, at time.Time) ([]DataPoint, error)
+	// Backend is "hub" for the hub VictoriaMetrics or "target" for a
+	// monitoring stack on the target cluster.
+	Backend() string
+}
+
+// httpQuerier queries the hub VictoriaMetrics directly over HTTP.
+type httpQuerier struct {
+	url string
+}
+
+func (q httpQuerier) QueryInstant(ctx context.Context, query string, at time.Time) ([]DataPoint, error) {
+	return queryMetricsInstant(ctx, q.url, query, at)
+}
+
+func (q httpQuerier) Backend() string { return "hub" }
+
+// proxyQuerier queries a target cluster's monitoring service through the
+// K8s API server proxy.
+type proxyQuerier struct {
+	restClient rest.Interface
+	ep         MonitoringEndpoint
+}
+
+func (q proxyQuerier) QueryInstant(ctx context.Context, query string, at time.Time) ([]DataPoint, error) {
+	return queryInstantViaProxy(ctx, q.restClient, q.ep, query, at)
+}
+
+func (q proxyQuerier) Backend() string { return "target" }
+
+// selectorPattern matches the first vector selector with label matchers in a
+// PromQL expression, e.g. `container_cpu_usage_seconds_total{namespace="x"}`.
+var selectorPattern = regexp.MustCompile(`([a-zA-Z_:][a-zA-Z0-9_:]*)\{([^}]*)\}`)
+
+// matcherPattern matches a single label matcher inside a selector.
+var matcherPattern = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!=|!~|=)\s*"((?:[^"\\]|\\.)*)"`)
+
+// rangeVectorPattern matches a range vector or subquery, e.g. `[5m]` or `[$DURATION:1m]`.
+var rangeVectorPattern = regexp.MustCompile(`\[[^\]]+\]`)
+
+// scrapeIdentityLabels are the labels carried over from a metric selector to
+// the up{} query: they identify the scrape target rather than the series.
+var scrapeIdentityLabels = map[string]bool{
+	"experiment": true,
+	"namespace":  true,
+	"job":        true,
+	"service":    true,
+	"pod":        true,
+	"instance":   true,
+}
+
+// DiagnoseMissingMetrics classifies why each named metric in result has no
+// data. Metrics that errored are backend errors. For the rest, the query's
+// selector is checked against the backend at time at:
+//   - series present now but the query is empty → rateWindow (the range or
+//     $DURATION window does not line up with when data exists)
+//   - series absent and up{} for the same target is 0 or missing → targetDown
+//   - series absent while the target is up → seriesAbsent
+//
+// When result has no queryable backend (cadvisor scrapes), non-error metrics
+// are reported as seriesAbsent.
+func DiagnoseMissingMetrics(ctx context.Context, result *MetricsResult, missing []string, at time.Time) []experimentsv1alpha1.MetricDiagnosis {
+	if result == nil {
+		return nil
+	}
+
+	var diagnoses []experimentsv1alpha1.MetricDiagnosis
+	for _, name := range missing {
+		qr, ok := result.Queries[name]
+		if !ok {
+			continue
+		}
+		diagnoses = append(diagnoses, diagnoseQuery(ctx, result.querier, name, qr, at))
+	}
+	return diagnoses
+}
+
+// diagnoseQuery classifies a single missing query result.
+func diagnoseQuery(ctx context.Context, q Querier, name string, qr QueryResult, at time.Time) experimentsv1alpha1.MetricDiagnosis {
+	d := experimentsv1alpha1.MetricDiagnosis{Metric: name}
+
+	if qr.Error != "" {
+		d.Cause = experimentsv1alpha1.MissingDataBackendError
+		d.Detail = qr.Error
+		return d
+	}
+	if q == nil {
+		d.Cause = experimentsv1alpha1.MissingDataSeriesAbsent
+		d.Detail = "no queryable backend to diagnose against"
+		return d
+	}
+
+	metric, matchers := firstSelector(qr.Query)
+	if metric == "" {
+		d.Cause = experimentsv1alpha1.MissingDataSeriesAbsent
+		d.Detail = "query has no label selector to check"
+		return d
+	}
+
+	selector := fmt.Sprintf("%s{%s}", metric, strings.Join(matchers, ","))
+	series, err := q.QueryInstant(ctx, "count("+selector+")", at)
+	if err != nil {
+		d.Cause = experimentsv1alpha1.MissingDataBackendError
+		d.Detail = fmt.Sprintf("count(%s): %v", selector, err)
+		return d
+	}
+	if len(series) > 0 && series[0].Value > 0 {
+		if rangeVectorPattern.MatchString(qr.Query) {
+			d.Cause = experimentsv1alpha1.MissingDataRateWindow
+			d.Detail = fmt.Sprintf("%.0f series match %s but the windowed query returned nothing", series[0].Value, metric)
+		} else {
+			d.Cause = experimentsv1alpha1.MissingDataSeriesAbsent
+			d.Detail = fmt.Sprintf("%.0f series match %s but the query filters them all out", series[0].Value, metric)
+		}
+		return d
+	}
+
+	upSelector := upSelectorFor(matchers)
+	up, err := q.QueryInstant(ctx, upSelector, at)
+	if err != nil {
+		d.Cause = experimentsv1alpha1.MissingDataBackendError
+		d.Detail = fmt.Sprintf("%s: %v", upSelector, err)
+		return d
+	}
+	if len(up) == 0 {
+		d.Cause = experimentsv1alpha1.MissingDataTargetDown
+		d.Detail = fmt.Sprintf("no scrape targets match %s", upSelector)
+		return d
+	}
+	down := 0
+	for _, dp := range up {
+		if dp.Value == 0 {
+			down++
+		}
+	}
+	if down == len(up) {
+		d.Cause = experimentsv1alpha1.MissingDataTargetDown
+		d.Detail = fmt.Sprintf("all %d scrape targets matching %s are down", len(up), upSelector)
+		return d
+	}
+	d.Cause = experimentsv1alpha1.MissingDataSeriesAbsent
+	d.Detail = fmt.Sprintf("%d/%d scrape targets up but no %s series ingested", len(up)-down, len(up), metric)
+	return d
+}
+
+// firstSelector returns the metric name and raw label matchers of the first
+// vector selector in a PromQL query, or "" if the query has none.
+func firstSelector(query string) (string, []string) {
+	m := selectorPattern.FindStringSubmatch(query)
+	if m == nil {
+		return "", nil
+	}
+	var matchers []string
+	for _, mm := range matcherPattern.FindAllString(m[2], -1) {
+		matchers = append(matchers, strings.TrimSpace(mm))
+	}
+	return m[1], matchers
+}
+
+// upSelectorFor builds an up{} selector from the positive scrape-identity
+// matchers of a metric selector.
+func upSelectorFor(matchers []string) string {
+	var kept []string
+	for _, raw := range matchers {
+		m := matcherPattern.FindStringSubmatch(raw)
+		if m == nil {
+			continue
+		}
+		if !scrapeIdentityLabels[m[1]] || (m[2] != "=" && m[2] != "=~") {
+			continue
+		}
+		kept = append(kept, raw)
+	}
+	if len(kept) == 0 {
+		return "up"
+	}
+	return "up{" + strings.Join(kept, ",") + "}"
+}
+
+// ChooseRemedy picks the re-collection strategy for a failed quality result.
+// Diagnoses of missing required metrics take precedence over the rest. A
+// backend error anywhere means the current backend cannot answer, so switching
+// backends comes first; rate-window problems are fixed by a shorter window;
+// everything else (target down, series not yet ingested) needs time.
+func ChooseRemedy(qr experimentsv1alpha1.QualityResult) experimentsv1alpha1.RemedyAction {
+	diagnoses := qr.Diagnoses
+	if len(qr.MissingRequired) > 0 {
+		required := make(map[string]bool, len(qr.MissingRequired))
+		for _, name := range qr.MissingRequired {
+			required[name] = true
+		}
+		var filtered []experimentsv1alpha1.MetricDiagnosis
+		for _, d := range diagnoses {
+			if required[d.Metric] {
+				filtered = append(filtered, d)
+			}
+		}
+		if len(filtered) > 0 {
+			diagnoses = filtered
+		}
+	}
+
+	counts := make(map[experimentsv1alpha1.MissingDataCause]int)
+	for _, d := range diagnoses {
+		counts[d.Cause]++
+	}
+	switch {
+	case counts[experimentsv1alpha1.MissingDataBackendError] > 0:
+		return experimentsv1alpha1.RemedySwitchBackend
+	case counts[experimentsv1alpha1.MissingDataRateWindow] > 0:
+		return experimentsv1alpha1.RemedyShortenWindow
+	default:
+		return experimentsv1alpha1.RemedyWaitForScrape
+	}
+}
+
+// DescribeRemedy returns a human-readable explanation of a quality result's
+// diagnoses and chosen action, for status and logs.
+func DescribeRemedy(qr experimentsv1alpha1.QualityResult) string {
+	counts := make(map[experimentsv1alpha1.MissingDataCause]int)
+	for _, d := range qr.Diagnoses {
+		counts[d.Cause]++
+	}
+	var causes []string
+	for _, c := range []experimentsv1alpha1.MissingDataCause{
+		experimentsv1alpha1.MissingDataBackendError,
+		experimentsv1alpha1.MissingDataTargetDown,
+		experimentsv1alpha1.MissingDataSeriesAbsent,
+		experimentsv1alpha1.MissingDataRateWindow,
+	} {
+		if counts[c] > 0 {
+			causes = append(causes, fmt.Sprintf("%d %s", counts[c], c))
+		}
+	}
+
+	var action string
+	switch qr.Action {
+	case experimentsv1alpha1.RemedySwitchBackend:
+		action = "Will re-collect from the other metrics backend."
+	case experimentsv1alpha1.RemedyShortenWindow:
+		action = "Will re-collect with a shorter $DURATION anchored to the workload window."
+	case experimentsv1alpha1.RemedyWaitForScrape:
+		action = "Will wait for scrapes and re-collect with the same window."
+	}
+
+	msg := fmt.Sprintf("Coverage %.0f%% (%d/%d metrics with data)", qr.Coverage*100, qr.MetricsWithData, qr.TotalMetrics)
+	if len(qr.MissingRequired) > 0 {
+		msg += fmt.Sprintf(", required missing: %s", strings.Join(qr.MissingRequired, ", "))
+	}
+	if len(causes) > 0 {
+		msg += "; causes: " + strings.Join(causes, ", ")
+	}
+	msg += "."
+	if action != "" {
+		msg += " " + action
+	}
+	return msg
+}
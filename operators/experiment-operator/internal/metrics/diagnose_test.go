@@ -0,0 +1,279 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeQuerier answers instant queries from a fixed table keyed by query prefix.
+type fakeQuerier struct {
+	responses map[string][]DataPoint
+	err       error
+	queries   []string
+}
+
+func (f *fakeQuerier) QueryInstant(_ context.Context, query string, _ time.Time) ([]DataPoint, error) {
+	f.queries = append(f.queries, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	for prefix, data := range f.responses {
+		if strings.HasPrefix(query, prefix) {
+			return data, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeQuerier) Backend() string { return "target" }
+
+func TestDiagnoseQuery(t *testing.T) {
+	rateQuery := `sum(rate(container_cpu_usage_seconds_total{namespace="exp-a",container!="POD"}[5m]))`
+	plainQuery := `sum(container_memory_working_set_bytes{namespace="exp-a"})`
+
+	tests := []struct {
+		name      string
+		qr        QueryResult
+		querier   Querier
+		wantCause experimentsv1alpha1.MissingDataCause
+	}{
+		{
+			name:      "query error",
+			qr:        QueryResult{Query: rateQuery, Error: "metrics query: timeout"},
+			querier:   &fakeQuerier{},
+			wantCause: experimentsv1alpha1.MissingDataBackendError,
+		},
+		{
+			name: "series present with range vector",
+			qr:   QueryResult{Query: rateQuery},
+			querier: &fakeQuerier{responses: map[string][]DataPoint{
+				"count(": {{Value: 12}},
+			}},
+			wantCause: experimentsv1alpha1.MissingDataRateWindow,
+		},
+		{
+			name: "series present without range vector",
+			qr:   QueryResult{Query: plainQuery},
+			querier: &fakeQuerier{responses: map[string][]DataPoint{
+				"count(": {{Value: 3}},
+			}},
+			wantCause: experimentsv1alpha1.MissingDataSeriesAbsent,
+		},
+		{
+			name:      "no scrape targets",
+			qr:        QueryResult{Query: rateQuery},
+			querier:   &fakeQuerier{},
+			wantCause: experimentsv1alpha1.MissingDataTargetDown,
+		},
+		{
+			name: "all scrape targets down",
+			qr:   QueryResult{Query: rateQuery},
+			querier: &fakeQuerier{responses: map[string][]DataPoint{
+				"up": {{Value: 0}, {Value: 0}},
+			}},
+			wantCause: experimentsv1alpha1.MissingDataTargetDown,
+		},
+		{
+			name: "targets up but series absent",
+			qr:   QueryResult{Query: rateQuery},
+			querier: &fakeQuerier{responses: map[string][]DataPoint{
+				"up": {{Value: 1}, {Value: 0}},
+			}},
+			wantCause: experimentsv1alpha1.MissingDataSeriesAbsent,
+		},
+		{
+			name:      "diagnostic query fails",
+			qr:        QueryResult{Query: rateQuery},
+			querier:   &fakeQuerier{err: errors.New("connection refused")},
+			wantCause: experimentsv1alpha1.MissingDataBackendError,
+		},
+		{
+			name:      "no backend",
+			qr:        QueryResult{Query: rateQuery},
+			querier:   nil,
+			wantCause: experimentsv1alpha1.MissingDataSeriesAbsent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diagnoseQuery(context.Background(), tt.querier, "m", tt.qr, time.Now())
+			if got.Cause != tt.wantCause {
+				t.Errorf("Cause = %q, want %q (detail: %s)", got.Cause, tt.wantCause, got.Detail)
+			}
+			if got.Metric != "m" {
+				t.Errorf("Metric = %q, want %q", got.Metric, "m")
+			}
+		})
+	}
+}
+
+func TestDiagnoseQueryUpSelector(t *testing.T) {
+	q := &fakeQuerier{}
+	qr := QueryResult{Query: `sum(rate(http_requests_total{namespace="exp-a",job=~"api.*",code!="500"}[1m]))`}
+	diagnoseQuery(context.Background(), q, "m", qr, time.Now())
+
+	want := []string{
+		`count(http_requests_total{namespace="exp-a",job=~"api.*",code!="500"})`,
+		`up{namespace="exp-a",job=~"api.*"}`,
+	}
+	if len(q.queries) != len(want) {
+		t.Fatalf("queries = %v, want %v", q.queries, want)
+	}
+	for i := range want {
+		if q.queries[i] != want[i] {
+			t.Errorf("query[%d] = %q, want %q", i, q.queries[i], want[i])
+		}
+	}
+}
+
+func TestUpSelectorForNoIdentityLabels(t *testing.T) {
+	if got := upSelectorFor([]string{`container!="POD"`, `le="0.5"`}); got != "up" {
+		t.Errorf("upSelectorFor() = %q, want %q", got, "up")
+	}
+}
+
+func TestFirstSelectorNone(t *testing.T) {
+	if metric, _ := firstSelector(`sum(up)`); metric != "" {
+		t.Errorf("firstSelector() metric = %q, want empty", metric)
+	}
+}
+
+func TestChooseRemedy(t *testing.T) {
+	diag := func(metric string, cause experimentsv1alpha1.MissingDataCause) experimentsv1alpha1.MetricDiagnosis {
+		return experimentsv1alpha1.MetricDiagnosis{Metric: metric, Cause: cause}
+	}
+
+	tests := []struct {
+		name string
+		qr   experimentsv1alpha1.QualityResult
+		want experimentsv1alpha1.RemedyAction
+	}{
+		{
+			name: "backend error wins",
+			qr: experimentsv1alpha1.QualityResult{Diagnoses: []experimentsv1alpha1.MetricDiagnosis{
+				diag("a", experimentsv1alpha1.MissingDataRateWindow),
+				diag("b", experimentsv1alpha1.MissingDataBackendError),
+			}},
+			want: experimentsv1alpha1.RemedySwitchBackend,
+		},
+		{
+			name: "rate window shortens",
+			qr: experimentsv1alpha1.QualityResult{Diagnoses: []experimentsv1alpha1.MetricDiagnosis{
+				diag("a", experimentsv1alpha1.MissingDataRateWindow),
+				diag("b", experimentsv1alpha1.MissingDataTargetDown),
+			}},
+			want: experimentsv1alpha1.RemedyShortenWindow,
+		},
+		{
+			name: "target down waits",
+			qr: experimentsv1alpha1.QualityResult{Diagnoses: []experimentsv1alpha1.MetricDiagnosis{
+				diag("a", experimentsv1alpha1.MissingDataTargetDown),
+			}},
+			want: experimentsv1alpha1.RemedyWaitForScrape,
+		},
+		{
+			name: "required metrics take precedence",
+			qr: experimentsv1alpha1.QualityResult{
+				MissingRequired: []string{"req"},
+				Diagnoses: []experimentsv1alpha1.MetricDiagnosis{
+					diag("opt", experimentsv1alpha1.MissingDataBackendError),
+					diag("req", experimentsv1alpha1.MissingDataRateWindow),
+				},
+			},
+			want: experimentsv1alpha1.RemedyShortenWindow,
+		},
+		{
+			name: "no diagnoses waits",
+			qr:   experimentsv1alpha1.QualityResult{},
+			want: experimentsv1alpha1.RemedyWaitForScrape,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChooseRemedy(tt.qr); got != tt.want {
+				t.Errorf("ChooseRemedy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateMetricsQualityRequired(t *testing.T) {
+	summary := &ExperimentSummary{Metrics: &MetricsResult{Queries: map[string]QueryResult{
+		"a": {Data: []DataPoint{{Value: 1}}},
+		"b": {Data: []DataPoint{{Value: 1}}},
+		"c": {Data: []DataPoint{{Value: 1}}},
+		"d": {Required: true},
+	}}}
+
+	qr := EvaluateMetricsQuality(summary, 0.5)
+	if qr.Coverage != 0.75 {
+		t.Errorf("Coverage = %v, want 0.75", qr.Coverage)
+	}
+	if qr.Passed {
+		t.Error("Passed = true, want false with a required metric missing")
+	}
+	if len(qr.MissingRequired) != 1 || qr.MissingRequired[0] != "d" {
+		t.Errorf("MissingRequired = %v, want [d]", qr.MissingRequired)
+	}
+
+	delete(summary.Metrics.Queries, "d")
+	summary.Metrics.Queries["e"] = QueryResult{}
+	if qr := EvaluateMetricsQuality(summary, 0.5); !qr.Passed {
+		t.Errorf("Passed = false, want true when only optional metrics are missing (%+v)", qr)
+	}
+}
+
+func TestIterationDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		step int
+		full time.Duration
+		want time.Duration
+	}{
+		{"full window", 0, 45 * time.Minute, 45 * time.Minute},
+		{"first shrink", 1, 45 * time.Minute, 10 * time.Minute},
+		{"second shrink", 2, 45 * time.Minute, 5 * time.Minute},
+		{"floor", 5, 45 * time.Minute, 2 * time.Minute},
+		{"capped by short workload", 1, 4 * time.Minute, 4 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IterationDuration(tt.step, tt.full); got != tt.want {
+				t.Errorf("IterationDuration(%d, %s) = %s, want %s", tt.step, tt.full, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkloadWindow(t *testing.T) {
+	created := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	started := created.Add(12 * time.Minute)
+	finished := started.Add(30 * time.Minute)
+
+	exp := &experimentsv1alpha1.Experiment{}
+	exp.CreationTimestamp = metav1.Time{Time: created}
+	exp.Status.WorkflowStatus = &experimentsv1alpha1.WorkflowStatus{
+		Name:       "wf",
+		StartedAt:  &metav1.Time{Time: started},
+		FinishedAt: &metav1.Time{Time: finished},
+	}
+
+	start, end := WorkloadWindow(exp)
+	if !start.Equal(started) || !end.Equal(finished) {
+		t.Errorf("WorkloadWindow() = %s..%s, want %s..%s", start, end, started, finished)
+	}
+
+	exp.Status.WorkflowStatus = nil
+	start, _ = WorkloadWindow(exp)
+	if !start.Equal(created) {
+		t.Errorf("WorkloadWindow() start = %s, want creation time %s", start, created)
+	}
+}
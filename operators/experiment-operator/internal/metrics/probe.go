@@ -0,0 +1,313 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultProbeIterations = 20
+	defaultProbeTimeout    = 10 * time.Second
+	defaultProbeRange      = 5 * time.Minute
+)
+
+// probeRequest is a fully resolved probe request: the path below the service
+// proxy and the query parameters to send.
+type probeRequest struct {
+	Path   string
+	Params map[string]string
+}
+
+// probeFunc issues a single probe request and returns an error if the backend
+// did not answer successfully.
+type probeFunc func(ctx context.Context, req probeRequest) error
+
+// RunProbes replays each probe against the target cluster's backend through the
+// API server service proxy and returns one ProbeResult per probe. A probe whose
+// requests all fail still yields a result (with Errors == Iterations), and a
+// probe with an unusable spec yields a result carrying only LastError, so
+// failures are visible in the summary rather than silently dropped. Probes
+// not finished when ctx is done are left out for the caller to run later.
+func RunProbes(ctx context.Context, kubeconfig []byte, exp *experimentsv1alpha1.Experiment, targetName string, probes []experimentsv1alpha1.ProbeSpec) ([]experimentsv1alpha1.ProbeResult, error) {
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("parse kubeconfig: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create clientset: %w", err)
+	}
+	restClient := clientset.CoreV1().RESTClient()
+	logger := log.FromContext(ctx)
+
+	now := time.Now()
+	start, _ := WorkloadWindow(exp)
+	vars := queryVars(exp, exp.Name, start, now, now.Sub(start))
+
+	var results []experimentsv1alpha1.ProbeResult
+	for _, p := range probes {
+		if ctx.Err() != nil {
+			break
+		}
+		req, err := buildProbeRequest(p, vars, now)
+		if err != nil {
+			results = append(results, FailedProbeResult(exp, targetName, p, err.Error()))
+			continue
+		}
+
+		ns := probeNamespace(exp, p)
+		do := proxyProbeFunc(restClient, ns, p.Service, p.Port, probeType(p))
+		pr, ok := runProbe(ctx, p, req, do)
+		if !ok {
+			break
+		}
+		pr.Target = targetName
+		pr.Endpoint = probeServiceEndpoint(exp, p)
+		logger.Info("Probe completed", "probe", p.Name, "target", targetName,
+			"iterations", pr.Iterations, "errors", pr.Errors, "p50Ms", pr.P50Ms, "p99Ms", pr.P99Ms)
+		results = append(results, pr)
+	}
+	return results, nil
+}
+
+// FailedProbeResult is the result of a probe that could not run, carrying
+// only the reason in LastError.
+func FailedProbeResult(exp *experimentsv1alpha1.Experiment, targetName string, p experimentsv1alpha1.ProbeSpec, reason string) experimentsv1alpha1.ProbeResult {
+	return experimentsv1alpha1.ProbeResult{
+		Name:        p.Name,
+		Target:      targetName,
+		Type:        probeType(p),
+		Endpoint:    probeServiceEndpoint(exp, p),
+		LastError:   reason,
+		CompletedAt: &metav1.Time{Time: time.Now()},
+	}
+}
+
+// probeNamespace returns the probe's namespace, defaulting to the
+// experiment's.
+func probeNamespace(exp *experimentsv1alpha1.Experiment, p experimentsv1alpha1.ProbeSpec) string {
+	if p.Namespace == "" {
+		return exp.Name
+	}
+	return p.Namespace
+}
+
+// probeServiceEndpoint names the probed service as namespace/service:port.
+func probeServiceEndpoint(exp *experimentsv1alpha1.Experiment, p experimentsv1alpha1.ProbeSpec) string {
+	return fmt.Sprintf("%s/%s:%d", probeNamespace(exp, p), p.Service, p.Port)
+}
+
+// probeType returns the probe type with the default applied.
+func probeType(p experimentsv1alpha1.ProbeSpec) string {
+	if p.Type == "" {
+		return "promql"
+	}
+	return p.Type
+}
+
+// buildProbeRequest resolves the request path and parameters for a probe.
+// Variables in the query are substituted once, so every iteration sends an
+// identical request.
+func buildProbeRequest(p experimentsv1alpha1.ProbeSpec, vars map[string]string, now time.Time) (probeRequest, error) {
+	req := probeRequest{Path: p.Path, Params: make(map[string]string)}
+	for k, v := range p.Params {
+		req.Params[k] = substituteVars(v, vars)
+	}
+
+	switch probeType(p) {
+	case "promql":
+		if p.Query == "" {
+			return req, fmt.Errorf("probe %s: query is required for promql probes", p.Name)
+		}
+		if req.Path == "" {
+			req.Path = "/api/v1/query"
+		}
+		req.Params["query"] = substituteVars(p.Query, vars)
+	case "logql":
+		if p.Query == "" {
+			return req, fmt.Errorf("probe %s: query is required for logql probes", p.Name)
+		}
+		if req.Path == "" {
+			req.Path = "/loki/api/v1/query_range"
+		}
+		lookback := defaultProbeRange
+		if p.Range != "" {
+			d, err := time.ParseDuration(p.Range)
+			if err != nil {
+				return req, fmt.Errorf("probe %s: invalid range %q: %w", p.Name, p.Range, err)
+			}
+			lookback = d
+		}
+		req.Params["query"] = substituteVars(p.Query, vars)
+		req.Params["start"] = strconv.FormatInt(now.Add(-lookback).UnixNano(), 10)
+		req.Params["end"] = strconv.FormatInt(now.UnixNano(), 10)
+	case "http":
+		if req.Path == "" {
+			return req, fmt.Errorf("probe %s: path is required for http probes", p.Name)
+		}
+	default:
+		return req, fmt.Errorf("probe %s: unknown type %q", p.Name, p.Type)
+	}
+	return req, nil
+}
+
+// proxyProbeFunc returns a probeFunc that sends requests through the K8s API
+// server service proxy. For promql and logql probes the response body must be
+// a Prometheus-style envelope with status "success"; http probes only require
+// a 2xx response.
+func proxyProbeFunc(restClient rest.Interface, namespace, service string, port int, typ string) probeFunc {
+	return func(ctx context.Context, req probeRequest) error {
+		segments := []string{}
+		for _, s := range strings.Split(req.Path, "/") {
+			if s != "" {
+				segments = append(segments, s)
+			}
+		}
+
+		r := restClient.Get().
+			Namespace(namespace).
+			Resource("services").
+			Name(fmt.Sprintf("%s:%d", service, port)).
+			SubResource(append([]string{"proxy"}, segments...)...)
+		for k, v := range req.Params {
+			r = r.Param(k, v)
+		}
+
+		raw, err := r.Do(ctx).Raw()
+		if err != nil {
+			return fmt.Errorf("proxy request: %w", err)
+		}
+		if typ == "http" {
+			return nil
+		}
+
+		var resp struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return fmt.Errorf("unmarshal response: %w (body: %.200s)", err, string(raw))
+		}
+		if resp.Status != "success" {
+			return fmt.Errorf("backend returned status %q: %s", resp.Status, resp.Error)
+		}
+		return nil
+	}
+}
+
+// runProbe sends the request p.Iterations times sequentially and summarizes
+// the latency of successful requests. Failed and timed-out requests are
+// counted as errors and excluded from the latency distribution. If ctx is
+// done before every iteration ran, the partial result is discarded and ok is
+// false.
+func runProbe(ctx context.Context, p experimentsv1alpha1.ProbeSpec, req probeRequest, do probeFunc) (result experimentsv1alpha1.ProbeResult, ok bool) {
+	iterations := p.Iterations
+	if iterations <= 0 {
+		iterations = defaultProbeIterations
+	}
+	timeout := defaultProbeTimeout
+	if p.TimeoutSeconds > 0 {
+		timeout = time.Duration(p.TimeoutSeconds) * time.Second
+	}
+
+	result = experimentsv1alpha1.ProbeResult{
+		Name:       p.Name,
+		Type:       probeType(p),
+		Iterations: iterations,
+	}
+
+	latencies := make([]float64, 0, iterations)
+	for i := 0; i < iterations; i++ {
+		if ctx.Err() != nil {
+			return experimentsv1alpha1.ProbeResult{}, false
+		}
+		reqCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := do(reqCtx, req)
+		elapsed := time.Since(start)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				// Cut off by ctx rather than failed by the backend.
+				return experimentsv1alpha1.ProbeResult{}, false
+			}
+			result.Errors++
+			result.LastError = err.Error()
+			continue
+		}
+		latencies = append(latencies, float64(elapsed.Microseconds())/1000)
+	}
+
+	result.ErrorRate = round2(float64(result.Errors) / float64(iterations))
+	stats := summarizeLatencies(latencies)
+	result.MinMs = stats.Min
+	result.MeanMs = stats.Mean
+	result.P50Ms = stats.P50
+	result.P90Ms = stats.P90
+	result.P95Ms = stats.P95
+	result.P99Ms = stats.P99
+	result.MaxMs = stats.Max
+	result.CompletedAt = &metav1.Time{Time: time.Now()}
+	return result, true
+}
+
+// latencyStats is the latency distribution of a set of samples, in milliseconds.
+type latencyStats struct {
+	Min, Mean, P50, P90, P95, P99, Max float64
+}
+
+// summarizeLatencies computes min/mean/max and nearest-rank percentiles.
+// Returns zero values for an empty sample set.
+func summarizeLatencies(ms []float64) latencyStats {
+	if len(ms) == 0 {
+		return latencyStats{}
+	}
+	sorted := append([]float64(nil), ms...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	return latencyStats{
+		Min:  round2(sorted[0]),
+		Mean: round2(sum / float64(len(sorted))),
+		P50:  round2(percentile(sorted, 50)),
+		P90:  round2(percentile(sorted, 90)),
+		P95:  round2(percentile(sorted, 95)),
+		P99:  round2(percentile(sorted, 99)),
+		Max:  round2(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile of an ascending-sorted slice.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// round2 rounds to two decimal places to keep summary.json readable.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
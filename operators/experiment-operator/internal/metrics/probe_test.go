@@ -0,0 +1,239 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+func TestBuildProbeRequest(t *testing.T) {
+	vars := map[string]string{
+		"$EXPERIMENT": "tsdb-comparison-abc",
+		"$NAMESPACE":  "tsdb-comparison-abc",
+		"$DURATION":   "30m",
+	}
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name       string
+		probe      experimentsv1alpha1.ProbeSpec
+		wantPath   string
+		wantParams map[string]string
+		wantErr    bool
+	}{
+		{
+			name:       "promql defaults",
+			probe:      experimentsv1alpha1.ProbeSpec{Name: "p", Query: `sum(rate(x{ns="$NAMESPACE"}[$DURATION]))`},
+			wantPath:   "/api/v1/query",
+			wantParams: map[string]string{"query": `sum(rate(x{ns="tsdb-comparison-abc"}[30m]))`},
+		},
+		{
+			name:       "promql path override",
+			probe:      experimentsv1alpha1.ProbeSpec{Name: "p", Type: "promql", Path: "/prometheus/api/v1/query", Query: "up"},
+			wantPath:   "/prometheus/api/v1/query",
+			wantParams: map[string]string{"query": "up"},
+		},
+		{
+			name:     "logql with range",
+			probe:    experimentsv1alpha1.ProbeSpec{Name: "l", Type: "logql", Query: `{app="x"}`, Range: "1m"},
+			wantPath: "/loki/api/v1/query_range",
+			wantParams: map[string]string{
+				"query": `{app="x"}`,
+				"start": "1699999940000000000",
+				"end":   "1700000000000000000",
+			},
+		},
+		{
+			name:       "http with params",
+			probe:      experimentsv1alpha1.ProbeSpec{Name: "h", Type: "http", Path: "/health", Params: map[string]string{"exp": "$EXPERIMENT"}},
+			wantPath:   "/health",
+			wantParams: map[string]string{"exp": "tsdb-comparison-abc"},
+		},
+		{
+			name:    "promql without query",
+			probe:   experimentsv1alpha1.ProbeSpec{Name: "p"},
+			wantErr: true,
+		},
+		{
+			name:    "http without path",
+			probe:   experimentsv1alpha1.ProbeSpec{Name: "h", Type: "http"},
+			wantErr: true,
+		},
+		{
+			name:    "logql invalid range",
+			probe:   experimentsv1alpha1.ProbeSpec{Name: "l", Type: "logql", Query: "x", Range: "five"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildProbeRequest(tt.probe, vars, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", got.Path, tt.wantPath)
+			}
+			if len(got.Params) != len(tt.wantParams) {
+				t.Fatalf("Params = %v, want %v", got.Params, tt.wantParams)
+			}
+			for k, v := range tt.wantParams {
+				if got.Params[k] != v {
+					t.Errorf("Params[%q] = %q, want %q", k, got.Params[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestRunProbeCountsErrors(t *testing.T) {
+	calls := 0
+	do := func(ctx context.Context, req probeRequest) error {
+		calls++
+		if calls%4 == 0 {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	probe := experimentsv1alpha1.ProbeSpec{Name: "p", Iterations: 8}
+	got, _ := runProbe(context.Background(), probe, probeRequest{}, do)
+
+	if calls != 8 {
+		t.Errorf("calls = %d, want 8", calls)
+	}
+	if got.Iterations != 8 || got.Errors != 2 {
+		t.Errorf("Iterations/Errors = %d/%d, want 8/2", got.Iterations, got.Errors)
+	}
+	if got.ErrorRate != 0.25 {
+		t.Errorf("ErrorRate = %v, want 0.25", got.ErrorRate)
+	}
+	if got.LastError != "boom" {
+		t.Errorf("LastError = %q, want %q", got.LastError, "boom")
+	}
+	if got.CompletedAt == nil {
+		t.Error("CompletedAt not set")
+	}
+}
+
+func TestRunProbeDefaultsIterations(t *testing.T) {
+	calls := 0
+	do := func(ctx context.Context, req probeRequest) error {
+		calls++
+		return nil
+	}
+	got, _ := runProbe(context.Background(), experimentsv1alpha1.ProbeSpec{Name: "p"}, probeRequest{}, do)
+	if calls != defaultProbeIterations || got.Iterations != defaultProbeIterations {
+		t.Errorf("calls = %d, Iterations = %d, want %d", calls, got.Iterations, defaultProbeIterations)
+	}
+}
+
+func TestRunProbeCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	do := func(ctx context.Context, req probeRequest) error {
+		t.Fatal("request sent on cancelled context")
+		return nil
+	}
+	if got, ok := runProbe(ctx, experimentsv1alpha1.ProbeSpec{Name: "p", Iterations: 5}, probeRequest{}, do); ok {
+		t.Errorf("runProbe() = %+v, want no result on a cancelled context", got)
+	}
+}
+
+func TestRunProbeDiscardsInterruptedRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	do := func(reqCtx context.Context, req probeRequest) error {
+		calls++
+		if calls == 3 {
+			cancel()
+			return reqCtx.Err()
+		}
+		return nil
+	}
+	if got, ok := runProbe(ctx, experimentsv1alpha1.ProbeSpec{Name: "p", Iterations: 10}, probeRequest{}, do); ok {
+		t.Errorf("runProbe() = %+v, want the partial run discarded", got)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRunProbesLeavesUnstartedProbes(t *testing.T) {
+	kubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: c
+  cluster: {server: "https://127.0.0.1:1"}
+contexts:
+- name: c
+  context: {cluster: c, user: u}
+current-context: c
+users:
+- name: u
+  user: {token: t}
+`)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	exp := &experimentsv1alpha1.Experiment{}
+	exp.Name = "exp"
+	probes := []experimentsv1alpha1.ProbeSpec{{Name: "a", Query: "up"}, {Name: "b", Query: "up"}}
+	got, err := RunProbes(ctx, kubeconfig, exp, "app", probes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("RunProbes() = %+v, want no results once the context is done", got)
+	}
+}
+
+func TestSummarizeLatencies(t *testing.T) {
+	var samples []float64
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, float64(i))
+	}
+
+	got := summarizeLatencies(samples)
+	want := latencyStats{Min: 1, Mean: 50.5, P50: 50, P90: 90, P95: 95, P99: 99, Max: 100}
+	if got != want {
+		t.Errorf("summarizeLatencies() = %+v, want %+v", got, want)
+	}
+	if samples[0] != 100 {
+		t.Error("summarizeLatencies() mutated its input")
+	}
+}
+
+func TestSummarizeLatenciesEmpty(t *testing.T) {
+	if got := summarizeLatencies(nil); got != (latencyStats{}) {
+		t.Errorf("summarizeLatencies(nil) = %+v, want zero", got)
+	}
+}
+
+func TestPercentileSmallSample(t *testing.T) {
+	sorted := []float64{10, 20, 30}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 10},
+		{50, 20},
+		{99, 30},
+		{100, 30},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
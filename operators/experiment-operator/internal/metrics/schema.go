@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SummarySchemaVersion is the current layout version of summary.json.
+// Summaries written before the field existed are version 0; MigrateSummary
+// upgrades them.
+const SummarySchemaVersion = 1
+
+// SummarySchemaID is the $id of the published schema, relative to the
+// benchmark site root (site/public/schema/).
+const SummarySchemaID = "/schema/experiment-summary.v1.json"
+
+// summaryEnums restricts string fields, keyed by "GoType.jsonName". Shared by
+// the schema generator and ValidateSummary.
+var summaryEnums = map[string][]string{
+	"QueryResult.type":                         {"instant", "range"},
+	"HypothesisContext.machineVerdict":         {"validated", "invalidated", "insufficient"},
+	"AnalysisResult.hypothesisVerdict":         {"validated", "invalidated", "insufficient"},
+	"AnalysisResult.architectureDiagramFormat": {"ascii", "mermaid"},
+	"CodeAnnotation.category":                  {"syscall", "algorithm", "hot-path", "config", "branching", "io", "general"},
+	"BodyBlock.size":                           {"large", "small"},
+	"BodyBlock.format":                         {"ascii", "mermaid"},
+	"BodyBlock.variant":                        {"info", "warning", "success", "finding"},
+	"BodyBlock.priority":                       {"p0", "p1", "p2", "p3"},
+	"BodyBlock.effort":                         {"low", "medium", "high"},
+}
+
+// bodyBlockTypes maps each BodyBlock type to the fields the site requires for
+// it. Fields not listed may still be set (e.g. caption, insight).
+var bodyBlockTypes = map[string][]string{
+	"text":           {"content"},
+	"topic":          {"title", "blocks"},
+	"metric":         {"key"},
+	"comparison":     {"items"},
+	"capabilityRow":  {"capability", "values"},
+	"table":          {"headers", "rows"},
+	"architecture":   {"diagram"},
+	"callout":        {"variant", "title", "content"},
+	"recommendation": {"priority", "title", "description"},
+	"code":           {"key"},
+	"row":            {"blocks"},
+}
+
+// SummaryJSONSchema returns a JSON Schema (draft 2020-12) for summary.json,
+// generated from ExperimentSummary and the types it references. Unknown
+// properties are allowed so older readers accept newer additive fields.
+func SummaryJSONSchema() map[string]any {
+	g := &schemaGen{defs: make(map[string]any), names: make(map[reflect.Type]string)}
+	root := g.structSchema(reflect.TypeOf(ExperimentSummary{}))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SummarySchemaID
+	root["title"] = "ExperimentSummary"
+	props := root["properties"].(map[string]any)
+	props["schemaVersion"] = map[string]any{"type": "integer", "const": SummarySchemaVersion}
+	root["$defs"] = g.defs
+	return root
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	metaTimeType   = reflect.TypeOf(metav1.Time{})
+	bodyBlockType  = reflect.TypeOf(BodyBlock{})
+	experimentType = reflect.TypeOf(ExperimentSummary{})
+)
+
+type schemaGen struct {
+	defs  map[string]any
+	names map[reflect.Type]string
+}
+
+func (g *schemaGen) schemaFor(t reflect.Type) map[string]any {
+	switch t {
+	case timeType, metaTimeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaFor(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t == experimentType {
+			return map[string]any{"$ref": "#"}
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = t.Name()
+			if _, taken := g.defs[name]; taken {
+				name = path.Base(t.PkgPath()) + name
+			}
+			g.names[t] = name
+			g.defs[name] = map[string]any{} // placeholder breaks recursion (BodyBlock)
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	return map[string]any{}
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	g.addFields(t, props, &required)
+
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if t == bodyBlockType {
+		g.addBodyBlockRules(s, props)
+	}
+	return s
+}
+
+func (g *schemaGen) addFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(ft, props, required)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schemaFor(f.Type)
+		if enum, ok := summaryEnums[t.Name()+"."+name]; ok {
+			fs["enum"] = enum
+		}
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+			// encoding/json writes nil slices and maps as null.
+			if k := f.Type.Kind(); k == reflect.Slice || k == reflect.Map {
+				fs["type"] = []string{fs["type"].(string), "null"}
+			}
+		}
+		props[name] = fs
+	}
+}
+
+// addBodyBlockRules turns the flat BodyBlock property list into a
+// discriminated union: type must be known, and each type requires its fields.
+func (g *schemaGen) addBodyBlockRules(s, props map[string]any) {
+	types := make([]string, 0, len(bodyBlockTypes))
+	for typ := range bodyBlockTypes {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	props["type"] = map[string]any{"type": "string", "enum": types}
+
+	var rules []any
+	for _, typ := range types {
+		rules = append(rules, map[string]any{
+			"if":   map[string]any{"properties": map[string]any{"type": map[string]any{"const": typ}}},
+			"then": map[string]any{"required": bodyBlockTypes[typ]},
+		})
+	}
+	s["allOf"] = rules
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// publishedSchema is the copy served by the benchmark site; regenerate it
+// with `make summary-schema`.
+const publishedSchema = "../../../../site/public/schema/experiment-summary.v1.json"
+
+func TestSummaryJSONSchema(t *testing.T) {
+	s := SummaryJSONSchema()
+
+	if s["$id"] != SummarySchemaID {
+		t.Errorf("$id = %v, want %s", s["$id"], SummarySchemaID)
+	}
+	required := s["required"].([]string)
+	for _, want := range []string{"schemaVersion", "name", "targets"} {
+		found := false
+		for _, r := range required {
+			found = found || r == want
+		}
+		if !found {
+			t.Errorf("required = %v, missing %s", required, want)
+		}
+	}
+
+	defs := s["$defs"].(map[string]any)
+	block, ok := defs["BodyBlock"].(map[string]any)
+	if !ok {
+		t.Fatalf("$defs missing BodyBlock: %v", defs)
+	}
+	if rules := block["allOf"].([]any); len(rules) != len(bodyBlockTypes) {
+		t.Errorf("BodyBlock has %d type rules, want %d", len(rules), len(bodyBlockTypes))
+	}
+	nested := block["properties"].(map[string]any)["blocks"].(map[string]any)["items"].(map[string]any)
+	if nested["$ref"] != "#/$defs/BodyBlock" {
+		t.Errorf("nested blocks = %v, want recursive $ref", nested)
+	}
+
+	analysis := defs["AnalysisResult"].(map[string]any)["properties"].(map[string]any)
+	verdict := analysis["hypothesisVerdict"].(map[string]any)
+	if enum, _ := verdict["enum"].([]string); len(enum) != 3 {
+		t.Errorf("hypothesisVerdict enum = %v, want 3 values", verdict["enum"])
+	}
+	if _, ok := defs["IterationStatus"]; !ok {
+		t.Error("$defs missing IterationStatus from the api package")
+	}
+}
+
+func TestPublishedSchemaUpToDate(t *testing.T) {
+	published, err := os.ReadFile(publishedSchema)
+	if os.IsNotExist(err) {
+		t.Skip("site checkout not present")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.MarshalIndent(SummaryJSONSchema(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(published), want) {
+		t.Error("published summary schema is stale; run `make summary-schema`")
+	}
+}
@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+const (
+	// defaultMaxCV is the coefficient of variation below which a window is steady.
+	defaultMaxCV = 0.1
+	// minSteadyPoints is the fewest points a series needs for auto detection.
+	minSteadyPoints = 6
+)
+
+// SteadyState is the interval of a range query used for statistics, with
+// warmup and cooldown excluded. Data outside it is kept for charts.
+type SteadyState struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Method string    `json:"method"`
+	Points int       `json:"points"`
+	Mean   float64   `json:"mean"`
+}
+
+// ApplySteadyState detects the steady-state interval of every range query in
+// result, using each query's spec.metrics trim setting (auto when unset), and
+// records it on the QueryResult.
+func ApplySteadyState(result *MetricsResult, queries []experimentsv1alpha1.MetricsQuery) {
+	if result == nil {
+		return
+	}
+	trims := make(map[string]*experimentsv1alpha1.TrimSpec, len(queries))
+	for _, mq := range queries {
+		trims[mq.Name] = mq.Trim
+	}
+	for name, qr := range result.Queries {
+		if qr.Type != "range" || len(qr.Data) == 0 {
+			continue
+		}
+		qr.SteadyState = DetectSteadyState(qr.Data, trims[name])
+		result.Queries[name] = qr
+	}
+}
+
+// DetectSteadyState returns the steady-state interval of range query data, or
+// nil if trimming is disabled or no usable interval exists.
+//
+// In auto mode each series is scanned with a sliding window (a fifth of the
+// series, at least 3 points) and the window's coefficient of variation is
+// compared against trim.maxCV when set. Otherwise the threshold is
+// max(defaultMaxCV, 1.5 × the series' median window CV), so a naturally noisy
+// series is judged against its own noise floor while ramps still stand out. A series' steady interval runs from the first steady window
+// to the last; the query's interval is the intersection across series, so
+// every series is compared over the same period.
+func DetectSteadyState(data []DataPoint, trim *experimentsv1alpha1.TrimSpec) *SteadyState {
+	mode := "auto"
+	if trim != nil && trim.Mode != "" {
+		mode = trim.Mode
+	}
+
+	var start, end time.Time
+	switch mode {
+	case "none":
+		return nil
+	case "fixed":
+		first, last := data[0].Timestamp, data[0].Timestamp
+		for _, dp := range data {
+			if dp.Timestamp.Before(first) {
+				first = dp.Timestamp
+			}
+			if dp.Timestamp.After(last) {
+				last = dp.Timestamp
+			}
+		}
+		start = first.Add(time.Duration(trim.WarmupSeconds) * time.Second)
+		end = last.Add(-time.Duration(trim.CooldownSeconds) * time.Second)
+	default:
+		var maxCV *float64
+		if trim != nil {
+			maxCV = trim.MaxCV
+		}
+		found := false
+		for _, series := range groupSeries(data) {
+			i, j, ok := steadyInterval(series, maxCV)
+			if !ok {
+				continue
+			}
+			if !found || series[i].Timestamp.After(start) {
+				start = series[i].Timestamp
+			}
+			if !found || series[j].Timestamp.Before(end) {
+				end = series[j].Timestamp
+			}
+			found = true
+		}
+		if !found {
+			return nil
+		}
+	}
+
+	if end.Before(start) {
+		return nil
+	}
+
+	ss := &SteadyState{Start: start, End: end, Method: mode}
+	var sum float64
+	for _, dp := range data {
+		if dp.Timestamp.Before(start) || dp.Timestamp.After(end) {
+			continue
+		}
+		sum += dp.Value
+		ss.Points++
+	}
+	if ss.Points == 0 {
+		return nil
+	}
+	ss.Mean = sum / float64(ss.Points)
+	return ss
+}
+
+// steadyMean returns the mean of a range query's points, restricted to its
+// steady-state interval when one was detected.
+func steadyMean(qr QueryResult) float64 {
+	if qr.SteadyState != nil && qr.SteadyState.Points > 0 {
+		return qr.SteadyState.Mean
+	}
+	var sum float64
+	for _, dp := range qr.Data {
+		sum += dp.Value
+	}
+	return sum / float64(len(qr.Data))
+}
+
+// groupSeries splits flattened range data into per-series slices ordered by time.
+func groupSeries(data []DataPoint) [][]DataPoint {
+	byKey := make(map[string][]DataPoint)
+	var keys []string
+	for _, dp := range data {
+		k := seriesKey(dp.Labels)
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
+		}
+		byKey[k] = append(byKey[k], dp)
+	}
+	sort.Strings(keys)
+
+	series := make([][]DataPoint, 0, len(keys))
+	for _, k := range keys {
+		s := byKey[k]
+		sort.Slice(s, func(i, j int) bool { return s[i].Timestamp.Before(s[j].Timestamp) })
+		series = append(series, s)
+	}
+	return series
+}
+
+// seriesKey builds a stable identity for a label set.
+func seriesKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// steadyInterval returns the index range [i, j] of a time-ordered series that
+// lies between the first and last steady windows. A nil maxCV uses the
+// default, noise-floor-adjusted threshold.
+func steadyInterval(series []DataPoint, maxCV *float64) (int, int, bool) {
+	n := len(series)
+	if n < minSteadyPoints {
+		return 0, 0, false
+	}
+	w := n / 5
+	if w < 3 {
+		w = 3
+	}
+
+	cvs := make([]float64, n-w+1)
+	for k := range cvs {
+		cvs[k] = coefficientOfVariation(series[k : k+w])
+	}
+
+	var threshold float64
+	if maxCV != nil {
+		threshold = *maxCV
+	} else {
+		sorted := append([]float64(nil), cvs...)
+		sort.Float64s(sorted)
+		threshold = math.Max(defaultMaxCV, 1.5*sorted[len(sorted)/2])
+	}
+
+	first, last := -1, -1
+	for k, cv := range cvs {
+		if cv <= threshold {
+			if first < 0 {
+				first = k
+			}
+			last = k
+		}
+	}
+	if first < 0 {
+		return 0, 0, false
+	}
+	return first, last + w - 1, true
+}
+
+// coefficientOfVariation returns stddev/|mean| of the window's values. A
+// window of zeros is perfectly steady; any other zero-mean window is not.
+func coefficientOfVariation(window []DataPoint) float64 {
+	var sum float64
+	for _, dp := range window {
+		sum += dp.Value
+	}
+	mean := sum / float64(len(window))
+
+	var sq float64
+	for _, dp := range window {
+		d := dp.Value - mean
+		sq += d * d
+	}
+	std := math.Sqrt(sq / float64(len(window)))
+
+	if mean == 0 {
+		if std == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return std / math.Abs(mean)
+}
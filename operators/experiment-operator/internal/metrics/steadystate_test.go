@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+// rampSeries builds a series that ramps up over warmup points, holds a plateau
+// with small jitter, then ramps down over cooldown points.
+func rampSeries(labels map[string]string, start time.Time, warmup, plateau, cooldown int) []DataPoint {
+	var data []DataPoint
+	ts := start
+	add := func(v float64) {
+		data = append(data, DataPoint{Timestamp: ts, Value: v, Labels: labels})
+		ts = ts.Add(15 * time.Second)
+	}
+	for i := 0; i < warmup; i++ {
+		add(100 * float64(i+1) / float64(warmup+1))
+	}
+	for i := 0; i < plateau; i++ {
+		add(100 + float64(i%3-1))
+	}
+	for i := cooldown; i > 0; i-- {
+		add(100 * float64(i) / float64(cooldown+1))
+	}
+	return data
+}
+
+func TestDetectSteadyStateAuto(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	data := rampSeries(nil, start, 10, 40, 10)
+
+	ss := DetectSteadyState(data, nil)
+	if ss == nil {
+		t.Fatal("DetectSteadyState() = nil, want interval")
+	}
+	if ss.Method != "auto" {
+		t.Errorf("Method = %q, want auto", ss.Method)
+	}
+	plateauStart := data[10].Timestamp
+	plateauEnd := data[49].Timestamp
+	if ss.Start.Before(data[7].Timestamp) || ss.Start.After(plateauStart) {
+		t.Errorf("Start = %s, want near plateau start %s", ss.Start, plateauStart)
+	}
+	if ss.End.After(data[52].Timestamp) || ss.End.Before(plateauEnd) {
+		t.Errorf("End = %s, want near plateau end %s", ss.End, plateauEnd)
+	}
+	if math.Abs(ss.Mean-100) > 5 {
+		t.Errorf("Mean = %v, want ~100", ss.Mean)
+	}
+}
+
+func TestDetectSteadyStateIntersectsSeries(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	early := rampSeries(map[string]string{"pod": "a"}, start, 5, 50, 5)
+	late := rampSeries(map[string]string{"pod": "b"}, start, 20, 35, 5)
+	data := append(append([]DataPoint(nil), early...), late...)
+
+	ss := DetectSteadyState(data, nil)
+	if ss == nil {
+		t.Fatal("DetectSteadyState() = nil, want interval")
+	}
+	only, _, _ := steadyInterval(early, nil)
+	if !ss.Start.After(early[only].Timestamp) {
+		t.Errorf("Start = %s, want after series a's steady start %s (the later series governs)", ss.Start, early[only].Timestamp)
+	}
+}
+
+func TestDetectSteadyStateMaxCV(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	// A plateau with ±20% jitter: steady against its own noise floor, but
+	// not under a user-set maxCV of 0.05.
+	var data []DataPoint
+	for i := 0; i < 40; i++ {
+		data = append(data, DataPoint{Timestamp: start.Add(time.Duration(i) * 15 * time.Second), Value: 100 + 20*float64(i%3-1)})
+	}
+
+	if ss := DetectSteadyState(data, nil); ss == nil {
+		t.Error("DetectSteadyState() = nil, want the default threshold to adapt to the noise")
+	}
+	tight := 0.05
+	if ss := DetectSteadyState(data, &experimentsv1alpha1.TrimSpec{MaxCV: &tight}); ss != nil {
+		t.Errorf("DetectSteadyState() = %+v, want nil under maxCV %v", ss, tight)
+	}
+	loose := 0.5
+	if ss := DetectSteadyState(data, &experimentsv1alpha1.TrimSpec{MaxCV: &loose}); ss == nil {
+		t.Errorf("DetectSteadyState() = nil, want an interval under maxCV %v", loose)
+	}
+}
+
+func TestDetectSteadyStateFixed(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	var data []DataPoint
+	for i := 0; i < 10; i++ {
+		data = append(data, DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: float64(i)})
+	}
+
+	ss := DetectSteadyState(data, &experimentsv1alpha1.TrimSpec{Mode: "fixed", WarmupSeconds: 120, CooldownSeconds: 180})
+	if ss == nil {
+		t.Fatal("DetectSteadyState() = nil, want interval")
+	}
+	if !ss.Start.Equal(start.Add(2*time.Minute)) || !ss.End.Equal(start.Add(6*time.Minute)) {
+		t.Errorf("interval = %s..%s, want +2m..+6m", ss.Start, ss.End)
+	}
+	if ss.Points != 5 || ss.Mean != 4 {
+		t.Errorf("Points/Mean = %d/%v, want 5/4", ss.Points, ss.Mean)
+	}
+
+	if ss := DetectSteadyState(data, &experimentsv1alpha1.TrimSpec{Mode: "fixed", WarmupSeconds: 600}); ss != nil {
+		t.Errorf("DetectSteadyState() = %+v, want nil when warmup exceeds the window", ss)
+	}
+}
+
+func TestDetectSteadyStateNone(t *testing.T) {
+	data := rampSeries(nil, time.Unix(1700000000, 0), 10, 40, 10)
+	if ss := DetectSteadyState(data, &experimentsv1alpha1.TrimSpec{Mode: "none"}); ss != nil {
+		t.Errorf("DetectSteadyState() = %+v, want nil", ss)
+	}
+}
+
+func TestDetectSteadyStateTooShort(t *testing.T) {
+	data := rampSeries(nil, time.Unix(1700000000, 0), 1, 3, 1)
+	if ss := DetectSteadyState(data, nil); ss != nil {
+		t.Errorf("DetectSteadyState() = %+v, want nil for a short series", ss)
+	}
+}
+
+func TestCoefficientOfVariation(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"constant", []float64{5, 5, 5}, 0},
+		{"zeros", []float64{0, 0, 0}, 0},
+		{"zero mean", []float64{-1, 0, 1}, math.Inf(1)},
+		{"spread", []float64{1, 3}, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var window []DataPoint
+			for _, v := range tt.values {
+				window = append(window, DataPoint{Value: v})
+			}
+			if got := coefficientOfVariation(window); got != tt.want {
+				t.Errorf("coefficientOfVariation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateSuccessCriteriaUsesSteadyState(t *testing.T) {
+	data := rampSeries(nil, time.Unix(1700000000, 0), 10, 40, 10)
+	result := &MetricsResult{Queries: map[string]QueryResult{
+		"throughput": {Type: "range", Data: data},
+	}}
+	ApplySteadyState(result, nil)
+	if result.Queries["throughput"].SteadyState == nil {
+		t.Fatal("SteadyState not recorded")
+	}
+
+	// The whole-series mean is ~84; only the plateau mean clears 95.
+	summary := &ExperimentSummary{
+		Metrics: result,
+		Hypothesis: &HypothesisContext{SuccessCriteria: []SuccessCriterionSummary{
+			{Metric: "throughput", Operator: "gte", Value: "95"},
+		}},
+	}
+	if got := EvaluateSuccessCriteria(summary); got != "validated" {
+		t.Errorf("EvaluateSuccessCriteria() = %q, want validated (actual %s)", got, summary.Hypothesis.SuccessCriteria[0].ActualValue)
+	}
+}
@@ -201,7 +201,8 @@ func probeEndpoint(ctx context.Context, restClient rest.Interface, ep Monitoring
 
 // CollectMetricsFromTarget tries each discovered monitoring endpoint and collects metrics
 // using default target queries that work on any Kubernetes cluster (no experiment label needed).
-// The iteration parameter controls $DURATION substitution for quality gate re-collection.
+// The iteration parameter is the quality gate window step; it controls the query
+// window and $DURATION substitution on re-collection.
 func CollectMetricsFromTarget(ctx context.Context, kubeconfig []byte, endpoints []MonitoringEndpoint, exp *experimentsv1alpha1.Experiment, iteration ...int) (*MetricsResult, error) {
 	if len(endpoints) == 0 {
 		return nil, fmt.Errorf("no monitoring endpoints provided")
@@ -219,10 +220,11 @@ func CollectMetricsFromTarget(ctx context.Context, kubeconfig []byte, endpoints
 	restClient := clientset.CoreV1().RESTClient()
 	logger := log.FromContext(ctx)
 
-	start := exp.CreationTimestamp.Time
-	// Use current time as end — resources are still running during metrics collection
-	// (cleanup happens after). This captures data Prometheus scraped after the workflow finished.
-	end := time.Now()
+	iter := 0
+	if len(iteration) > 0 {
+		iter = iteration[0]
+	}
+	start, end := WorkloadWindow(exp)
 
 	duration := end.Sub(start)
 	if duration < 30*time.Second {
@@ -239,21 +241,6 @@ func CollectMetricsFromTarget(ctx context.Context, kubeconfig []byte, endpoints
 		queries = defaultTargetQueries()
 	}
 
-	// Build substitution variables (same as CollectMetricsSnapshot)
-	// On target clusters, pods deploy to the experiment-named namespace
-	// (e.g., "db-baseline-fsync-b8twf"), not the Experiment CR's namespace ("experiments").
-	iter := 0
-	if len(iteration) > 0 {
-		iter = iteration[0]
-	}
-	durationForQuery := IterationDuration(iter, duration)
-
-	vars := map[string]string{
-		"$EXPERIMENT": exp.Name,
-		"$NAMESPACE":  exp.Name,
-		"$DURATION":   promDuration(durationForQuery),
-	}
-
 	// Try each verified endpoint until one works
 	for i, ep := range endpoints {
 		logger.Info("Trying monitoring endpoint", "index", i, "service", ep.Service, "namespace", ep.Namespace, "port", ep.Port)
@@ -267,28 +254,41 @@ func CollectMetricsFromTarget(ctx context.Context, kubeconfig []byte, endpoints
 				StepSec:  stepSec,
 			},
 			Queries: make(map[string]QueryResult),
+			querier: proxyQuerier{restClient: restClient, ep: ep},
 		}
 
 		anyData := false
 		anySuccess := false
 
 		for _, mq := range queries {
-			resolvedQuery := substituteVars(mq.Query, vars)
 			qr := QueryResult{
-				Query:       resolvedQuery,
+				Query:       mq.Query,
 				Type:        mq.Type,
 				Unit:        mq.Unit,
 				Description: mq.Description,
+				Required:    mq.Required,
+				Window:      mq.Window,
+			}
+
+			qStart, qEnd, rateDuration, windowErr := ResolveWindow(exp, mq.Window, iter)
+			if windowErr != nil {
+				qr.Error = windowErr.Error()
+				result.Queries[mq.Name] = qr
+				continue
 			}
+			// On target clusters, pods deploy to the experiment-named namespace
+			// (e.g., "db-baseline-fsync-b8twf"), not the Experiment CR's namespace ("experiments").
+			resolvedQuery := substituteVars(mq.Query, queryVars(exp, exp.Name, qStart, qEnd, rateDuration))
+			qr.Query = resolvedQuery
 
 			var data []DataPoint
 			var queryErr error
 
 			switch mq.Type {
 			case "range":
-				data, queryErr = queryRangeViaProxy(ctx, restClient, ep, resolvedQuery, start, end, stepStr)
+				data, queryErr = queryRangeViaProxy(ctx, restClient, ep, resolvedQuery, qStart, qEnd, selectStep(qEnd.Sub(qStart)))
 			case "instant":
-				data, queryErr = queryInstantViaProxy(ctx, restClient, ep, resolvedQuery, end)
+				data, queryErr = queryInstantViaProxy(ctx, restClient, ep, resolvedQuery, qEnd)
 			}
 
 			if queryErr != nil {
@@ -462,140 +462,43 @@ func isInfrastructurePod(pod string) bool {
 	return false
 }
 
-// CollectCadvisorMetrics scrapes cadvisor metrics directly from kubelet on target
-// cluster nodes via the Kubernetes API server proxy. This works on any cluster
-// without requiring Prometheus, VictoriaMetrics, or a remote-write pipeline.
-// It returns CPU (cores) and memory (bytes) aggregated by namespace.
-func CollectCadvisorMetrics(ctx context.Context, kubeconfig []byte, exp *experimentsv1alpha1.Experiment) (*MetricsResult, error) {
-	logger := log.FromContext(ctx)
-
-	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
-	if err != nil {
-		return nil, fmt.Errorf("parse kubeconfig: %w", err)
-	}
-	clientset, err := kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("create clientset: %w", err)
-	}
-
-	// List nodes
-	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+// CollectCadvisorMetrics builds resource metrics for a target from kubelet
+// cadvisor, scraped via the Kubernetes API server proxy. This works on any
+// cluster without requiring Prometheus, VictoriaMetrics, or a remote-write
+// pipeline. samples holds the scrapes recorded during the Running phase; one
+// more is taken now, which only counts while the workload is still running.
+// Only samples inside the workload window are used; with fewer than two, the
+// queries that need them carry an insufficient-data error.
+// It returns CPU (cores) and memory (bytes) by pod, by namespace and in total.
+func CollectCadvisorMetrics(ctx context.Context, kubeconfig []byte, exp *experimentsv1alpha1.Experiment, samples []CadvisorSample) (*MetricsResult, error) {
+	sample, err := ScrapeCadvisor(ctx, kubeconfig)
 	if err != nil {
-		return nil, fmt.Errorf("list nodes: %w", err)
-	}
-	if len(nodes.Items) == 0 {
-		return nil, fmt.Errorf("no nodes found")
-	}
-
-	now := time.Now()
-
-	// Aggregate CPU (counter seconds) and memory (gauge bytes) by pod
-	cpuByPod := make(map[string]float64)
-	memByPod := make(map[string]float64)
-
-	restClient := clientset.CoreV1().RESTClient()
-
-	for _, node := range nodes.Items {
-		scrapeCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
-		raw, err := restClient.Get().
-			Resource("nodes").
-			Name(node.Name).
-			SubResource("proxy", "metrics", "cadvisor").
-			Do(scrapeCtx).
-			Raw()
-		cancel()
-		if err != nil {
-			logger.Error(err, "Failed to scrape cadvisor", "node", node.Name)
-			continue
+		if len(samples) == 0 {
+			return nil, err
 		}
-
-		parseCadvisorMetrics(string(raw), cpuByPod, memByPod)
-		logger.Info("Scraped cadvisor metrics", "node", node.Name, "cpuPods", len(cpuByPod), "memPods", len(memByPod))
+		log.FromContext(ctx).Error(err, "Final cadvisor scrape failed, using recorded samples")
+	} else {
+		samples = append(samples, sample)
 	}
+	// An unfinished workload's window ends now, after the final scrape.
+	workloadStart, workloadEnd := WorkloadWindow(exp)
+	return cadvisorResult(clipSamples(samples, workloadStart, workloadEnd), workloadStart), nil
+}
 
-	if len(cpuByPod) == 0 && len(memByPod) == 0 {
-		return nil, fmt.Errorf("no cadvisor metrics found on any node")
+// clipSamples returns the samples taken within [start, end].
+func clipSamples(samples []CadvisorSample, start, end time.Time) []CadvisorSample {
+	var out []CadvisorSample
+	for _, s := range samples {
+		if !s.Timestamp.Before(start) && !s.Timestamp.After(end) {
+			out = append(out, s)
+		}
 	}
-
-	// Build MetricsResult with instant-style data points
-	result := &MetricsResult{
-		CollectedAt: now.UTC(),
-		Source:      "target:cadvisor",
-		TimeRange: TimeRange{
-			Start:    exp.CreationTimestamp.Time,
-			End:      now,
-			Duration: now.Sub(exp.CreationTimestamp.Time).String(),
-			StepSec:  0, // instant snapshot
-		},
-		Queries: make(map[string]QueryResult),
-	}
-
-	// CPU usage by pod (counter total — represents cumulative CPU seconds)
-	var cpuPoints []DataPoint
-	var cpuTotal float64
-	for pod, val := range cpuByPod {
-		cpuPoints = append(cpuPoints, DataPoint{
-			Labels:    map[string]string{"pod": pod},
-			Timestamp: now,
-			Value:     val,
-		})
-		cpuTotal += val
-	}
-	result.Queries["cpu_by_pod"] = QueryResult{
-		Query:       "container_cpu_usage_seconds_total by pod (cadvisor)",
-		Type:        "instant",
-		Unit:        "cores",
-		Description: "CPU usage by pod (cumulative seconds)",
-		Data:        cpuPoints,
-	}
-	result.Queries["cpu_total"] = QueryResult{
-		Query:       "sum(container_cpu_usage_seconds_total) (cadvisor)",
-		Type:        "instant",
-		Unit:        "cores",
-		Description: "Total CPU usage (cumulative seconds)",
-		Data: []DataPoint{{
-			Labels:    map[string]string{"scope": "total"},
-			Timestamp: now,
-			Value:     cpuTotal,
-		}},
-	}
-
-	// Memory by pod (gauge — current working set bytes)
-	var memPoints []DataPoint
-	var memTotal float64
-	for pod, val := range memByPod {
-		memPoints = append(memPoints, DataPoint{
-			Labels:    map[string]string{"pod": pod},
-			Timestamp: now,
-			Value:     val,
-		})
-		memTotal += val
-	}
-	result.Queries["memory_by_pod"] = QueryResult{
-		Query:       "container_memory_working_set_bytes by pod (cadvisor)",
-		Type:        "instant",
-		Unit:        "bytes",
-		Description: "Memory working set by pod",
-		Data:        memPoints,
-	}
-	result.Queries["memory_total"] = QueryResult{
-		Query:       "sum(container_memory_working_set_bytes) (cadvisor)",
-		Type:        "instant",
-		Unit:        "bytes",
-		Description: "Total memory working set",
-		Data: []DataPoint{{
-			Labels:    map[string]string{"scope": "total"},
-			Timestamp: now,
-			Value:     memTotal,
-		}},
-	}
-
-	return result, nil
+	return out
 }
 
 // parseCadvisorMetrics parses Prometheus text format from cadvisor and accumulates
-// CPU and memory values by namespace, filtering out system namespaces and pause containers.
-func parseCadvisorMetrics(text string, cpuByPod, memByPod map[string]float64) {
+// CPU and memory values by pod, filtering out system namespaces and pause containers.
+func parseCadvisorMetrics(text string, cpuByPod, memByPod map[podRef]float64) {
 	scanner := bufio.NewScanner(strings.NewReader(text))
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -633,10 +536,11 @@ func parseCadvisorMetrics(text string, cpuByPod, memByPod map[string]float64) {
 			continue
 		}
 
+		ref := podRef{Namespace: ns, Pod: pod}
 		if isCPU {
-			cpuByPod[pod] += val
+			cpuByPod[ref] += val
 		} else if isMem {
-			memByPod[pod] += val
+			memByPod[ref] += val
 		}
 	}
 }
@@ -0,0 +1,255 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ValidationError lists every problem found in a summary, each prefixed with
+// the JSON path it applies to.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid experiment summary: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateSummary checks s against the summary schema: the schema version,
+// required identity fields, enum values, and the analyzer-produced analysis
+// block structure the site renders. It returns a *ValidationError.
+func ValidateSummary(s *ExperimentSummary) error {
+	v := &validator{}
+	if s.SchemaVersion != SummarySchemaVersion {
+		v.addf("schemaVersion", "is %d, want %d (run MigrateSummary first)", s.SchemaVersion, SummarySchemaVersion)
+	}
+	v.required("name", s.Name)
+	v.required("namespace", s.Namespace)
+	v.required("phase", s.Phase)
+
+	if s.Hypothesis != nil {
+		v.enum("hypothesis.machineVerdict", "HypothesisContext.machineVerdict", s.Hypothesis.MachineVerdict)
+	}
+	if s.Metrics != nil {
+		for _, name := range sortedKeys(s.Metrics.Queries) {
+			v.enum("metrics.queries."+name+".type", "QueryResult.type", s.Metrics.Queries[name].Type)
+		}
+	}
+	if s.Analysis != nil {
+		v.analysis(s.Analysis)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.addf(path, "is required")
+	}
+}
+
+// enum checks a value against summaryEnums[key]; empty values are allowed.
+func (v *validator) enum(path, key, value string) {
+	if value == "" {
+		return
+	}
+	if allowed := summaryEnums[key]; !slices.Contains(allowed, value) {
+		v.addf(path, "%q is not one of %s", value, strings.Join(allowed, ", "))
+	}
+}
+
+func (v *validator) analysis(a *AnalysisResult) {
+	v.enum("analysis.hypothesisVerdict", "AnalysisResult.hypothesisVerdict", a.HypothesisVerdict)
+	v.enum("analysis.architectureDiagramFormat", "AnalysisResult.architectureDiagramFormat", a.ArchitectureDiagramFormat)
+
+	if m := a.CapabilitiesMatrix; m != nil {
+		if len(m.Technologies) == 0 {
+			v.addf("analysis.capabilitiesMatrix.technologies", "is required")
+		}
+		for i, c := range m.Categories {
+			path := fmt.Sprintf("analysis.capabilitiesMatrix.categories[%d]", i)
+			v.required(path+".name", c.Name)
+			for j, e := range c.Capabilities {
+				v.required(fmt.Sprintf("%s.capabilities[%d].name", path, j), e.Name)
+			}
+		}
+	}
+	if a.Body != nil {
+		v.blocks("analysis.body.blocks", a.Body.Blocks)
+	}
+	for i, g := range a.Glossary {
+		v.required(fmt.Sprintf("analysis.glossary[%d].term", i), g.Term)
+	}
+}
+
+func (v *validator) blocks(path string, blocks []BodyBlock) {
+	for i := range blocks {
+		v.block(fmt.Sprintf("%s[%d]", path, i), &blocks[i])
+	}
+}
+
+func (v *validator) block(path string, b *BodyBlock) {
+	fields, ok := bodyBlockTypes[b.Type]
+	if !ok {
+		v.addf(path+".type", "unknown block type %q", b.Type)
+		return
+	}
+	rv := reflect.ValueOf(*b)
+	for _, name := range fields {
+		if f := rv.Field(bodyBlockFieldIndex[name]); f.IsZero() || (f.Kind() != reflect.String && f.Len() == 0) {
+			v.addf(path+"."+name, "is required for %s blocks", b.Type)
+		}
+	}
+	for _, name := range []string{"size", "format", "variant", "priority", "effort"} {
+		v.enum(path+"."+name, "BodyBlock."+name, rv.Field(bodyBlockFieldIndex[name]).String())
+	}
+
+	for i, item := range b.Items {
+		v.required(fmt.Sprintf("%s.items[%d].label", path, i), item.Label)
+	}
+	for i, a := range b.Annotations {
+		ap := fmt.Sprintf("%s.annotations[%d]", path, i)
+		if a.FromLine < 1 {
+			v.addf(ap+".fromLine", "must be >= 1")
+		}
+		if a.ToLine != 0 && a.ToLine < a.FromLine {
+			v.addf(ap+".toLine", "must be >= fromLine")
+		}
+		v.enum(ap+".category", "CodeAnnotation.category", a.Category)
+	}
+	v.blocks(path+".blocks", b.Blocks)
+}
+
+// bodyBlockFieldIndex maps BodyBlock JSON names to struct field indexes.
+var bodyBlockFieldIndex = func() map[string]int {
+	idx := make(map[string]int)
+	for i := 0; i < bodyBlockType.NumField(); i++ {
+		name, _, _ := strings.Cut(bodyBlockType.Field(i).Tag.Get("json"), ",")
+		idx[name] = i
+	}
+	return idx
+}()
+
+// summaryMigrations[n] upgrades a version-n summary to version n+1.
+var summaryMigrations = []func(doc map[string]any){
+	migrateSummaryV0,
+}
+
+// MigrateSummary upgrades stored summary JSON to SummarySchemaVersion.
+// Fields the Go types don't model (e.g. the site's series) are preserved.
+// Summaries from a newer operator are rejected rather than downgraded.
+func MigrateSummary(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode summary: %w", err)
+	}
+
+	version := 0
+	if n, ok := doc["schemaVersion"].(json.Number); ok {
+		v, err := n.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("schemaVersion %q is not an integer", n)
+		}
+		version = int(v)
+	}
+	if version > SummarySchemaVersion {
+		return nil, fmt.Errorf("summary schemaVersion %d is newer than supported %d", version, SummarySchemaVersion)
+	}
+	if version == SummarySchemaVersion {
+		return data, nil
+	}
+	for ; version < SummarySchemaVersion; version++ {
+		summaryMigrations[version](doc)
+	}
+	doc["schemaVersion"] = SummarySchemaVersion
+	return json.Marshal(doc)
+}
+
+// DecodeSummary migrates, decodes and validates summary JSON.
+func DecodeSummary(data []byte) (*ExperimentSummary, error) {
+	migrated, err := MigrateSummary(data)
+	if err != nil {
+		return nil, err
+	}
+	var s ExperimentSummary
+	if err := json.Unmarshal(migrated, &s); err != nil {
+		return nil, fmt.Errorf("decode summary: %w", err)
+	}
+	if err := ValidateSummary(&s); err != nil {
+		return &s, err
+	}
+	return &s, nil
+}
+
+var mermaidDiagram = regexp.MustCompile(`^(flowchart|graph|sequenceDiagram|classDiagram)`)
+
+// migrateSummaryV0 handles summaries written before schemaVersion:
+//   - analysisConfig was renamed analyzerConfig
+//   - study{hypothesis,questions,focus} became hypothesis{claim,questions,focus}
+//   - early analyzers stored the raw CLI result envelope as analysis
+//   - architectureDiagramFormat was not set for Mermaid diagrams
+func migrateSummaryV0(doc map[string]any) {
+	if cfg, ok := doc["analysisConfig"]; ok {
+		if _, exists := doc["analyzerConfig"]; !exists {
+			doc["analyzerConfig"] = cfg
+		}
+		delete(doc, "analysisConfig")
+	}
+
+	if study, ok := doc["study"].(map[string]any); ok {
+		if _, exists := doc["hypothesis"]; !exists {
+			h := map[string]any{}
+			if claim, ok := study["hypothesis"]; ok {
+				h["claim"] = claim
+			}
+			for _, k := range []string{"questions", "focus"} {
+				if v, ok := study[k]; ok {
+					h[k] = v
+				}
+			}
+			doc["hypothesis"] = h
+		}
+		delete(doc, "study")
+	}
+
+	analysis, ok := doc["analysis"].(map[string]any)
+	if !ok {
+		return
+	}
+	if _, envelope := analysis["session_id"]; envelope {
+		delete(doc, "analysis")
+		return
+	}
+	if diagram, ok := analysis["architectureDiagram"].(string); ok {
+		if _, set := analysis["architectureDiagramFormat"]; !set && mermaidDiagram.MatchString(strings.TrimSpace(diagram)) {
+			analysis["architectureDiagramFormat"] = "mermaid"
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func validSummary() *ExperimentSummary {
+	return &ExperimentSummary{
+		SchemaVersion: SummarySchemaVersion,
+		Name:          "tsdb-x7k2p",
+		Namespace:     "experiments",
+		Phase:         "Complete",
+		Metrics: &MetricsResult{Queries: map[string]QueryResult{
+			"cpu_total": {Query: "sum(rate(x[1m]))", Type: "range"},
+		}},
+		Analysis: &AnalysisResult{
+			HypothesisVerdict: "validated",
+			Body: &AnalysisBody{Blocks: []BodyBlock{
+				{Type: "text", Content: "Intro"},
+				{Type: "topic", Title: "Latency", Blocks: []BodyBlock{
+					{Type: "metric", Key: "cpu_total", Size: "large"},
+					{Type: "callout", Variant: "finding", Title: "Fast", Content: "p99 under 10ms"},
+				}},
+				{Type: "code", Key: "ingest", Annotations: []CodeAnnotation{
+					{FromLine: 3, ToLine: 5, Category: "hot-path", Label: "Loop", Content: "Tight loop"},
+				}},
+			}},
+		},
+	}
+}
+
+func TestValidateSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(s *ExperimentSummary)
+		want   []string // substrings of expected problems; nil means valid
+	}{
+		{"valid", func(s *ExperimentSummary) {}, nil},
+		{"unversioned", func(s *ExperimentSummary) { s.SchemaVersion = 0 }, []string{"schemaVersion"}},
+		{"missing name", func(s *ExperimentSummary) { s.Name = "" }, []string{"name: is required"}},
+		{"bad verdict", func(s *ExperimentSummary) { s.Analysis.HypothesisVerdict = "maybe" }, []string{"analysis.hypothesisVerdict"}},
+		{"bad query type", func(s *ExperimentSummary) {
+			s.Metrics.Queries["cpu_total"] = QueryResult{Type: "matrix"}
+		}, []string{"metrics.queries.cpu_total.type"}},
+		{"unknown block", func(s *ExperimentSummary) {
+			s.Analysis.Body.Blocks[0].Type = "video"
+		}, []string{"analysis.body.blocks[0].type: unknown block type"}},
+		{"nested missing field", func(s *ExperimentSummary) {
+			s.Analysis.Body.Blocks[1].Blocks[1].Variant = ""
+		}, []string{"analysis.body.blocks[1].blocks[1].variant: is required for callout blocks"}},
+		{"empty topic", func(s *ExperimentSummary) {
+			s.Analysis.Body.Blocks[1].Blocks = []BodyBlock{}
+		}, []string{"analysis.body.blocks[1].blocks: is required"}},
+		{"bad enum and annotation", func(s *ExperimentSummary) {
+			s.Analysis.Body.Blocks[1].Blocks[0].Size = "huge"
+			s.Analysis.Body.Blocks[2].Annotations[0].FromLine = 0
+		}, []string{"blocks[1].blocks[0].size", "annotations[0].fromLine"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := validSummary()
+			tt.mutate(s)
+			err := ValidateSummary(s)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateSummary() = %v, want nil", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("ValidateSummary() = %v, want *ValidationError", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q missing %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrateSummaryV0(t *testing.T) {
+	legacy := `{
+		"name": "logging-comparison-abcde", "namespace": "experiments", "phase": "Complete",
+		"series": "observability",
+		"analysisConfig": {"sections": ["abstract"]},
+		"study": {"hypothesis": "Loki is cheaper", "questions": ["How much?"]},
+		"analysis": {"summary": "ok", "architectureDiagram": "flowchart LR\n a --> b"},
+		"metrics": {"queries": {"n": {"query": "up", "type": "instant", "data": [{"timestamp": "2026-01-01T00:00:00Z", "value": 12345678901234}]}}}
+	}`
+	out, err := MigrateSummary([]byte(legacy))
+	if err != nil {
+		t.Fatalf("MigrateSummary: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["series"] != "observability" {
+		t.Errorf("series = %v, want unknown fields preserved", doc["series"])
+	}
+	if _, ok := doc["analysisConfig"]; ok {
+		t.Error("analysisConfig not renamed")
+	}
+	if !strings.Contains(string(out), `"value":12345678901234`) {
+		t.Errorf("numbers not preserved exactly: %s", out)
+	}
+
+	s, err := DecodeSummary([]byte(legacy))
+	if err != nil {
+		t.Fatalf("DecodeSummary: %v", err)
+	}
+	if s.SchemaVersion != SummarySchemaVersion || s.AnalyzerConfig == nil || s.AnalyzerConfig.Sections[0] != "abstract" {
+		t.Errorf("migrated = version %d, analyzerConfig %+v", s.SchemaVersion, s.AnalyzerConfig)
+	}
+	if s.Hypothesis == nil || s.Hypothesis.Claim != "Loki is cheaper" || len(s.Hypothesis.Questions) != 1 {
+		t.Errorf("hypothesis = %+v, want migrated from study", s.Hypothesis)
+	}
+	if s.Analysis.ArchitectureDiagramFormat != "mermaid" {
+		t.Errorf("architectureDiagramFormat = %q, want mermaid", s.Analysis.ArchitectureDiagramFormat)
+	}
+}
+
+func TestMigrateSummaryEdgeCases(t *testing.T) {
+	envelope := `{"name":"a","namespace":"b","phase":"Complete","analysis":{"type":"result","session_id":"x","is_error":true}}`
+	s, err := DecodeSummary([]byte(envelope))
+	if err != nil {
+		t.Fatalf("DecodeSummary: %v", err)
+	}
+	if s.Analysis != nil {
+		t.Errorf("analysis = %+v, want raw CLI envelope dropped", s.Analysis)
+	}
+
+	current := []byte(`{"schemaVersion":1,"name":"a"}`)
+	if out, err := MigrateSummary(current); err != nil || string(out) != string(current) {
+		t.Errorf("current version rewritten: %s (%v)", out, err)
+	}
+	if _, err := MigrateSummary([]byte(`{"schemaVersion":99}`)); err == nil {
+		t.Error("newer schema version accepted, want error")
+	}
+}
+
+// TestSiteDataValidates keeps every summary already published on the site
+// decodable under the current schema.
+func TestSiteDataValidates(t *testing.T) {
+	files, _ := filepath.Glob("../../../../site/data/*.json")
+	if len(files) == 0 {
+		t.Skip("site checkout not present")
+	}
+	for _, f := range files {
+		base := filepath.Base(f)
+		if strings.HasPrefix(base, "_") || strings.HasSuffix(base, ".tutorial.json") {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DecodeSummary(data); err != nil {
+			t.Errorf("%s: %v", base, err)
+		}
+	}
+}
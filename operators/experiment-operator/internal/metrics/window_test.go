@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func windowTestExperiment() *experimentsv1alpha1.Experiment {
+	created := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	started := created.Add(12 * time.Minute)
+	exp := &experimentsv1alpha1.Experiment{}
+	exp.Name = "tsdb-comparison-abc"
+	exp.CreationTimestamp = metav1.Time{Time: created}
+	exp.Status.WorkflowStatus = &experimentsv1alpha1.WorkflowStatus{
+		Name:       "wf",
+		StartedAt:  &metav1.Time{Time: started},
+		FinishedAt: &metav1.Time{Time: started.Add(60 * time.Minute)},
+		Phases: []experimentsv1alpha1.PhaseWindowStatus{
+			{
+				Name:       "warmup",
+				StartedAt:  &metav1.Time{Time: started},
+				FinishedAt: &metav1.Time{Time: started.Add(10 * time.Minute)},
+			},
+			{
+				Name:      "steady-state",
+				StartedAt: &metav1.Time{Time: started.Add(10 * time.Minute)},
+			},
+		},
+	}
+	return exp
+}
+
+func TestResolveWindow(t *testing.T) {
+	exp := windowTestExperiment()
+	started := exp.Status.WorkflowStatus.StartedAt.Time
+	finished := exp.Status.WorkflowStatus.FinishedAt.Time
+
+	tests := []struct {
+		name      string
+		window    string
+		step      int
+		wantStart time.Time
+		wantEnd   time.Time
+		wantDur   time.Duration
+		wantErr   bool
+	}{
+		{"workload window", "", 0, started, finished, 60 * time.Minute, false},
+		{"workload window shortened", "", 2, started, finished, 5 * time.Minute, false},
+		{"finished phase", "warmup", 0, started, started.Add(10 * time.Minute), 10 * time.Minute, false},
+		{"unfinished phase ends with workload", "steady-state", 0, started.Add(10 * time.Minute), finished, 50 * time.Minute, false},
+		{"unknown phase", "cooldown", 0, time.Time{}, time.Time{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, dur, err := ResolveWindow(exp, tt.window, tt.step)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("window = %s..%s, want %s..%s", start, end, tt.wantStart, tt.wantEnd)
+			}
+			if dur != tt.wantDur {
+				t.Errorf("duration = %s, want %s", dur, tt.wantDur)
+			}
+		})
+	}
+}
+
+func TestQueryVars(t *testing.T) {
+	exp := windowTestExperiment()
+	start, end, dur, err := ResolveWindow(exp, "warmup", 0)
+	if err != nil {
+		t.Fatalf("ResolveWindow: %v", err)
+	}
+
+	vars := queryVars(exp, "experiments", start, end, dur)
+	want := map[string]string{
+		"$EXPERIMENT":        "tsdb-comparison-abc",
+		"$NAMESPACE":         "experiments",
+		"$DURATION":          "10m",
+		"$WORKLOAD_DURATION": "1h",
+		"$START":             strconv.FormatInt(start.Unix(), 10),
+		"$END":               strconv.FormatInt(end.Unix(), 10),
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("vars[%s] = %q, want %q", k, vars[k], v)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+// Client publishes experiment results through a ResultPublisher: it lays out
+// the result files and site index, writes them to the experiment branch, and
+// keeps the change request for review in sync.
+type Client struct {
+	forge ResultPublisher
+	path  string // e.g. "site/data"
+}
+
+// NewClient creates a results publisher writing under path in forge's repository.
+func NewClient(forge ResultPublisher, path string) *Client {
+	return &Client{forge: forge, path: path}
+}
+
+// RepoPath names the repository for logging.
+func (c *Client) RepoPath() string {
+	return c.forge.RepoPath()
+}
+
+// BranchName returns the publish branch of an experiment.
+func BranchName(expName string) string {
+	return "experiment/" + expName
+}
+
+// PublishExperimentResult commits results to the experiment branch and opens
+// a change request for review. It is idempotent: an existing branch is reused
+// (and fast-forwarded if it has no results yet), unchanged results add no
+// commit, and an open change request from the branch is updated rather than
+// duplicated. Returns the branch name, change request number, and URL; the
+// number is 0 on forges without change requests.
+func (c *Client) PublishExperimentResult(ctx context.Context, expName string, summary *metrics.ExperimentSummary, opts ...PublishOption) (string, int, string, error) {
+	branchName := BranchName(expName)
+
+	// Create (or reuse) the experiment branch from the base branch
+	if err := c.forge.EnsureBranch(ctx, branchName); err != nil {
+		return "", 0, "", fmt.Errorf("prepare experiment branch: %w", err)
+	}
+
+	// Commit results to the experiment branch
+	if err := c.commitResults(ctx, branchName, expName, summary, nil, ""); err != nil {
+		return "", 0, "", fmt.Errorf("commit results to branch: %w", err)
+	}
+
+	// Open a PR — use title if provided for human-readable PR names
+	var po publishOptions
+	for _, opt := range opts {
+		opt(&po)
+	}
+	title := fmt.Sprintf("data: Add %s experiment results", expName)
+	if po.title != "" {
+		title = fmt.Sprintf("data: %s", po.title)
+	}
+	body := c.PRBody(expName, summary, po.review)
+	cr, err := c.forge.UpsertChangeRequest(ctx, branchName, title, body)
+	if err != nil {
+		return branchName, 0, "", fmt.Errorf("open PR: %w", err)
+	}
+
+	return branchName, cr.Number, cr.URL, nil
+}
+
+// PushAnalysis commits the summary.json the analyzer wrote onto the
+// experiment branch as an additional commit on the open results PR. The
+// summary is committed as written: the analyzer emits sections that
+// metrics.ExperimentSummary does not model, so it is decoded only for the
+// derived files and the site index line.
+func (c *Client) PushAnalysis(ctx context.Context, branch, expName string, data []byte) error {
+	var summary metrics.ExperimentSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return fmt.Errorf("decode analyzed summary: %w", err)
+	}
+	return c.commitResults(ctx, branch, expName, &summary, data, fmt.Sprintf("data: Update %s with AI analysis", expName))
+}
+
+// UpdateExperimentResult replaces the summary committed to an existing
+// experiment branch, e.g. to revert malformed analyzer output before review.
+func (c *Client) UpdateExperimentResult(ctx context.Context, branch, expName string, summary *metrics.ExperimentSummary) error {
+	return c.commitResults(ctx, branch, expName, summary, nil, "")
+}
+
+// CommitResult commits an experiment's results directly to the configured
+// base branch, bypassing review.
+func (c *Client) CommitResult(ctx context.Context, expName string, summary *metrics.ExperimentSummary) error {
+	return c.commitResults(ctx, c.forge.BaseBranch(), expName, summary, nil, "")
+}
+
+// commitResults commits an experiment's result files and site index line to
+// branch as one commit. raw, when set, is the summary JSON to commit in place
+// of summary's own encoding. An empty commitMsg picks "Add" or "Update"
+// depending on whether the branch already has the experiment's summary.
+func (c *Client) commitResults(ctx context.Context, branch, expName string, summary *metrics.ExperimentSummary, raw []byte, commitMsg string) error {
+	change, err := c.resultsChange(expName, summary, raw)
+	if err != nil {
+		return err
+	}
+
+	if commitMsg == "" {
+		commitMsg = fmt.Sprintf("data: Add %s experiment results", expName)
+		existing, err := c.forge.ReadFile(ctx, c.path+"/"+expName+".json", branch)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			commitMsg = fmt.Sprintf("data: Update %s experiment results", expName)
+		}
+	}
+
+	if _, err := c.forge.CommitFiles(ctx, branch, commitMsg, change); err != nil {
+		return fmt.Errorf("commit %s results: %w", expName, err)
+	}
+	return nil
+}
+
+// publishOptions holds optional parameters for PublishExperimentResult.
+type publishOptions struct {
+	title  string
+	review ReviewContext
+}
+
+// PublishOption configures PublishExperimentResult behavior.
+type PublishOption func(*publishOptions)
+
+// WithTitle sets a human-readable title for the PR.
+func WithTitle(title string) PublishOption {
+	return func(o *publishOptions) {
+		o.title = title
+	}
+}
+
+// FetchFileContent fetches a file's content from the configured repo (or a different repo).
+// If repo is empty, the client's configured repository is used.
+// If ref is empty, the repo's default branch is used.
+func (c *Client) FetchFileContent(ctx context.Context, repo, path, ref string) (string, error) {
+	content, err := c.forge.FetchFile(ctx, repo, path, ref)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// GetPRStatus reads the review state of a change request.
+func (c *Client) GetPRStatus(ctx context.Context, prNumber int) (*ChangeRequestStatus, error) {
+	return c.forge.ChangeRequestStatus(ctx, prNumber)
+}
+
+// MergePR squash-merges a change request and deletes the source branch.
+func (c *Client) MergePR(ctx context.Context, prNumber int, branchName string) error {
+	return c.forge.MergeChangeRequest(ctx, prNumber, branchName)
+}
+
+// ClosePR closes a change request without merging and deletes the source branch.
+func (c *Client) ClosePR(ctx context.Context, prNumber int, branchName string) error {
+	return c.forge.CloseChangeRequest(ctx, prNumber, branchName)
+}
@@ -0,0 +1,476 @@
+package publish
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGitHub is an in-memory stand-in for the parts of the GitHub REST API
+// the client uses: refs, commits, trees, blobs and contents.
+type fakeGitHub struct {
+	mu      sync.Mutex
+	refs    map[string]string // branch -> commit SHA
+	commits map[string]fakeCommit
+	trees   map[string]map[string]string // tree SHA -> path -> content
+	blobs   map[string]string
+
+	// racePush, if set, is committed to the branch just before the next ref
+	// update, which then fails as a non-fast-forward.
+	racePush map[string]string
+	// refUpdates counts PATCH ref calls.
+	refUpdates int
+	pulls      []fakePull
+}
+
+type fakePull struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Head   string `json:"head"`
+	Base   string `json:"base"`
+	State  string `json:"state"`
+	Labels []string
+	Merged bool
+	// Reviews are (login, state) pairs, oldest first.
+	Reviews [][2]string
+}
+
+func (p fakePull) response() map[string]any {
+	return map[string]any{
+		"number":   p.Number,
+		"title":    p.Title,
+		"body":     p.Body,
+		"state":    p.State,
+		"merged":   p.Merged,
+		"html_url": fmt.Sprintf("https://github.com/o/r/pull/%d", p.Number),
+	}
+}
+
+type fakeCommit struct {
+	tree    string
+	parent  string
+	message string
+}
+
+func newFakeGitHub(t *testing.T, base map[string]string) (*fakeGitHub, *Client) {
+	t.Helper()
+	f := &fakeGitHub{
+		refs:    map[string]string{},
+		commits: map[string]fakeCommit{},
+		trees:   map[string]map[string]string{},
+		blobs:   map[string]string{},
+	}
+	root := f.putTree(base)
+	f.refs["main"] = f.putCommit(fakeCommit{tree: root, message: "initial"})
+
+	srv := httptest.NewServer(f.handler())
+	t.Cleanup(srv.Close)
+	g, err := newGitHubForURL(srv.Client(), srv.URL, "o", "r", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.retryDelay = 0
+	return f, NewClient(g, "site/data")
+}
+
+func hashOf(parts ...string) string {
+	h := sha1.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (f *fakeGitHub) putTree(files map[string]string) string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	parts := []string{"tree"}
+	for _, p := range paths {
+		parts = append(parts, p, files[p])
+	}
+	sha := hashOf(parts...)
+	copied := make(map[string]string, len(files))
+	for k, v := range files {
+		copied[k] = v
+	}
+	f.trees[sha] = copied
+	return sha
+}
+
+func (f *fakeGitHub) putCommit(c fakeCommit) string {
+	sha := hashOf("commit", c.tree, c.parent, c.message, fmt.Sprint(len(f.commits)))
+	f.commits[sha] = c
+	return sha
+}
+
+// files returns the tree of a branch head or commit SHA.
+func (f *fakeGitHub) files(ref string) map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if sha, ok := f.refs[ref]; ok {
+		ref = sha
+	}
+	return f.trees[f.commits[ref].tree]
+}
+
+// history returns commit messages from a branch head back to the root.
+func (f *fakeGitHub) history(branch string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var msgs []string
+	for sha := f.refs[branch]; sha != ""; sha = f.commits[sha].parent {
+		msgs = append(msgs, f.commits[sha].message)
+	}
+	return msgs
+}
+
+// compare reports head relative to base the way the compare API does.
+func (f *fakeGitHub) compare(base, head string) string {
+	if base == head {
+		return "identical"
+	}
+	for sha := f.commits[head].parent; sha != ""; sha = f.commits[sha].parent {
+		if sha == base {
+			return "ahead"
+		}
+	}
+	for sha := f.commits[base].parent; sha != ""; sha = f.commits[sha].parent {
+		if sha == head {
+			return "behind"
+		}
+	}
+	return "diverged"
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeGitHub) handler() http.Handler {
+	mux := http.NewServeMux()
+	const repo = "/repos/o/r"
+
+	mux.HandleFunc("GET "+repo+"/git/ref/heads/{branch...}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		sha, ok := f.refs[r.PathValue("branch")]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"ref":    "refs/heads/" + r.PathValue("branch"),
+			"object": map[string]string{"sha": sha, "type": "commit"},
+		})
+	})
+
+	mux.HandleFunc("POST "+repo+"/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Ref, SHA string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		branch := strings.TrimPrefix(req.Ref, "refs/heads/")
+		if _, exists := f.refs[branch]; exists {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "Reference already exists"})
+			return
+		}
+		f.refs[branch] = req.SHA
+		writeJSON(w, http.StatusCreated, map[string]any{"ref": req.Ref, "object": map[string]string{"sha": req.SHA}})
+	})
+
+	mux.HandleFunc("PATCH "+repo+"/git/refs/heads/{branch...}", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SHA   string `json:"sha"`
+			Force bool   `json:"force"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.refUpdates++
+		branch := r.PathValue("branch")
+		if f.racePush != nil {
+			head := f.refs[branch]
+			files := map[string]string{}
+			for k, v := range f.trees[f.commits[head].tree] {
+				files[k] = v
+			}
+			for k, v := range f.racePush {
+				files[k] = v
+			}
+			f.refs[branch] = f.putCommit(fakeCommit{tree: f.putTree(files), parent: head, message: "concurrent push"})
+			f.racePush = nil
+		}
+		if !req.Force && f.commits[req.SHA].parent != f.refs[branch] {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "Update is not a fast forward"})
+			return
+		}
+		f.refs[branch] = req.SHA
+		writeJSON(w, http.StatusOK, map[string]any{"ref": "refs/heads/" + branch, "object": map[string]string{"sha": req.SHA}})
+	})
+
+	mux.HandleFunc("GET "+repo+"/git/commits/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		c, ok := f.commits[r.PathValue("sha")]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"sha": r.PathValue("sha"), "message": c.message, "tree": map[string]string{"sha": c.tree}})
+	})
+
+	mux.HandleFunc("POST "+repo+"/git/commits", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Message string   `json:"message"`
+			Tree    string   `json:"tree"`
+			Parents []string `json:"parents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		c := fakeCommit{tree: req.Tree, message: req.Message}
+		if len(req.Parents) > 0 {
+			c.parent = req.Parents[0]
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"sha": f.putCommit(c)})
+	})
+
+	mux.HandleFunc("POST "+repo+"/git/blobs", func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Content, Encoding string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		content := req.Content
+		if req.Encoding == "base64" {
+			b, _ := base64.StdEncoding.DecodeString(req.Content)
+			content = string(b)
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		sha := hashOf("blob", content)
+		f.blobs[sha] = content
+		writeJSON(w, http.StatusCreated, map[string]any{"sha": sha})
+	})
+
+	mux.HandleFunc("POST "+repo+"/git/trees", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			BaseTree string `json:"base_tree"`
+			Tree     []struct {
+				Path    string  `json:"path"`
+				SHA     *string `json:"sha"`
+				Content *string `json:"content"`
+			} `json:"tree"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		files := map[string]string{}
+		for k, v := range f.trees[req.BaseTree] {
+			files[k] = v
+		}
+		for _, e := range req.Tree {
+			switch {
+			case e.Content != nil:
+				files[e.Path] = *e.Content
+			case e.SHA != nil:
+				files[e.Path] = f.blobs[*e.SHA]
+			default:
+				delete(files, e.Path)
+			}
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"sha": f.putTree(files)})
+	})
+
+	mux.HandleFunc("GET "+repo+"/contents/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		ref := r.URL.Query().Get("ref")
+		if ref == "" {
+			ref = "main"
+		}
+		content, ok := f.files(ref)[r.PathValue("path")]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"type":     "file",
+			"encoding": "base64",
+			"path":     r.PathValue("path"),
+			"sha":      hashOf("blob", content),
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+	})
+
+	mux.HandleFunc("GET "+repo+"/compare/{basehead...}", func(w http.ResponseWriter, r *http.Request) {
+		base, head, _ := strings.Cut(r.PathValue("basehead"), "...")
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if sha, ok := f.refs[head]; ok {
+			head = sha
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"status": f.compare(base, head)})
+	})
+
+	mux.HandleFunc("POST "+repo+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+		var pr fakePull
+		_ = json.NewDecoder(r.Body).Decode(&pr)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, p := range f.pulls {
+			if p.Head == pr.Head && p.State == "open" {
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "A pull request already exists for o:" + pr.Head})
+				return
+			}
+		}
+		pr.Number = len(f.pulls) + 1
+		pr.State = "open"
+		f.pulls = append(f.pulls, pr)
+		writeJSON(w, http.StatusCreated, pr.response())
+	})
+
+	mux.HandleFunc("GET "+repo+"/pulls", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		out := []map[string]any{}
+		for _, p := range f.pulls {
+			if q.Get("head") != "" && "o:"+p.Head != q.Get("head") {
+				continue
+			}
+			if q.Get("state") != "" && q.Get("state") != "all" && p.State != q.Get("state") {
+				continue
+			}
+			out = append(out, p.response())
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+
+	mux.HandleFunc("PATCH "+repo+"/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Title *string `json:"title"`
+			Body  *string `json:"body"`
+			State *string `json:"state"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for i := range f.pulls {
+			p := &f.pulls[i]
+			if fmt.Sprint(p.Number) != r.PathValue("number") {
+				continue
+			}
+			if req.Title != nil {
+				p.Title = *req.Title
+			}
+			if req.Body != nil {
+				p.Body = *req.Body
+			}
+			if req.State != nil {
+				p.State = *req.State
+			}
+			writeJSON(w, http.StatusOK, p.response())
+			return
+		}
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	})
+
+	mux.HandleFunc("GET "+repo+"/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		p := f.pull(r.PathValue("number"))
+		if p == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, p.response())
+	})
+
+	mux.HandleFunc("GET "+repo+"/pulls/{number}/reviews", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		p := f.pull(r.PathValue("number"))
+		if p == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		out := []map[string]any{}
+		for i, rv := range p.Reviews {
+			out = append(out, map[string]any{"id": i + 1, "user": map[string]string{"login": rv[0]}, "state": rv[1]})
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+
+	mux.HandleFunc("GET "+repo+"/issues/{number}/labels", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		p := f.pull(r.PathValue("number"))
+		if p == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, labelsJSON(p.Labels))
+	})
+
+	mux.HandleFunc("POST "+repo+"/issues/{number}/labels", func(w http.ResponseWriter, r *http.Request) {
+		var add []string
+		_ = json.NewDecoder(r.Body).Decode(&add)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		p := f.pull(r.PathValue("number"))
+		if p == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		for _, l := range add {
+			if !slices.Contains(p.Labels, l) {
+				p.Labels = append(p.Labels, l)
+			}
+		}
+		writeJSON(w, http.StatusOK, labelsJSON(p.Labels))
+	})
+
+	mux.HandleFunc("DELETE "+repo+"/issues/{number}/labels/{name}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		p := f.pull(r.PathValue("number"))
+		if p == nil || !slices.Contains(p.Labels, r.PathValue("name")) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Label does not exist"})
+			return
+		}
+		p.Labels = slices.DeleteFunc(p.Labels, func(l string) bool { return l == r.PathValue("name") })
+		writeJSON(w, http.StatusOK, labelsJSON(p.Labels))
+	})
+
+	return mux
+}
+
+// pull returns the PR with the given number; the caller holds f.mu.
+func (f *fakeGitHub) pull(number string) *fakePull {
+	for i := range f.pulls {
+		if fmt.Sprint(f.pulls[i].Number) == number {
+			return &f.pulls[i]
+		}
+	}
+	return nil
+}
+
+func labelsJSON(names []string) []map[string]string {
+	out := make([]map[string]string, len(names))
+	for i, n := range names {
+		out[i] = map[string]string{"name": n}
+	}
+	return out
+}
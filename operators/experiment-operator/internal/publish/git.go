@@ -0,0 +1,497 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitConfig configures the plain git provider.
+type GitConfig struct {
+	// Remote is the repository URL, e.g. "git@host:team/results.git" or a
+	// local path to a bare repository.
+	Remote string
+	// SSHKeyFile is the private key for SSH remotes.
+	SSHKeyFile string
+	// KnownHostsFile pins SSH host keys. Without it, unknown hosts are
+	// accepted on first use.
+	KnownHostsFile string
+	// WorkDir holds the local mirror. Defaults to a temporary directory.
+	WorkDir string
+	// AuthorName and AuthorEmail sign result commits.
+	AuthorName  string
+	AuthorEmail string
+}
+
+// Git publishes to any git remote by shelling out to the git binary, which
+// must be on PATH. There are no change requests: results are pushed to the
+// experiment branch, and merging squashes it onto the base branch as a single
+// commit (merge-tree, then commit-tree with the base as the only parent)
+// before deleting the branch. A local bare repository works as the remote in
+// tests.
+type Git struct {
+	cfg        GitConfig
+	branch     string
+	dir        string
+	retryDelay time.Duration
+
+	// mu serializes fetches into the mirror; object writes and pushes are
+	// safe to run concurrently.
+	mu     sync.Mutex
+	inited bool
+}
+
+// NewGit creates a plain git publisher pushing to cfg.Remote.
+func NewGit(branch string, cfg GitConfig) (*Git, error) {
+	if cfg.Remote == "" {
+		return nil, fmt.Errorf("git: remote is required")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git: %w", err)
+	}
+	if sshRemote(cfg.Remote) || cfg.SSHKeyFile != "" {
+		if _, err := exec.LookPath("ssh"); err != nil {
+			return nil, fmt.Errorf("git: %w", err)
+		}
+	}
+	if cfg.AuthorName == "" {
+		cfg.AuthorName = "experiment-operator"
+	}
+	if cfg.AuthorEmail == "" {
+		cfg.AuthorEmail = "experiment-operator@localhost"
+	}
+	dir := cfg.WorkDir
+	if dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "publish-git-"); err != nil {
+			return nil, fmt.Errorf("git: create work dir: %w", err)
+		}
+	}
+	return &Git{cfg: cfg, branch: branch, dir: filepath.Join(dir, "mirror.git"), retryDelay: commitRetryDelay}, nil
+}
+
+// RepoPath returns the remote URL, without credentials, for logging.
+func (g *Git) RepoPath() string {
+	if u, err := url.Parse(g.cfg.Remote); err == nil && u.User != nil {
+		u.User = nil
+		return u.String()
+	}
+	return g.cfg.Remote
+}
+
+// BaseBranch returns the branch results are merged into.
+func (g *Git) BaseBranch() string {
+	return g.branch
+}
+
+// git runs a git command in the mirror and returns its trimmed stdout.
+func (g *Git) git(ctx context.Context, stdin []byte, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_AUTHOR_NAME="+g.cfg.AuthorName,
+		"GIT_AUTHOR_EMAIL="+g.cfg.AuthorEmail,
+		"GIT_COMMITTER_NAME="+g.cfg.AuthorName,
+		"GIT_COMMITTER_EMAIL="+g.cfg.AuthorEmail,
+	)
+	if ssh := g.sshCommand(); ssh != "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND="+ssh)
+	}
+	cmd.Env = append(cmd.Env, env...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", &gitError{Args: args, Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitError is a failed git command.
+type gitError struct {
+	Args   []string
+	Err    error
+	Stderr string
+}
+
+func (e *gitError) Error() string {
+	return fmt.Sprintf("git %s: %v: %s", strings.Join(e.Args, " "), e.Err, e.Stderr)
+}
+
+func (e *gitError) Unwrap() error { return e.Err }
+
+// pushRejected reports whether err is a push refused because the remote ref
+// moved.
+func pushRejected(err error) bool {
+	var ge *gitError
+	return errors.As(err, &ge) &&
+		(strings.Contains(ge.Stderr, "[rejected]") || strings.Contains(ge.Stderr, "stale info") ||
+			strings.Contains(ge.Stderr, "non-fast-forward") || strings.Contains(ge.Stderr, "fetch first"))
+}
+
+// sshRemote reports whether git reaches remote over SSH: an ssh:// URL or
+// the scp-like user@host:path form.
+func sshRemote(remote string) bool {
+	if strings.HasPrefix(remote, "ssh://") {
+		return true
+	}
+	at, colon := strings.Index(remote, "@"), strings.Index(remote, ":")
+	return !strings.Contains(remote, "://") && at > 0 && colon > at
+}
+
+func (g *Git) sshCommand() string {
+	if g.cfg.SSHKeyFile == "" && g.cfg.KnownHostsFile == "" {
+		return ""
+	}
+	ssh := "ssh -o BatchMode=yes"
+	if g.cfg.SSHKeyFile != "" {
+		ssh += " -o IdentitiesOnly=yes -i " + g.cfg.SSHKeyFile
+	}
+	if g.cfg.KnownHostsFile != "" {
+		ssh += " -o StrictHostKeyChecking=yes -o UserKnownHostsFile=" + g.cfg.KnownHostsFile
+	} else {
+		ssh += " -o StrictHostKeyChecking=accept-new"
+	}
+	return ssh
+}
+
+// fetch creates the bare mirror on first use and updates its branches from
+// the remote.
+func (g *Git) fetch(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.inited {
+		if _, err := os.Stat(filepath.Join(g.dir, "HEAD")); err != nil {
+			if err := os.MkdirAll(g.dir, 0o755); err != nil {
+				return fmt.Errorf("create mirror: %w", err)
+			}
+			if _, err := g.git(ctx, nil, nil, "init", "-q", "--bare"); err != nil {
+				return err
+			}
+			if _, err := g.git(ctx, nil, nil, "remote", "add", "origin", g.cfg.Remote); err != nil {
+				return err
+			}
+		}
+		g.inited = true
+	}
+	if _, err := g.git(ctx, nil, nil, "fetch", "-q", "--prune", "origin", "+refs/heads/*:refs/heads/*"); err != nil {
+		return fmt.Errorf("fetch %s: %w", g.RepoPath(), err)
+	}
+	return nil
+}
+
+// resolve returns the commit a branch name or revision points to, or "" if
+// it does not exist.
+func (g *Git) resolve(ctx context.Context, ref string) (string, error) {
+	for _, candidate := range []string{"refs/heads/" + ref, ref} {
+		sha, err := g.git(ctx, nil, nil, "rev-parse", "-q", "--verify", candidate+"^{commit}")
+		if err == nil {
+			return sha, nil
+		}
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// push pushes refspecs ("src:dst", or ":dst" to delete) without force and
+// mirrors them into the local refs.
+func (g *Git) push(ctx context.Context, refspecs ...string) error {
+	if _, err := g.git(ctx, nil, nil, append([]string{"push", "-q", "origin"}, refspecs...)...); err != nil {
+		return err
+	}
+	for _, spec := range refspecs {
+		src, dst, _ := strings.Cut(spec, ":")
+		args := []string{"update-ref", dst, src}
+		if src == "" {
+			args = []string{"update-ref", "-d", dst}
+		}
+		if _, err := g.git(ctx, nil, nil, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureBranch creates branch from the base branch, or reuses it if it
+// already exists. A branch with no commits of its own is fast-forwarded to
+// the base.
+func (g *Git) EnsureBranch(ctx context.Context, branch string) error {
+	if err := g.fetch(ctx); err != nil {
+		return err
+	}
+	base, err := g.resolve(ctx, g.branch)
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		return fmt.Errorf("base branch %s not found on %s", g.branch, g.RepoPath())
+	}
+	head, err := g.resolve(ctx, branch)
+	if err != nil || head == base {
+		return err
+	}
+	if head != "" {
+		if _, err := g.git(ctx, nil, nil, "merge-base", "--is-ancestor", head, base); err != nil {
+			return nil // the branch has commits of its own
+		}
+	}
+	if err := g.push(ctx, base+":refs/heads/"+branch); err != nil {
+		return fmt.Errorf("create branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// CommitFiles writes the files from change to branch as one commit built
+// with a private index, and pushes it without force. A rejected push means
+// the branch moved: the change is recomputed on the new head and retried.
+func (g *Git) CommitFiles(ctx context.Context, branch, message string, change ChangeFunc) (string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= maxCommitAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Duration(attempt-1) * g.retryDelay):
+			}
+		}
+
+		if err := g.fetch(ctx); err != nil {
+			return "", err
+		}
+		head, err := g.resolve(ctx, branch)
+		if err != nil {
+			return "", err
+		}
+		if head == "" {
+			return "", fmt.Errorf("branch %s not found on %s", branch, g.RepoPath())
+		}
+		files, err := change(ctx, head)
+		if err != nil {
+			return "", err
+		}
+		commit, err := g.commitTree(ctx, head, message, files)
+		if err != nil || commit == head {
+			return commit, err
+		}
+		err = g.push(ctx, commit+":refs/heads/"+branch)
+		if err == nil {
+			return commit, nil
+		}
+		if !pushRejected(err) {
+			return "", fmt.Errorf("push %s: %w", branch, err)
+		}
+		lastErr = err
+	}
+	return "", fmt.Errorf("update branch %s after %d attempts: %w", branch, maxCommitAttempts, lastErr)
+}
+
+// commitTree writes a commit of files on top of parent and returns it, or
+// parent if the files are already identical.
+func (g *Git) commitTree(ctx context.Context, parent, message string, files []File) (string, error) {
+	index, err := os.CreateTemp("", "publish-index-")
+	if err != nil {
+		return "", fmt.Errorf("create index: %w", err)
+	}
+	index.Close()
+	// git expects the index to be absent or valid, not empty.
+	os.Remove(index.Name())
+	defer os.Remove(index.Name())
+	env := []string{"GIT_INDEX_FILE=" + index.Name()}
+
+	if _, err := g.git(ctx, nil, env, "read-tree", parent); err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		blob, err := g.git(ctx, f.Content, nil, "hash-object", "-w", "--stdin")
+		if err != nil {
+			return "", err
+		}
+		if _, err := g.git(ctx, nil, env, "update-index", "--add", "--cacheinfo", "100644,"+blob+","+f.Path); err != nil {
+			return "", err
+		}
+	}
+	tree, err := g.git(ctx, nil, env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	parentTree, err := g.git(ctx, nil, nil, "rev-parse", parent+"^{tree}")
+	if err != nil {
+		return "", err
+	}
+	if tree == parentTree {
+		return parent, nil
+	}
+	return g.git(ctx, nil, nil, "commit-tree", tree, "-p", parent, "-m", message)
+}
+
+// ReadFile returns a file's content at ref (a branch or commit), or nil if it
+// does not exist.
+func (g *Git) ReadFile(ctx context.Context, path, ref string) ([]byte, error) {
+	commit, err := g.resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if commit == "" {
+		return nil, fmt.Errorf("ref %s not found on %s", ref, g.RepoPath())
+	}
+	return g.readBlob(ctx, commit, path)
+}
+
+// readBlob returns the content of the blob at path in commit, or nil if the
+// commit has no such file. ls-tree lists nothing for a missing path, so any
+// error it returns is a real git failure rather than a missing file.
+func (g *Git) readBlob(ctx context.Context, commit, path string) ([]byte, error) {
+	out, err := g.git(ctx, nil, nil, "ls-tree", "-z", commit, "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s at %s: %w", path, commit, err)
+	}
+	var blob string
+	for _, entry := range strings.Split(out, "\x00") {
+		// "<mode> <type> <object>\t<path>"
+		meta, name, ok := strings.Cut(entry, "\t")
+		if fields := strings.Fields(meta); ok && name == path && len(fields) == 3 && fields[1] == "blob" {
+			blob = fields[2]
+		}
+	}
+	if blob == "" {
+		return nil, nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "blob", blob)
+	cmd.Dir = g.dir
+	content, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("read %s at %s: %w", path, commit, err)
+	}
+	return content, nil
+}
+
+// FetchFile fetches a file from the remote. Only this repository can be
+// read: repo must be empty or match the remote.
+func (g *Git) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	if repo != "" && repo != g.cfg.Remote && repo != g.RepoPath() {
+		return nil, fmt.Errorf("git provider can only fetch from %s, not %s", g.RepoPath(), repo)
+	}
+	if ref == "" {
+		ref = g.branch
+	}
+	if err := g.fetch(ctx); err != nil {
+		return nil, err
+	}
+	commit, err := g.resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if commit == "" {
+		return nil, fmt.Errorf("ref %s: %w", ref, ErrNotFound)
+	}
+	content, err := g.readBlob(ctx, commit, path)
+	if err == nil && content == nil {
+		return nil, fmt.Errorf("get %s:%s: %w", ref, path, ErrNotFound)
+	}
+	return content, err
+}
+
+// UpsertChangeRequest is a no-op: plain git has no change requests, so the
+// branch itself is what gets reviewed. The returned number is 0.
+func (g *Git) UpsertChangeRequest(ctx context.Context, branch, title, body string) (ChangeRequest, error) {
+	return ChangeRequest{}, nil
+}
+
+// UpdateChangeRequestBody is a no-op.
+func (g *Git) UpdateChangeRequestBody(ctx context.Context, number int, body string) error {
+	return nil
+}
+
+// ChangeRequestLabels returns no labels.
+func (g *Git) ChangeRequestLabels(ctx context.Context, number int) ([]string, error) {
+	return nil, nil
+}
+
+// EditChangeRequestLabels is a no-op.
+func (g *Git) EditChangeRequestLabels(ctx context.Context, number int, add, remove []string) error {
+	return nil
+}
+
+// ChangeRequestStatus is unsupported: without change requests there is no
+// review state to read.
+func (g *Git) ChangeRequestStatus(ctx context.Context, number int) (*ChangeRequestStatus, error) {
+	return nil, fmt.Errorf("git provider has no change requests")
+}
+
+// MergeChangeRequest squashes branch onto the base branch as one commit and
+// deletes branch. The number is ignored.
+func (g *Git) MergeChangeRequest(ctx context.Context, number int, branch string) error {
+	var lastErr error
+	for attempt := 1; attempt <= maxCommitAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt-1) * g.retryDelay):
+			}
+		}
+		if err := g.fetch(ctx); err != nil {
+			return err
+		}
+		base, err := g.resolve(ctx, g.branch)
+		if err != nil {
+			return err
+		}
+		head, err := g.resolve(ctx, branch)
+		if err != nil {
+			return err
+		}
+		if head == "" {
+			return fmt.Errorf("branch %s not found on %s", branch, g.RepoPath())
+		}
+
+		// merge-tree leaves the branch's tree when base has not moved on.
+		tree, err := g.git(ctx, nil, nil, "merge-tree", "--write-tree", base, head)
+		if err != nil {
+			return fmt.Errorf("merge %s into %s: %w", branch, g.branch, err)
+		}
+		commit, err := g.git(ctx, nil, nil, "commit-tree", tree, "-p", base, "-m", "Merge "+branch)
+		if err != nil {
+			return err
+		}
+		err = g.push(ctx, commit+":refs/heads/"+g.branch)
+		if err == nil {
+			return g.deleteBranch(ctx, branch)
+		}
+		if !pushRejected(err) {
+			return fmt.Errorf("push %s: %w", g.branch, err)
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("merge %s after %d attempts: %w", branch, maxCommitAttempts, lastErr)
+}
+
+// CloseChangeRequest deletes branch without merging. The number is ignored.
+func (g *Git) CloseChangeRequest(ctx context.Context, number int, branch string) error {
+	return g.deleteBranch(ctx, branch)
+}
+
+func (g *Git) deleteBranch(ctx context.Context, branch string) error {
+	if err := g.push(ctx, ":refs/heads/"+branch); err != nil {
+		var ge *gitError
+		if errors.As(err, &ge) && strings.Contains(ge.Stderr, "remote ref does not exist") {
+			return nil
+		}
+		return fmt.Errorf("delete branch %s: %w", branch, err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newBareRemote creates a bare repository whose main branch has one commit
+// with README.md, and a Git publisher pushing to it.
+func newBareRemote(t *testing.T) (string, *Git) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	work := filepath.Join(dir, "work")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@x", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@x")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(dir, "init", "-q", "--bare", remote)
+	run(dir, "init", "-q", "-b", "main", work)
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("hi"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "add", ".")
+	run(work, "commit", "-q", "-m", "initial")
+	run(work, "push", "-q", remote, "main")
+
+	g, err := NewGit("main", GitConfig{Remote: remote, WorkDir: filepath.Join(dir, "cache")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.retryDelay = 0
+	return remote, g
+}
+
+func TestGitPublishAndMerge(t *testing.T) {
+	_, g := newBareRemote(t)
+	c := NewClient(g, "site/data")
+	ctx := context.Background()
+
+	branch, num, _, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", sampleSummary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num != 0 {
+		t.Errorf("change request number = %d, want 0 for plain git", num)
+	}
+	head, err := g.resolve(ctx, branch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Publishing identical results again adds no commit.
+	if _, _, _, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", sampleSummary()); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := g.resolve(ctx, branch); again != head {
+		t.Errorf("unchanged results moved %s from %s to %s", branch, head, again)
+	}
+
+	summary, err := c.FetchFileContent(ctx, "", "site/data/tsdb-b2c4d.json", branch)
+	if err != nil || !strings.Contains(summary, `"name": "tsdb-b2c4d"`) {
+		t.Fatalf("summary on branch = %q, %v", summary, err)
+	}
+	if _, err := g.FetchFile(ctx, "", "site/data/tsdb-b2c4d.json", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("summary on main before merge: err = %v, want ErrNotFound", err)
+	}
+
+	if err := c.MergePR(ctx, num, branch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.FetchFile(ctx, "", "site/data/tsdb-b2c4d.json", ""); err != nil {
+		t.Errorf("summary on main after merge: %v", err)
+	}
+	if readme, err := g.FetchFile(ctx, "", "README.md", ""); err != nil || string(readme) != "hi" {
+		t.Errorf("README.md after merge = %q, %v", readme, err)
+	}
+	if sha, _ := g.resolve(ctx, branch); sha != "" {
+		t.Errorf("branch %s still exists after merge", branch)
+	}
+}
+
+func TestGitCommitFilesRetriesRejectedPush(t *testing.T) {
+	_, g := newBareRemote(t)
+	other, err := NewGit("main", GitConfig{Remote: g.cfg.Remote, WorkDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	var parents []string
+	_, err = g.CommitFiles(ctx, "main", "data: add a", func(ctx context.Context, parent string) ([]File, error) {
+		if len(parents) == 0 {
+			// Another writer pushes between our fetch and push.
+			if _, err := other.CommitFiles(ctx, "main", "data: add other", staticChange(File{Path: "site/data/other.json", Content: []byte("{}")})); err != nil {
+				t.Fatal(err)
+			}
+		}
+		parents = append(parents, parent)
+		return []File{{Path: "site/data/a.json", Content: []byte("{}")}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parents) != 2 || parents[0] == parents[1] {
+		t.Errorf("change called with parents %v, want a rebuild on the new head", parents)
+	}
+	for _, path := range []string{"site/data/a.json", "site/data/other.json"} {
+		if _, err := g.FetchFile(ctx, "", path, "main"); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+func TestGitReadBlob(t *testing.T) {
+	_, g := newBareRemote(t)
+	ctx := context.Background()
+	if err := g.fetch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := g.resolve(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if content, err := g.readBlob(ctx, commit, "README.md"); err != nil || string(content) != "hi" {
+		t.Errorf("readBlob(README.md) = %q, %v, want hi", content, err)
+	}
+	if content, err := g.readBlob(ctx, commit, "missing.json"); err != nil || content != nil {
+		t.Errorf("readBlob(missing.json) = %q, %v, want nil without error", content, err)
+	}
+	// A commit the mirror does not have is a git failure, not a missing file.
+	if _, err := g.readBlob(ctx, strings.Repeat("0", len(commit)), "README.md"); err == nil {
+		t.Error("readBlob() on an unknown commit returned no error")
+	}
+}
+
+func TestSSHRemote(t *testing.T) {
+	for remote, want := range map[string]bool{
+		"git@github.com:team/results.git":      true,
+		"ssh://git@host:2222/team/results.git": true,
+		"https://user@host/team/results.git":   false,
+		"/srv/git/results.git":                 false,
+		"file:///srv/git/results.git":          false,
+	} {
+		if got := sshRemote(remote); got != want {
+			t.Errorf("sshRemote(%q) = %v, want %v", remote, got, want)
+		}
+	}
+}
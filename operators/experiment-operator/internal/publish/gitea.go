@@ -0,0 +1,495 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Gitea publishes to a Gitea (or Forgejo) repository through the REST API
+// (v1). Commits use the multi-file contents endpoint and are reviewed as
+// pull requests.
+type Gitea struct {
+	api         *restClient
+	owner, repo string
+	branch      string
+	retryDelay  time.Duration
+	labelColor  string
+}
+
+// NewGitea creates a Gitea publisher for repo ("owner/repo") on the instance
+// at baseURL.
+func NewGitea(token, repo, branch, baseURL string) (*Gitea, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("gitea: base URL is required")
+	}
+	api := newRESTClient(strings.TrimSuffix(baseURL, "/")+"/api/v1", func(r *http.Request) {
+		if token != "" {
+			r.Header.Set("Authorization", "token "+token)
+		}
+	})
+	return &Gitea{
+		api:        api,
+		owner:      owner,
+		repo:       name,
+		branch:     branch,
+		retryDelay: commitRetryDelay,
+		labelColor: "#ededed",
+	}, nil
+}
+
+// RepoPath returns "owner/repo" for logging.
+func (g *Gitea) RepoPath() string {
+	return g.owner + "/" + g.repo
+}
+
+// BaseBranch returns the branch pull requests target.
+func (g *Gitea) BaseBranch() string {
+	return g.branch
+}
+
+func (g *Gitea) repoPath(format string, args ...any) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(g.owner), url.PathEscape(g.repo)) + fmt.Sprintf(format, args...)
+}
+
+func (g *Gitea) branchHead(ctx context.Context, branch string) (string, error) {
+	var b struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.repoPath("/branches/%s", url.PathEscape(branch)), nil, &b); err != nil {
+		return "", fmt.Errorf("get branch %s: %w", branch, err)
+	}
+	return b.Commit.ID, nil
+}
+
+func (g *Gitea) createBranch(ctx context.Context, branch string) error {
+	return g.api.do(ctx, http.MethodPost, g.repoPath("/branches"), map[string]string{
+		"new_branch_name": branch,
+		"old_branch_name": g.branch,
+	}, nil)
+}
+
+func (g *Gitea) deleteBranch(ctx context.Context, branch string) error {
+	err := g.api.do(ctx, http.MethodDelete, g.repoPath("/branches/%s", url.PathEscape(branch)), nil, nil)
+	if err != nil && statusCode(err) != http.StatusNotFound {
+		return fmt.Errorf("delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// EnsureBranch creates branch from the base branch, or reuses it if it
+// already exists. Gitea cannot move a branch, so a stale branch with no
+// commits of its own is recreated at the base instead of fast-forwarded.
+func (g *Gitea) EnsureBranch(ctx context.Context, branch string) error {
+	err := g.createBranch(ctx, branch)
+	if err == nil {
+		return nil
+	}
+	if statusCode(err) != http.StatusConflict {
+		return fmt.Errorf("create branch %s: %w", branch, err)
+	}
+
+	var cmp struct {
+		TotalCommits int `json:"total_commits"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.repoPath("/compare/%s...%s", url.PathEscape(g.branch), url.PathEscape(branch)), nil, &cmp); err != nil {
+		return fmt.Errorf("compare existing branch %s with %s: %w", branch, g.branch, err)
+	}
+	if cmp.TotalCommits > 0 {
+		return nil // the branch has commits of its own
+	}
+	if err := g.deleteBranch(ctx, branch); err != nil {
+		return err
+	}
+	if err := g.createBranch(ctx, branch); err != nil {
+		return fmt.Errorf("fast-forward branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+type giteaContents struct {
+	SHA     string `json:"sha"`
+	Content string `json:"content"`
+}
+
+// contents returns a file's blob SHA and content at ref, or nil if it does
+// not exist.
+func (g *Gitea) contents(ctx context.Context, owner, repo, path, ref string) (*giteaContents, error) {
+	p := fmt.Sprintf("/repos/%s/%s/contents/%s", url.PathEscape(owner), url.PathEscape(repo), escapePath(path))
+	if ref != "" {
+		p += "?ref=" + url.QueryEscape(ref)
+	}
+	var c giteaContents
+	err := g.api.do(ctx, http.MethodGet, p, nil, &c)
+	if statusCode(err) == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+type giteaFileOp struct {
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+	Content   string `json:"content"`
+	SHA       string `json:"sha,omitempty"`
+}
+
+// CommitFiles writes the files from change to branch as one commit through
+// the contents API. Updates carry the blob SHA they replace, so a concurrent
+// change to the same file is rejected and retried; when the new commit's
+// parent is not the head the files were computed against, the change is
+// recomputed on the new head and committed again.
+func (g *Gitea) CommitFiles(ctx context.Context, branch, message string, change ChangeFunc) (string, error) {
+	for attempt := 1; attempt <= maxCommitAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Duration(attempt-1) * g.retryDelay):
+			}
+		}
+
+		head, err := g.branchHead(ctx, branch)
+		if err != nil {
+			return "", err
+		}
+		files, err := change(ctx, head)
+		if err != nil {
+			return "", err
+		}
+		var ops []giteaFileOp
+		for _, f := range files {
+			existing, err := g.contents(ctx, g.owner, g.repo, f.Path, head)
+			if err != nil {
+				return "", err
+			}
+			op := giteaFileOp{Operation: "create", Path: f.Path, Content: base64.StdEncoding.EncodeToString(f.Content)}
+			if existing != nil {
+				if old, err := base64.StdEncoding.DecodeString(existing.Content); err == nil && bytes.Equal(old, f.Content) {
+					continue
+				}
+				op.Operation, op.SHA = "update", existing.SHA
+			}
+			ops = append(ops, op)
+		}
+		if len(ops) == 0 {
+			return head, nil
+		}
+
+		var resp struct {
+			Commit struct {
+				SHA     string `json:"sha"`
+				Parents []struct {
+					SHA string `json:"sha"`
+				} `json:"parents"`
+			} `json:"commit"`
+		}
+		err = g.api.do(ctx, http.MethodPost, g.repoPath("/contents"), map[string]any{
+			"branch":  branch,
+			"message": message,
+			"files":   ops,
+		}, &resp)
+		switch statusCode(err) {
+		case http.StatusConflict, http.StatusUnprocessableEntity:
+			// A file changed concurrently; recompute.
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("commit to %s: %w", branch, err)
+		}
+		if len(resp.Commit.Parents) > 0 && resp.Commit.Parents[0].SHA == head {
+			return resp.Commit.SHA, nil
+		}
+		// The branch moved: the commit landed on another push, so derived
+		// files such as the site index are recomputed against it.
+	}
+	return "", fmt.Errorf("update branch %s: gave up after %d attempts", branch, maxCommitAttempts)
+}
+
+// ReadFile returns a file's content at ref, or nil if it does not exist.
+func (g *Gitea) ReadFile(ctx context.Context, path, ref string) ([]byte, error) {
+	content, err := g.rawFile(ctx, g.owner, g.repo, path, ref)
+	if statusCode(err) == http.StatusNotFound {
+		return nil, nil
+	}
+	return content, err
+}
+
+// FetchFile fetches a file from the configured repository, or from repo
+// ("owner/repo") on the same instance.
+func (g *Gitea) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	owner, name := g.owner, g.repo
+	if repo != "" {
+		var err error
+		if owner, name, err = splitRepo(repo); err != nil {
+			return nil, err
+		}
+	}
+	content, err := g.rawFile(ctx, owner, name, path, ref)
+	if statusCode(err) == http.StatusNotFound {
+		return nil, fmt.Errorf("get %s/%s:%s: %w", owner, name, path, ErrNotFound)
+	}
+	return content, err
+}
+
+func (g *Gitea) rawFile(ctx context.Context, owner, repo, path, ref string) ([]byte, error) {
+	p := fmt.Sprintf("/repos/%s/%s/raw/%s", url.PathEscape(owner), url.PathEscape(repo), escapePath(path))
+	if ref != "" {
+		p += "?ref=" + url.QueryEscape(ref)
+	}
+	var content []byte
+	if err := g.api.do(ctx, http.MethodGet, p, nil, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// escapePath escapes each segment of a slash-separated repository path.
+func escapePath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+type giteaLabel struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type giteaPR struct {
+	Number  int          `json:"number"`
+	HTMLURL string       `json:"html_url"`
+	State   string       `json:"state"`
+	Merged  bool         `json:"merged"`
+	Labels  []giteaLabel `json:"labels"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (g *Gitea) findPR(ctx context.Context, branch string) (*giteaPR, error) {
+	for page := 1; ; page++ {
+		var prs []giteaPR
+		if err := g.api.do(ctx, http.MethodGet, g.repoPath("/pulls?state=open&limit=50&page=%d", page), nil, &prs); err != nil {
+			return nil, fmt.Errorf("list PRs for %s: %w", branch, err)
+		}
+		for i := range prs {
+			if prs[i].Head.Ref == branch && prs[i].Base.Ref == g.branch {
+				return &prs[i], nil
+			}
+		}
+		if len(prs) < 50 {
+			return nil, nil
+		}
+	}
+}
+
+// UpsertChangeRequest updates the open pull request from branch, or creates one.
+func (g *Gitea) UpsertChangeRequest(ctx context.Context, branch, title, body string) (ChangeRequest, error) {
+	pr, err := g.findPR(ctx, branch)
+	if err != nil {
+		return ChangeRequest{}, err
+	}
+	if pr == nil {
+		var created giteaPR
+		createErr := g.api.do(ctx, http.MethodPost, g.repoPath("/pulls"), map[string]string{
+			"head":  branch,
+			"base":  g.branch,
+			"title": title,
+			"body":  body,
+		}, &created)
+		if createErr == nil {
+			return ChangeRequest{Number: created.Number, URL: created.HTMLURL}, nil
+		}
+		// Lost a race with another publish: fall back to the PR it opened.
+		if pr, err = g.findPR(ctx, branch); err != nil || pr == nil {
+			return ChangeRequest{}, fmt.Errorf("create PR from %s to %s: %w", branch, g.branch, createErr)
+		}
+	}
+	if err := g.api.do(ctx, http.MethodPatch, g.repoPath("/pulls/%d", pr.Number), map[string]string{
+		"title": title,
+		"body":  body,
+	}, nil); err != nil {
+		return ChangeRequest{}, fmt.Errorf("update PR #%d: %w", pr.Number, err)
+	}
+	return ChangeRequest{Number: pr.Number, URL: pr.HTMLURL}, nil
+}
+
+// UpdateChangeRequestBody replaces a pull request's description.
+func (g *Gitea) UpdateChangeRequestBody(ctx context.Context, number int, body string) error {
+	if err := g.api.do(ctx, http.MethodPatch, g.repoPath("/pulls/%d", number), map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("update PR #%d body: %w", number, err)
+	}
+	return nil
+}
+
+func (g *Gitea) getPR(ctx context.Context, number int) (*giteaPR, error) {
+	var pr giteaPR
+	if err := g.api.do(ctx, http.MethodGet, g.repoPath("/pulls/%d", number), nil, &pr); err != nil {
+		return nil, fmt.Errorf("get PR #%d: %w", number, err)
+	}
+	return &pr, nil
+}
+
+// ChangeRequestLabels returns a pull request's labels.
+func (g *Gitea) ChangeRequestLabels(ctx context.Context, number int) ([]string, error) {
+	pr, err := g.getPR(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(pr.Labels))
+	for _, l := range pr.Labels {
+		names = append(names, l.Name)
+	}
+	return names, nil
+}
+
+// EditChangeRequestLabels adds and removes labels. Gitea addresses labels by
+// ID, so unknown labels are created on the repository first.
+func (g *Gitea) EditChangeRequestLabels(ctx context.Context, number int, add, remove []string) error {
+	ids, err := g.labelIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range remove {
+		id, ok := ids[name]
+		if !ok {
+			continue
+		}
+		err := g.api.do(ctx, http.MethodDelete, g.repoPath("/issues/%d/labels/%d", number, id), nil, nil)
+		if err != nil && statusCode(err) != http.StatusNotFound {
+			return fmt.Errorf("remove label %q from PR #%d: %w", name, number, err)
+		}
+	}
+	if len(add) == 0 {
+		return nil
+	}
+	addIDs := make([]int64, 0, len(add))
+	for _, name := range add {
+		id, ok := ids[name]
+		if !ok {
+			var created giteaLabel
+			if err := g.api.do(ctx, http.MethodPost, g.repoPath("/labels"), map[string]string{
+				"name":  name,
+				"color": g.labelColor,
+			}, &created); err != nil {
+				return fmt.Errorf("create label %q: %w", name, err)
+			}
+			id = created.ID
+		}
+		addIDs = append(addIDs, id)
+	}
+	if err := g.api.do(ctx, http.MethodPost, g.repoPath("/issues/%d/labels", number), map[string]any{"labels": addIDs}, nil); err != nil {
+		return fmt.Errorf("label PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// labelIDs maps the repository's label names to IDs.
+func (g *Gitea) labelIDs(ctx context.Context) (map[string]int64, error) {
+	ids := map[string]int64{}
+	for page := 1; ; page++ {
+		var labels []giteaLabel
+		if err := g.api.do(ctx, http.MethodGet, g.repoPath("/labels?limit=50&page=%d", page), nil, &labels); err != nil {
+			return nil, fmt.Errorf("list labels: %w", err)
+		}
+		for _, l := range labels {
+			ids[l.Name] = l.ID
+		}
+		if len(labels) < 50 {
+			return ids, nil
+		}
+	}
+}
+
+// ChangeRequestStatus reads whether a pull request is open, merged, or has
+// reviewers whose latest review requests changes. Dismissed reviews are
+// ignored.
+func (g *Gitea) ChangeRequestStatus(ctx context.Context, number int) (*ChangeRequestStatus, error) {
+	pr, err := g.getPR(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	status := &ChangeRequestStatus{
+		Number: number,
+		Open:   pr.State == "open",
+		Merged: pr.Merged,
+	}
+	if !status.Open {
+		return status, nil
+	}
+
+	latest := map[string]string{}
+	for page := 1; ; page++ {
+		var reviews []struct {
+			User struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			State     string `json:"state"`
+			Dismissed bool   `json:"dismissed"`
+		}
+		if err := g.api.do(ctx, http.MethodGet, g.repoPath("/pulls/%d/reviews?limit=50&page=%d", number, page), nil, &reviews); err != nil {
+			return nil, fmt.Errorf("list reviews of PR #%d: %w", number, err)
+		}
+		// Reviews are returned oldest first.
+		for _, rv := range reviews {
+			switch {
+			case rv.Dismissed:
+				delete(latest, rv.User.Login)
+			case rv.State == "APPROVED", rv.State == "REQUEST_CHANGES":
+				latest[rv.User.Login] = rv.State
+			}
+		}
+		if len(reviews) < 50 {
+			break
+		}
+	}
+	for user, state := range latest {
+		if state == "REQUEST_CHANGES" {
+			status.ChangesRequestedBy = append(status.ChangesRequestedBy, user)
+		}
+	}
+	sort.Strings(status.ChangesRequestedBy)
+	return status, nil
+}
+
+// MergeChangeRequest squash-merges a pull request and deletes the source branch.
+func (g *Gitea) MergeChangeRequest(ctx context.Context, number int, branch string) error {
+	if err := g.api.do(ctx, http.MethodPost, g.repoPath("/pulls/%d/merge", number), map[string]any{
+		"Do":                        "squash",
+		"delete_branch_after_merge": true,
+	}, nil); err != nil {
+		return fmt.Errorf("merge PR #%d: %w", number, err)
+	}
+	return g.deleteBranch(ctx, branch)
+}
+
+// CloseChangeRequest closes a pull request without merging and deletes the source branch.
+func (g *Gitea) CloseChangeRequest(ctx context.Context, number int, branch string) error {
+	if err := g.api.do(ctx, http.MethodPatch, g.repoPath("/pulls/%d", number), map[string]string{
+		"state": "closed",
+	}, nil); err != nil {
+		return fmt.Errorf("close PR #%d: %w", number, err)
+	}
+	return g.deleteBranch(ctx, branch)
+}
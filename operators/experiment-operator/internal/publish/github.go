@@ -0,0 +1,416 @@
+package publish
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/oauth2"
+
+	gh "github.com/google/go-github/v68/github"
+)
+
+// GitHub publishes to a GitHub (or GitHub Enterprise) repository. Commits are
+// built with the Git Data API and reviewed as pull requests.
+type GitHub struct {
+	client *gh.Client
+	owner  string
+	repo   string
+	branch string
+
+	retryDelay time.Duration
+}
+
+// NewGitHub creates a GitHub publisher for repo ("owner/repo"). baseURL is
+// the GitHub Enterprise API URL, or "" for github.com.
+func NewGitHub(token, repo, branch, baseURL string) (*GitHub, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	tc := oauth2.NewClient(context.Background(), ts)
+	if baseURL == "" {
+		return newGitHub(gh.NewClient(tc), owner, name, branch), nil
+	}
+	return newGitHubForURL(tc, baseURL, owner, name, branch)
+}
+
+func newGitHub(client *gh.Client, owner, repo, branch string) *GitHub {
+	return &GitHub{
+		client:     client,
+		owner:      owner,
+		repo:       repo,
+		branch:     branch,
+		retryDelay: commitRetryDelay,
+	}
+}
+
+// newGitHubForURL points a client at a GitHub API base URL.
+func newGitHubForURL(httpClient *http.Client, baseURL, owner, repo, branch string) (*GitHub, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/")
+	if err != nil {
+		return nil, err
+	}
+	client := gh.NewClient(httpClient)
+	client.BaseURL = u
+	return newGitHub(client, owner, repo, branch), nil
+}
+
+// RepoPath returns "owner/repo" for logging.
+func (g *GitHub) RepoPath() string {
+	return g.owner + "/" + g.repo
+}
+
+// BaseBranch returns the branch pull requests target.
+func (g *GitHub) BaseBranch() string {
+	return g.branch
+}
+
+// EnsureBranch creates branchName from the base branch, or reuses it if it
+// already exists (e.g. a publish interrupted by an operator restart).
+func (g *GitHub) EnsureBranch(ctx context.Context, branchName string) error {
+	// Get the SHA of the base branch
+	baseRef, _, err := g.client.Git.GetRef(ctx, g.owner, g.repo, "refs/heads/"+g.branch)
+	if err != nil {
+		return fmt.Errorf("get base branch %s ref: %w", g.branch, err)
+	}
+
+	// Create the new branch pointing at the same SHA
+	newRef := &gh.Reference{
+		Ref:    gh.Ptr("refs/heads/" + branchName),
+		Object: baseRef.Object,
+	}
+	_, resp, err := g.client.Git.CreateRef(ctx, g.owner, g.repo, newRef)
+	if err == nil {
+		return nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+		return fmt.Errorf("create branch %s: %w", branchName, err)
+	}
+
+	// 422: the branch already exists.
+	baseSHA := baseRef.GetObject().GetSHA()
+	cmp, _, err := g.client.Repositories.CompareCommits(ctx, g.owner, g.repo, baseSHA, branchName, nil)
+	if err != nil {
+		return fmt.Errorf("compare existing branch %s with %s: %w", branchName, g.branch, err)
+	}
+	if cmp.GetStatus() == "behind" {
+		if _, _, err := g.client.Git.UpdateRef(ctx, g.owner, g.repo, newRef, false); err != nil {
+			return fmt.Errorf("fast-forward branch %s: %w", branchName, err)
+		}
+	}
+	return nil
+}
+
+// CommitFiles writes the files from change to branch as a single commit
+// built with the Git Data API (blobs, one tree, one commit, a fast-forward
+// ref update), so a publish is never left half-applied. If the ref update
+// loses a race with another push, the commit is rebuilt on the new head.
+func (g *GitHub) CommitFiles(ctx context.Context, branch, message string, change ChangeFunc) (string, error) {
+	refName := "refs/heads/" + branch
+	var lastErr error
+	for attempt := 1; attempt <= maxCommitAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Duration(attempt-1) * g.retryDelay):
+			}
+		}
+
+		ref, _, err := g.client.Git.GetRef(ctx, g.owner, g.repo, refName)
+		if err != nil {
+			return "", fmt.Errorf("get branch %s ref: %w", branch, err)
+		}
+		parentSHA := ref.GetObject().GetSHA()
+		parent, _, err := g.client.Git.GetCommit(ctx, g.owner, g.repo, parentSHA)
+		if err != nil {
+			return "", fmt.Errorf("get commit %s: %w", parentSHA, err)
+		}
+		baseTree := parent.GetTree().GetSHA()
+
+		files, err := change(ctx, parentSHA)
+		if err != nil {
+			return "", err
+		}
+		entries, err := g.treeEntries(ctx, files)
+		if err != nil {
+			return "", err
+		}
+		tree, _, err := g.client.Git.CreateTree(ctx, g.owner, g.repo, baseTree, entries)
+		if err != nil {
+			return "", fmt.Errorf("create tree: %w", err)
+		}
+		if tree.GetSHA() == baseTree {
+			return parentSHA, nil
+		}
+
+		commit, _, err := g.client.Git.CreateCommit(ctx, g.owner, g.repo, &gh.Commit{
+			Message: gh.Ptr(message),
+			Tree:    &gh.Tree{SHA: tree.SHA},
+			Parents: []*gh.Commit{{SHA: gh.Ptr(parentSHA)}},
+		}, nil)
+		if err != nil {
+			return "", fmt.Errorf("create commit: %w", err)
+		}
+
+		_, resp, err := g.client.Git.UpdateRef(ctx, g.owner, g.repo, &gh.Reference{
+			Ref:    gh.Ptr(refName),
+			Object: &gh.GitObject{SHA: commit.SHA},
+		}, false)
+		if err == nil {
+			return commit.GetSHA(), nil
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+			return "", fmt.Errorf("update branch %s: %w", branch, err)
+		}
+		// 422: not a fast-forward — the branch moved; rebuild on the new head.
+		lastErr = err
+	}
+	return "", fmt.Errorf("update branch %s: gave up after %d attempts: %w", branch, maxCommitAttempts, lastErr)
+}
+
+// treeEntries turns files into tree entries. Text is inlined in the tree
+// request; binary content (e.g. images) is uploaded as a base64 blob first.
+func (g *GitHub) treeEntries(ctx context.Context, files []File) ([]*gh.TreeEntry, error) {
+	entries := make([]*gh.TreeEntry, 0, len(files))
+	for _, f := range files {
+		entry := &gh.TreeEntry{
+			Path: gh.Ptr(f.Path),
+			Mode: gh.Ptr("100644"),
+			Type: gh.Ptr("blob"),
+		}
+		if utf8.Valid(f.Content) {
+			entry.Content = gh.Ptr(string(f.Content))
+		} else {
+			blob, _, err := g.client.Git.CreateBlob(ctx, g.owner, g.repo, &gh.Blob{
+				Content:  gh.Ptr(base64.StdEncoding.EncodeToString(f.Content)),
+				Encoding: gh.Ptr("base64"),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("create blob %s: %w", f.Path, err)
+			}
+			entry.SHA = blob.SHA
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadFile returns a file's content at ref, or nil if it does not exist.
+func (g *GitHub) ReadFile(ctx context.Context, path, ref string) ([]byte, error) {
+	content, err := g.getContents(ctx, g.owner, g.repo, path, ref)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return content, err
+}
+
+// FetchFile fetches a file from the configured repo, or from repo
+// ("owner/repo") on the same GitHub instance.
+func (g *GitHub) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	owner, name := g.owner, g.repo
+	if repo != "" {
+		var err error
+		if owner, name, err = splitRepo(repo); err != nil {
+			return nil, err
+		}
+	}
+	return g.getContents(ctx, owner, name, path, ref)
+}
+
+func (g *GitHub) getContents(ctx context.Context, owner, repo, path, ref string) ([]byte, error) {
+	fc, _, resp, err := g.client.Repositories.GetContents(ctx, owner, repo, path, &gh.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("get %s/%s:%s: %w", owner, repo, path, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get %s/%s:%s: %w", owner, repo, path, err)
+	}
+	if fc == nil {
+		return nil, fmt.Errorf("%s is a directory, not a file", path)
+	}
+	content, err := fc.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return []byte(content), nil
+}
+
+// findPR returns the open pull request from branchName into the base
+// branch, or nil if there is none.
+func (g *GitHub) findPR(ctx context.Context, branchName string) (*gh.PullRequest, error) {
+	prs, _, err := g.client.PullRequests.List(ctx, g.owner, g.repo, &gh.PullRequestListOptions{
+		State: "open",
+		Head:  g.owner + ":" + branchName,
+		Base:  g.branch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list PRs for %s: %w", branchName, err)
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0], nil
+}
+
+// UpsertChangeRequest updates the open PR from branchName, or creates one.
+func (g *GitHub) UpsertChangeRequest(ctx context.Context, branchName, title, body string) (ChangeRequest, error) {
+	pr, err := g.findPR(ctx, branchName)
+	if err != nil {
+		return ChangeRequest{}, err
+	}
+	if pr == nil {
+		created, _, createErr := g.client.PullRequests.Create(ctx, g.owner, g.repo, &gh.NewPullRequest{
+			Title: &title,
+			Body:  &body,
+			Head:  &branchName,
+			Base:  &g.branch,
+		})
+		if createErr == nil {
+			return ChangeRequest{Number: created.GetNumber(), URL: created.GetHTMLURL()}, nil
+		}
+		// Lost a race with another publish: fall back to the PR it opened.
+		if pr, err = g.findPR(ctx, branchName); err != nil || pr == nil {
+			return ChangeRequest{}, fmt.Errorf("create PR from %s to %s: %w", branchName, g.branch, createErr)
+		}
+	}
+	if _, _, err := g.client.PullRequests.Edit(ctx, g.owner, g.repo, pr.GetNumber(), &gh.PullRequest{
+		Title: &title,
+		Body:  &body,
+	}); err != nil {
+		return ChangeRequest{}, fmt.Errorf("update PR #%d: %w", pr.GetNumber(), err)
+	}
+	return ChangeRequest{Number: pr.GetNumber(), URL: pr.GetHTMLURL()}, nil
+}
+
+// UpdateChangeRequestBody replaces a pull request's description.
+func (g *GitHub) UpdateChangeRequestBody(ctx context.Context, number int, body string) error {
+	if _, _, err := g.client.PullRequests.Edit(ctx, g.owner, g.repo, number, &gh.PullRequest{Body: &body}); err != nil {
+		return fmt.Errorf("update PR #%d body: %w", number, err)
+	}
+	return nil
+}
+
+// ChangeRequestLabels returns a pull request's labels.
+func (g *GitHub) ChangeRequestLabels(ctx context.Context, number int) ([]string, error) {
+	labels, _, err := g.client.Issues.ListLabelsByIssue(ctx, g.owner, g.repo, number, &gh.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("list labels of PR #%d: %w", number, err)
+	}
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.GetName()
+	}
+	return names, nil
+}
+
+// EditChangeRequestLabels adds and removes labels. GitHub creates labels it
+// does not know yet on the fly.
+func (g *GitHub) EditChangeRequestLabels(ctx context.Context, number int, add, remove []string) error {
+	for _, name := range remove {
+		resp, err := g.client.Issues.RemoveLabelForIssue(ctx, g.owner, g.repo, number, name)
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return fmt.Errorf("remove label %q from PR #%d: %w", name, number, err)
+		}
+	}
+	if len(add) == 0 {
+		return nil
+	}
+	if _, _, err := g.client.Issues.AddLabelsToIssue(ctx, g.owner, g.repo, number, add); err != nil {
+		return fmt.Errorf("label PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// ChangeRequestStatus reads whether a PR is open, merged, or has
+// outstanding change requests. Each reviewer's latest review counts;
+// comments and pending reviews are ignored, and a dismissed review clears
+// that reviewer's request.
+func (g *GitHub) ChangeRequestStatus(ctx context.Context, number int) (*ChangeRequestStatus, error) {
+	pr, _, err := g.client.PullRequests.Get(ctx, g.owner, g.repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("get PR #%d: %w", number, err)
+	}
+	status := &ChangeRequestStatus{
+		Number: number,
+		Open:   pr.GetState() == "open",
+		Merged: pr.GetMerged(),
+	}
+	if !status.Open {
+		return status, nil
+	}
+
+	latest := map[string]string{}
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := g.client.PullRequests.ListReviews(ctx, g.owner, g.repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list reviews of PR #%d: %w", number, err)
+		}
+		// Reviews are returned oldest first.
+		for _, rv := range reviews {
+			switch state := rv.GetState(); state {
+			case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+				latest[rv.GetUser().GetLogin()] = state
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	for user, state := range latest {
+		if state == "CHANGES_REQUESTED" {
+			status.ChangesRequestedBy = append(status.ChangesRequestedBy, user)
+		}
+	}
+	sort.Strings(status.ChangesRequestedBy)
+	return status, nil
+}
+
+// MergeChangeRequest squash-merges a pull request and deletes the source branch.
+func (g *GitHub) MergeChangeRequest(ctx context.Context, number int, branchName string) error {
+	_, _, err := g.client.PullRequests.Merge(ctx, g.owner, g.repo, number, "", &gh.PullRequestOptions{
+		MergeMethod: "squash",
+	})
+	if err != nil {
+		return fmt.Errorf("merge PR #%d: %w", number, err)
+	}
+
+	// Delete the source branch
+	_, err = g.client.Git.DeleteRef(ctx, g.owner, g.repo, "refs/heads/"+branchName)
+	if err != nil {
+		// Non-fatal: PR is merged, branch cleanup is best-effort
+		return fmt.Errorf("delete branch %s after merge: %w", branchName, err)
+	}
+
+	return nil
+}
+
+// CloseChangeRequest closes a pull request without merging and deletes the source branch.
+func (g *GitHub) CloseChangeRequest(ctx context.Context, number int, branchName string) error {
+	closed := "closed"
+	_, _, err := g.client.PullRequests.Edit(ctx, g.owner, g.repo, number, &gh.PullRequest{
+		State: &closed,
+	})
+	if err != nil {
+		return fmt.Errorf("close PR #%d: %w", number, err)
+	}
+
+	// Delete the source branch
+	_, err = g.client.Git.DeleteRef(ctx, g.owner, g.repo, "refs/heads/"+branchName)
+	if err != nil {
+		return fmt.Errorf("delete branch %s after close: %w", branchName, err)
+	}
+
+	return nil
+}
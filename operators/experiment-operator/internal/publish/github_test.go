@@ -0,0 +1,104 @@
+package publish
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func staticChange(files ...File) ChangeFunc {
+	return func(context.Context, string) ([]File, error) { return files, nil }
+}
+
+func TestCommitFilesSingleCommit(t *testing.T) {
+	f, c := newFakeGitHub(t, map[string]string{"README.md": "hi"})
+	ctx := context.Background()
+
+	binary := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+	sha, err := c.forge.CommitFiles(ctx, "main", "data: add", staticChange(
+		File{Path: "site/data/a.json", Content: []byte(`{"name":"a"}`)},
+		File{Path: "site/data/a/metrics/cpu.csv", Content: []byte("timestamp,value\n")},
+		File{Path: "site/data/a/chart.png", Content: binary},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sha == "" || len(f.history("main")) != 2 {
+		t.Fatalf("history = %v, want one commit on top of initial", f.history("main"))
+	}
+	files := f.files("main")
+	if files["README.md"] != "hi" || files["site/data/a.json"] != `{"name":"a"}` || files["site/data/a/chart.png"] != string(binary) {
+		t.Errorf("tree = %v", files)
+	}
+
+	// Committing identical content is a no-op.
+	again, err := c.forge.CommitFiles(ctx, "main", "data: add", staticChange(File{Path: "site/data/a.json", Content: []byte(`{"name":"a"}`)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != sha || len(f.history("main")) != 2 {
+		t.Errorf("unchanged files created a commit: head %s, history %v", again, f.history("main"))
+	}
+}
+
+func TestCommitFilesRetriesRefRace(t *testing.T) {
+	f, c := newFakeGitHub(t, nil)
+	f.racePush = map[string]string{"site/data/other.json": "{}"}
+
+	var parents []string
+	_, err := c.forge.CommitFiles(context.Background(), "main", "data: add a", func(_ context.Context, parent string) ([]File, error) {
+		parents = append(parents, parent)
+		return []File{{Path: "site/data/a.json", Content: []byte("{}")}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parents) != 2 || parents[0] == parents[1] {
+		t.Errorf("change called with parents %v, want a rebuild on the new head", parents)
+	}
+	files := f.files("main")
+	if _, ok := files["site/data/other.json"]; !ok {
+		t.Error("concurrent push was overwritten")
+	}
+	if _, ok := files["site/data/a.json"]; !ok {
+		t.Error("retried commit missing")
+	}
+	if f.refUpdates != 2 {
+		t.Errorf("ref updates = %d, want 2", f.refUpdates)
+	}
+}
+
+func TestGetPRStatus(t *testing.T) {
+	f, c := newFakeGitHub(t, nil)
+	ctx := context.Background()
+	_, num, _, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", sampleSummary())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		state   string
+		merged  bool
+		reviews [][2]string
+		want    ChangeRequestStatus
+	}{
+		{"open", "open", false, nil, ChangeRequestStatus{Open: true}},
+		{"changes requested", "open", false, [][2]string{{"ana", "APPROVED"}, {"bo", "CHANGES_REQUESTED"}, {"ana", "COMMENTED"}},
+			ChangeRequestStatus{Open: true, ChangesRequestedBy: []string{"bo"}}},
+		{"request superseded", "open", false, [][2]string{{"bo", "CHANGES_REQUESTED"}, {"bo", "APPROVED"}}, ChangeRequestStatus{Open: true}},
+		{"request dismissed", "open", false, [][2]string{{"bo", "CHANGES_REQUESTED"}, {"bo", "DISMISSED"}}, ChangeRequestStatus{Open: true}},
+		{"merged", "closed", true, nil, ChangeRequestStatus{Merged: true}},
+		{"closed", "closed", false, [][2]string{{"bo", "CHANGES_REQUESTED"}}, ChangeRequestStatus{}},
+	}
+	for _, tt := range tests {
+		f.pulls[0].State, f.pulls[0].Merged, f.pulls[0].Reviews = tt.state, tt.merged, tt.reviews
+		got, err := c.GetPRStatus(ctx, num)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got.Open != tt.want.Open || got.Merged != tt.want.Merged || !slices.Equal(got.ChangesRequestedBy, tt.want.ChangesRequestedBy) {
+			t.Errorf("%s: status = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GitLab publishes to a GitLab project through the REST API (v4). Commits
+// use the multi-file commits endpoint and are reviewed as merge requests.
+type GitLab struct {
+	api     *restClient
+	project string // URL-escaped project path
+	repo    string
+	branch  string
+
+	retryDelay time.Duration
+}
+
+// NewGitLab creates a GitLab publisher for the project path repo (e.g.
+// "group/subgroup/project"). baseURL is the instance URL, or "" for gitlab.com.
+func NewGitLab(token, repo, branch, baseURL string) (*GitLab, error) {
+	if repo == "" {
+		return nil, fmt.Errorf("gitlab: project path is required")
+	}
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	api := newRESTClient(strings.TrimSuffix(baseURL, "/")+"/api/v4", func(r *http.Request) {
+		r.Header.Set("PRIVATE-TOKEN", token)
+	})
+	return &GitLab{
+		api:        api,
+		project:    url.PathEscape(repo),
+		repo:       repo,
+		branch:     branch,
+		retryDelay: commitRetryDelay,
+	}, nil
+}
+
+// RepoPath returns the project path for logging.
+func (g *GitLab) RepoPath() string {
+	return g.repo
+}
+
+// BaseBranch returns the branch merge requests target.
+func (g *GitLab) BaseBranch() string {
+	return g.branch
+}
+
+func (g *GitLab) projectPath(format string, args ...any) string {
+	return "/projects/" + g.project + fmt.Sprintf(format, args...)
+}
+
+type gitlabBranch struct {
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+func (g *GitLab) branchHead(ctx context.Context, branch string) (string, error) {
+	var b gitlabBranch
+	if err := g.api.do(ctx, http.MethodGet, g.projectPath("/repository/branches/%s", url.PathEscape(branch)), nil, &b); err != nil {
+		return "", fmt.Errorf("get branch %s: %w", branch, err)
+	}
+	return b.Commit.ID, nil
+}
+
+func (g *GitLab) createBranch(ctx context.Context, branch, ref string) error {
+	q := url.Values{"branch": {branch}, "ref": {ref}}
+	return g.api.do(ctx, http.MethodPost, g.projectPath("/repository/branches?%s", q.Encode()), nil, nil)
+}
+
+// EnsureBranch creates branch from the base branch, or reuses it if it
+// already exists. GitLab cannot move a branch, so a stale branch with no
+// commits of its own is recreated at the base instead of fast-forwarded.
+func (g *GitLab) EnsureBranch(ctx context.Context, branch string) error {
+	baseSHA, err := g.branchHead(ctx, g.branch)
+	if err != nil {
+		return err
+	}
+	err = g.createBranch(ctx, branch, baseSHA)
+	if err == nil {
+		return nil
+	}
+	if statusCode(err) != http.StatusBadRequest || !strings.Contains(err.Error(), "already exists") {
+		return fmt.Errorf("create branch %s: %w", branch, err)
+	}
+
+	headSHA, err := g.branchHead(ctx, branch)
+	if err != nil || headSHA == baseSHA {
+		return err
+	}
+	var mergeBase struct {
+		ID string `json:"id"`
+	}
+	q := url.Values{"refs[]": {baseSHA, headSHA}}
+	if err := g.api.do(ctx, http.MethodGet, g.projectPath("/repository/merge_base?%s", q.Encode()), nil, &mergeBase); err != nil {
+		return fmt.Errorf("compare existing branch %s with %s: %w", branch, g.branch, err)
+	}
+	if mergeBase.ID != headSHA {
+		return nil // the branch has commits of its own
+	}
+	if err := g.deleteBranch(ctx, branch); err != nil {
+		return err
+	}
+	if err := g.createBranch(ctx, branch, baseSHA); err != nil {
+		return fmt.Errorf("fast-forward branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+func (g *GitLab) deleteBranch(ctx context.Context, branch string) error {
+	err := g.api.do(ctx, http.MethodDelete, g.projectPath("/repository/branches/%s", url.PathEscape(branch)), nil, nil)
+	if err != nil && statusCode(err) != http.StatusNotFound {
+		return fmt.Errorf("delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+type gitlabAction struct {
+	Action   string `json:"action"`
+	FilePath string `json:"file_path"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// CommitFiles writes the files from change to branch as one commit through
+// the commits API. GitLab applies it to whatever the branch head is, so when
+// the new commit's parent is not the head the files were computed against,
+// the change is recomputed on the new head and committed again.
+func (g *GitLab) CommitFiles(ctx context.Context, branch, message string, change ChangeFunc) (string, error) {
+	for attempt := 1; attempt <= maxCommitAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Duration(attempt-1) * g.retryDelay):
+			}
+		}
+
+		head, err := g.branchHead(ctx, branch)
+		if err != nil {
+			return "", err
+		}
+		files, err := change(ctx, head)
+		if err != nil {
+			return "", err
+		}
+		var actions []gitlabAction
+		for _, f := range files {
+			existing, err := g.ReadFile(ctx, f.Path, head)
+			if err != nil {
+				return "", err
+			}
+			if existing != nil && bytes.Equal(existing, f.Content) {
+				continue
+			}
+			a := gitlabAction{Action: "create", FilePath: f.Path, Content: string(f.Content), Encoding: "text"}
+			if existing != nil {
+				a.Action = "update"
+			}
+			if !utf8.Valid(f.Content) {
+				a.Content, a.Encoding = base64.StdEncoding.EncodeToString(f.Content), "base64"
+			}
+			actions = append(actions, a)
+		}
+		if len(actions) == 0 {
+			return head, nil
+		}
+
+		var commit struct {
+			ID        string   `json:"id"`
+			ParentIDs []string `json:"parent_ids"`
+		}
+		err = g.api.do(ctx, http.MethodPost, g.projectPath("/repository/commits"), map[string]any{
+			"branch":         branch,
+			"commit_message": message,
+			"actions":        actions,
+		}, &commit)
+		if statusCode(err) == http.StatusBadRequest {
+			// A file was created or deleted concurrently; recompute.
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("commit to %s: %w", branch, err)
+		}
+		if len(commit.ParentIDs) > 0 && commit.ParentIDs[0] == head {
+			return commit.ID, nil
+		}
+		// The branch moved: the commit landed on another push, so derived
+		// files such as the site index are recomputed against it.
+	}
+	return "", fmt.Errorf("update branch %s: gave up after %d attempts", branch, maxCommitAttempts)
+}
+
+// ReadFile returns a file's content at ref, or nil if it does not exist.
+func (g *GitLab) ReadFile(ctx context.Context, path, ref string) ([]byte, error) {
+	content, err := g.rawFile(ctx, g.project, path, ref)
+	if statusCode(err) == http.StatusNotFound {
+		return nil, nil
+	}
+	return content, err
+}
+
+// FetchFile fetches a file from the configured project, or from the project
+// path repo on the same instance.
+func (g *GitLab) FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	project := g.project
+	if repo != "" {
+		project = url.PathEscape(repo)
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	content, err := g.rawFile(ctx, project, path, ref)
+	if statusCode(err) == http.StatusNotFound {
+		return nil, fmt.Errorf("get %s:%s: %w", repo, path, ErrNotFound)
+	}
+	return content, err
+}
+
+func (g *GitLab) rawFile(ctx context.Context, project, path, ref string) ([]byte, error) {
+	var content []byte
+	p := fmt.Sprintf("/projects/%s/repository/files/%s/raw?ref=%s", project, url.PathEscape(path), url.QueryEscape(ref))
+	if err := g.api.do(ctx, http.MethodGet, p, nil, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+type gitlabMR struct {
+	IID    int      `json:"iid"`
+	WebURL string   `json:"web_url"`
+	State  string   `json:"state"`
+	Labels []string `json:"labels"`
+}
+
+func (g *GitLab) findMR(ctx context.Context, branch string) (*gitlabMR, error) {
+	var mrs []gitlabMR
+	q := url.Values{"state": {"opened"}, "source_branch": {branch}, "target_branch": {g.branch}}
+	if err := g.api.do(ctx, http.MethodGet, g.projectPath("/merge_requests?%s", q.Encode()), nil, &mrs); err != nil {
+		return nil, fmt.Errorf("list merge requests for %s: %w", branch, err)
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return &mrs[0], nil
+}
+
+// UpsertChangeRequest updates the open merge request from branch, or creates one.
+func (g *GitLab) UpsertChangeRequest(ctx context.Context, branch, title, body string) (ChangeRequest, error) {
+	mr, err := g.findMR(ctx, branch)
+	if err != nil {
+		return ChangeRequest{}, err
+	}
+	if mr == nil {
+		var created gitlabMR
+		createErr := g.api.do(ctx, http.MethodPost, g.projectPath("/merge_requests"), map[string]any{
+			"source_branch":        branch,
+			"target_branch":        g.branch,
+			"title":                title,
+			"description":          body,
+			"remove_source_branch": true,
+		}, &created)
+		if createErr == nil {
+			return ChangeRequest{Number: created.IID, URL: created.WebURL}, nil
+		}
+		// Lost a race with another publish: fall back to the MR it opened.
+		if mr, err = g.findMR(ctx, branch); err != nil || mr == nil {
+			return ChangeRequest{}, fmt.Errorf("create merge request from %s to %s: %w", branch, g.branch, createErr)
+		}
+	}
+	if err := g.api.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d", mr.IID), map[string]any{
+		"title":       title,
+		"description": body,
+	}, nil); err != nil {
+		return ChangeRequest{}, fmt.Errorf("update merge request !%d: %w", mr.IID, err)
+	}
+	return ChangeRequest{Number: mr.IID, URL: mr.WebURL}, nil
+}
+
+// UpdateChangeRequestBody replaces a merge request's description.
+func (g *GitLab) UpdateChangeRequestBody(ctx context.Context, number int, body string) error {
+	if err := g.api.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d", number), map[string]any{"description": body}, nil); err != nil {
+		return fmt.Errorf("update merge request !%d description: %w", number, err)
+	}
+	return nil
+}
+
+func (g *GitLab) getMR(ctx context.Context, number int) (*gitlabMR, error) {
+	var mr gitlabMR
+	if err := g.api.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d", number), nil, &mr); err != nil {
+		return nil, fmt.Errorf("get merge request !%d: %w", number, err)
+	}
+	return &mr, nil
+}
+
+// ChangeRequestLabels returns a merge request's labels.
+func (g *GitLab) ChangeRequestLabels(ctx context.Context, number int) ([]string, error) {
+	mr, err := g.getMR(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return mr.Labels, nil
+}
+
+// EditChangeRequestLabels adds and removes labels. GitLab creates unknown
+// labels on the project.
+func (g *GitLab) EditChangeRequestLabels(ctx context.Context, number int, add, remove []string) error {
+	if err := g.api.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d", number), map[string]any{
+		"add_labels":    strings.Join(add, ","),
+		"remove_labels": strings.Join(remove, ","),
+	}, nil); err != nil {
+		return fmt.Errorf("label merge request !%d: %w", number, err)
+	}
+	return nil
+}
+
+// ChangeRequestStatus reads whether a merge request is open, merged, or has
+// reviewers who requested changes. Instances without reviewer states report
+// no change requests.
+func (g *GitLab) ChangeRequestStatus(ctx context.Context, number int) (*ChangeRequestStatus, error) {
+	mr, err := g.getMR(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	status := &ChangeRequestStatus{
+		Number: number,
+		Open:   mr.State == "opened",
+		Merged: mr.State == "merged",
+	}
+	if !status.Open {
+		return status, nil
+	}
+	var reviewers []struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+		State string `json:"state"`
+	}
+	err = g.api.do(ctx, http.MethodGet, g.projectPath("/merge_requests/%d/reviewers", number), nil, &reviewers)
+	if err != nil && statusCode(err) != http.StatusNotFound {
+		return nil, fmt.Errorf("list reviewers of merge request !%d: %w", number, err)
+	}
+	for _, r := range reviewers {
+		if r.State == "requested_changes" {
+			status.ChangesRequestedBy = append(status.ChangesRequestedBy, r.User.Username)
+		}
+	}
+	sort.Strings(status.ChangesRequestedBy)
+	return status, nil
+}
+
+// MergeChangeRequest squash-merges a merge request and deletes the source branch.
+func (g *GitLab) MergeChangeRequest(ctx context.Context, number int, branch string) error {
+	if err := g.api.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d/merge", number), map[string]any{
+		"squash":                      true,
+		"should_remove_source_branch": true,
+	}, nil); err != nil {
+		return fmt.Errorf("merge merge request !%d: %w", number, err)
+	}
+	return g.deleteBranch(ctx, branch)
+}
+
+// CloseChangeRequest closes a merge request without merging and deletes the source branch.
+func (g *GitLab) CloseChangeRequest(ctx context.Context, number int, branch string) error {
+	if err := g.api.do(ctx, http.MethodPut, g.projectPath("/merge_requests/%d", number), map[string]any{
+		"state_event": "close",
+	}, nil); err != nil {
+		return fmt.Errorf("close merge request !%d: %w", number, err)
+	}
+	return g.deleteBranch(ctx, branch)
+}
@@ -0,0 +1,350 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/catalog"
+	"github.com/illmadecoder/experiment-operator/internal/export"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+// Label prefixes of the labels the operator manages on results PRs. Labels
+// without these prefixes are left alone so reviewers can add their own.
+const (
+	LabelResults       = "experiment-results"
+	LabelTagPrefix     = "tag:"
+	LabelVerdictPrefix = "verdict:"
+)
+
+// maxLabelLen is GitHub's limit on label names.
+const maxLabelLen = 50
+
+// ReviewContext is the experiment state around a summary that the PR body
+// shows next to it: where the artifacts are and how the analyzer is doing.
+type ReviewContext struct {
+	// ResultsURL is the store prefix of the experiment's results.
+	ResultsURL string
+	// AnalysisPhase and AnalysisJob describe the analyzer Job, if any.
+	AnalysisPhase experimentsv1alpha1.AnalysisPhase
+	AnalysisJob   string
+}
+
+// WithReviewContext adds artifact links and analyzer status to the PR body.
+func WithReviewContext(rc ReviewContext) PublishOption {
+	return func(o *publishOptions) {
+		o.review = rc
+	}
+}
+
+// PRLabels returns the labels for an experiment's results PR: one per tag
+// and one for the verdict, preferring the analyzer's over the machine's.
+func PRLabels(summary *metrics.ExperimentSummary) []string {
+	labels := []string{LabelResults}
+	for _, t := range summary.Tags {
+		labels = append(labels, truncateLabel(LabelTagPrefix+t))
+	}
+	if v := summaryVerdict(summary); v != "" {
+		labels = append(labels, LabelVerdictPrefix+v)
+	}
+	return labels
+}
+
+func truncateLabel(l string) string {
+	if len(l) > maxLabelLen {
+		return l[:maxLabelLen]
+	}
+	return l
+}
+
+// summaryVerdict is the analyzer's verdict if there is one, else the machine verdict.
+func summaryVerdict(s *metrics.ExperimentSummary) string {
+	if s.Analysis != nil && s.Analysis.HypothesisVerdict != "" {
+		return s.Analysis.HypothesisVerdict
+	}
+	if s.Hypothesis != nil {
+		return s.Hypothesis.MachineVerdict
+	}
+	return ""
+}
+
+// LabelPR sets the operator-managed labels on a change request to labels.
+// Stale tag and verdict labels are removed, other labels are kept.
+func (c *Client) LabelPR(ctx context.Context, prNumber int, labels []string) error {
+	if prNumber == 0 {
+		return nil
+	}
+	current, err := c.forge.ChangeRequestLabels(ctx, prNumber)
+	if err != nil {
+		return err
+	}
+	want := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		want[l] = true
+	}
+	have := make(map[string]bool, len(current))
+	var remove []string
+	for _, name := range current {
+		have[name] = true
+		managed := name == LabelResults || strings.HasPrefix(name, LabelTagPrefix) || strings.HasPrefix(name, LabelVerdictPrefix)
+		if managed && !want[name] {
+			remove = append(remove, name)
+		}
+	}
+	var add []string
+	for _, l := range labels {
+		if !have[l] {
+			add = append(add, l)
+		}
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	return c.forge.EditChangeRequestLabels(ctx, prNumber, add, remove)
+}
+
+// UpdatePRBody re-renders a results change request's body, e.g. once the
+// analyzer has finished.
+func (c *Client) UpdatePRBody(ctx context.Context, prNumber int, expName string, summary *metrics.ExperimentSummary, opts ...PublishOption) error {
+	if prNumber == 0 {
+		return nil
+	}
+	var po publishOptions
+	for _, opt := range opts {
+		opt(&po)
+	}
+	return c.forge.UpdateChangeRequestBody(ctx, prNumber, c.PRBody(expName, summary, po.review))
+}
+
+// PRBody renders the description of an experiment's results PR: everything
+// a reviewer needs to approve or reject the results without checking out
+// the branch.
+func (c *Client) PRBody(expName string, s *metrics.ExperimentSummary, rc ReviewContext) string {
+	var b strings.Builder
+	title := s.Title
+	if title == "" {
+		title = expName
+	}
+	fmt.Fprintf(&b, "## %s\n\n", title)
+	if s.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(s.Description))
+	}
+	fmt.Fprintf(&b, "Experiment `%s` finished **%s** after %s", expName, s.Phase, formatDuration(s.DurationSec))
+	if !s.CompletedAt.IsZero() {
+		fmt.Fprintf(&b, " (%s)", s.CompletedAt.UTC().Format(time.RFC3339))
+	}
+	b.WriteString(".")
+	if len(s.Tags) > 0 {
+		fmt.Fprintf(&b, " Tags: %s.", "`"+strings.Join(s.Tags, "`, `")+"`")
+	}
+	b.WriteString("\n\n")
+
+	writeHypothesis(&b, s)
+	writeQualityGate(&b, s.IterationStatus)
+	writeCost(&b, s.CostEstimate)
+	writeKeyMetrics(&b, s.Metrics)
+	writeArtifacts(&b, rc.ResultsURL)
+	writeAnalysis(&b, s, rc)
+
+	b.WriteString("### Review\n\n")
+	fmt.Fprintf(&b, "Results are committed to `%s/%s.json`. Approve or reject on the cluster:\n\n", c.path, expName)
+	ns := s.Namespace
+	if ns == "" {
+		ns = "<namespace>"
+	}
+	fmt.Fprintf(&b, "```\nkubectl annotate experiment %s -n %s %s=approved\nkubectl annotate experiment %s -n %s %s=rejected\n```\n\n",
+		expName, ns, experimentsv1alpha1.AnnotationReview, expName, ns, experimentsv1alpha1.AnnotationReview)
+	fmt.Fprintf(&b, "Preview locally:\n```\ngit fetch && git checkout %s\ncd site && npm run dev\n```\n", BranchName(expName))
+	return b.String()
+}
+
+func writeHypothesis(b *strings.Builder, s *metrics.ExperimentSummary) {
+	h := s.Hypothesis
+	if h == nil {
+		return
+	}
+	b.WriteString("### Hypothesis\n\n")
+	if h.Claim != "" {
+		fmt.Fprintf(b, "> %s\n\n", strings.ReplaceAll(strings.TrimSpace(h.Claim), "\n", "\n> "))
+	}
+	if h.MachineVerdict != "" {
+		fmt.Fprintf(b, "**Machine verdict:** %s\n\n", verdictBadge(h.MachineVerdict))
+	}
+	if len(h.SuccessCriteria) == 0 {
+		return
+	}
+	b.WriteString("| Criterion | Expected | Actual | Result |\n|---|---|---|---|\n")
+	for _, sc := range h.SuccessCriteria {
+		name := "`" + cell(sc.Metric) + "`"
+		if sc.Description != "" {
+			name += " — " + cell(sc.Description)
+		}
+		actual := sc.ActualValue
+		if actual == "" {
+			actual = "—"
+		}
+		result := "—"
+		if sc.Passed != nil {
+			result = "❌ fail"
+			if *sc.Passed {
+				result = "✅ pass"
+			}
+		}
+		fmt.Fprintf(b, "| %s | %s %s | %s | %s |\n", name, cell(sc.Operator), cell(sc.Value), cell(actual), result)
+	}
+	b.WriteString("\n")
+}
+
+func writeQualityGate(b *strings.Builder, is *experimentsv1alpha1.IterationStatus) {
+	if is == nil || len(is.QualityResults) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "### Quality gate\n\n%d of at most %d iterations", len(is.QualityResults), is.MaxIterations)
+	if is.Phase != "" {
+		fmt.Fprintf(b, ", %s", strings.ToLower(string(is.Phase)))
+	}
+	b.WriteString(".\n\n| Iteration | Coverage | Missing required | Action | Passed |\n|---|---|---|---|---|\n")
+	for _, q := range is.QualityResults {
+		missing := "—"
+		if len(q.MissingRequired) > 0 {
+			missing = cell(strings.Join(q.MissingRequired, ", "))
+		}
+		action := string(q.Action)
+		if action == "" {
+			action = "—"
+		}
+		passed := "no"
+		if q.Passed {
+			passed = "yes"
+		}
+		fmt.Fprintf(b, "| %d | %.0f%% (%d/%d) | %s | %s | %s |\n", q.Iteration, q.Coverage*100, q.MetricsWithData, q.TotalMetrics, missing, action, passed)
+	}
+	b.WriteString("\n")
+}
+
+func writeCost(b *strings.Builder, c *metrics.CostEstimate) {
+	if c == nil {
+		return
+	}
+	fmt.Fprintf(b, "### Cost\n\n**$%.2f** estimated over %.1f h", c.TotalUSD, c.DurationHrs)
+	if len(c.PerTarget) > 0 {
+		targets := make([]string, 0, len(c.PerTarget))
+		for t := range c.PerTarget {
+			targets = append(targets, t)
+		}
+		sort.Strings(targets)
+		parts := make([]string, len(targets))
+		for i, t := range targets {
+			parts[i] = fmt.Sprintf("%s $%.2f", t, c.PerTarget[t])
+		}
+		fmt.Fprintf(b, " (%s)", strings.Join(parts, ", "))
+	}
+	b.WriteString(".")
+	if c.Note != "" {
+		fmt.Fprintf(b, " %s", c.Note)
+	}
+	b.WriteString("\n\n")
+}
+
+func writeKeyMetrics(b *strings.Builder, result *metrics.MetricsResult) {
+	km := catalog.KeyMetrics(result)
+	if len(km) == 0 {
+		return
+	}
+	names := make([]string, 0, len(km))
+	for n := range km {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	b.WriteString("### Key metrics\n\n| Metric | Value | Unit |\n|---|---|---|\n")
+	for _, n := range names {
+		fmt.Fprintf(b, "| `%s` | %.4g | %s |\n", cell(n), km[n], cell(result.Queries[n].Unit))
+	}
+	b.WriteString("\n")
+}
+
+func writeArtifacts(b *strings.Builder, resultsURL string) {
+	if resultsURL == "" || resultsURL == "disabled" {
+		return
+	}
+	base := strings.TrimSuffix(resultsURL, "/") + "/"
+	b.WriteString("### Artifacts\n\n")
+	for _, a := range []struct{ name, desc string }{
+		{"summary.json", "summary"},
+		{"metrics-snapshot.json", "raw metrics"},
+		{export.ParquetFile, "Parquet"},
+		{export.OpenMetricsFile, "OpenMetrics"},
+		{export.CSVDir, "per-query CSV"},
+		{"index.json", "run manifest"},
+	} {
+		fmt.Fprintf(b, "- %s: %s\n", a.desc, artifactLink(base+a.name))
+	}
+	b.WriteString("\n")
+}
+
+// artifactLink renders URLs a browser can open as links and others (s3://)
+// as code so they can be copied.
+func artifactLink(u string) string {
+	if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+		return fmt.Sprintf("[%s](%s)", u, u)
+	}
+	return "`" + u + "`"
+}
+
+func writeAnalysis(b *strings.Builder, s *metrics.ExperimentSummary, rc ReviewContext) {
+	phase := string(rc.AnalysisPhase)
+	if phase == "" && s.AnalyzerConfig != nil {
+		phase = string(experimentsv1alpha1.AnalysisPhasePending)
+	}
+	if phase == "" && s.Analysis == nil {
+		return
+	}
+	b.WriteString("### Analysis\n\n")
+	if phase != "" {
+		fmt.Fprintf(b, "Analyzer: **%s**", phase)
+		if rc.AnalysisJob != "" {
+			fmt.Fprintf(b, " (Job `%s`)", rc.AnalysisJob)
+		}
+		b.WriteString("\n\n")
+	}
+	a := s.Analysis
+	if a == nil {
+		return
+	}
+	if a.HypothesisVerdict != "" {
+		fmt.Fprintf(b, "**Analyzer verdict:** %s\n\n", verdictBadge(a.HypothesisVerdict))
+	}
+	abstract := a.Abstract
+	if abstract == "" {
+		abstract = a.Summary
+	}
+	if abstract != "" {
+		fmt.Fprintf(b, "%s\n\n", strings.TrimSpace(abstract))
+	}
+}
+
+func verdictBadge(v string) string {
+	switch v {
+	case "validated":
+		return "✅ validated"
+	case "invalidated":
+		return "❌ invalidated"
+	case "insufficient":
+		return "⚠️ insufficient"
+	}
+	return v
+}
+
+// cell escapes a value for a Markdown table cell.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func formatDuration(sec float64) string {
+	return (time.Duration(sec) * time.Second).Round(time.Second).String()
+}
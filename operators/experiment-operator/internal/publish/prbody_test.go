@@ -0,0 +1,98 @@
+package publish
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+func TestPRBody(t *testing.T) {
+	c := NewClient(nil, "site/data")
+	s := sampleSummary()
+	s.Namespace = "experiments"
+	s.DurationSec = 3600
+	s.AnalyzerConfig = &metrics.AnalyzerConfigJSON{Sections: []string{"abstract"}}
+	passed, failed := true, false
+	s.Hypothesis.Claim = "Mimir needs less memory than Prometheus"
+	s.Hypothesis.SuccessCriteria = []metrics.SuccessCriterionSummary{
+		{Metric: "memory_ratio", Operator: "lt", Value: "0.8", Passed: &passed, ActualValue: "0.61"},
+		{Metric: "p99|latency", Operator: "lt", Value: "0.5", Passed: &failed, ActualValue: "0.7"},
+	}
+	s.IterationStatus = &experimentsv1alpha1.IterationStatus{MaxIterations: 3, QualityResults: []experimentsv1alpha1.QualityResult{
+		{Iteration: 0, TotalMetrics: 4, MetricsWithData: 2, Coverage: 0.5, MissingRequired: []string{"cpu"}, Action: "shortenWindow"},
+		{Iteration: 1, TotalMetrics: 4, MetricsWithData: 4, Coverage: 1, Passed: true},
+	}}
+	s.CostEstimate = &metrics.CostEstimate{TotalUSD: 1.234, DurationHrs: 1, PerTarget: map[string]float64{"app": 1.234}}
+
+	body := c.PRBody(s.Name, s, ReviewContext{ResultsURL: "s3://results/tsdb-b2c4d/"})
+	for _, want := range []string{
+		"## TSDB comparison",
+		"finished **Complete** after 1h0m0s",
+		"> Mimir needs less memory than Prometheus",
+		"**Machine verdict:** ✅ validated",
+		"| `memory_ratio` | lt 0.8 | 0.61 | ✅ pass |",
+		"| `p99\\|latency` | lt 0.5 | 0.7 | ❌ fail |",
+		"| 0 | 50% (2/4) | cpu | shortenWindow | no |",
+		"**$1.23** estimated over 1.0 h (app $1.23)",
+		"| `cpu by pod` | 0.5 |",
+		"`s3://results/tsdb-b2c4d/metrics.parquet`",
+		"Analyzer: **Pending**",
+		"kubectl annotate experiment tsdb-b2c4d -n experiments experiments.illm.io/review=approved",
+		"git checkout experiment/tsdb-b2c4d",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("PR body missing %q:\n%s", want, body)
+		}
+	}
+
+	s.Analysis = &metrics.AnalysisResult{HypothesisVerdict: "invalidated", Abstract: "Memory fell; latency rose."}
+	body = c.PRBody(s.Name, s, ReviewContext{AnalysisPhase: experimentsv1alpha1.AnalysisPhaseSucceeded, AnalysisJob: "analyze-tsdb"})
+	for _, want := range []string{"Analyzer: **Succeeded** (Job `analyze-tsdb`)", "**Analyzer verdict:** ❌ invalidated", "Memory fell; latency rose."} {
+		if !strings.Contains(body, want) {
+			t.Errorf("PR body missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "### Artifacts") {
+		t.Error("artifacts listed without a results URL")
+	}
+}
+
+func TestLabelPR(t *testing.T) {
+	f, c := newFakeGitHub(t, nil)
+	ctx := context.Background()
+	s := sampleSummary()
+
+	_, num, _, err := c.PublishExperimentResult(ctx, s.Name, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.pulls[0].Labels = []string{"needs-discussion"}
+	if err := c.LabelPR(ctx, num, PRLabels(s)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"needs-discussion", "experiment-results", "tag:tsdb", "verdict:validated"}
+	if !slices.Equal(f.pulls[0].Labels, want) {
+		t.Errorf("labels = %v, want %v", f.pulls[0].Labels, want)
+	}
+
+	// The analyzer's verdict replaces the machine verdict label.
+	s.Analysis = &metrics.AnalysisResult{HypothesisVerdict: "insufficient"}
+	if err := c.LabelPR(ctx, num, PRLabels(s)); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"needs-discussion", "experiment-results", "tag:tsdb", "verdict:insufficient"}
+	if !slices.Equal(f.pulls[0].Labels, want) {
+		t.Errorf("labels = %v, want %v", f.pulls[0].Labels, want)
+	}
+
+	if err := c.UpdatePRBody(ctx, num, s.Name, s); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(f.pulls[0].Body, "⚠️ insufficient") {
+		t.Errorf("PR body not refreshed: %s", f.pulls[0].Body)
+	}
+}
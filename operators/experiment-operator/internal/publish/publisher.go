@@ -0,0 +1,153 @@
+// Package publish commits experiment results to a Git repository and opens a
+// change request (pull or merge request) for review before they reach the
+// benchmark site. The forge behind it is a ResultPublisher: GitHub, GitLab,
+// Gitea, or a plain git remote.
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// File is one file of a commit, with a path relative to the repository root.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// ChangeFunc returns the files to commit on top of the parent commit. It is
+// called again after a ref race, so files derived from the branch contents
+// (such as the site index) are recomputed against the new head.
+type ChangeFunc func(ctx context.Context, parent string) ([]File, error)
+
+// ChangeRequest identifies a pull request (GitHub, Gitea) or merge request
+// (GitLab). Number is 0 for forges without change requests.
+type ChangeRequest struct {
+	Number int
+	URL    string
+}
+
+// ChangeRequestStatus is the review state of a change request.
+type ChangeRequestStatus struct {
+	Number int
+	// Open is false once the change request is closed, merged or not.
+	Open   bool
+	Merged bool
+	// ChangesRequestedBy lists reviewers whose latest review requests changes.
+	ChangesRequestedBy []string
+}
+
+// ErrNotFound is returned by FetchFile when the file does not exist.
+var ErrNotFound = errors.New("file not found")
+
+// ResultPublisher is the forge-specific half of publishing: branches,
+// commits and change requests. Client builds results publishing on top of
+// it. Implementations must be safe for concurrent use.
+type ResultPublisher interface {
+	// RepoPath names the repository for logs, e.g. "owner/repo".
+	RepoPath() string
+	// BaseBranch is the branch change requests target.
+	BaseBranch() string
+	// EnsureBranch creates branch from the base branch, or reuses an existing
+	// one. An existing branch with no commits of its own is fast-forwarded to
+	// the base; one that already carries commits is kept.
+	EnsureBranch(ctx context.Context, branch string) error
+	// CommitFiles writes the files from change to branch as a single commit,
+	// rebuilding it on the new head if the branch moves concurrently. It
+	// returns the new head; when the files are already identical on the
+	// branch no commit is made and the current head is returned.
+	CommitFiles(ctx context.Context, branch, message string, change ChangeFunc) (string, error)
+	// ReadFile returns a file's content at ref (a branch or commit), or nil
+	// if it does not exist.
+	ReadFile(ctx context.Context, path, ref string) ([]byte, error)
+	// FetchFile returns a file from repo ("" for this repository) at ref (""
+	// for the default branch), or an error wrapping ErrNotFound.
+	FetchFile(ctx context.Context, repo, path, ref string) ([]byte, error)
+
+	// UpsertChangeRequest opens a change request from branch into the base
+	// branch, or updates the title and body of the open one.
+	UpsertChangeRequest(ctx context.Context, branch, title, body string) (ChangeRequest, error)
+	// UpdateChangeRequestBody replaces a change request's description.
+	UpdateChangeRequestBody(ctx context.Context, number int, body string) error
+	// ChangeRequestLabels returns a change request's labels.
+	ChangeRequestLabels(ctx context.Context, number int) ([]string, error)
+	// EditChangeRequestLabels adds and removes labels, creating unknown ones.
+	EditChangeRequestLabels(ctx context.Context, number int, add, remove []string) error
+	// ChangeRequestStatus reads whether a change request is open, merged, or
+	// has outstanding change requests.
+	ChangeRequestStatus(ctx context.Context, number int) (*ChangeRequestStatus, error)
+	// MergeChangeRequest squash-merges a change request and deletes branch.
+	MergeChangeRequest(ctx context.Context, number int, branch string) error
+	// CloseChangeRequest closes a change request unmerged and deletes branch.
+	CloseChangeRequest(ctx context.Context, number int, branch string) error
+}
+
+// Provider names accepted by Config.Provider.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGitea  = "gitea"
+	ProviderGit    = "git"
+)
+
+const (
+	// maxCommitAttempts bounds retries when the branch moves between reading
+	// its head and updating it.
+	maxCommitAttempts = 5
+	commitRetryDelay  = 500 * time.Millisecond
+)
+
+// Config selects and configures a ResultPublisher.
+type Config struct {
+	// Provider is github (default), gitlab, gitea or git.
+	Provider string
+	// Repo is "owner/repo" (GitHub, Gitea) or the project path (GitLab).
+	Repo string
+	// BaseURL is the forge URL: required for Gitea, optional for GitLab
+	// (gitlab.com) and GitHub (GitHub Enterprise API URL).
+	BaseURL string
+	// Token authenticates API calls. Unused by the git provider.
+	Token string
+	// Branch is the base branch results are reviewed into.
+	Branch string
+	// Path is the results directory in the repository, e.g. "site/data".
+	Path string
+	// Git configures the git provider.
+	Git GitConfig
+}
+
+// New constructs the publisher selected by cfg.Provider.
+func New(cfg Config) (*Client, error) {
+	var (
+		forge ResultPublisher
+		err   error
+	)
+	switch cfg.Provider {
+	case "", ProviderGitHub:
+		forge, err = NewGitHub(cfg.Token, cfg.Repo, cfg.Branch, cfg.BaseURL)
+	case ProviderGitLab:
+		forge, err = NewGitLab(cfg.Token, cfg.Repo, cfg.Branch, cfg.BaseURL)
+	case ProviderGitea:
+		forge, err = NewGitea(cfg.Token, cfg.Repo, cfg.Branch, cfg.BaseURL)
+	case ProviderGit:
+		forge, err = NewGit(cfg.Branch, cfg.Git)
+	default:
+		err = fmt.Errorf("unknown git provider %q (want github, gitlab, gitea or git)", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(forge, cfg.Path), nil
+}
+
+// splitRepo splits "owner/repo".
+func splitRepo(repo string) (string, string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" {
+		return "", "", fmt.Errorf("invalid repository %q (expected owner/repo)", repo)
+	}
+	return owner, name, nil
+}
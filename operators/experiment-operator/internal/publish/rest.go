@@ -0,0 +1,92 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// restClient is the small JSON-over-HTTP client the GitLab and Gitea
+// publishers share.
+type restClient struct {
+	http    *http.Client
+	baseURL string // API root, without a trailing slash
+	// auth sets the credentials header on each request.
+	auth func(*http.Request)
+}
+
+// apiError is a non-2xx API response.
+type apiError struct {
+	Method, Path string
+	StatusCode   int
+	Body         string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// statusCode returns the HTTP status of an apiError, or 0.
+func statusCode(err error) int {
+	if e, ok := err.(*apiError); ok {
+		return e.StatusCode
+	}
+	return 0
+}
+
+func newRESTClient(baseURL string, auth func(*http.Request)) *restClient {
+	return &restClient{
+		http:    &http.Client{Timeout: 30 * time.Second},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		auth:    auth,
+	}
+}
+
+// do sends in as JSON (if non-nil) and decodes the response into out (if
+// non-nil). Non-2xx responses are returned as *apiError.
+func (c *restClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode %s %s: %w", method, path, err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.auth != nil {
+		c.auth(req)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &apiError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: string(msg)}
+	}
+	if out == nil {
+		return nil
+	}
+	if raw, ok := out.(*[]byte); ok {
+		*raw, err = io.ReadAll(resp.Body)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s: %w", method, path, err)
+	}
+	return nil
+}
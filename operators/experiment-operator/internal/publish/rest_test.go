@@ -0,0 +1,132 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// fakeREST serves canned JSON keyed by "METHOD path?query" and records
+// request bodies.
+type fakeREST struct {
+	mu        sync.Mutex
+	responses map[string]any
+	requests  map[string]map[string]any
+}
+
+func newFakeREST(t *testing.T, responses map[string]any) (*fakeREST, string) {
+	t.Helper()
+	f := &fakeREST{responses: responses, requests: map[string]map[string]any{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var body map[string]any
+		if b, _ := io.ReadAll(r.Body); len(b) > 0 {
+			_ = json.Unmarshal(b, &body)
+		}
+		f.requests[key] = body
+		resp, ok := f.responses[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+func TestGitLabChangeRequests(t *testing.T) {
+	mr := "/api/v4/projects/grp%2Fsub%2Fresults/merge_requests"
+	f, url := newFakeREST(t, map[string]any{
+		"GET " + mr + "?source_branch=experiment%2Fa&state=opened&target_branch=main": []any{},
+		"POST " + mr:       map[string]any{"iid": 7, "web_url": "https://gl/mr/7"},
+		"GET " + mr + "/7": map[string]any{"iid": 7, "state": "opened", "labels": []string{"tag:x"}},
+		"GET " + mr + "/7/reviewers": []any{
+			map[string]any{"user": map[string]any{"username": "bo"}, "state": "requested_changes"},
+			map[string]any{"user": map[string]any{"username": "ana"}, "state": "approved"},
+		},
+		"PUT " + mr + "/7": map[string]any{},
+	})
+	g, err := NewGitLab("tok", "grp/sub/results", "main", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	cr, err := g.UpsertChangeRequest(ctx, "experiment/a", "title", "body")
+	if err != nil || cr.Number != 7 || cr.URL != "https://gl/mr/7" {
+		t.Fatalf("UpsertChangeRequest = %+v, %v", cr, err)
+	}
+	if got := f.requests["POST "+mr]; got["source_branch"] != "experiment/a" || got["description"] != "body" {
+		t.Errorf("create request = %v", got)
+	}
+
+	status, err := g.ChangeRequestStatus(ctx, 7)
+	if err != nil || !status.Open || !slices.Equal(status.ChangesRequestedBy, []string{"bo"}) {
+		t.Errorf("ChangeRequestStatus = %+v, %v", status, err)
+	}
+
+	if err := g.EditChangeRequestLabels(ctx, 7, []string{"verdict:validated", "tag:y"}, []string{"tag:x"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.requests["PUT "+mr+"/7"]; got["add_labels"] != "verdict:validated,tag:y" || got["remove_labels"] != "tag:x" {
+		t.Errorf("label request = %v", got)
+	}
+}
+
+func TestGiteaChangeRequests(t *testing.T) {
+	repo := "/api/v1/repos/o/r"
+	f, url := newFakeREST(t, map[string]any{
+		"GET " + repo + "/pulls?state=open&limit=50&page=1": []any{
+			map[string]any{"number": 3, "html_url": "https://gt/pr/3", "head": map[string]any{"ref": "experiment/a"}, "base": map[string]any{"ref": "main"}},
+		},
+		"PATCH " + repo + "/pulls/3": map[string]any{},
+		"GET " + repo + "/pulls/3":   map[string]any{"number": 3, "state": "open"},
+		"GET " + repo + "/pulls/3/reviews?limit=50&page=1": []any{
+			map[string]any{"user": map[string]any{"login": "bo"}, "state": "REQUEST_CHANGES"},
+			map[string]any{"user": map[string]any{"login": "cy"}, "state": "REQUEST_CHANGES"},
+			map[string]any{"user": map[string]any{"login": "cy"}, "state": "REQUEST_CHANGES", "dismissed": true},
+		},
+		"GET " + repo + "/labels?limit=50&page=1": []any{map[string]any{"id": 1, "name": "experiment-results"}},
+		"POST " + repo + "/labels":                map[string]any{"id": 2, "name": "tag:tsdb"},
+		"POST " + repo + "/issues/3/labels":       []any{},
+	})
+	g, err := NewGitea("tok", "o/r", "main", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	cr, err := g.UpsertChangeRequest(ctx, "experiment/a", "title", "body")
+	if err != nil || cr.Number != 3 {
+		t.Fatalf("UpsertChangeRequest = %+v, %v", cr, err)
+	}
+	if got := f.requests["PATCH "+repo+"/pulls/3"]; got["title"] != "title" || got["body"] != "body" {
+		t.Errorf("update request = %v", got)
+	}
+
+	status, err := g.ChangeRequestStatus(ctx, 3)
+	if err != nil || !status.Open || !slices.Equal(status.ChangesRequestedBy, []string{"bo"}) {
+		t.Errorf("ChangeRequestStatus = %+v, %v", status, err)
+	}
+
+	if err := g.EditChangeRequestLabels(ctx, 3, []string{"experiment-results", "tag:tsdb"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.requests["POST "+repo+"/labels"]; got["name"] != "tag:tsdb" {
+		t.Errorf("create label request = %v", got)
+	}
+	if got := f.requests["POST "+repo+"/issues/3/labels"]; !slices.Equal(got["labels"].([]any), []any{1.0, 2.0}) {
+		t.Errorf("add labels request = %v", got)
+	}
+}
@@ -0,0 +1,180 @@
+package publish
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/illmadecoder/experiment-operator/internal/export"
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+// SiteIndexFile is the site index under the results path: one JSON object
+// per line, sorted by name. Line-per-entry keeps concurrent results PRs
+// mergeable since each only inserts or replaces its own line.
+const SiteIndexFile = "_index.jsonl"
+
+// SiteIndexEntry is one line of the site index.
+type SiteIndexEntry struct {
+	Name        string    `json:"name"`
+	Title       string    `json:"title,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Phase       string    `json:"phase"`
+	Verdict     string    `json:"verdict,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	// Files lists the experiment's published files relative to the results path.
+	Files []string `json:"files"`
+}
+
+// ResultFiles returns the files published for an experiment, relative to
+// the repository root:
+//
+//	{path}/{exp}.json                  summary read by the site
+//	{path}/{exp}/metrics/{query}.csv   per-query data points
+//	{path}/{exp}/architecture.md       analyzer diagram, rendered by GitHub
+func (c *Client) ResultFiles(expName string, summary *metrics.ExperimentSummary) ([]File, error) {
+	body, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal summary JSON: %w", err)
+	}
+	files := []File{{Path: c.path + "/" + expName + ".json", Content: body}}
+	dir := c.path + "/" + expName + "/"
+
+	if summary.Metrics != nil {
+		rows := export.Rows(expName, summary.Metrics, summaryTarget(summary))
+		queries := make([]string, 0, len(summary.Metrics.Queries))
+		for name := range summary.Metrics.Queries {
+			queries = append(queries, name)
+		}
+		sort.Strings(queries)
+		csvFiles := export.CSVFiles(queries)
+		for _, q := range queries {
+			csv, err := export.CSV(rows, q)
+			if err != nil {
+				return nil, fmt.Errorf("render %s CSV: %w", q, err)
+			}
+			name := strings.TrimPrefix(csvFiles[q], export.CSVDir)
+			files = append(files, File{Path: dir + "metrics/" + name, Content: csv})
+		}
+	}
+
+	if a := summary.Analysis; a != nil && a.ArchitectureDiagram != "" {
+		format := a.ArchitectureDiagramFormat
+		if format == "" {
+			format = "text"
+		}
+		md := fmt.Sprintf("# %s architecture\n\n```%s\n%s\n```\n", expName, format, strings.TrimRight(a.ArchitectureDiagram, "\n"))
+		files = append(files, File{Path: dir + "architecture.md", Content: []byte(md)})
+	}
+	return files, nil
+}
+
+// summaryTarget names the only non-hub target, or "" when there are several.
+func summaryTarget(s *metrics.ExperimentSummary) string {
+	name := ""
+	for _, t := range s.Targets {
+		if t.ClusterType == "hub" {
+			continue
+		}
+		if name != "" {
+			return ""
+		}
+		name = t.Name
+	}
+	return name
+}
+
+// resultsChange commits an experiment's result files together with its
+// line in the site index, re-reading the index at each parent commit. A
+// non-nil raw replaces the encoded summary, indented like ResultFiles does.
+func (c *Client) resultsChange(expName string, summary *metrics.ExperimentSummary, raw []byte) (ChangeFunc, error) {
+	files, err := c.ResultFiles(expName, summary)
+	if err != nil {
+		return nil, err
+	}
+	if raw != nil {
+		var body bytes.Buffer
+		if err := json.Indent(&body, raw, "", "  "); err != nil {
+			return nil, fmt.Errorf("indent summary JSON: %w", err)
+		}
+		files[0].Content = body.Bytes()
+	}
+	entry := SiteIndexEntry{
+		Name:        expName,
+		Title:       summary.Title,
+		Tags:        summary.Tags,
+		Phase:       summary.Phase,
+		CreatedAt:   summary.CreatedAt,
+		CompletedAt: summary.CompletedAt,
+	}
+	if summary.Hypothesis != nil {
+		entry.Verdict = summary.Hypothesis.MachineVerdict
+	}
+	if summary.Analysis != nil && summary.Analysis.HypothesisVerdict != "" {
+		entry.Verdict = summary.Analysis.HypothesisVerdict
+	}
+	for _, f := range files {
+		entry.Files = append(entry.Files, strings.TrimPrefix(f.Path, c.path+"/"))
+	}
+
+	return func(ctx context.Context, parent string) ([]File, error) {
+		indexPath := c.path + "/" + SiteIndexFile
+		existing, err := c.forge.ReadFile(ctx, indexPath, parent)
+		if err != nil {
+			return nil, err
+		}
+		index, err := upsertIndexLine(existing, entry)
+		if err != nil {
+			return nil, fmt.Errorf("update %s: %w", indexPath, err)
+		}
+		return append(files, File{Path: indexPath, Content: index}), nil
+	}, nil
+}
+
+// upsertIndexLine replaces or inserts entry's line in a JSON-lines index,
+// keeping lines sorted by name. Lines it cannot parse are kept as they are.
+func upsertIndexLine(existing []byte, entry SiteIndexEntry) ([]byte, error) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	type indexLine struct {
+		name string
+		raw  string
+	}
+	lines := []indexLine{{name: entry.Name, raw: string(line)}}
+	sc := bufio.NewScanner(bytes.NewReader(existing))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		raw := strings.TrimSpace(sc.Text())
+		if raw == "" {
+			continue
+		}
+		var e struct {
+			Name string `json:"name"`
+		}
+		_ = json.Unmarshal([]byte(raw), &e)
+		if e.Name == entry.Name {
+			continue
+		}
+		lines = append(lines, indexLine{name: e.Name, raw: raw})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].name < lines[j].name })
+
+	var buf bytes.Buffer
+	for _, l := range lines {
+		buf.WriteString(l.raw)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,187 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/illmadecoder/experiment-operator/internal/metrics"
+)
+
+func sampleSummary() *metrics.ExperimentSummary {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &metrics.ExperimentSummary{
+		SchemaVersion: metrics.SummarySchemaVersion,
+		Name:          "tsdb-b2c4d",
+		Title:         "TSDB comparison",
+		Phase:         "Complete",
+		Tags:          []string{"tsdb"},
+		CreatedAt:     t0,
+		CompletedAt:   t0.Add(time.Hour),
+		Targets:       []metrics.TargetSummary{{Name: "app", ClusterType: "gke"}},
+		Hypothesis:    &metrics.HypothesisContext{MachineVerdict: "validated"},
+		Metrics: &metrics.MetricsResult{Queries: map[string]metrics.QueryResult{
+			"cpu by pod": {Type: "instant", Data: []metrics.DataPoint{{Labels: map[string]string{"pod": "a"}, Timestamp: t0, Value: 0.5}}},
+		}},
+	}
+}
+
+func TestResultFiles(t *testing.T) {
+	c := NewClient(nil, "site/data")
+	s := sampleSummary()
+	s.Analysis = &metrics.AnalysisResult{ArchitectureDiagram: "graph TD\n  A-->B\n", ArchitectureDiagramFormat: "mermaid"}
+
+	files, err := c.ResultFiles(s.Name, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPath := map[string]string{}
+	for _, f := range files {
+		byPath[f.Path] = string(f.Content)
+	}
+	if !strings.Contains(byPath["site/data/tsdb-b2c4d.json"], `"name": "tsdb-b2c4d"`) {
+		t.Error("summary JSON missing")
+	}
+	if csv := byPath["site/data/tsdb-b2c4d/metrics/cpu_by_pod.csv"]; !strings.HasPrefix(csv, "timestamp,value,target,pod\n") || !strings.Contains(csv, ",app,a") {
+		t.Errorf("metric CSV = %q", csv)
+	}
+	if md := byPath["site/data/tsdb-b2c4d/architecture.md"]; !strings.Contains(md, "```mermaid\ngraph TD\n  A-->B\n```") {
+		t.Errorf("architecture.md = %q", md)
+	}
+}
+
+func TestUpsertIndexLine(t *testing.T) {
+	existing := []byte(`{"name":"a","phase":"Complete"}` + "\n" + `{"name":"c","phase":"Complete"}` + "\n")
+	out, err := upsertIndexLine(existing, SiteIndexEntry{Name: "b", Phase: "Failed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], `"name":"b"`) {
+		t.Fatalf("index = %s", out)
+	}
+	// Replacing keeps a single line per experiment.
+	out, _ = upsertIndexLine(out, SiteIndexEntry{Name: "b", Phase: "Complete"})
+	if strings.Count(string(out), `"name":"b"`) != 1 || !strings.Contains(string(out), `"name":"b","phase":"Complete"`) {
+		t.Errorf("index after replace = %s", out)
+	}
+}
+
+func TestPublishExperimentResultSingleCommit(t *testing.T) {
+	f, c := newFakeGitHub(t, map[string]string{"site/data/_index.jsonl": `{"name":"older-f5g6h","phase":"Complete"}` + "\n"})
+	ctx := context.Background()
+
+	branch, num, url, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", sampleSummary(), WithTitle("TSDB comparison"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if branch != "experiment/tsdb-b2c4d" || num != 1 || url == "" {
+		t.Errorf("publish = %s, %d, %s", branch, num, url)
+	}
+	if h := f.history(branch); len(h) != 2 || h[0] != "data: Add tsdb-b2c4d experiment results" {
+		t.Errorf("branch history = %v, want a single results commit", h)
+	}
+	files := f.files(branch)
+	for _, p := range []string{"site/data/tsdb-b2c4d.json", "site/data/tsdb-b2c4d/metrics/cpu_by_pod.csv"} {
+		if _, ok := files[p]; !ok {
+			t.Errorf("missing %s", p)
+		}
+	}
+	index := files["site/data/_index.jsonl"]
+	if !strings.HasPrefix(index, `{"name":"older-f5g6h"`) || !strings.Contains(index, `"verdict":"validated"`) {
+		t.Errorf("site index = %s", index)
+	}
+
+	// Updating the branch (e.g. restoring a summary) is one more commit.
+	s := sampleSummary()
+	s.Phase = "Failed"
+	if err := c.UpdateExperimentResult(ctx, branch, "tsdb-b2c4d", s); err != nil {
+		t.Fatal(err)
+	}
+	if h := f.history(branch); len(h) != 3 || h[0] != "data: Update tsdb-b2c4d experiment results" {
+		t.Errorf("branch history = %v", h)
+	}
+}
+
+func TestPublishExperimentResultReusesBranchAndPR(t *testing.T) {
+	f, c := newFakeGitHub(t, nil)
+	ctx := context.Background()
+
+	_, num, _, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", sampleSummary())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-publishing (e.g. after an operator restart) reuses the branch and PR.
+	s := sampleSummary()
+	s.Title = "TSDB comparison, rerun"
+	branch, again, _, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", s, WithTitle(s.Title))
+	if err != nil {
+		t.Fatalf("re-publish: %v", err)
+	}
+	if again != num || len(f.pulls) != 1 {
+		t.Errorf("re-publish PR = #%d (%d PRs), want #%d reused", again, len(f.pulls), num)
+	}
+	if f.pulls[0].Title != "data: TSDB comparison, rerun" {
+		t.Errorf("PR title = %q, want it updated", f.pulls[0].Title)
+	}
+	if h := f.history(branch); len(h) != 3 || h[0] != "data: Update tsdb-b2c4d experiment results" {
+		t.Errorf("branch history = %v", h)
+	}
+
+	// Publishing identical results adds no commit.
+	if _, _, _, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", s, WithTitle(s.Title)); err != nil {
+		t.Fatal(err)
+	}
+	if h := f.history(branch); len(h) != 3 {
+		t.Errorf("branch history = %v, want no new commit", h)
+	}
+
+	// Analyzer output is pushed onto the same PR as written, keeping the
+	// sections ExperimentSummary does not model.
+	s.Analysis = &metrics.AnalysisResult{HypothesisVerdict: "invalidated"}
+	var doc map[string]any
+	data, _ := json.Marshal(s)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	doc["analysis"].(map[string]any)["targetAnalysis"] = map[string]any{"overview": "per-target notes"}
+	if data, err = json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PushAnalysis(ctx, branch, "tsdb-b2c4d", data); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(f.files(branch)["site/data/tsdb-b2c4d.json"], "per-target notes") {
+		t.Error("analyzer section targetAnalysis dropped from the committed summary")
+	}
+	if h := f.history(branch); len(h) != 4 || h[0] != "data: Update tsdb-b2c4d with AI analysis" {
+		t.Errorf("branch history = %v", h)
+	}
+	if !strings.Contains(f.files(branch)["site/data/_index.jsonl"], `"verdict":"invalidated"`) {
+		t.Error("site index not updated with analyzer verdict")
+	}
+}
+
+func TestPublishExperimentResultFastForwardsStaleBranch(t *testing.T) {
+	f, c := newFakeGitHub(t, nil)
+	ctx := context.Background()
+
+	// A branch left behind by an interrupted publish, with main moved on since.
+	f.refs["experiment/tsdb-b2c4d"] = f.refs["main"]
+	f.refs["main"] = f.putCommit(fakeCommit{tree: f.putTree(map[string]string{"README.md": "x"}), parent: f.refs["main"], message: "unrelated"})
+
+	branch, num, _, err := c.PublishExperimentResult(ctx, "tsdb-b2c4d", sampleSummary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num != 1 {
+		t.Errorf("PR = #%d", num)
+	}
+	h := f.history(branch)
+	if len(h) != 3 || h[1] != "unrelated" {
+		t.Errorf("branch history = %v, want results on top of the current main", h)
+	}
+}
@@ -0,0 +1,81 @@
+package publish
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v68/github"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// WebhookPath is where WebhookServer receives GitHub deliveries.
+const WebhookPath = "/github/webhook"
+
+// WebhookServer receives GitHub pull_request and pull_request_review events
+// for results PRs so the review gate reacts to merges, closes and review
+// submissions without waiting for its next poll. Deliveries are verified
+// against the shared secret (X-Hub-Signature-256).
+//
+// It implements manager.Runnable and runs on the leader only, where the
+// controller consuming Notify runs.
+type WebhookServer struct {
+	// Addr is the listen address, e.g. ":8083".
+	Addr   string
+	Secret []byte
+	// Notify is called with the number of a results PR that changed.
+	Notify func(ctx context.Context, prNumber int)
+}
+
+// Start serves until ctx is cancelled.
+func (s *WebhookServer) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("github-webhook")
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler(ctx),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	logger.Info("Receiving GitHub webhooks", "addr", s.Addr, "path", WebhookPath)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// Handler returns the webhook's HTTP handler. Notify runs with ctx.
+func (s *WebhookServer) Handler(ctx context.Context) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+WebhookPath, func(w http.ResponseWriter, r *http.Request) {
+		payload, err := gh.ValidatePayload(r, s.Secret)
+		if err != nil {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		event, err := gh.ParseWebHook(gh.WebHookType(r), payload)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var pr *gh.PullRequest
+		switch e := event.(type) {
+		case *gh.PullRequestEvent:
+			pr = e.GetPullRequest()
+		case *gh.PullRequestReviewEvent:
+			pr = e.GetPullRequest()
+		}
+		// Ignore other events (ping included) and PRs not opened by the operator.
+		if pr != nil && strings.HasPrefix(pr.GetHead().GetRef(), BranchName("")) {
+			s.Notify(ctx, pr.GetNumber())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
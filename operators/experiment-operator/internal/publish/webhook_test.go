@@ -0,0 +1,58 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestWebhookServer(t *testing.T) {
+	secret := []byte("s3cret")
+	var notified []int
+	srv := httptest.NewServer((&WebhookServer{
+		Secret: secret,
+		Notify: func(_ context.Context, pr int) { notified = append(notified, pr) },
+	}).Handler(context.Background()))
+	defer srv.Close()
+
+	deliver := func(event, body string, key []byte) int {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+WebhookPath, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", event)
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(body))
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := deliver("pull_request", `{"action":"closed","pull_request":{"number":7,"head":{"ref":"experiment/tsdb-b2c4d"}}}`, secret); code != http.StatusNoContent {
+		t.Errorf("pull_request = %d", code)
+	}
+	if code := deliver("pull_request_review", `{"action":"submitted","pull_request":{"number":8,"head":{"ref":"experiment/logs-f5g6h"}}}`, secret); code != http.StatusNoContent {
+		t.Errorf("pull_request_review = %d", code)
+	}
+	if code := deliver("pull_request", `{"action":"closed","pull_request":{"number":9,"head":{"ref":"feature/x"}}}`, secret); code != http.StatusNoContent {
+		t.Errorf("unrelated PR = %d", code)
+	}
+	if code := deliver("ping", `{"zen":"hi"}`, secret); code != http.StatusNoContent {
+		t.Errorf("ping = %d", code)
+	}
+	if code := deliver("pull_request", `{"action":"closed","pull_request":{"number":10,"head":{"ref":"experiment/x"}}}`, []byte("wrong")); code != http.StatusUnauthorized {
+		t.Errorf("bad signature = %d, want 401", code)
+	}
+	if !slices.Equal(notified, []int{7, 8}) {
+		t.Errorf("notified = %v, want [7 8]", notified)
+	}
+}
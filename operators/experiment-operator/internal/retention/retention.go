@@ -0,0 +1,431 @@
+// Package retention applies a lifecycle policy to stored experiment results
+// and terminal Experiment CRs so the hub and the results bucket don't grow
+// without bound.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/catalog"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+const (
+	// ArchivePrefix is where archived results are moved. Underscore-prefixed
+	// top-level keys cannot collide with Experiment names.
+	ArchivePrefix = catalog.ArchivePrefix
+	// ReportKey is where the latest retention report is written.
+	ReportKey = "_retention/report.json"
+)
+
+// Policy configures retention. Zero values disable the corresponding rule.
+type Policy struct {
+	// KeepLast keeps the newest N result sets per experiment family; older
+	// ones are archived if published and deleted otherwise.
+	KeepLast int
+	// UnpublishedMaxAge deletes unpublished results older than this.
+	UnpublishedMaxAge time.Duration
+	// ArchivePublishedAfter moves published results under ArchivePrefix once
+	// older than this.
+	ArchivePublishedAfter time.Duration
+	// PruneCRsAfter deletes terminal Experiment CRs completed longer ago than
+	// this, once their results are stored and no analysis or review is pending.
+	PruneCRsAfter time.Duration
+	// DryRun computes and reports actions without applying them.
+	DryRun bool
+	// Interval between retention passes (default 6h).
+	Interval time.Duration
+}
+
+// ActionKind is what retention does to a result set or CR.
+type ActionKind string
+
+const (
+	ActionDelete  ActionKind = "delete"
+	ActionArchive ActionKind = "archive"
+	ActionPruneCR ActionKind = "pruneCR"
+)
+
+// Action is a single planned retention step.
+type Action struct {
+	Kind       ActionKind `json:"kind"`
+	Experiment string     `json:"experiment"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Family     string     `json:"family"`
+	Reason     string     `json:"reason"`
+	Objects    int        `json:"objects,omitempty"`
+	Bytes      int64      `json:"bytes,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Report is the outcome of a retention pass.
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	DryRun      bool      `json:"dryRun"`
+	ResultSets  int       `json:"resultSets"`
+	Experiments int       `json:"experiments"`
+	Actions     []Action  `json:"actions"`
+}
+
+// ResultSet is everything stored under one experiment's prefix.
+type ResultSet struct {
+	Experiment   string
+	Objects      []storage.ObjectInfo
+	LastModified time.Time
+	// Promoted is true once a final run was promoted ({exp}/summary.json exists).
+	Promoted bool
+	// Published is true if the results were published to the site.
+	Published bool
+}
+
+// Size returns the total bytes of the result set.
+func (rs ResultSet) Size() int64 {
+	var n int64
+	for _, o := range rs.Objects {
+		n += o.Size
+	}
+	return n
+}
+
+// generatedSuffix matches the 5-character suffix Kubernetes appends for
+// generateName.
+var generatedSuffix = regexp.MustCompile(`-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+
+// Family returns the experiment family of a name: the generateName prefix
+// without its trailing dash, or the name itself if it wasn't generated.
+func Family(name string) string {
+	if loc := generatedSuffix.FindStringIndex(name); loc != nil && loc[0] > 0 {
+		return name[:loc[0]]
+	}
+	return name
+}
+
+// familyOf prefers the CR's recorded generateName over parsing the name.
+func familyOf(name string, exp *experimentsv1alpha1.Experiment) string {
+	if exp != nil && exp.GenerateName != "" {
+		return strings.TrimSuffix(exp.GenerateName, "-")
+	}
+	return Family(name)
+}
+
+// GroupObjects groups a bucket listing into result sets by top-level prefix,
+// skipping reserved (underscore-prefixed) prefixes.
+func GroupObjects(objects []storage.ObjectInfo) map[string]*ResultSet {
+	sets := make(map[string]*ResultSet)
+	for _, o := range objects {
+		name, rest, ok := strings.Cut(o.Key, "/")
+		if !ok || name == "" || strings.HasPrefix(name, "_") {
+			continue
+		}
+		rs := sets[name]
+		if rs == nil {
+			rs = &ResultSet{Experiment: name}
+			sets[name] = rs
+		}
+		rs.Objects = append(rs.Objects, o)
+		if o.LastModified.After(rs.LastModified) {
+			rs.LastModified = o.LastModified
+		}
+		if rest == "summary.json" {
+			rs.Promoted = true
+		}
+	}
+	return sets
+}
+
+// isTerminal reports whether an experiment has finished running.
+func isTerminal(exp *experimentsv1alpha1.Experiment) bool {
+	return exp.Status.Phase == experimentsv1alpha1.PhaseComplete ||
+		exp.Status.Phase == experimentsv1alpha1.PhaseFailed
+}
+
+// isBusy reports whether an experiment still needs its results: it is running,
+// or analysis is queued or in progress, or review is.
+func isBusy(exp *experimentsv1alpha1.Experiment) bool {
+	if !isTerminal(exp) {
+		return true
+	}
+	switch exp.Status.AnalysisPhase {
+	case experimentsv1alpha1.AnalysisPhaseQueued, experimentsv1alpha1.AnalysisPhasePending,
+		experimentsv1alpha1.AnalysisPhaseRunning:
+		return true
+	}
+	switch exp.Status.ReviewPhase {
+	case experimentsv1alpha1.ReviewPhasePending, experimentsv1alpha1.ReviewPhaseChangesRequested:
+		return true
+	}
+	return false
+}
+
+// Plan computes retention actions. Result sets whose Experiment is still busy
+// are never touched. Within a family, sets are ranked newest first; a set is
+// removed if it falls outside KeepLast or exceeds its age limit. Removal means
+// archiving for published sets and deletion otherwise. CR pruning is planned
+// independently and only for CRs whose results were promoted and are not
+// being deleted in the same pass.
+func Plan(sets map[string]*ResultSet, exps []experimentsv1alpha1.Experiment, p Policy, now time.Time) []Action {
+	byName := make(map[string]*experimentsv1alpha1.Experiment, len(exps))
+	for i := range exps {
+		byName[exps[i].Name] = &exps[i]
+	}
+
+	families := make(map[string][]*ResultSet)
+	for name, rs := range sets {
+		exp := byName[name]
+		if exp != nil && exp.Status.Published {
+			rs.Published = true
+		}
+		families[familyOf(name, exp)] = append(families[familyOf(name, exp)], rs)
+	}
+
+	var actions []Action
+	removed := make(map[string]bool)
+	for family, members := range families {
+		sort.Slice(members, func(i, j int) bool {
+			return members[i].LastModified.After(members[j].LastModified)
+		})
+		for rank, rs := range members {
+			exp := byName[rs.Experiment]
+			if exp != nil && isBusy(exp) {
+				continue
+			}
+			age := now.Sub(rs.LastModified)
+
+			var reason string
+			switch {
+			case p.KeepLast > 0 && rank >= p.KeepLast:
+				reason = fmt.Sprintf("beyond newest %d of family", p.KeepLast)
+			case !rs.Published && p.UnpublishedMaxAge > 0 && age > p.UnpublishedMaxAge:
+				reason = fmt.Sprintf("unpublished and older than %s", p.UnpublishedMaxAge)
+			case rs.Published && p.ArchivePublishedAfter > 0 && age > p.ArchivePublishedAfter:
+				reason = fmt.Sprintf("published and older than %s", p.ArchivePublishedAfter)
+			default:
+				continue
+			}
+
+			kind := ActionDelete
+			if rs.Published {
+				kind = ActionArchive
+			}
+			actions = append(actions, Action{
+				Kind:       kind,
+				Experiment: rs.Experiment,
+				Family:     family,
+				Reason:     reason,
+				Objects:    len(rs.Objects),
+				Bytes:      rs.Size(),
+			})
+			removed[rs.Experiment] = true
+		}
+	}
+
+	if p.PruneCRsAfter > 0 {
+		for i := range exps {
+			exp := &exps[i]
+			if isBusy(exp) || !exp.Status.ResourcesCleaned || exp.Status.CompletedAt == nil {
+				continue
+			}
+			if now.Sub(exp.Status.CompletedAt.Time) <= p.PruneCRsAfter {
+				continue
+			}
+			rs := sets[exp.Name]
+			if rs == nil || !rs.Promoted || removed[exp.Name] && !rs.Published {
+				// Results not safely stored (or about to be deleted): keep the
+				// CR as the only record.
+				continue
+			}
+			actions = append(actions, Action{
+				Kind:       ActionPruneCR,
+				Experiment: exp.Name,
+				Namespace:  exp.Namespace,
+				Family:     familyOf(exp.Name, exp),
+				Reason:     fmt.Sprintf("terminal for more than %s with results stored", p.PruneCRsAfter),
+			})
+		}
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		if actions[i].Family != actions[j].Family {
+			return actions[i].Family < actions[j].Family
+		}
+		if actions[i].Experiment != actions[j].Experiment {
+			return actions[i].Experiment < actions[j].Experiment
+		}
+		return actions[i].Kind < actions[j].Kind
+	})
+	return actions
+}
+
+// Runner applies a Policy periodically. It implements manager.Runnable and
+// runs only on the leader.
+type Runner struct {
+	Client client.Client
+	Store  storage.ResultsStore
+	Policy Policy
+	// Catalog, if set, has deleted results removed from the index and
+	// archived ones marked Archived.
+	Catalog *catalog.Catalog
+}
+
+// NeedLeaderElection ensures only one replica deletes results.
+func (r *Runner) NeedLeaderElection() bool { return true }
+
+// Start runs a retention pass immediately and then every Policy.Interval
+// until ctx is cancelled.
+func (r *Runner) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("retention")
+	interval := r.Policy.Interval
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := r.RunOnce(ctx)
+		if err != nil {
+			logger.Error(err, "Retention pass failed")
+		} else {
+			logger.Info("Retention pass complete", "dryRun", report.DryRun,
+				"resultSets", report.ResultSets, "experiments", report.Experiments, "actions", len(report.Actions))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce plans and (unless DryRun) applies retention, then writes the report
+// to ReportKey. Per-action failures are recorded in the report, not returned.
+func (r *Runner) RunOnce(ctx context.Context) (*Report, error) {
+	logger := log.FromContext(ctx).WithName("retention")
+	now := time.Now()
+
+	objects, err := r.Store.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("list results: %w", err)
+	}
+	sets := GroupObjects(objects)
+	for name, rs := range sets {
+		if !rs.Promoted {
+			continue
+		}
+		if m, err := storage.LoadManifest(ctx, r.Store, name); err == nil && m.Published {
+			rs.Published = true
+		}
+	}
+
+	var list experimentsv1alpha1.ExperimentList
+	if err := r.Client.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("list experiments: %w", err)
+	}
+
+	report := &Report{
+		GeneratedAt: now.UTC(),
+		DryRun:      r.Policy.DryRun,
+		ResultSets:  len(sets),
+		Experiments: len(list.Items),
+		Actions:     Plan(sets, list.Items, r.Policy, now),
+	}
+
+	for i := range report.Actions {
+		a := &report.Actions[i]
+		logger.Info("Retention action", "kind", a.Kind, "experiment", a.Experiment,
+			"reason", a.Reason, "dryRun", r.Policy.DryRun)
+		if r.Policy.DryRun {
+			continue
+		}
+		var err error
+		switch a.Kind {
+		case ActionDelete:
+			err = r.deleteSet(ctx, sets[a.Experiment])
+		case ActionArchive:
+			err = r.archiveSet(ctx, sets[a.Experiment])
+		case ActionPruneCR:
+			exp := &experimentsv1alpha1.Experiment{}
+			exp.Name, exp.Namespace = a.Experiment, a.Namespace
+			err = client.IgnoreNotFound(r.Client.Delete(ctx, exp))
+		}
+		if err != nil {
+			a.Error = err.Error()
+			logger.Error(err, "Retention action failed", "kind", a.Kind, "experiment", a.Experiment)
+			continue
+		}
+		if err := r.updateCatalog(ctx, a); err != nil {
+			logger.Error(err, "Failed to update results index — non-fatal", "experiment", a.Experiment)
+		}
+	}
+
+	if err := storage.PutJSON(ctx, r.Store, ReportKey, report); err != nil {
+		logger.Error(err, "Failed to store retention report — non-fatal")
+	}
+	return report, nil
+}
+
+// deleteSet removes every object of a result set. The manifest goes last so
+// an interrupted delete is retried on the next pass.
+func (r *Runner) deleteSet(ctx context.Context, rs *ResultSet) error {
+	manifest := storage.ManifestKey(rs.Experiment)
+	for _, o := range rs.Objects {
+		if o.Key == manifest {
+			continue
+		}
+		if err := r.Store.Delete(ctx, o.Key); err != nil {
+			return err
+		}
+	}
+	return r.Store.Delete(ctx, manifest)
+}
+
+// archiveSet copies a result set under ArchivePrefix and then deletes it.
+func (r *Runner) archiveSet(ctx context.Context, rs *ResultSet) error {
+	for _, o := range rs.Objects {
+		rc, err := r.Store.Get(ctx, o.Key)
+		if err != nil {
+			return err
+		}
+		err = r.Store.Put(ctx, ArchivePrefix+o.Key, rc, contentType(o.Key))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return r.deleteSet(ctx, rs)
+}
+
+// updateCatalog reflects an applied action in the results index.
+func (r *Runner) updateCatalog(ctx context.Context, a *Action) error {
+	if r.Catalog == nil {
+		return nil
+	}
+	switch a.Kind {
+	case ActionDelete:
+		return r.Catalog.Remove(ctx, a.Experiment)
+	case ActionArchive:
+		url := r.Store.URL(ArchivePrefix + a.Experiment + "/")
+		return r.Catalog.Update(ctx, a.Experiment, func(e *catalog.Entry) {
+			e.Archived = true
+			e.ResultsURL = url
+		})
+	}
+	return nil
+}
+
+// contentType guesses an object's content type from its key.
+func contentType(key string) string {
+	if strings.HasSuffix(key, ".json") {
+		return "application/json"
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,248 @@
+package retention
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+	"github.com/illmadecoder/experiment-operator/internal/catalog"
+	"github.com/illmadecoder/experiment-operator/internal/storage"
+)
+
+var now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+func resultSet(name string, modified time.Time, published bool) *ResultSet {
+	return &ResultSet{
+		Experiment:   name,
+		Objects:      []storage.ObjectInfo{{Key: name + "/summary.json", Size: 10, LastModified: modified}},
+		LastModified: modified,
+		Promoted:     true,
+		Published:    published,
+	}
+}
+
+func terminalExp(name string, completed time.Time) experimentsv1alpha1.Experiment {
+	exp := experimentsv1alpha1.Experiment{}
+	exp.Name, exp.Namespace = name, "experiments"
+	exp.Status.Phase = experimentsv1alpha1.PhaseComplete
+	exp.Status.ResourcesCleaned = true
+	exp.Status.CompletedAt = &metav1.Time{Time: completed}
+	return exp
+}
+
+func kinds(actions []Action) map[string]ActionKind {
+	out := make(map[string]ActionKind)
+	for _, a := range actions {
+		out[a.Experiment+"/"+string(a.Kind)] = a.Kind
+	}
+	return out
+}
+
+func TestFamily(t *testing.T) {
+	tests := map[string]string{
+		"tsdb-comparison-x7k2p": "tsdb-comparison",
+		"db-fvm-bq5zt":          "db-fvm",
+		"hand-named":            "hand-named",
+		"short-abcde":           "short-abcde", // vowels never appear in generated suffixes
+	}
+	for name, want := range tests {
+		if got := Family(name); got != want {
+			t.Errorf("Family(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPlanKeepLast(t *testing.T) {
+	sets := map[string]*ResultSet{
+		"tsdb-b2c4d": resultSet("tsdb-b2c4d", daysAgo(1), false),
+		"tsdb-f5g6h": resultSet("tsdb-f5g6h", daysAgo(2), false),
+		"tsdb-j7k8l": resultSet("tsdb-j7k8l", daysAgo(3), true),
+		"tsdb-m9n2p": resultSet("tsdb-m9n2p", daysAgo(4), false),
+		"logs-q2r4s": resultSet("logs-q2r4s", daysAgo(9), false),
+	}
+	got := kinds(Plan(sets, nil, Policy{KeepLast: 2}, now))
+
+	want := map[string]ActionKind{
+		"tsdb-j7k8l/archive": ActionArchive,
+		"tsdb-m9n2p/delete":  ActionDelete,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	for k := range want {
+		if _, ok := got[k]; !ok {
+			t.Errorf("missing action %s (got %v)", k, got)
+		}
+	}
+}
+
+func TestPlanAgeRules(t *testing.T) {
+	sets := map[string]*ResultSet{
+		"a-b2c4d": resultSet("a-b2c4d", daysAgo(40), false),
+		"b-b2c4d": resultSet("b-b2c4d", daysAgo(10), false),
+		"c-b2c4d": resultSet("c-b2c4d", daysAgo(100), true),
+		"d-b2c4d": resultSet("d-b2c4d", daysAgo(40), true),
+	}
+	p := Policy{UnpublishedMaxAge: 30 * 24 * time.Hour, ArchivePublishedAfter: 90 * 24 * time.Hour}
+	got := kinds(Plan(sets, nil, p, now))
+
+	if len(got) != 2 || got["a-b2c4d/delete"] != ActionDelete || got["c-b2c4d/archive"] != ActionArchive {
+		t.Errorf("actions = %v, want a deleted and c archived", got)
+	}
+}
+
+func TestPlanSkipsBusyExperiments(t *testing.T) {
+	running := experimentsv1alpha1.Experiment{}
+	running.Name = "a-b2c4d"
+	running.Status.Phase = experimentsv1alpha1.PhaseRunning
+
+	analyzing := terminalExp("b-b2c4d", daysAgo(60))
+	analyzing.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseRunning
+
+	queued := terminalExp("c-b2c4d", daysAgo(60))
+	queued.Status.AnalysisPhase = experimentsv1alpha1.AnalysisPhaseQueued
+
+	sets := map[string]*ResultSet{
+		"a-b2c4d": resultSet("a-b2c4d", daysAgo(60), false),
+		"b-b2c4d": resultSet("b-b2c4d", daysAgo(60), false),
+		"c-b2c4d": resultSet("c-b2c4d", daysAgo(60), false),
+	}
+	p := Policy{UnpublishedMaxAge: 24 * time.Hour, PruneCRsAfter: 24 * time.Hour}
+	if got := Plan(sets, []experimentsv1alpha1.Experiment{running, analyzing, queued}, p, now); len(got) != 0 {
+		t.Errorf("actions = %+v, want none for busy experiments", got)
+	}
+}
+
+func TestPlanPruneCRs(t *testing.T) {
+	stored := terminalExp("a-b2c4d", daysAgo(10))
+	recent := terminalExp("b-b2c4d", daysAgo(1))
+	noResults := terminalExp("c-b2c4d", daysAgo(10))
+	unpromoted := terminalExp("d-b2c4d", daysAgo(10))
+
+	sets := map[string]*ResultSet{
+		"a-b2c4d": resultSet("a-b2c4d", daysAgo(10), false),
+		"b-b2c4d": resultSet("b-b2c4d", daysAgo(1), false),
+		"d-b2c4d": {Experiment: "d-b2c4d", LastModified: daysAgo(10)},
+	}
+	exps := []experimentsv1alpha1.Experiment{stored, recent, noResults, unpromoted}
+	got := Plan(sets, exps, Policy{PruneCRsAfter: 7 * 24 * time.Hour}, now)
+
+	if len(got) != 1 || got[0].Kind != ActionPruneCR || got[0].Experiment != "a-b2c4d" || got[0].Namespace != "experiments" {
+		t.Errorf("actions = %+v, want only a-b2c4d pruned", got)
+	}
+
+	// Results about to be deleted are not "safely stored": keep the CR.
+	got = Plan(sets, exps, Policy{PruneCRsAfter: 7 * 24 * time.Hour, UnpublishedMaxAge: 5 * 24 * time.Hour}, now)
+	for _, a := range got {
+		if a.Kind == ActionPruneCR {
+			t.Errorf("pruned CR %s whose results are being deleted", a.Experiment)
+		}
+	}
+}
+
+func TestRunOnce(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(key string, age time.Duration) {
+		if err := store.Put(ctx, key, strings.NewReader("{}"), "application/json"); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(filepath.Join(dir, key), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := 60 * 24 * time.Hour
+	write("old-b2c4d/summary.json", old)
+	write("old-b2c4d/runs/0/summary.json", old)
+	write("pub-b2c4d/summary.json", old)
+	write("new-b2c4d/summary.json", time.Hour)
+
+	scheme := runtime.NewScheme()
+	if err := experimentsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pub := terminalExp("pub-b2c4d", time.Now().Add(-old))
+	pub.Status.Published = true
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&pub).Build()
+
+	policy := Policy{
+		UnpublishedMaxAge:     30 * 24 * time.Hour,
+		ArchivePublishedAfter: 30 * 24 * time.Hour,
+		PruneCRsAfter:         7 * 24 * time.Hour,
+		DryRun:                true,
+	}
+	cat := catalog.New(store)
+	for _, name := range []string{"old-b2c4d", "pub-b2c4d", "new-b2c4d"} {
+		if err := cat.Upsert(ctx, catalog.Entry{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &Runner{Client: c, Store: store, Policy: policy, Catalog: cat}
+
+	report, err := r.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce (dry run): %v", err)
+	}
+	if len(report.Actions) != 3 {
+		t.Fatalf("dry-run actions = %+v, want delete old, archive pub, prune pub", report.Actions)
+	}
+	if _, err := store.Get(ctx, "old-b2c4d/summary.json"); err != nil {
+		t.Errorf("dry run deleted results: %v", err)
+	}
+	var stored Report
+	if err := storage.GetJSON(ctx, store, ReportKey, &stored); err != nil || !stored.DryRun {
+		t.Errorf("stored report = %+v (%v), want dry-run report", stored, err)
+	}
+
+	r.Policy.DryRun = false
+	if _, err := r.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	objs, err := store.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, o := range objs {
+		keys = append(keys, o.Key)
+	}
+	want := []string{"_archive/pub-b2c4d/summary.json", catalog.IndexKey, ReportKey, "new-b2c4d/summary.json"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("objects after retention = %v, want %v", keys, want)
+	}
+
+	idx, err := cat.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Get("old-b2c4d") != nil {
+		t.Error("deleted results still indexed")
+	}
+	if e := idx.Get("pub-b2c4d"); e == nil || !e.Archived || !strings.Contains(e.ResultsURL, ArchivePrefix) {
+		t.Errorf("archived entry = %+v, want Archived with archive URL", e)
+	}
+
+	var list experimentsv1alpha1.ExperimentList
+	if err := c.List(ctx, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("experiments after retention = %d, want published CR pruned", len(list.Items))
+	}
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcsDefaultEndpoint = "https://storage.googleapis.com"
+	gcsScope           = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+// GCSStore is a ResultsStore backed by a Google Cloud Storage bucket, using
+// the JSON API with Application Default Credentials (Workload Identity on
+// GKE, GOOGLE_APPLICATION_CREDENTIALS elsewhere).
+type GCSStore struct {
+	client   *http.Client
+	endpoint string
+	bucket   string
+}
+
+// NewGCSStore creates a GCS store for bucket. A non-empty endpoint (e.g. a
+// fake-gcs-server emulator) is used without authentication.
+func NewGCSStore(ctx context.Context, bucket, endpoint string) (*GCSStore, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("gcs: bucket is required")
+	}
+	if endpoint != "" {
+		return newGCSStore(http.DefaultClient, endpoint, bucket), nil
+	}
+	client, err := google.DefaultClient(ctx, gcsScope)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: default credentials: %w", err)
+	}
+	return newGCSStore(client, gcsDefaultEndpoint, bucket), nil
+}
+
+func newGCSStore(client *http.Client, endpoint, bucket string) *GCSStore {
+	return &GCSStore{client: client, endpoint: strings.TrimSuffix(endpoint, "/"), bucket: bucket}
+}
+
+// objectURL returns the metadata URL of an object.
+func (s *GCSStore) objectURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", s.endpoint, url.PathEscape(s.bucket), url.PathEscape(key))
+}
+
+// do sends req and returns the response, turning non-2xx statuses into errors.
+// A 404 is returned as ErrNotFound.
+func (s *GCSStore) do(req *http.Request, op, key string) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", op, key, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s %s: %w", op, key, ErrNotFound)
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, fmt.Errorf("%s %s: HTTP %d: %s", op, key, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// Put streams body to key with a simple media upload.
+func (s *GCSStore) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		s.endpoint, url.PathEscape(s.bucket), url.QueryEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return fmt.Errorf("put object %s: %w", key, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := s.do(req, "put object", key)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get opens the object's content.
+func (s *GCSStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key)+"?alt=media", nil)
+	if err != nil {
+		return nil, fmt.Errorf("get object %s: %w", key, err)
+	}
+	resp, err := s.do(req, "get object", key)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// gcsListResponse is a page of the objects.list response.
+type gcsListResponse struct {
+	Items []struct {
+		Name    string    `json:"name"`
+		Size    string    `json:"size"`
+		Updated time.Time `json:"updated"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// List pages through objects.list for prefix.
+func (s *GCSStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var out []ObjectInfo
+	pageToken := ""
+	for {
+		q := url.Values{"prefix": {prefix}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		u := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucket), q.Encode())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, fmt.Errorf("list objects %s: %w", prefix, err)
+		}
+		resp, err := s.do(req, "list objects", prefix)
+		if err != nil {
+			return nil, err
+		}
+		var page gcsListResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("list objects %s: decode: %w", prefix, err)
+		}
+		for _, item := range page.Items {
+			size, _ := strconv.ParseInt(item.Size, 10, 64)
+			out = append(out, ObjectInfo{Key: item.Name, Size: size, LastModified: item.Updated})
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// Delete removes the object at key.
+func (s *GCSStore) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("delete object %s: %w", key, err)
+	}
+	resp, err := s.do(req, "delete object", key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// URL returns gs://bucket/key.
+func (s *GCSStore) URL(key string) string {
+	return fmt.Sprintf("gs://%s/%s", s.bucket, key)
+}
@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+// ManifestSchemaVersion is the layout version of index.json.
+const ManifestSchemaVersion = 1
+
+// ErrRunExists is returned by WriteRun when the iteration was already stored.
+// Run artifacts are immutable; the first write wins.
+var ErrRunExists = errors.New("run already stored")
+
+// Run outcomes recorded in the manifest.
+const (
+	RunOutcomeRecollecting = "recollecting"
+	RunOutcomePassed       = "passed"
+	RunOutcomeExhausted    = "exhausted"
+	RunOutcomeUngated      = "ungated"
+)
+
+// RunManifest is {exp}/index.json: every collection attempt of an experiment
+// and the one promoted to the top-level results.
+type RunManifest struct {
+	SchemaVersion int        `json:"schemaVersion"`
+	Experiment    string     `json:"experiment"`
+	Runs          []RunEntry `json:"runs"`
+	// FinalIteration is the run whose artifacts were copied to {exp}/.
+	FinalIteration *int       `json:"finalIteration,omitempty"`
+	PromotedAt     *time.Time `json:"promotedAt,omitempty"`
+	// Published records that the promoted results were published to the
+	// site, so retention archives rather than deletes them.
+	Published    bool      `json:"published,omitempty"`
+	PublishedURL string    `json:"publishedURL,omitempty"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// RunEntry records one collection attempt stored under {exp}/runs/{iteration}/.
+type RunEntry struct {
+	Iteration   int       `json:"iteration"`
+	CollectedAt time.Time `json:"collectedAt"`
+	// Outcome is recollecting, passed, exhausted or ungated (no quality gate).
+	Outcome   string                             `json:"outcome"`
+	Quality   *experimentsv1alpha1.QualityResult `json:"quality,omitempty"`
+	Artifacts []Artifact                         `json:"artifacts"`
+}
+
+// Artifact is a stored object belonging to a run.
+type Artifact struct {
+	Name        string `json:"name"`
+	Key         string `json:"key"`
+	SHA256      string `json:"sha256"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"contentType"`
+}
+
+// Raw is a WriteRun item stored as-is rather than marshaled to JSON.
+type Raw struct {
+	Body        []byte
+	ContentType string
+}
+
+// ManifestKey returns the key of an experiment's run manifest.
+func ManifestKey(exp string) string {
+	return exp + "/index.json"
+}
+
+// RunPrefix returns the key prefix of an iteration's artifacts.
+func RunPrefix(exp string, iteration int) string {
+	return fmt.Sprintf("%s/runs/%d/", exp, iteration)
+}
+
+// LoadManifest reads an experiment's manifest, returning an empty one if none
+// has been written yet.
+func LoadManifest(ctx context.Context, s ResultsStore, exp string) (*RunManifest, error) {
+	m := &RunManifest{SchemaVersion: ManifestSchemaVersion, Experiment: exp}
+	err := GetJSON(ctx, s, ManifestKey(exp), m)
+	if errors.Is(err, ErrNotFound) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Run returns the manifest entry for iteration, or nil.
+func (m *RunManifest) Run(iteration int) *RunEntry {
+	for i := range m.Runs {
+		if m.Runs[i].Iteration == iteration {
+			return &m.Runs[i]
+		}
+	}
+	return nil
+}
+
+// WriteRun stores each item of data under {exp}/runs/{entry.Iteration}/, as
+// JSON unless it is a Raw, and records the run, with artifact checksums, in the manifest. If the
+// iteration is already in the manifest nothing is written and the existing
+// manifest is returned with ErrRunExists.
+func WriteRun(ctx context.Context, s ResultsStore, exp string, entry RunEntry, data map[string]any) (*RunManifest, error) {
+	m, err := LoadManifest(ctx, s, exp)
+	if err != nil {
+		return nil, err
+	}
+	if m.Run(entry.Iteration) != nil {
+		return m, ErrRunExists
+	}
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prefix := RunPrefix(exp, entry.Iteration)
+	entry.Artifacts = nil
+	for _, name := range names {
+		var body []byte
+		contentType := "application/json"
+		if raw, ok := data[name].(Raw); ok {
+			body, contentType = raw.Body, raw.ContentType
+		} else if body, err = json.MarshalIndent(data[name], "", "  "); err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", name, err)
+		}
+		key := prefix + name
+		if err := s.Put(ctx, key, bytes.NewReader(body), contentType); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(body)
+		entry.Artifacts = append(entry.Artifacts, Artifact{
+			Name:        name,
+			Key:         key,
+			SHA256:      hex.EncodeToString(sum[:]),
+			Size:        int64(len(body)),
+			ContentType: contentType,
+		})
+	}
+	if entry.CollectedAt.IsZero() {
+		entry.CollectedAt = time.Now().UTC()
+	}
+
+	m.Runs = append(m.Runs, entry)
+	sort.Slice(m.Runs, func(i, j int) bool { return m.Runs[i].Iteration < m.Runs[j].Iteration })
+	return m, saveManifest(ctx, s, m)
+}
+
+// PromoteRun copies an iteration's artifacts to the experiment's top-level
+// keys ({exp}/summary.json, ...), which the site and analyzer read, and marks
+// it final in the manifest. Each copy is verified against its checksum.
+func PromoteRun(ctx context.Context, s ResultsStore, exp string, iteration int) (*RunManifest, error) {
+	m, err := LoadManifest(ctx, s, exp)
+	if err != nil {
+		return nil, err
+	}
+	run := m.Run(iteration)
+	if run == nil {
+		return nil, fmt.Errorf("promote: iteration %d not in manifest", iteration)
+	}
+
+	for _, a := range run.Artifacts {
+		rc, err := s.Get(ctx, a.Key)
+		if err != nil {
+			return nil, fmt.Errorf("promote: %w", err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("promote: read %s: %w", a.Key, err)
+		}
+		if sum := sha256.Sum256(body); hex.EncodeToString(sum[:]) != a.SHA256 {
+			return nil, fmt.Errorf("promote: checksum mismatch for %s", a.Key)
+		}
+		if err := s.Put(ctx, exp+"/"+a.Name, bytes.NewReader(body), a.ContentType); err != nil {
+			return nil, fmt.Errorf("promote: %w", err)
+		}
+	}
+
+	now := time.Now().UTC()
+	m.FinalIteration = &iteration
+	m.PromotedAt = &now
+	return m, saveManifest(ctx, s, m)
+}
+
+// MarkPublished records in the manifest that the experiment's results were
+// published, with a link to the publication (e.g. the PR URL).
+func MarkPublished(ctx context.Context, s ResultsStore, exp, url string) error {
+	m, err := LoadManifest(ctx, s, exp)
+	if err != nil {
+		return err
+	}
+	m.Published = true
+	m.PublishedURL = url
+	return saveManifest(ctx, s, m)
+}
+
+func saveManifest(ctx context.Context, s ResultsStore, m *RunManifest) error {
+	m.SchemaVersion = ManifestSchemaVersion
+	m.UpdatedAt = time.Now().UTC()
+	if err := PutJSON(ctx, s, ManifestKey(m.Experiment), m); err != nil {
+		return fmt.Errorf("save manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
+)
+
+func TestWriteRunAndPromote(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := RunEntry{
+		Iteration: 0,
+		Outcome:   RunOutcomeRecollecting,
+		Quality:   &experimentsv1alpha1.QualityResult{Iteration: 0, Coverage: 0.4},
+	}
+	if _, err := WriteRun(ctx, s, "exp-a", first, map[string]any{
+		"summary.json":          map[string]int{"attempt": 0},
+		"metrics-snapshot.json": map[string]int{"points": 1},
+	}); err != nil {
+		t.Fatalf("WriteRun(0): %v", err)
+	}
+	m, err := WriteRun(ctx, s, "exp-a", RunEntry{Iteration: 1, Outcome: RunOutcomePassed}, map[string]any{
+		"summary.json": map[string]int{"attempt": 1},
+	})
+	if err != nil {
+		t.Fatalf("WriteRun(1): %v", err)
+	}
+
+	if m.SchemaVersion != ManifestSchemaVersion || len(m.Runs) != 2 {
+		t.Fatalf("manifest = %+v, want 2 runs at schema %d", m, ManifestSchemaVersion)
+	}
+	run0 := m.Run(0)
+	if len(run0.Artifacts) != 2 || run0.Artifacts[0].Name != "metrics-snapshot.json" {
+		t.Errorf("run 0 artifacts = %+v, want sorted metrics-snapshot.json, summary.json", run0.Artifacts)
+	}
+	for _, a := range run0.Artifacts {
+		if !strings.HasPrefix(a.Key, "exp-a/runs/0/") || len(a.SHA256) != 64 || a.Size == 0 {
+			t.Errorf("artifact %+v missing key prefix, checksum or size", a)
+		}
+	}
+	if run0.Quality == nil || run0.Quality.Coverage != 0.4 {
+		t.Errorf("run 0 quality = %+v, want coverage 0.4", run0.Quality)
+	}
+
+	// Runs are immutable: rewriting an iteration is refused.
+	if _, err := WriteRun(ctx, s, "exp-a", RunEntry{Iteration: 0}, map[string]any{
+		"summary.json": map[string]int{"attempt": 99},
+	}); !errors.Is(err, ErrRunExists) {
+		t.Errorf("rewrite error = %v, want ErrRunExists", err)
+	}
+	var stored map[string]int
+	if err := GetJSON(ctx, s, "exp-a/runs/0/summary.json", &stored); err != nil || stored["attempt"] != 0 {
+		t.Errorf("run 0 summary = %v (%v), want original attempt 0", stored, err)
+	}
+
+	m, err = PromoteRun(ctx, s, "exp-a", 1)
+	if err != nil {
+		t.Fatalf("PromoteRun: %v", err)
+	}
+	if m.FinalIteration == nil || *m.FinalIteration != 1 || m.PromotedAt == nil {
+		t.Errorf("FinalIteration = %v, want 1 with PromotedAt", m.FinalIteration)
+	}
+	var top map[string]int
+	if err := GetJSON(ctx, s, "exp-a/summary.json", &top); err != nil || top["attempt"] != 1 {
+		t.Errorf("top-level summary = %v (%v), want promoted attempt 1", top, err)
+	}
+
+	loaded, err := LoadManifest(ctx, s, "exp-a")
+	if err != nil || loaded.FinalIteration == nil || *loaded.FinalIteration != 1 {
+		t.Errorf("reloaded manifest = %+v (%v), want final iteration 1", loaded, err)
+	}
+}
+
+func TestPromoteRunDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteRun(ctx, s, "exp-a", RunEntry{Iteration: 0}, map[string]any{"summary.json": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, "exp-a/runs/0/summary.json", strings.NewReader("2"), "application/json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PromoteRun(ctx, s, "exp-a", 0); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("PromoteRun error = %v, want checksum mismatch", err)
+	}
+	if _, err := PromoteRun(ctx, s, "exp-a", 5); err == nil {
+		t.Error("PromoteRun of unknown iteration succeeded, want error")
+	}
+}
+
+func TestWriteRunRawArtifacts(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := WriteRun(ctx, s, "exp-a", RunEntry{Iteration: 0}, map[string]any{
+		"summary.json":    map[string]int{"attempt": 0},
+		"csv/cpu.csv":     Raw{Body: []byte("timestamp,value\n"), ContentType: "text/csv"},
+		"metrics.parquet": Raw{Body: []byte("PAR1"), ContentType: "application/vnd.apache.parquet"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := m.Run(0).Artifacts[0]; a.Name != "csv/cpu.csv" || a.ContentType != "text/csv" || a.Size != 16 {
+		t.Errorf("raw artifact = %+v, want csv stored as-is", a)
+	}
+	if _, err := PromoteRun(ctx, s, "exp-a", 0); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := s.Get(ctx, "exp-a/csv/cpu.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	body, _ := io.ReadAll(rc)
+	if string(body) != "timestamp,value\n" {
+		t.Errorf("promoted csv = %q, want raw body", body)
+	}
+}
@@ -3,44 +3,135 @@ package storage
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/endpointcreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-// Client wraps an S3 client configured for SeaweedFS.
-type Client struct {
+// S3Config configures an S3-compatible backend (SeaweedFS, MinIO, AWS S3).
+type S3Config struct {
+	// Endpoint is host:port or a full URL. Empty uses the AWS endpoint for Region.
+	Endpoint string
+	// Region is the signing region (default us-east-1; ignored by SeaweedFS).
+	Region string
+	// UseTLS selects https when Endpoint has no scheme.
+	UseTLS bool
+	// PathStyle addresses buckets as /bucket/key (required by SeaweedFS/MinIO).
+	PathStyle bool
+	// CAFile is an optional PEM bundle trusted in addition to the system roots.
+	CAFile string
+
+	// AccessKeyID, SecretAccessKey and SessionToken are static credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// CredentialsURI is a container credentials endpoint (ECS task role or EKS
+	// Pod Identity), used when no static keys are set. CredentialsTokenFile
+	// holds the bearer token sent to it.
+	CredentialsURI       string
+	CredentialsTokenFile string
+}
+
+// S3Store is a ResultsStore backed by an S3-compatible bucket.
+type S3Store struct {
 	s3Client *s3.Client
 	bucket   string
+	// plaintext is true for http endpoints, where the SDK cannot stream
+	// unseekable bodies and they must be buffered.
+	plaintext bool
 }
 
-// NewClient creates an S3 client pointing at a SeaweedFS endpoint.
-func NewClient(endpoint, accessKey, secretKey, bucket string) (*Client, error) {
-	s3Client := s3.New(s3.Options{
-		BaseEndpoint: aws.String("http://" + endpoint),
-		Region:       "us-east-1", // required by SDK but ignored by SeaweedFS
-		Credentials:  credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""),
-		UsePathStyle: true, // SeaweedFS requires path-style
-	})
-	return &Client{s3Client: s3Client, bucket: bucket}, nil
-}
+// NewS3Store creates an S3 store for bucket. With no credentials configured it
+// signs with placeholder keys, which SeaweedFS accepts when auth is disabled.
+func NewS3Store(bucket string, cfg S3Config) (*S3Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("s3: bucket is required")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
 
-// PutJSON marshals data as JSON and uploads it to the given key.
-func (c *Client) PutJSON(ctx context.Context, key string, data any) error {
-	body, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshal JSON: %w", err)
+	opts := s3.Options{
+		Region:       region,
+		UsePathStyle: cfg.PathStyle,
+	}
+
+	plaintext := false
+	if cfg.Endpoint != "" {
+		endpoint := cfg.Endpoint
+		if !strings.Contains(endpoint, "://") {
+			if cfg.UseTLS {
+				endpoint = "https://" + endpoint
+			} else {
+				endpoint = "http://" + endpoint
+			}
+		}
+		plaintext = strings.HasPrefix(endpoint, "http://")
+		opts.BaseEndpoint = aws.String(endpoint)
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("s3: read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("s3: no certificates found in %s", cfg.CAFile)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		opts.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	switch {
+	case cfg.AccessKeyID != "":
+		opts.Credentials = credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken)
+	case cfg.CredentialsURI != "":
+		opts.Credentials = aws.NewCredentialsCache(endpointcreds.New(cfg.CredentialsURI, func(o *endpointcreds.Options) {
+			if cfg.CredentialsTokenFile != "" {
+				o.AuthorizationTokenProvider = endpointcreds.TokenProviderFunc(func() (string, error) {
+					token, err := os.ReadFile(cfg.CredentialsTokenFile)
+					return strings.TrimSpace(string(token)), err
+				})
+			}
+		}))
+	default:
+		opts.Credentials = credentials.NewStaticCredentialsProvider("any", "any", "")
 	}
 
-	contentType := "application/json"
-	_, err = c.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      &c.bucket,
+	return &S3Store{s3Client: s3.New(opts), bucket: bucket, plaintext: plaintext}, nil
+}
+
+// Put uploads body to key.
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if _, seekable := body.(io.ReadSeeker); !seekable && s.plaintext {
+		buf, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("read body for %s: %w", key, err)
+		}
+		body = bytes.NewReader(buf)
+	}
+	_, err := s.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &s.bucket,
 		Key:         &key,
-		Body:        bytes.NewReader(body),
+		Body:        body,
 		ContentType: &contentType,
 	})
 	if err != nil {
@@ -49,20 +140,59 @@ func (c *Client) PutJSON(ctx context.Context, key string, data any) error {
 	return nil
 }
 
-// GetJSON downloads an object and unmarshals its JSON content into out.
-func (c *Client) GetJSON(ctx context.Context, key string, out any) error {
-	resp, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: &c.bucket,
+// Get opens the object at key.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
 		Key:    &key,
 	})
 	if err != nil {
-		return fmt.Errorf("get object %s: %w", key, err)
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return nil, fmt.Errorf("get object %s: %w", key, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get object %s: %w", key, err)
 	}
-	defer resp.Body.Close()
+	return resp.Body, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// List returns every object under prefix.
+func (s *S3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var out []ObjectInfo
+	p := s3.NewListObjectsV2Paginator(s.s3Client, &s3.ListObjectsV2Input{
+		Bucket: &s.bucket,
+		Prefix: &prefix,
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list objects %s: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			info := ObjectInfo{Key: aws.ToString(obj.Key), Size: aws.ToInt64(obj.Size)}
+			if obj.LastModified != nil {
+				info.LastModified = *obj.LastModified
+			}
+			out = append(out, info)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// Delete removes the object at key.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	})
 	if err != nil {
-		return fmt.Errorf("read object %s: %w", key, err)
+		return fmt.Errorf("delete object %s: %w", key, err)
 	}
-	return json.Unmarshal(body, out)
+	return nil
+}
+
+// URL returns s3://bucket/key.
+func (s *S3Store) URL(key string) string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key)
 }
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned by Get when the key does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object returned by List.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ResultsStore persists experiment results under slash-separated keys
+// ("<experiment>/summary.json"). Implementations must be safe for concurrent use.
+type ResultsStore interface {
+	// Put uploads body to key, replacing any existing object.
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	// Get opens the object at key. The caller must close the reader. Returns
+	// an error wrapping ErrNotFound if the key does not exist.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns every object whose key starts with prefix, sorted by key.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Delete removes the object at key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns a human-readable location for a key or key prefix, recorded
+	// in status.resultsURL (e.g. "s3://experiment-results/exp-abc/").
+	URL(key string) string
+}
+
+// Backend names accepted by Config.Backend.
+const (
+	BackendS3    = "s3"
+	BackendGCS   = "gcs"
+	BackendLocal = "local"
+)
+
+// Config selects and configures a ResultsStore backend.
+type Config struct {
+	// Backend is s3 (default), gcs or local.
+	Backend string
+	// Bucket is the S3 or GCS bucket name.
+	Bucket string
+	// S3 configures the s3 backend.
+	S3 S3Config
+	// GCSEndpoint overrides the GCS JSON API base URL (for emulators).
+	GCSEndpoint string
+	// LocalDir is the root directory of the local backend (e.g. a PVC mount).
+	LocalDir string
+}
+
+// New constructs the ResultsStore selected by cfg.Backend.
+func New(ctx context.Context, cfg Config) (ResultsStore, error) {
+	var (
+		store ResultsStore
+		err   error
+	)
+	switch cfg.Backend {
+	case "", BackendS3:
+		store, err = NewS3Store(cfg.Bucket, cfg.S3)
+	case BackendGCS:
+		store, err = NewGCSStore(ctx, cfg.Bucket, cfg.GCSEndpoint)
+	case BackendLocal:
+		store, err = NewLocalStore(cfg.LocalDir)
+	default:
+		err = fmt.Errorf("unknown results store backend %q (want s3, gcs or local)", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// PutJSON marshals data as indented JSON and uploads it to key.
+func PutJSON(ctx context.Context, s ResultsStore, key string, data any) error {
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal JSON: %w", err)
+	}
+	return s.Put(ctx, key, bytes.NewReader(body), "application/json")
+}
+
+// GetJSON downloads the object at key and unmarshals its JSON content into out.
+func GetJSON(ctx context.Context, s ResultsStore, key string, out any) error {
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("read object %s: %w", key, err)
+	}
+	return json.Unmarshal(body, out)
+}
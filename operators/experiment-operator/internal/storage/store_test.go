@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// exerciseStore runs the ResultsStore contract against s.
+func exerciseStore(t *testing.T, s ResultsStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := PutJSON(ctx, s, "exp-a/summary.json", map[string]string{"name": "exp-a"}); err != nil {
+		t.Fatalf("PutJSON: %v", err)
+	}
+	if err := s.Put(ctx, "exp-a/cadvisor-samples/app.json", strings.NewReader(`{}`), "application/json"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put(ctx, "exp-b/summary.json", strings.NewReader(`{}`), "application/json"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	var got map[string]string
+	if err := GetJSON(ctx, s, "exp-a/summary.json", &got); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if got["name"] != "exp-a" {
+		t.Errorf("GetJSON = %v, want name=exp-a", got)
+	}
+
+	objs, err := s.List(ctx, "exp-a/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var keys []string
+	for _, o := range objs {
+		keys = append(keys, o.Key)
+	}
+	want := []string{"exp-a/cadvisor-samples/app.json", "exp-a/summary.json"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("List = %v, want %v", keys, want)
+	}
+
+	if err := s.Delete(ctx, "exp-a/summary.json"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, "exp-a/summary.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "exp-a/summary.json"); err != nil {
+		t.Errorf("Delete of missing key = %v, want nil", err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseStore(t, s)
+
+	if _, err := os.Stat(filepath.Join(dir, "exp-b", "summary.json")); err != nil {
+		t.Errorf("results not written under the root directory: %v", err)
+	}
+	if got := s.URL("exp-b/"); got != "file://"+filepath.ToSlash(dir)+"/exp-b/" {
+		t.Errorf("URL = %q", got)
+	}
+}
+
+func TestLocalStoreRejectsEscapingKeys(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStore(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(context.Background(), "../outside.json", strings.NewReader("x"), ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "outside.json")); err == nil {
+		t.Error("key escaped the store root")
+	}
+	if err := s.Put(context.Background(), "", strings.NewReader("x"), ""); err == nil {
+		t.Error("Put with empty key succeeded, want error")
+	}
+}
+
+// fakeGCS is a minimal in-memory implementation of the GCS JSON API paths
+// used by GCSStore.
+type fakeGCS struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	const objPrefix = "/storage/v1/b/bucket/o/"
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/bucket/o":
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Query().Get("name")] = body
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/bucket/o":
+		prefix := r.URL.Query().Get("prefix")
+		var names []string
+		for name := range f.objects {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		// Serve one item per page to exercise pagination.
+		start := 0
+		if tok := r.URL.Query().Get("pageToken"); tok != "" {
+			for i, n := range names {
+				if n == tok {
+					start = i
+				}
+			}
+		}
+		resp := map[string]any{"items": []map[string]string{}}
+		if start < len(names) {
+			resp["items"] = []map[string]string{{"name": names[start], "size": "2"}}
+			if start+1 < len(names) {
+				resp["nextPageToken"] = names[start+1]
+			}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	case strings.HasPrefix(r.URL.EscapedPath(), objPrefix):
+		name, _ := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), objPrefix))
+		body, ok := f.objects[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(f.objects, name)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write(body)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestGCSStore(t *testing.T) {
+	srv := httptest.NewServer(&fakeGCS{objects: map[string][]byte{}})
+	defer srv.Close()
+
+	s, err := NewGCSStore(context.Background(), "bucket", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseStore(t, s)
+
+	if got := s.URL("exp-b/"); got != "gs://bucket/exp-b/" {
+		t.Errorf("URL = %q", got)
+	}
+}
+
+func TestNewUnknownBackend(t *testing.T) {
+	if _, err := New(context.Background(), Config{Backend: "ftp"}); err == nil {
+		t.Error("New with unknown backend succeeded, want error")
+	}
+}
+
+func TestNewS3StoreRequiresBucket(t *testing.T) {
+	if _, err := NewS3Store("", S3Config{}); err == nil {
+		t.Error("NewS3Store without bucket succeeded, want error")
+	}
+	s, err := NewS3Store("experiment-results", S3Config{Endpoint: "seaweedfs:8333", PathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.plaintext {
+		t.Error("plaintext = false for a scheme-less endpoint without TLS")
+	}
+	if got := s.URL("exp-a/"); got != "s3://experiment-results/exp-a/" {
+		t.Errorf("URL = %q", got)
+	}
+}
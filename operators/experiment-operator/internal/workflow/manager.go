@@ -54,6 +54,15 @@ type WorkflowResult struct {
 	StartedAt  *metav1.Time
 	FinishedAt *metav1.Time
 	Message    string
+	Nodes      []NodeWindow
+}
+
+// NodeWindow is the execution window of a single workflow node (step or pod).
+type NodeWindow struct {
+	DisplayName  string
+	TemplateName string
+	StartedAt    *metav1.Time
+	FinishedAt   *metav1.Time
 }
 
 // SubmitWorkflow creates an Argo Workflow from the experiment's workflow spec
@@ -288,9 +297,79 @@ func (m *Manager) GetWorkflowStatus(ctx context.Context, workflowName string) (*
 		}
 	}
 
+	// Get node execution windows (used for named metrics phase windows)
+	nodes, _, _ := unstructured.NestedMap(wf.Object, "status", "nodes")
+	result.Nodes = parseNodeWindows(nodes)
+
 	return result, nil
 }
 
+// parseNodeWindows extracts the execution window of each node in an Argo
+// Workflow's status.nodes map. Nodes that have not started are skipped.
+func parseNodeWindows(nodes map[string]interface{}) []NodeWindow {
+	var windows []NodeWindow
+	for _, raw := range nodes {
+		node, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		nw := NodeWindow{}
+		nw.DisplayName, _, _ = unstructured.NestedString(node, "displayName")
+		nw.TemplateName, _, _ = unstructured.NestedString(node, "templateName")
+		if nw.TemplateName == "" {
+			nw.TemplateName, _, _ = unstructured.NestedString(node, "templateRef", "template")
+		}
+		if s, _, _ := unstructured.NestedString(node, "startedAt"); s != "" {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				nw.StartedAt = &metav1.Time{Time: t}
+			}
+		}
+		if nw.StartedAt == nil {
+			continue
+		}
+		if s, _, _ := unstructured.NestedString(node, "finishedAt"); s != "" {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				nw.FinishedAt = &metav1.Time{Time: t}
+			}
+		}
+		windows = append(windows, nw)
+	}
+	return windows
+}
+
+// ResolvePhaseWindows maps each named phase window to the nodes whose display
+// name or template name matches its step. A window spans the earliest start to
+// the latest finish of its matching nodes; FinishedAt stays nil while any
+// matching node is still running. Windows with no started node are omitted.
+func ResolvePhaseWindows(specs []experimentsv1alpha1.PhaseWindowSpec, nodes []NodeWindow) []experimentsv1alpha1.PhaseWindowStatus {
+	var phases []experimentsv1alpha1.PhaseWindowStatus
+	for _, spec := range specs {
+		pw := experimentsv1alpha1.PhaseWindowStatus{Name: spec.Name}
+		running := false
+		for _, n := range nodes {
+			if n.DisplayName != spec.Step && n.TemplateName != spec.Step {
+				continue
+			}
+			if pw.StartedAt == nil || n.StartedAt.Before(pw.StartedAt) {
+				pw.StartedAt = n.StartedAt
+			}
+			if n.FinishedAt == nil {
+				running = true
+			} else if pw.FinishedAt == nil || pw.FinishedAt.Before(n.FinishedAt) {
+				pw.FinishedAt = n.FinishedAt
+			}
+		}
+		if pw.StartedAt == nil {
+			continue
+		}
+		if running {
+			pw.FinishedAt = nil
+		}
+		phases = append(phases, pw)
+	}
+	return phases
+}
+
 // DeleteWorkflow deletes an Argo Workflow
 func (m *Manager) DeleteWorkflow(ctx context.Context, workflowName string) error {
 	logger := log.FromContext(ctx)
@@ -2,6 +2,11 @@ package workflow
 
 import (
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	experimentsv1alpha1 "github.com/illmadecoder/experiment-operator/api/v1alpha1"
 )
 
 func TestIsTerminal(t *testing.T) {
@@ -57,3 +62,66 @@ func TestNewManager_Defaults(t *testing.T) {
 		t.Errorf("expected default namespace to be 'argo', got %s", DefaultNamespace)
 	}
 }
+
+func TestParseNodeWindows(t *testing.T) {
+	nodes := map[string]interface{}{
+		"wf-1": map[string]interface{}{
+			"displayName":  "run-load",
+			"templateName": "k6",
+			"startedAt":    "2026-01-01T10:00:00Z",
+			"finishedAt":   "2026-01-01T10:30:00Z",
+		},
+		"wf-2": map[string]interface{}{
+			"displayName": "cooldown",
+			"templateRef": map[string]interface{}{"name": "shared", "template": "sleep"},
+			"startedAt":   "2026-01-01T10:30:00Z",
+		},
+		"wf-3": map[string]interface{}{
+			"displayName": "not-started",
+		},
+	}
+
+	windows := parseNodeWindows(nodes)
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 started nodes, got %d", len(windows))
+	}
+	byName := map[string]NodeWindow{}
+	for _, w := range windows {
+		byName[w.DisplayName] = w
+	}
+	if w := byName["run-load"]; w.TemplateName != "k6" || w.FinishedAt == nil {
+		t.Errorf("run-load = %+v, want template k6 with finishedAt", w)
+	}
+	if w := byName["cooldown"]; w.TemplateName != "sleep" || w.FinishedAt != nil {
+		t.Errorf("cooldown = %+v, want templateRef template sleep and no finishedAt", w)
+	}
+}
+
+func TestResolvePhaseWindows(t *testing.T) {
+	at := func(min int) *metav1.Time {
+		return &metav1.Time{Time: time.Date(2026, 1, 1, 10, min, 0, 0, time.UTC)}
+	}
+	nodes := []NodeWindow{
+		{DisplayName: "warmup", TemplateName: "k6", StartedAt: at(0), FinishedAt: at(5)},
+		{DisplayName: "load(0)", TemplateName: "load", StartedAt: at(5), FinishedAt: at(20)},
+		{DisplayName: "load(1)", TemplateName: "load", StartedAt: at(20), FinishedAt: at(35)},
+		{DisplayName: "cooldown", TemplateName: "sleep", StartedAt: at(35)},
+	}
+	specs := []experimentsv1alpha1.PhaseWindowSpec{
+		{Name: "warmup", Step: "warmup"},
+		{Name: "steady-state", Step: "load"},
+		{Name: "cooldown", Step: "cooldown"},
+		{Name: "never", Step: "missing"},
+	}
+
+	got := ResolvePhaseWindows(specs, nodes)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 phases, got %d: %+v", len(got), got)
+	}
+	if !got[1].StartedAt.Equal(at(5)) || !got[1].FinishedAt.Equal(at(35)) {
+		t.Errorf("steady-state = %s..%s, want 10:05..10:35", got[1].StartedAt, got[1].FinishedAt)
+	}
+	if got[2].FinishedAt != nil {
+		t.Errorf("cooldown still running, FinishedAt = %s, want nil", got[2].FinishedAt)
+	}
+}
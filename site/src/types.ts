@@ -1,6 +1,8 @@
 /** Mirrors Go structs in operators/experiment-operator/internal/metrics/collector.go */
 
 export interface ExperimentSummary {
+  /** Layout version; schema published at /schema/experiment-summary.v1.json */
+  schemaVersion?: number;
   name: string;
   title?: string;
   namespace: string;
@@ -11,7 +13,9 @@ export interface ExperimentSummary {
   phase: string;
   tags?: string[];
   series?: string;
+  /** Pre-schemaVersion field, migrated to hypothesis */
   study?: StudyContext;
+  /** Pre-schemaVersion field, migrated to analyzerConfig */
   analysisConfig?: { sections: string[] };
   hypothesis?: HypothesisContext;
   analyzerConfig?: { sections: string[] };
@@ -128,6 +132,7 @@ export interface AnalysisResult {
   // Structured analysis sections
   abstract?: string;
   capabilitiesMatrix?: CapabilitiesMatrix;
+  finopsAnalysis?: FinopsAnalysis;
   body?: AnalysisBody;
   feedback?: AnalysisFeedback;
   architectureDiagram?: string;
@@ -141,6 +146,13 @@ export interface CapabilitiesMatrix {
   summary?: string;
 }
 
+export interface FinopsAnalysis {
+  overview?: string;
+  costDrivers?: string[];
+  projection?: string;
+  optimizations?: string[];
+}
+
 export interface CapabilitiesCategory {
   name: string;
   capabilities: CapabilityEntry[];
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/illmadecoder/labctl/internal/k8s"
+	"github.com/spf13/cobra"
+)
+
+var analyzeSections []string
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze <experiment-name>",
+	Short: "Re-run AI analysis of a completed experiment",
+	Long: `Asks the operator to run the analyzer again against the experiment's stored
+summary.json, without re-running the experiment or its clusters. The previous
+analysis is kept in status.analysisHistory and the results PR is updated if
+its review is still open.
+
+Without --sections the experiment's spec.analyzerConfig.sections are used.`,
+	Example: `  labctl analyze tsdb-b2c4d
+  labctl analyze tsdb-b2c4d --sections abstract,body,capabilitiesMatrix`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAnalyze,
+}
+
+func init() {
+	analyzeCmd.Flags().StringSliceVar(&analyzeSections, "sections", nil, "analysis sections to generate (comma-separated)")
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return fmt.Errorf("cannot connect to hub cluster: %w", err)
+	}
+
+	if err := client.RequestReanalysis(cmd.Context(), name, analyzeSections); err != nil {
+		return fmt.Errorf("cannot request analysis of %q: %w", name, err)
+	}
+
+	fmt.Printf("Reanalysis requested for %s.\n", name)
+	fmt.Printf("Follow it with: kubectl get experiment %s -n experiments -o jsonpath='{.status.analysisPhase}'\n", name)
+	return nil
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/illmadecoder/labctl/internal/k8s"
+	"github.com/illmadecoder/labctl/internal/results"
+	"github.com/spf13/cobra"
+)
+
+var resultsCmd = &cobra.Command{
+	Use:   "results",
+	Short: "Work with stored experiment results",
+}
+
+var resultsExportCmd = &cobra.Command{
+	Use:   "export <experiment-name>",
+	Short: "Download experiment results as CSV, Parquet, OpenMetrics or JSON",
+	Long: `Downloads the final run's exported metrics for an experiment into
+./<experiment>/ (or --output). The results location is read from the
+Experiment's status.resultsURL; pass --url for experiments whose CR was pruned.
+
+Formats:
+  csv          one CSV per query (csv/<query>.csv)
+  parquet      metrics.parquet, every data point with target and labels columns
+  openmetrics  metrics.openmetrics text exposition
+  json         summary.json and metrics-snapshot.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runResultsExport,
+}
+
+var (
+	exportFormat     string
+	exportOutput     string
+	exportURL        string
+	exportS3Endpoint string
+)
+
+func init() {
+	resultsExportCmd.Flags().StringVarP(&exportFormat, "format", "f", "parquet", "export format: "+strings.Join(results.Formats, ", "))
+	resultsExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output directory (default ./<experiment>)")
+	resultsExportCmd.Flags().StringVar(&exportURL, "url", "", "results URL (default: the Experiment's status.resultsURL)")
+	resultsExportCmd.Flags().StringVar(&exportS3Endpoint, "s3-endpoint", os.Getenv("S3_ENDPOINT"), "S3 endpoint for s3:// results")
+	resultsCmd.AddCommand(resultsExportCmd)
+
+	resultsListCmd.Flags().StringSliceVarP(&listTags, "tag", "t", nil, "only experiments with all these tags")
+	resultsListCmd.Flags().StringVar(&listVerdict, "verdict", "", "hypothesis verdict (validated, invalidated, insufficient)")
+	resultsListCmd.Flags().StringVar(&listPhase, "phase", "", "experiment phase (Complete, Failed)")
+	resultsListCmd.Flags().StringVar(&listSince, "since", "", "completed since: RFC 3339 time, date, or duration ago (72h, 30d)")
+	resultsListCmd.Flags().StringVar(&listUntil, "until", "", "completed until: RFC 3339 time, date, or duration ago")
+	resultsListCmd.Flags().IntVar(&listLimit, "limit", 0, "maximum number of experiments (newest first)")
+	resultsListCmd.Flags().StringVarP(&listOutput, "output", "o", "table", "output format: table or json")
+	resultsListCmd.Flags().StringVar(&listAPI, "api", os.Getenv("RESULTS_API"), "results API base URL (default: hub service proxy)")
+	resultsCmd.AddCommand(resultsListCmd)
+}
+
+var resultsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Search completed experiments in the operator's results index",
+	Long: `Lists completed experiments from the operator's cross-experiment results
+index, newest first, with their verdict, duration, cost and published PR.
+
+The index API is reached through the hub's API server service proxy; pass
+--api (or RESULTS_API) to use another address, e.g. a port-forward.`,
+	Example: `  labctl results list --tag tsdb --verdict validated
+  labctl results list --since 30d -o json`,
+	Args: cobra.NoArgs,
+	RunE: runResultsList,
+}
+
+var (
+	listTags    []string
+	listVerdict string
+	listPhase   string
+	listSince   string
+	listUntil   string
+	listLimit   int
+	listOutput  string
+	listAPI     string
+)
+
+func runResultsList(cmd *cobra.Command, args []string) error {
+	if listOutput != "table" && listOutput != "json" {
+		return fmt.Errorf("unknown output format %q (want table or json)", listOutput)
+	}
+
+	get := results.DirectGet(listAPI, nil)
+	if listAPI == "" {
+		client, err := k8s.NewClient()
+		if err != nil {
+			return fmt.Errorf("cannot connect to hub cluster: %w", err)
+		}
+		get = client.ResultsAPIGet
+	}
+
+	params := map[string]string{}
+	if len(listTags) > 0 {
+		params["tag"] = strings.Join(listTags, ",")
+	}
+	for k, v := range map[string]string{"verdict": listVerdict, "phase": listPhase, "since": listSince, "until": listUntil} {
+		if v != "" {
+			params[k] = v
+		}
+	}
+	if listLimit > 0 {
+		params["limit"] = fmt.Sprint(listLimit)
+	}
+
+	list, err := results.ListIndex(cmd.Context(), get, params)
+	if err != nil {
+		return fmt.Errorf("cannot query results index: %w", err)
+	}
+
+	if listOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list.Experiments)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPHASE\tVERDICT\tCOMPLETED\tDURATION\tCOST\tTAGS\tPR")
+	for _, e := range list.Experiments {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Name, e.Phase, dash(e.Verdict()),
+			e.CompletedAt.Local().Format("2006-01-02 15:04"),
+			(time.Duration(e.DurationSec) * time.Second).Round(time.Minute),
+			fmt.Sprintf("$%.2f", e.CostUSD),
+			dash(strings.Join(e.Tags, ",")), dash(e.PublishPRURL))
+	}
+	return w.Flush()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func runResultsExport(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !slices.Contains(results.Formats, exportFormat) {
+		return fmt.Errorf("unknown format %q (want one of %s)", exportFormat, strings.Join(results.Formats, ", "))
+	}
+
+	resultsURL := exportURL
+	if resultsURL == "" {
+		client, err := k8s.NewClient()
+		if err != nil {
+			return fmt.Errorf("cannot connect to hub cluster: %w", err)
+		}
+		exp, err := client.GetExperiment(cmd.Context(), name)
+		if err != nil {
+			return fmt.Errorf("cannot get experiment %q: %w", name, err)
+		}
+		if exp.ResultsURL == "" {
+			return fmt.Errorf("experiment %q has no stored results yet (phase %s)", name, exp.Phase)
+		}
+		resultsURL = exp.ResultsURL
+	}
+
+	dir := exportOutput
+	if dir == "" {
+		dir = name
+	}
+
+	f := &results.Fetcher{S3Endpoint: exportS3Endpoint}
+	paths, err := f.Export(cmd.Context(), resultsURL, exportFormat, dir)
+	for _, p := range paths {
+		fmt.Println(p)
+	}
+	return err
+}
@@ -22,4 +22,6 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(kubeconfigCmd)
+	rootCmd.AddCommand(resultsCmd)
+	rootCmd.AddCommand(analyzeCmd)
 }
@@ -42,12 +42,16 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	// Targets
 	if len(exp.Targets) > 0 {
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "TARGET\tCLUSTER\tPHASE\tENDPOINT")
+		fmt.Fprintln(w, "TARGET\tCLUSTER\tPHASE\tENDPOINT\tDEPLOYMENT")
 		for _, t := range exp.Targets {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Name, t.ClusterName, t.Phase, t.Endpoint)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Name, t.ClusterName, t.Phase, t.Endpoint, dash(t.Deployment.Reason))
 		}
 		w.Flush()
 		fmt.Println()
+
+		for _, t := range exp.Targets {
+			printDeploymentHealth(t)
+		}
 	}
 
 	// Tutorial services
@@ -71,3 +75,28 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printDeploymentHealth explains why what was deployed for a target is not
+// healthy: the condition message, then the unhealthy resources.
+func printDeploymentHealth(t k8s.TargetInfo) {
+	if t.Deployment.Reason == "" || t.Deployment.Status == "True" {
+		return
+	}
+	fmt.Printf("Deployment %s: %s\n", t.Name, t.Deployment.Reason)
+	fmt.Printf("  %s\n", t.Deployment.Message)
+
+	if len(t.Unhealthy) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  APPLICATION\tRESOURCE\tHEALTH\tSYNC\tMESSAGE")
+		for _, r := range t.Unhealthy {
+			name := r.Kind + "/" + r.Name
+			if r.Namespace != "" {
+				name = r.Kind + "/" + r.Namespace + "/" + r.Name
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", r.Application, name, dash(r.Health), dash(r.Sync), r.Message)
+		}
+		w.Flush()
+	}
+	fmt.Println()
+}
@@ -2,8 +2,10 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -13,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -68,6 +71,7 @@ type ExperimentInfo struct {
 	Phase             string
 	TTLDays           int
 	CompletionMode    string
+	ResultsURL        string
 	Targets           []TargetInfo
 	Services          []ServiceInfo
 	KubeconfigSecrets map[string]string
@@ -79,6 +83,28 @@ type TargetInfo struct {
 	ClusterName string
 	Phase       string
 	Endpoint    string
+	// Deployment is the target's DeploymentHealthy condition; its Reason is
+	// empty until the operator has checked what it deployed.
+	Deployment ConditionInfo
+	Unhealthy  []ResourceInfo
+}
+
+// ConditionInfo holds a status condition.
+type ConditionInfo struct {
+	Status  string
+	Reason  string
+	Message string
+}
+
+// ResourceInfo holds a deployed resource that is not Healthy or not Synced.
+type ResourceInfo struct {
+	Application string
+	Kind        string
+	Namespace   string
+	Name        string
+	Health      string
+	Sync        string
+	Message     string
 }
 
 // ServiceInfo holds discovered service info.
@@ -103,6 +129,9 @@ func (c *Client) GetExperiment(ctx context.Context, name string) (*ExperimentInf
 	// Phase
 	info.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
 
+	// Results location (s3://, gs:// or file:// prefix)
+	info.ResultsURL, _, _ = unstructured.NestedString(obj.Object, "status", "resultsURL")
+
 	// TTL
 	ttl, _, _ := unstructured.NestedInt64(obj.Object, "spec", "ttlDays")
 	info.TTLDays = int(ttl)
@@ -122,6 +151,38 @@ func (c *Client) GetExperiment(ctx context.Context, name string) (*ExperimentInf
 		ti.ClusterName, _, _ = unstructured.NestedString(tm, "clusterName")
 		ti.Phase, _, _ = unstructured.NestedString(tm, "phase")
 		ti.Endpoint, _, _ = unstructured.NestedString(tm, "endpoint")
+
+		conditions, _, _ := unstructured.NestedSlice(tm, "conditions")
+		for _, c := range conditions {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if condType, _, _ := unstructured.NestedString(cm, "type"); condType != "DeploymentHealthy" {
+				continue
+			}
+			ti.Deployment.Status, _, _ = unstructured.NestedString(cm, "status")
+			ti.Deployment.Reason, _, _ = unstructured.NestedString(cm, "reason")
+			ti.Deployment.Message, _, _ = unstructured.NestedString(cm, "message")
+		}
+
+		unhealthy, _, _ := unstructured.NestedSlice(tm, "unhealthyResources")
+		for _, u := range unhealthy {
+			um, ok := u.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ri := ResourceInfo{}
+			ri.Application, _, _ = unstructured.NestedString(um, "application")
+			ri.Kind, _, _ = unstructured.NestedString(um, "kind")
+			ri.Namespace, _, _ = unstructured.NestedString(um, "namespace")
+			ri.Name, _, _ = unstructured.NestedString(um, "name")
+			ri.Health, _, _ = unstructured.NestedString(um, "health")
+			ri.Sync, _, _ = unstructured.NestedString(um, "sync")
+			ri.Message, _, _ = unstructured.NestedString(um, "message")
+			ti.Unhealthy = append(ti.Unhealthy, ri)
+		}
+
 		info.Targets = append(info.Targets, ti)
 	}
 
@@ -163,6 +224,31 @@ func (c *Client) ListExperimentPhases(ctx context.Context) (map[string]string, e
 	return result, nil
 }
 
+// reanalyzeAnnotation asks the operator to re-run the analyzer of a completed
+// experiment; its value lists the analysis sections.
+const reanalyzeAnnotation = "experiments.illm.io/reanalyze"
+
+// RequestReanalysis annotates an experiment so the operator re-runs its
+// analyzer against the stored summary. Empty sections reuse the experiment's
+// configured ones.
+func (c *Client) RequestReanalysis(ctx context.Context, name string, sections []string) error {
+	value := "true"
+	if len(sections) > 0 {
+		value = strings.Join(sections, ",")
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{reanalyzeAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamic.Resource(experimentGVR).Namespace(defaultNamespace).
+		Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
 // GetSecretData reads a specific key from a Secret.
 func (c *Client) GetSecretData(ctx context.Context, namespace, name, key string) ([]byte, error) {
 	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -182,3 +268,18 @@ func (c *Client) GetSecretData(ctx context.Context, namespace, name, key string)
 	}
 	return data, nil
 }
+
+// Results index API Service created by the operator's kustomize config.
+const (
+	resultsAPINamespace = "experiment-operator-system"
+	resultsAPIService   = "experiment-operator-results-api"
+	resultsAPIPort      = "8082"
+)
+
+// ResultsAPIGet calls the operator's results index API through the API
+// server's service proxy, so no port-forward is needed.
+func (c *Client) ResultsAPIGet(ctx context.Context, path string, params map[string]string) ([]byte, error) {
+	return c.clientset.CoreV1().Services(resultsAPINamespace).
+		ProxyGet("http", resultsAPIService, resultsAPIPort, path, params).
+		DoRaw(ctx)
+}
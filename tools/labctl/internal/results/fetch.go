@@ -0,0 +1,184 @@
+// Package results downloads experiment results written by the operator's
+// results store (S3, GCS or a local directory).
+package results
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Export formats accepted by `labctl results export --format`.
+var Formats = []string{"csv", "parquet", "openmetrics", "json"}
+
+// Fetcher reads objects by the URLs the operator records in
+// status.resultsURL: s3://, gs://, file:// or plain http(s)://. Requests are
+// unsigned, so s3:// and gs:// results are only readable from buckets that
+// allow anonymous reads, such as a SeaweedFS without auth; a private bucket
+// answers 403 and Open says so.
+type Fetcher struct {
+	// S3Endpoint resolves s3:// URLs (e.g. "localhost:8333" via port-forward).
+	// A bare host:port is read over plain HTTP.
+	S3Endpoint string
+	HTTP       *http.Client
+}
+
+// Open returns the object at rawURL.
+func (f *Fetcher) Open(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		return os.Open(u.Path)
+	case "s3":
+		if f.S3Endpoint == "" {
+			return nil, fmt.Errorf("%s: set --s3-endpoint (or S3_ENDPOINT) to read S3 results", rawURL)
+		}
+		endpoint := f.S3Endpoint
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+		rawURL = strings.TrimSuffix(endpoint, "/") + "/" + u.Host + u.Path
+	case "gs":
+		rawURL = "https://storage.googleapis.com/" + u.Host + u.Path
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("unsupported results URL %q", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := f.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if (u.Scheme == "s3" || u.Scheme == "gs") && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized) {
+			return nil, fmt.Errorf("GET %s: %s: labctl reads %s:// results without credentials, "+
+				"so the bucket must allow anonymous reads; copy the results locally (e.g. with the aws or gsutil CLI) "+
+				"and pass --url file://<dir>/ instead", rawURL, resp.Status, u.Scheme)
+		}
+		return nil, fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// manifest mirrors the fields of the operator's {exp}/index.json that
+// export needs.
+type manifest struct {
+	FinalIteration *int `json:"finalIteration"`
+	Runs           []struct {
+		Iteration int `json:"iteration"`
+		Artifacts []struct {
+			Name   string `json:"name"`
+			SHA256 string `json:"sha256"`
+		} `json:"artifacts"`
+	} `json:"runs"`
+}
+
+// matches reports whether an artifact belongs to an export format.
+func matches(format, name string) bool {
+	switch format {
+	case "csv":
+		return strings.HasPrefix(name, "csv/")
+	case "parquet":
+		return strings.HasSuffix(name, ".parquet")
+	case "openmetrics":
+		return strings.HasSuffix(name, ".openmetrics")
+	case "json":
+		return name == "summary.json" || name == "metrics-snapshot.json"
+	}
+	return false
+}
+
+// Export downloads the promoted run's files of the given format from
+// resultsURL (the experiment's prefix, ending in "/") into dir, verifying
+// each against the manifest checksum. Files are read from the run's own
+// runs/{n}/ copy: the promoted copies at the prefix root can be rewritten
+// after promotion (summary.json gains the analysis). It returns the written
+// paths.
+func (f *Fetcher) Export(ctx context.Context, resultsURL, format, dir string) ([]string, error) {
+	base := strings.TrimSuffix(resultsURL, "/") + "/"
+
+	rc, err := f.Open(ctx, base+"index.json")
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var m manifest
+	err = json.NewDecoder(rc).Decode(&m)
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	if m.FinalIteration == nil {
+		return nil, fmt.Errorf("experiment has no promoted results yet")
+	}
+
+	var written []string
+	for _, run := range m.Runs {
+		if run.Iteration != *m.FinalIteration {
+			continue
+		}
+		runBase := fmt.Sprintf("%sruns/%d/", base, run.Iteration)
+		for _, a := range run.Artifacts {
+			if !matches(format, a.Name) {
+				continue
+			}
+			path, err := f.download(ctx, runBase+a.Name, a.SHA256, filepath.Join(dir, filepath.FromSlash(a.Name)))
+			if err != nil {
+				return written, err
+			}
+			written = append(written, path)
+		}
+	}
+	if len(written) == 0 {
+		return nil, fmt.Errorf("no %s export in the final run (collected before exports were added?)", format)
+	}
+	return written, nil
+}
+
+func (f *Fetcher) download(ctx context.Context, src, sum, dst string) (string, error) {
+	rc, err := f.Open(ctx, src)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, h), rc); err != nil {
+		out.Close()
+		return "", fmt.Errorf("download %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); sum != "" && got != sum {
+		return "", fmt.Errorf("%s: checksum mismatch", src)
+	}
+	return dst, nil
+}
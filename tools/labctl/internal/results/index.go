@@ -0,0 +1,111 @@
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// IndexEntry is one experiment in the operator's results index
+// (GET /api/v1/experiments).
+type IndexEntry struct {
+	Name            string             `json:"name"`
+	Namespace       string             `json:"namespace,omitempty"`
+	Title           string             `json:"title,omitempty"`
+	Tags            []string           `json:"tags,omitempty"`
+	Phase           string             `json:"phase"`
+	CreatedAt       time.Time          `json:"createdAt"`
+	CompletedAt     time.Time          `json:"completedAt"`
+	DurationSec     float64            `json:"durationSeconds"`
+	CostUSD         float64            `json:"costUSD,omitempty"`
+	MachineVerdict  string             `json:"machineVerdict,omitempty"`
+	AnalysisVerdict string             `json:"analysisVerdict,omitempty"`
+	AnalysisPhase   string             `json:"analysisPhase,omitempty"`
+	ReviewPhase     string             `json:"reviewPhase,omitempty"`
+	KeyMetrics      map[string]float64 `json:"keyMetrics,omitempty"`
+	ResultsURL      string             `json:"resultsURL,omitempty"`
+	Published       bool               `json:"published,omitempty"`
+	PublishPRURL    string             `json:"publishPRURL,omitempty"`
+	Archived        bool               `json:"archived,omitempty"`
+}
+
+// Verdict is the analyzer's verdict when there is one, else the machine verdict.
+func (e *IndexEntry) Verdict() string {
+	if e.AnalysisVerdict != "" {
+		return e.AnalysisVerdict
+	}
+	return e.MachineVerdict
+}
+
+// IndexList is the body of GET /api/v1/experiments.
+type IndexList struct {
+	UpdatedAt   time.Time    `json:"updatedAt"`
+	Total       int          `json:"total"`
+	Experiments []IndexEntry `json:"experiments"`
+}
+
+// ListPath is the index API's list endpoint.
+const ListPath = "/api/v1/experiments"
+
+// GetFunc performs a GET against the index API and returns the body.
+type GetFunc func(ctx context.Context, path string, params map[string]string) ([]byte, error)
+
+// DirectGet returns a GetFunc for an API base URL such as
+// http://localhost:8082 (e.g. via kubectl port-forward).
+func DirectGet(base string, client *http.Client) GetFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, path string, params map[string]string) ([]byte, error) {
+		q := url.Values{}
+		for k, v := range params {
+			q.Set(k, v)
+		}
+		u := strings.TrimSuffix(base, "/") + path
+		if len(q) > 0 {
+			u += "?" + q.Encode()
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			var apiErr struct {
+				Error string `json:"error"`
+			}
+			if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+				return nil, fmt.Errorf("%s: %s", resp.Status, apiErr.Error)
+			}
+			return nil, fmt.Errorf("GET %s: %s", u, resp.Status)
+		}
+		return body, nil
+	}
+}
+
+// ListIndex queries the results index with API filter parameters (tag,
+// verdict, phase, since, until, published, limit).
+func ListIndex(ctx context.Context, get GetFunc, params map[string]string) (*IndexList, error) {
+	body, err := get(ctx, ListPath, params)
+	if err != nil {
+		return nil, err
+	}
+	var list IndexList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("decode results index: %w", err)
+	}
+	return &list, nil
+}